@@ -2,28 +2,42 @@ package agent
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
+// heartbeat reports to the masters in turn, so a standby master is used
+// after the active master fails.
 func (as *AgentServer) heartbeat() {
 
-	for {
-		err := as.doHeartbeat(10 * time.Second)
+	masters := strings.Split(as.Master, ",")
+	for i := 0; ; i++ {
+		master := strings.TrimSpace(masters[i%len(masters)])
+		if as.isDrained() {
+			log.Printf("Drained, stop heartbeats to %s", as.Master)
+			return
+		}
+		err := as.doHeartbeat(master, 10*time.Second)
 		if err != nil {
-			time.Sleep(30 * time.Second)
+			log.Printf("Heartbeat to %s: %v", master, err)
+			if (i+1)%len(masters) == 0 {
+				time.Sleep(5 * time.Second)
+			}
 		}
 	}
 
 }
 
-func (as *AgentServer) doHeartbeat(sleepInterval time.Duration) error {
+func (as *AgentServer) doHeartbeat(master string, sleepInterval time.Duration) error {
 
-	grpcConnection, err := grpc.Dial(as.Master, grpc.WithInsecure())
+	grpcConnection, err := grpc.Dial(master, security.GrpcDialOptions(&security.Credentials{Token: *as.Option.MasterToken})...)
 	if err != nil {
 		return fmt.Errorf("fail to dial: %v", err)
 	}
@@ -33,24 +47,49 @@ func (as *AgentServer) doHeartbeat(sleepInterval time.Duration) error {
 
 	stream, err := client.SendHeartbeat(context.Background())
 	if err != nil {
-		log.Printf("SendHeartbeat error: %v", err)
 		return err
 	}
 
-	log.Printf("Heartbeat to %s", as.Master)
+	// the master only responds when rejecting or closing the stream
+	closed := make(chan error, 1)
+	go func() {
+		if err := stream.RecvMsg(&pb.Empty{}); err != nil {
+			closed <- err
+		} else {
+			closed <- io.EOF
+		}
+	}()
+
+	if err := as.sendOneHeartbeat(stream); err != nil {
+		return err
+	}
+
+	log.Printf("Heartbeat to %s", master)
 
 	ticker := time.NewTicker(sleepInterval)
+	defer ticker.Stop()
 	quickTicker := time.NewTicker(500 * time.Millisecond)
+	defer quickTicker.Stop()
 	for {
 		select {
+		case err := <-closed:
+			return err
+		case <-as.drained:
+			return stream.CloseSend()
 		case <-quickTicker.C:
-			if as.allocatedHasChanges {
-				as.allocatedHasChanges = false
+			// select picks randomly among the ready cases, even after drained
+			if as.isDrained() {
+				return stream.CloseSend()
+			}
+			if as.takeChanges() {
 				if err := as.sendOneHeartbeat(stream); err != nil {
 					return err
 				}
 			}
 		case <-ticker.C:
+			if as.isDrained() {
+				return stream.CloseSend()
+			}
 			if err := as.sendOneHeartbeat(stream); err != nil {
 				return err
 			}
@@ -60,6 +99,15 @@ func (as *AgentServer) doHeartbeat(sleepInterval time.Duration) error {
 }
 
 func (as *AgentServer) sendOneHeartbeat(stream pb.GleamMaster_SendHeartbeatClient) error {
+	// the disk is reported as the capacity and the usage of the dataset files
+	capacity, used := as.datasetDiskUsage()
+	resource := *as.computeResource
+	resource.DiskMb = capacity / 1024 / 1024
+	as.allocatedResourceLock.Lock()
+	allocated := *as.allocatedResource
+	as.allocatedResourceLock.Unlock()
+	allocated.DiskMb = used / 1024 / 1024
+
 	beat := &pb.Heartbeat{
 		Location: &pb.Location{
 			DataCenter: *as.Option.DataCenter,
@@ -67,8 +115,10 @@ func (as *AgentServer) sendOneHeartbeat(stream pb.GleamMaster_SendHeartbeatClien
 			Server:     *as.Option.Host,
 			Port:       int32(*as.Option.Port),
 		},
-		Resource:  as.computeResource,
-		Allocated: as.allocatedResource,
+		Resource:      &resource,
+		Allocated:     &allocated,
+		FlowAllocated: as.flowAllocations(),
+		Draining:      as.draining(),
 	}
 
 	// log.Printf("Reporting allocated %v", as.allocatedResource)
@@ -8,15 +8,25 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/chrislusf/gleam/distributed/resource"
 	"github.com/chrislusf/gleam/pb"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func (as *AgentServer) serveGrpc(listener net.Listener) {
-	grpcServer := grpc.NewServer()
+	var grpcServer *grpc.Server
+	if as.auth != nil {
+		grpcServer = grpc.NewServer(
+			grpc.UnaryInterceptor(as.auth.UnaryServerInterceptor),
+			grpc.StreamInterceptor(as.auth.StreamServerInterceptor),
+		)
+	} else {
+		grpcServer = grpc.NewServer()
+	}
 	pb.RegisterGleamAgentServer(grpcServer, as)
 	grpcServer.Serve(listener)
 }
@@ -82,8 +92,10 @@ func (as *AgentServer) SendFileResource(stream pb.GleamAgent_SendFileResourceSer
 func (as *AgentServer) Cleanup(ctx context.Context, cleanupRequest *pb.CleanupRequest) (*pb.CleanupResponse, error) {
 
 	log.Println("cleaning up", cleanupRequest.GetFlowHashCode())
+	as.killExecutions(cleanupRequest.GetFlowHashCode())
 	dir := path.Join(*as.Option.Dir, fmt.Sprintf("%d", cleanupRequest.GetFlowHashCode()))
 	os.RemoveAll(dir)
+	as.shuffles.Cleanup(cleanupRequest.GetFlowHashCode())
 
 	return &pb.CleanupResponse{}, nil
 }
@@ -91,11 +103,25 @@ func (as *AgentServer) Cleanup(ctx context.Context, cleanupRequest *pb.CleanupRe
 // Execute executes a request and stream stdout and stderr back
 func (as *AgentServer) Execute(request *pb.ExecutionRequest, stream pb.GleamAgent_ExecuteServer) error {
 
+	allocated := *request.GetResource()
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	execution := &runningExecution{
+		flowHashCode:  request.GetInstructionSet().GetFlowHashCode(),
+		resource:      allocated,
+		startTime:     time.Now(),
+		cancel:        cancel,
+		isPreemptible: request.GetIsPreemptible(),
+	}
+	if err := as.addExecution(execution); err != nil {
+		return err
+	}
+	defer as.removeExecution(execution)
+
 	dir := path.Join(*as.Option.Dir, fmt.Sprintf("%d", request.GetInstructionSet().GetFlowHashCode()), request.GetDir())
 	os.MkdirAll(dir, 0755)
 
-	allocated := *request.GetResource()
-
 	as.plusAllocated(allocated)
 	defer as.minusAllocated(allocated)
 
@@ -105,7 +131,15 @@ func (as *AgentServer) Execute(request *pb.ExecutionRequest, stream pb.GleamAgen
 
 	defer deleteStatsChanByInstructionSet(request.InstructionSet)
 
-	return as.executeCommand(stream, request, dir, statsChan)
+	err := as.executeCommand(ctx, stream, request, dir, statsChan)
+	if as.isPreempted(execution) {
+		// tell the driver to bid for the resource again
+		return grpc.Errorf(codes.Aborted, "%s is preempted", request.GetInstructionSet().GetName())
+	}
+	if err != nil {
+		as.metrics.executorFailures.Inc()
+	}
+	return err
 
 }
 
@@ -137,6 +171,7 @@ func (as *AgentServer) Delete(ctx context.Context, deleteRequest *pb.DeleteDatas
 	log.Println("deleting", deleteRequest.Name)
 	as.storageBackend.DeleteNamedDatasetShard(deleteRequest.Name)
 	as.inMemoryChannels.Cleanup(deleteRequest.Name)
+	as.shuffles.DeleteNamedDatasetShard(deleteRequest.Name)
 
 	return &pb.DeleteDatasetShardResponse{}, nil
 }
@@ -154,3 +189,19 @@ func (as *AgentServer) minusAllocated(allocated pb.ComputeResource) {
 	as.allocatedHasChanges = true
 	*as.allocatedResource = as.allocatedResource.Minus(allocated)
 }
+
+// markChanged reports to the master at the next quick heartbeat.
+func (as *AgentServer) markChanged() {
+	as.allocatedResourceLock.Lock()
+	defer as.allocatedResourceLock.Unlock()
+	as.allocatedHasChanges = true
+}
+
+// takeChanges returns whether anything changed since the last call.
+func (as *AgentServer) takeChanges() bool {
+	as.allocatedResourceLock.Lock()
+	defer as.allocatedResourceLock.Unlock()
+	hasChanges := as.allocatedHasChanges
+	as.allocatedHasChanges = false
+	return hasChanges
+}
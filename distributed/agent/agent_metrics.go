@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// agentMetrics are updated as the agent works. Other metrics are read from
+// the agent state when scraped.
+type agentMetrics struct {
+	shardBytesIn     *prometheus.CounterVec
+	shardBytesOut    *prometheus.CounterVec
+	executorFailures prometheus.Counter
+}
+
+func newAgentMetrics() *agentMetrics {
+	return &agentMetrics{
+		shardBytesIn: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gleam_agent_shard_bytes_in_total",
+			Help: "Bytes written into dataset shards on this agent.",
+		}, []string{"storage"}),
+		shardBytesOut: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gleam_agent_shard_bytes_out_total",
+			Help: "Bytes read from dataset shards on this agent.",
+		}, []string{"storage"}),
+		executorFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gleam_agent_executor_failures_total",
+			Help: "Executors that failed or were killed.",
+		}),
+	}
+}
+
+var (
+	agentCpuDesc = prometheus.NewDesc("gleam_agent_cpu_count",
+		"Number of executors this agent can run.", nil, nil)
+	agentMemoryDesc = prometheus.NewDesc("gleam_agent_memory_mb",
+		"Memory in MB this agent can allocate.", nil, nil)
+	agentAllocatedCpuDesc = prometheus.NewDesc("gleam_agent_allocated_cpu_count",
+		"Number of executors allocated on this agent.", nil, nil)
+	agentAllocatedMemoryDesc = prometheus.NewDesc("gleam_agent_allocated_memory_mb",
+		"Memory in MB allocated on this agent.", nil, nil)
+	agentRunningExecutorsDesc = prometheus.NewDesc("gleam_agent_running_executors",
+		"Number of executors running on this agent.", nil, nil)
+	agentDiskUsageDesc = prometheus.NewDesc("gleam_agent_disk_usage_bytes",
+		"Bytes used by the files in the agent folder.", nil, nil)
+)
+
+// agentCollector reads the agent state when scraped.
+type agentCollector struct {
+	as *AgentServer
+}
+
+func (c *agentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- agentCpuDesc
+	ch <- agentMemoryDesc
+	ch <- agentAllocatedCpuDesc
+	ch <- agentAllocatedMemoryDesc
+	ch <- agentRunningExecutorsDesc
+	ch <- agentDiskUsageDesc
+}
+
+func (c *agentCollector) Collect(ch chan<- prometheus.Metric) {
+	as := c.as
+	ch <- prometheus.MustNewConstMetric(agentCpuDesc, prometheus.GaugeValue, float64(as.computeResource.GetCpuCount()))
+	ch <- prometheus.MustNewConstMetric(agentMemoryDesc, prometheus.GaugeValue, float64(as.computeResource.GetMemoryMb()))
+
+	as.allocatedResourceLock.Lock()
+	allocated := *as.allocatedResource
+	as.allocatedResourceLock.Unlock()
+	ch <- prometheus.MustNewConstMetric(agentAllocatedCpuDesc, prometheus.GaugeValue, float64(allocated.CpuCount))
+	ch <- prometheus.MustNewConstMetric(agentAllocatedMemoryDesc, prometheus.GaugeValue, float64(allocated.MemoryMb))
+
+	as.executionsLock.Lock()
+	running := len(as.executions)
+	as.executionsLock.Unlock()
+	ch <- prometheus.MustNewConstMetric(agentRunningExecutorsDesc, prometheus.GaugeValue, float64(running))
+
+	as.diskStatsLock.Lock()
+	diskTotal := as.diskStats.total
+	as.diskStatsLock.Unlock()
+	ch <- prometheus.MustNewConstMetric(agentDiskUsageDesc, prometheus.GaugeValue, float64(diskTotal))
+}
+
+func (as *AgentServer) metricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		as.metrics.shardBytesIn,
+		as.metrics.shardBytesOut,
+		as.metrics.executorFailures,
+		&agentCollector{as},
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// diskUsage sums up the sizes of all files under the folder, and of those
+// with the name suffix.
+func diskUsage(dir, suffix string) (total, matched int64) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			total += info.Size()
+			if strings.HasSuffix(info.Name(), suffix) {
+				matched += info.Size()
+			}
+		}
+		return nil
+	})
+	return
+}
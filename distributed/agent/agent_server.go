@@ -3,16 +3,21 @@
 package agent
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/chrislusf/gleam/distributed/netchan"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"github.com/chrislusf/gleam/util"
 	"github.com/golang/protobuf/proto"
@@ -30,6 +35,18 @@ type AgentServerOption struct {
 	MemoryMB     *int64
 	CPULevel     *int32
 	CleanRestart *bool
+	TLSOption    *security.TLSOption
+	MasterToken  *string
+	// authenticate the callers, like the master
+	AuthTokenFile      *string
+	AuthHtpasswdFile   *string
+	AuthPermissionFile *string
+	CgroupDir          *string
+	LogMaxMB           *int64
+	LogMaxAge          *time.Duration
+	DiskMaxMB          *int64
+	// "none", "snappy" or "flate" to move the dataset shards when draining
+	MigrateCompression *string
 }
 
 type AgentServer struct {
@@ -41,7 +58,19 @@ type AgentServer struct {
 	allocatedResourceLock   sync.Mutex
 	storageBackend          *LocalDatasetShardsManager
 	inMemoryChannels        *LocalDatasetShardsManagerInMemory
+	shuffles                *LocalShuffleManager
 	receiveFileResourceLock sync.Mutex
+	executions              []*runningExecution
+	executionsLock          sync.Mutex
+	metrics                 *agentMetrics
+	auth                    *security.Auth
+	// set when draining, new executions are rejected
+	isDraining bool
+	// closed when drained, to stop the heartbeats
+	drained       chan struct{}
+	drainedOnce   sync.Once
+	diskStats     diskStats
+	diskStatsLock sync.Mutex
 }
 
 func RunAgentServer(option *AgentServerOption) {
@@ -52,21 +81,46 @@ func RunAgentServer(option *AgentServerOption) {
 	println("starting in", absoluteDir)
 	option.Dir = &absoluteDir
 
+	if !netchan.IsSupportedCompression(*option.MigrateCompression) {
+		log.Fatalf("unsupported compression %s to migrate dataset shards", *option.MigrateCompression)
+	}
+
+	if *option.CgroupDir != "" {
+		if err := initCgroup(*option.CgroupDir); err != nil {
+			log.Fatalf("Failed to set up cgroup %s: %v", *option.CgroupDir, err)
+		}
+	}
+
+	auth, err := security.NewAuth(*option.AuthTokenFile, *option.AuthHtpasswdFile, *option.AuthPermissionFile, methodPermissions)
+	if err != nil {
+		log.Fatalf("agent server fails to load authentication: %v", err)
+	}
+	// to move the dataset shards to other agents when draining
+	security.SetAgentCredentials(&security.Credentials{Token: *option.MasterToken})
+
 	as := &AgentServer{
 		Option:           option,
 		Master:           *option.Master,
 		storageBackend:   NewLocalDatasetShardsManager(*option.Dir, int(*option.Port)),
 		inMemoryChannels: NewLocalDatasetShardsManagerInMemory(),
+		shuffles:         NewLocalShuffleManager(*option.Dir, int(*option.Port)),
 		computeResource: &pb.ComputeResource{
 			CpuCount: int32(*option.MaxExecutor),
 			CpuLevel: int32(*option.CPULevel),
 			MemoryMb: *option.MemoryMB,
 		},
 		allocatedResource: &pb.ComputeResource{},
+		metrics:           newAgentMetrics(),
+		auth:              auth,
+		drained:           make(chan struct{}),
 	}
 
 	go as.storageBackend.purgeExpiredEntries()
 	go as.inMemoryChannels.purgeExpiredEntries()
+	go as.shuffles.purgeExpiredEntries()
+	go as.purgeExpiredLogs(*option.LogMaxAge)
+	as.refreshDiskUsage()
+	go as.keepDiskUsage()
 	go as.heartbeat()
 
 	listener, err := net.Listen("tcp", fmt.Sprintf("%v:%d", *option.Host, *option.Port))
@@ -89,12 +143,16 @@ func RunAgentServer(option *AgentServerOption) {
 		}
 	}
 
-	m := cmux.New(listener)
+	m := cmux.New(security.NewListener(listener))
 	grpcListener := m.Match(cmux.HTTP2HeaderField("content-type", "application/grpc"))
+	muxListener := m.Match(cmux.PrefixMatcher(netchan.MuxPreface))
+	httpListener := m.Match(cmux.HTTP1Fast())
 	tcpListener := m.Match(cmux.Any())
 
 	go as.serveGrpc(grpcListener)
+	go as.serveHttp(httpListener)
 	go as.serveTcp(tcpListener)
+	go as.serveMux(muxListener)
 
 	if err := m.Serve(); !strings.Contains(err.Error(), "use of closed network connection") {
 		panic(err)
@@ -102,20 +160,44 @@ func RunAgentServer(option *AgentServerOption) {
 
 }
 
+// methodPermissions lists the permission required by each GleamAgent rpc.
+// The executors call the agent with the credentials of the driver.
+var methodPermissions = map[string]security.Permission{
+	"/pb.GleamAgent/SendFileResource":           security.PermissionSubmit,
+	"/pb.GleamAgent/Execute":                    security.PermissionSubmit,
+	"/pb.GleamAgent/CollectExecutionStatistics": security.PermissionSubmit,
+	"/pb.GleamAgent/Delete":                     security.PermissionSubmit,
+	"/pb.GleamAgent/Cleanup":                    security.PermissionSubmit,
+	"/pb.GleamAgent/Preempt":                    security.PermissionAgent,
+	"/pb.GleamAgent/Drain":                      security.PermissionAgent,
+	"/pb.GleamAgent/GetLogs":                    security.PermissionAgent,
+}
+
+// serveHttp serves the prometheus metrics.
+func (as *AgentServer) serveHttp(listener net.Listener) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", as.metricsHandler())
+	var handler http.Handler = mux
+	if as.auth != nil {
+		handler = as.auth.HttpHandler(mux)
+	}
+	(&http.Server{Handler: handler}).Serve(listener)
+}
+
 // Run starts the heartbeating to master and starts accepting requests.
 func (as *AgentServer) serveTcp(listener net.Listener) {
 
 	for {
 		// Listen for an incoming connection.
-		conn, err := listener.Accept()
+		conn, err := accept(listener)
 		if err != nil {
-			fmt.Println("Error accepting: ", err.Error())
-			continue
+			log.Printf("Stop accepting: %v", err)
+			return
 		}
 		// Handle connections in a new goroutine.
 		go func() {
 			defer conn.Close()
-			if err = conn.SetDeadline(time.Time{}); err != nil {
+			if err := conn.SetDeadline(time.Time{}); err != nil {
 				fmt.Printf("Failed to set timeout: %v\n", err)
 			}
 			if c, ok := conn.(*net.TCPConn); ok {
@@ -126,6 +208,64 @@ func (as *AgentServer) serveTcp(listener net.Listener) {
 	}
 }
 
+// serveMux accepts the shard transfers multiplexed on one connection per process.
+func (as *AgentServer) serveMux(listener net.Listener) {
+
+	for {
+		conn, err := accept(listener)
+		if err != nil {
+			log.Printf("Stop accepting multiplexed sessions: %v", err)
+			return
+		}
+		go func() {
+			defer conn.Close()
+			if err := conn.SetDeadline(time.Time{}); err != nil {
+				fmt.Printf("Failed to set timeout: %v\n", err)
+			}
+			session, err := netchan.NewServerSession(conn)
+			if err != nil {
+				log.Printf("Failed to start multiplexed session: %v", err)
+				return
+			}
+			for {
+				stream, err := session.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer stream.Close()
+					as.handleRequest(stream)
+				}()
+			}
+		}()
+	}
+}
+
+// accept waits for the next connection. It backs off on temporary errors,
+// such as running out of file descriptors, instead of spinning on them.
+func accept(listener net.Listener) (net.Conn, error) {
+	var delay time.Duration
+	for {
+		conn, err := listener.Accept()
+		if err == nil {
+			return conn, nil
+		}
+		if ne, ok := err.(net.Error); !ok || !ne.Temporary() {
+			return nil, err
+		}
+		if delay == 0 {
+			delay = 5 * time.Millisecond
+		} else {
+			delay *= 2
+		}
+		if delay > time.Second {
+			delay = time.Second
+		}
+		log.Printf("Error accepting: %v; retrying in %v", err, delay)
+		time.Sleep(delay)
+	}
+}
+
 func (r *AgentServer) handleRequest(conn net.Conn) {
 
 	data, err := util.ReadMessage(conn)
@@ -139,23 +279,77 @@ func (r *AgentServer) handleRequest(conn net.Conn) {
 	if err := proto.Unmarshal(data, newCmd); err != nil {
 		log.Fatal("unmarshaling error: ", err)
 	}
+	// the executors transfer with the credentials of the driver, the agents with their own
+	if _, err := r.auth.Authorize(newCmd.GetAuthorization(), security.PermissionSubmit, security.PermissionAgent); err != nil {
+		log.Printf("Failed to authorize %s: %v", conn.RemoteAddr(), err)
+		return
+	}
 	r.handleCommandConnection(conn, newCmd)
 }
 
 func (as *AgentServer) handleCommandConnection(conn net.Conn,
 	command *pb.ControlMessage) {
+
+	// confirm the compression, or only the checksums if it is not supported
+	compression := command.GetCompression()
+	if compression != "" {
+		if !netchan.IsSupportedCompression(compression) {
+			compression = "none"
+		}
+		if err := util.WriteMessage(conn, []byte(compression)); err != nil {
+			log.Printf("Failed to confirm compression %s: %v", compression, err)
+			return
+		}
+	}
+
 	if command.GetReadRequest() != nil {
-		if !command.GetIsOnDiskIO() {
-			as.handleInMemoryReadConnection(conn, command.ReadRequest.ReaderName, command.ReadRequest.ChannelName)
+		var writer io.Writer = conn
+		var blockWriter io.WriteCloser
+		if compression != "" {
+			blockWriter = netchan.NewBlockWriter(conn, compression)
+			writer = blockWriter
+		}
+		var err error
+		if command.GetShuffle() != nil {
+			ctx := readerContext(conn)
+			if command.ReadRequest.GetIsInspecting() {
+				// only the parts already pushed, without waiting
+				done, cancel := context.WithCancel(ctx)
+				cancel()
+				ctx = done
+			}
+			err = as.handleShuffleReadConnection(ctx, writer, command.ReadRequest.ReaderName, command.ReadRequest.ChannelName, command.GetShuffle())
+		} else if !command.GetIsOnDiskIO() {
+			err = as.handleInMemoryReadConnection(writer, command.ReadRequest.ReaderName, command.ReadRequest.ChannelName)
 		} else {
-			as.handleReadConnection(conn, command.ReadRequest.ReaderName, command.ReadRequest.ChannelName)
+			err = as.handleReadConnection(writer, command.ReadRequest.ReaderName, command.ReadRequest.ChannelName, command.ReadRequest.GetIsInspecting())
+		}
+		// without the end block, the reader fails instead of taking partial data
+		if blockWriter != nil && err == nil {
+			if err = blockWriter.Close(); err != nil {
+				log.Printf("Failed to end %s: %v", command.ReadRequest.ChannelName, err)
+			}
 		}
 	}
 	if command.GetWriteRequest() != nil {
-		if !command.GetIsOnDiskIO() {
-			as.handleLocalInMemoryWriteConnection(conn, command.WriteRequest.WriterName, command.WriteRequest.ChannelName, int(command.GetWriteRequest().GetReaderCount()))
+		var reader io.Reader = conn
+		if compression != "" {
+			reader = netchan.NewBlockReader(conn)
+		}
+		var err error
+		if command.GetShuffle() != nil {
+			err = as.handleShuffleWriteConnection(reader, command.WriteRequest.WriterName, command.WriteRequest.ChannelName, command.GetShuffle())
+		} else if !command.GetIsOnDiskIO() {
+			err = as.handleLocalInMemoryWriteConnection(reader, command.WriteRequest.WriterName, command.WriteRequest.ChannelName, int(command.GetWriteRequest().GetReaderCount()))
 		} else {
-			as.handleLocalWriteConnection(conn, command.WriteRequest.WriterName, command.WriteRequest.ChannelName, int(command.GetWriteRequest().GetReaderCount()))
+			err = as.handleLocalWriteConnection(reader, command.WriteRequest.WriterName, command.WriteRequest.ChannelName, int(command.GetWriteRequest().GetReaderCount()))
+		}
+		if compression != "" {
+			var result []byte
+			if err != nil {
+				result = []byte(err.Error())
+			}
+			util.WriteMessage(conn, result)
 		}
 	}
 }
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// When the dataset files use more than diskHighPercent of the disk capacity,
+// the least recently read dataset shards are evicted down to diskLowPercent.
+const (
+	diskHighPercent = 90
+	diskLowPercent  = 80
+)
+
+// diskStats is refreshed by keepDiskUsage, so the heartbeats and the metrics
+// do not walk the folder each time.
+type diskStats struct {
+	// the disk space for the dataset files, 0 if unknown
+	capacity int64
+	// the size of the dataset files
+	used int64
+	// the size of all files in the folder
+	total int64
+}
+
+// datasetDiskUsage returns the disk space in bytes for the dataset files, which is
+// the used space plus the free disk space, limited by --disk.max, and the used space.
+// The capacity is 0 if unknown.
+func (as *AgentServer) datasetDiskUsage() (capacity, used int64) {
+	as.diskStatsLock.Lock()
+	defer as.diskStatsLock.Unlock()
+	return as.diskStats.capacity, as.diskStats.used
+}
+
+func (as *AgentServer) refreshDiskUsage() {
+	total, used := diskUsage(*as.Option.Dir, fmt.Sprintf("-%d.dat", *as.Option.Port))
+
+	maxBytes := *as.Option.DiskMaxMB * 1024 * 1024
+	capacity := maxBytes
+	if free, err := freeDiskBytes(*as.Option.Dir); err == nil {
+		capacity = used + free
+		if maxBytes > 0 && maxBytes < capacity {
+			capacity = maxBytes
+		}
+	}
+
+	as.diskStatsLock.Lock()
+	as.diskStats = diskStats{capacity: capacity, used: used, total: total}
+	as.diskStatsLock.Unlock()
+}
+
+// keepDiskUsage evicts dataset shards when the disk is running low.
+func (as *AgentServer) keepDiskUsage() {
+	for {
+		capacity, used := as.datasetDiskUsage()
+		if capacity > 0 && used*100 > capacity*diskHighPercent {
+			freed := as.storageBackend.evictLeastRecentlyRead(used - capacity*diskLowPercent/100)
+			log.Printf("disk used %dMB of %dMB, evicted %dMB", used/1024/1024, capacity/1024/1024, freed/1024/1024)
+		}
+		time.Sleep(5 * time.Second)
+		as.refreshDiskUsage()
+	}
+}
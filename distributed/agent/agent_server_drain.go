@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/netchan"
+	"github.com/chrislusf/gleam/distributed/store"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+	"golang.org/x/net/context"
+)
+
+// Drain rejects new executions and waits for the running executions to finish.
+// With migrate, the on disk dataset shards of the flows are copied to the
+// target agents. Afterwards the heartbeats stop, and the agent can be shut down.
+// If the drain is cancelled or the migration fails, the agent accepts new
+// executions again.
+func (as *AgentServer) Drain(ctx context.Context, request *pb.DrainRequest) (*pb.DrainResponse, error) {
+	as.setDraining(true)
+
+	log.Printf("draining, waiting for %d executions", as.runningExecutions())
+	for as.runningExecutions() > 0 {
+		select {
+		case <-ctx.Done():
+			log.Printf("drain cancelled: %v", ctx.Err())
+			as.setDraining(false)
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	response := &pb.DrainResponse{}
+	if request.GetMigrate() {
+		migrated, err := as.migrateDatasetShards(ctx, request.GetFlowHashCodes(), request.GetTargets())
+		if err != nil {
+			log.Printf("drain failed: %v", err)
+			as.setDraining(false)
+			return nil, err
+		}
+		response.MigratedShards = migrated
+	}
+
+	as.drainedOnce.Do(func() {
+		close(as.drained)
+	})
+	log.Printf("drained, migrated %d dataset shards", len(response.GetMigratedShards()))
+
+	return response, nil
+}
+
+func (as *AgentServer) setDraining(isDraining bool) {
+	as.executionsLock.Lock()
+	as.isDraining = isDraining
+	as.executionsLock.Unlock()
+	// report to the master at the next quick heartbeat
+	as.markChanged()
+}
+
+func (as *AgentServer) draining() bool {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+	return as.isDraining
+}
+
+// isDrained checks whether the heartbeats should stop.
+func (as *AgentServer) isDrained() bool {
+	select {
+	case <-as.drained:
+		return true
+	default:
+		return false
+	}
+}
+
+func (as *AgentServer) runningExecutions() int {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+	return len(as.executions)
+}
+
+// migrateDatasetShards copies the dataset shards of the flows to the targets in turn.
+func (as *AgentServer) migrateDatasetShards(ctx context.Context, flowHashCodes []uint32, targets []*pb.Location) (migrated []*pb.DataLocation, err error) {
+	shards := as.storageBackend.flowDatasetShards(flowHashCodes)
+	if len(shards) == 0 {
+		return nil, nil
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no agent to migrate %d dataset shards to", len(shards))
+	}
+
+	var names []string
+	for name := range shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		target := targets[i%len(targets)]
+		if err := migrateDatasetShard(ctx, name, shards[name], target.URL(), *as.Option.MigrateCompression); err != nil {
+			return nil, fmt.Errorf("migrate %s to %s: %v", name, target.URL(), err)
+		}
+		log.Printf("migrated %s to %s", name, target.URL())
+		migrated = append(migrated, &pb.DataLocation{
+			Name:     name,
+			Location: target,
+			OnDisk:   true,
+		})
+	}
+	return migrated, nil
+}
+
+// migrateDatasetShard writes the dataset shard to the other agent, as an executor would.
+func migrateDatasetShard(ctx context.Context, name string, dsStore store.DataStore, address, compression string) error {
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	go func() {
+		writer.CloseWithError(copyDatasetShard(dsStore, writer))
+	}()
+
+	// the remaining readers are unknown, so the target agent does not evict it
+	var wg sync.WaitGroup
+	wg.Add(1)
+	return netchan.DialWriteChannel(ctx, &wg, "drain", address, name, true, compression, reader, 0)
+}
+
+// copyDatasetShard writes the messages of a completed dataset shard, ending with an EOF message.
+func copyDatasetShard(dsStore store.DataStore, writer io.Writer) error {
+	var offset int64
+	sizeBuf := make([]byte, 4)
+	for {
+		if _, err := dsStore.ReadAt(sizeBuf, offset); err != nil {
+			return fmt.Errorf("read size at offset %d: %v", offset, err)
+		}
+		size := int32(binary.LittleEndian.Uint32(sizeBuf))
+		if size == int32(util.MessageControlEOF) {
+			return util.WriteEOFMessage(writer)
+		}
+		offset += 4
+
+		message := make([]byte, size)
+		if size > 0 {
+			if _, err := dsStore.ReadAt(message, offset); err != nil {
+				return fmt.Errorf("read data at offset %d: %v", offset, err)
+			}
+			offset += int64(size)
+		}
+
+		if err := util.WriteMessage(writer, message); err != nil {
+			return err
+		}
+	}
+}
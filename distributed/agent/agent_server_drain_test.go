@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+)
+
+func TestDrainFailureAcceptsExecutionsAgain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	as := &AgentServer{
+		storageBackend: NewLocalDatasetShardsManager(dir, 1),
+		drained:        make(chan struct{}),
+	}
+
+	// cancelled while waiting for the running execution
+	running := &runningExecution{flowHashCode: 1}
+	if err := as.addExecution(running); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := as.Drain(ctx, &pb.DrainRequest{}); err == nil {
+		t.Errorf("expecting the cancelled drain to fail")
+	}
+	if as.draining() || as.isDrained() {
+		t.Errorf("draining %v, drained %v after the drain is cancelled", as.draining(), as.isDrained())
+	}
+	if err := as.addExecution(&runningExecution{flowHashCode: 2}); err != nil {
+		t.Errorf("rejecting executions after the drain is cancelled: %v", err)
+	}
+	as.executions = nil
+
+	// the dataset shard has no agent to migrate to
+	as.storageBackend.CreateNamedDatasetShard("f1-d1-s0", 1)
+	if _, err := as.Drain(context.Background(), &pb.DrainRequest{Migrate: true, FlowHashCodes: []uint32{1}}); err == nil {
+		t.Errorf("expecting the migration to fail")
+	}
+	if as.draining() || as.isDrained() {
+		t.Errorf("draining %v, drained %v after the migration failed", as.draining(), as.isDrained())
+	}
+
+	if _, err := as.Drain(context.Background(), &pb.DrainRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if !as.draining() || !as.isDrained() {
+		t.Errorf("draining %v, drained %v after the drain", as.draining(), as.isDrained())
+	}
+}
@@ -7,31 +7,32 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"time"
 
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"github.com/golang/protobuf/proto"
 	"github.com/kardianos/osext"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func (as *AgentServer) executeCommand(
+	ctx context.Context,
 	stream pb.GleamAgent_ExecuteServer,
 	startRequest *pb.ExecutionRequest,
 	dir string,
 	statChan chan *pb.ExecutionStat,
 ) (err error) {
 
-	ctx := stream.Context()
 	stopChan := make(chan bool)
 
 	// start the command
 	executableFullFilename, _ := osext.Executable()
-	command := exec.CommandContext(
-		ctx,
-		executableFullFilename,
-		"execute",
-		"--note",
-		startRequest.GetInstructionSet().GetName(),
-	)
+	args := []string{"execute", "--note", startRequest.GetInstructionSet().GetName()}
+	args = append(args, as.Option.TLSOption.Args()...)
+	command := exec.CommandContext(ctx, executableFullFilename, args...)
 	stdin, err := command.StdinPipe()
 	if err != nil {
 		log.Printf("Failed to create stdin pipe: %v", err)
@@ -49,6 +50,24 @@ func (as *AgentServer) executeCommand(
 	}
 	// msg.Env = startRequest.Envs
 	command.Dir = dir
+	if authorization := security.AuthorizationFromContext(ctx); authorization != "" {
+		// not on the command line, to keep it out of the process list
+		command.Env = append(os.Environ(), security.AuthorizationEnv+"="+authorization)
+	}
+
+	var cgroup *executorCgroup
+	if *as.Option.CgroupDir != "" {
+		name := fmt.Sprintf("f%d-%d", startRequest.GetInstructionSet().GetFlowHashCode(), time.Now().UnixNano())
+		if cgroup, err = newExecutorCgroup(*as.Option.CgroupDir, name, *startRequest.GetResource()); err != nil {
+			log.Printf("Failed to create cgroup for %s: %v", startRequest.GetInstructionSet().GetName(), err)
+			return err
+		}
+		defer func() {
+			if err := cgroup.remove(); err != nil {
+				log.Printf("Failed to remove cgroup of %s: %v", startRequest.GetInstructionSet().GetName(), err)
+			}
+		}()
+	}
 
 	if err = command.Start(); err != nil {
 		log.Printf("Failed to start command %s under %s: %v",
@@ -56,6 +75,26 @@ func (as *AgentServer) executeCommand(
 		return err
 	}
 
+	if cgroup != nil {
+		// the executor starts child processes only after reading the instructions
+		if err = cgroup.addProcess(command.Process.Pid); err != nil {
+			log.Printf("Failed to limit command %s: %v", startRequest.GetInstructionSet().GetName(), err)
+			command.Process.Kill()
+			command.Wait()
+			return err
+		}
+	}
+
+	// keep a copy of the stderr, readable later with GetLogs
+	var logWriter io.Writer = os.Stderr
+	taskLog, logErr := as.openTaskLog(startRequest.GetInstructionSet().GetFlowHashCode(), startRequest.GetInstructionSet().GetName())
+	if logErr != nil {
+		log.Printf("Failed to open log for %s: %v", startRequest.GetInstructionSet().GetName(), logErr)
+	} else {
+		defer taskLog.Close()
+		logWriter = io.MultiWriter(os.Stderr, taskLog)
+	}
+
 	errors := make([]error, 2)
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -64,7 +103,7 @@ func (as *AgentServer) executeCommand(
 	}()
 	wg.Add(1)
 	go func() {
-		errors[1] = streamError(&wg, stream, stderr)
+		errors[1] = streamError(&wg, stream, stderr, logWriter)
 	}()
 	wg.Add(1)
 	go streamPulse(&wg, stopChan, statChan, stream)
@@ -89,6 +128,10 @@ func (as *AgentServer) executeCommand(
 	waitErr := command.Wait()
 	if waitErr != nil {
 		log.Printf("Failed to run command %s: %v", startRequest.GetInstructionSet().GetName(), waitErr)
+		if cgroup != nil && cgroup.oomKilled() {
+			waitErr = grpc.Errorf(codes.ResourceExhausted, "%s was killed for exceeding %d MB memory",
+				startRequest.GetInstructionSet().GetName(), startRequest.GetResource().GetMemoryMb())
+		}
 	}
 
 	stopChan <- true
@@ -135,11 +178,11 @@ func streamOutput(wg *sync.WaitGroup, stream pb.GleamAgent_ExecuteServer, reader
 	}
 }
 
-func streamError(wg *sync.WaitGroup, stream pb.GleamAgent_ExecuteServer, reader io.Reader) error {
+func streamError(wg *sync.WaitGroup, stream pb.GleamAgent_ExecuteServer, reader io.Reader, logWriter io.Writer) error {
 
 	defer wg.Done()
 
-	tee := io.TeeReader(reader, os.Stderr)
+	tee := io.TeeReader(reader, logWriter)
 
 	buffer := make([]byte, 1024)
 	for {
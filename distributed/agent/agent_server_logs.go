@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// taskLog keeps the executor stderr of a task group in a file
+// <dir>/logs/f<flowHashCode>/<task group>.log. When the file grows beyond
+// maxBytes, it is renamed to .log.1, replacing the previous one.
+type taskLog struct {
+	sync.Mutex
+	path     string
+	file     *os.File
+	size     int64
+	maxBytes int64
+	failed   bool
+}
+
+func (as *AgentServer) logsDir() string {
+	return filepath.Join(*as.Option.Dir, "logs")
+}
+
+func (as *AgentServer) taskLogPath(flowHashCode uint32, name string) string {
+	return filepath.Join(as.logsDir(), fmt.Sprintf("f%d", flowHashCode), name+".log")
+}
+
+func (as *AgentServer) openTaskLog(flowHashCode uint32, name string) (*taskLog, error) {
+	l := &taskLog{
+		path:     as.taskLogPath(flowHashCode, name),
+		maxBytes: *as.Option.LogMaxMB * 1024 * 1024,
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return nil, err
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(l, "==== %s started on %s\n", name, time.Now().Format(time.RFC3339))
+	return l, nil
+}
+
+func (l *taskLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// Write never fails, so the stderr is still streamed to the driver
+// when the log file can not be written.
+func (l *taskLog) Write(p []byte) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.failed {
+		return len(p), nil
+	}
+	if l.maxBytes > 0 && l.size+int64(len(p)) > l.maxBytes && l.size > 0 {
+		l.file.Close()
+		if err := os.Rename(l.path, l.path+".1"); err != nil {
+			log.Printf("Failed to rotate %s: %v", l.path, err)
+		}
+		if err := l.open(); err != nil {
+			log.Printf("Failed to reopen %s: %v", l.path, err)
+			l.failed = true
+			return len(p), nil
+		}
+	}
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	if err != nil {
+		log.Printf("Failed to write %s: %v", l.path, err)
+		l.failed = true
+	}
+	return len(p), nil
+}
+
+func (l *taskLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// the log is returned in one grpc message, below the default 4MB limit
+const maxLogBytes = 3 << 20
+
+// GetLogs reads the log of a task group, limited to the last tailBytes if
+// positive, and to the last maxLogBytes.
+func (as *AgentServer) GetLogs(ctx context.Context, request *pb.GetLogsRequest) (*pb.GetLogsResponse, error) {
+	name := request.GetName()
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, grpc.Errorf(codes.InvalidArgument, "invalid task group name %q", name)
+	}
+	path := as.taskLogPath(request.GetFlowHashCode(), name)
+
+	tail := request.GetTailBytes()
+	if tail <= 0 || tail > maxLogBytes {
+		tail = maxLogBytes
+	}
+	// read the rotated log only if the current log is shorter than the tail
+	var content []byte
+	found := false
+	for _, p := range []string{path, path + ".1"} {
+		if int64(len(content)) >= tail {
+			break
+		}
+		data, err := readTail(p, tail-int64(len(content)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, grpc.Errorf(codes.Internal, "read %s: %v", p, err)
+		}
+		found = true
+		content = append(data, content...)
+	}
+	if !found {
+		return nil, grpc.Errorf(codes.NotFound, "no log for %s of flow %d", name, request.GetFlowHashCode())
+	}
+	return &pb.GetLogsResponse{Content: content}, nil
+}
+
+// readTail reads the last bytes of the file.
+func readTail(path string, tail int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := fi.Size() - tail
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(io.LimitReader(f, tail))
+}
+
+// purgeExpiredLogs removes the logs of the flows not written for maxAge.
+func (as *AgentServer) purgeExpiredLogs(maxAge time.Duration) {
+	for {
+		cutoverLimit := time.Now().Add(-maxAge)
+		flowDirs, _ := ioutil.ReadDir(as.logsDir())
+		for _, flowDir := range flowDirs {
+			dir := filepath.Join(as.logsDir(), flowDir.Name())
+			if lastWriteAt(dir).Before(cutoverLimit) {
+				log.Printf("purging logs %s", dir)
+				os.RemoveAll(dir)
+			}
+		}
+		time.Sleep(1 * time.Hour)
+	}
+}
+
+// lastWriteAt is the latest modification time of the files in the folder.
+func lastWriteAt(dir string) (t time.Time) {
+	fileInfos, _ := ioutil.ReadDir(dir)
+	for _, fi := range fileInfos {
+		if fi.ModTime().After(t) {
+			t = fi.ModTime()
+		}
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+)
+
+func TestGetLogsTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	as := &AgentServer{Option: &AgentServerOption{Dir: &dir}}
+
+	path := as.taskLogPath(1, "tg")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	rotated := bytes.Repeat([]byte("r"), maxLogBytes)
+	ioutil.WriteFile(path+".1", rotated, 0644)
+	ioutil.WriteFile(path, []byte("current"), 0644)
+
+	tests := []struct {
+		name      string
+		tailBytes int64
+		expected  []byte
+	}{
+		{"in the current log", 4, []byte("rent")},
+		{"across the rotated log", 10, []byte("rrrcurrent")},
+		{"limited to maxLogBytes", 0, append(rotated[:maxLogBytes-7], "current"...)},
+		{"larger than maxLogBytes", maxLogBytes * 2, append(rotated[:maxLogBytes-7], "current"...)},
+	}
+	for _, test := range tests {
+		response, err := as.GetLogs(context.Background(), &pb.GetLogsRequest{FlowHashCode: 1, Name: "tg", TailBytes: test.tailBytes})
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(response.GetContent(), test.expected) {
+			t.Errorf("%s: read %d bytes, expecting %d", test.name, len(response.GetContent()), len(test.expected))
+		}
+	}
+
+	if _, err := as.GetLogs(context.Background(), &pb.GetLogsRequest{FlowHashCode: 2, Name: "tg"}); err == nil {
+		t.Errorf("expecting an error for a missing log")
+	}
+	if _, err := as.GetLogs(context.Background(), &pb.GetLogsRequest{FlowHashCode: 1, Name: "../tg"}); err == nil {
+		t.Errorf("expecting an error for a name out of the log folder")
+	}
+}
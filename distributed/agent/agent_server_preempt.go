@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// runningExecution is one "gleam execute" process started by this agent.
+type runningExecution struct {
+	flowHashCode uint32
+	resource     pb.ComputeResource
+	startTime    time.Time
+	cancel       context.CancelFunc
+	// only executions that can run again elsewhere are preempted
+	isPreemptible bool
+	isPreempted   bool
+}
+
+// addExecution tracks the execution, unless the agent is draining.
+func (as *AgentServer) addExecution(e *runningExecution) error {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+	if as.isDraining {
+		return grpc.Errorf(codes.Unavailable, "agent is draining")
+	}
+	as.executions = append(as.executions, e)
+	return nil
+}
+
+func (as *AgentServer) removeExecution(e *runningExecution) {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+	for i, x := range as.executions {
+		if x == e {
+			as.executions = append(as.executions[:i], as.executions[i+1:]...)
+			return
+		}
+	}
+}
+
+// flowAllocations sums up the allocated resources by flow, to report to the master.
+func (as *AgentServer) flowAllocations() (ret []*pb.Heartbeat_FlowAllocated) {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+
+	byFlow := make(map[uint32]*pb.Heartbeat_FlowAllocated)
+	for _, e := range as.executions {
+		f, found := byFlow[e.flowHashCode]
+		if !found {
+			f = &pb.Heartbeat_FlowAllocated{
+				FlowHashCode: e.flowHashCode,
+				Allocated:    &pb.ComputeResource{},
+			}
+			byFlow[e.flowHashCode] = f
+			ret = append(ret, f)
+		}
+		*f.Allocated = f.Allocated.Plus(e.resource)
+	}
+	return
+}
+
+// Preempt stops the most recently started executions of the flow,
+// until the released resource covers the requested resource.
+func (as *AgentServer) Preempt(ctx context.Context, request *pb.PreemptRequest) (*pb.PreemptResponse, error) {
+	as.executionsLock.Lock()
+	var candidates []*runningExecution
+	for _, e := range as.executions {
+		if e.flowHashCode == request.GetFlowHashCode() && e.isPreemptible && !e.isPreempted {
+			candidates = append(candidates, e)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].startTime.After(candidates[j].startTime)
+	})
+
+	var preempted pb.ComputeResource
+	for _, e := range candidates {
+		if preempted.Covers(*request.GetResource()) {
+			break
+		}
+		e.isPreempted = true
+		e.cancel()
+		preempted = preempted.Plus(e.resource)
+	}
+	as.executionsLock.Unlock()
+
+	log.Printf("preempted %+v of flow %d", preempted, request.GetFlowHashCode())
+
+	return &pb.PreemptResponse{Preempted: &preempted}, nil
+}
+
+func (as *AgentServer) isPreempted(e *runningExecution) bool {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+	return e.isPreempted
+}
+
+// killExecutions stops all executions of the flow.
+func (as *AgentServer) killExecutions(flowHashCode uint32) {
+	as.executionsLock.Lock()
+	defer as.executionsLock.Unlock()
+
+	for _, e := range as.executions {
+		if e.flowHashCode == flowHashCode {
+			e.cancel()
+		}
+	}
+}
@@ -3,23 +3,42 @@ package agent
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"log"
-	"net"
 
+	"github.com/chrislusf/gleam/distributed/store"
 	"github.com/chrislusf/gleam/util"
 )
 
-func (as *AgentServer) handleReadConnection(conn net.Conn, readerName, channelName string) {
-
-	log.Printf("on disk %s waits for %s", readerName, channelName)
-
-	dsStore := as.storageBackend.WaitForNamedDatasetShard(channelName)
+// handleReadConnection sends the dataset shard once it is created. An
+// inspecting read only sends a finished dataset shard, and is not counted
+// as one of its readers.
+func (as *AgentServer) handleReadConnection(conn io.Writer, readerName, channelName string, isInspecting bool) error {
+
+	var dsStore store.DataStore
+	if isInspecting {
+		dsStore = as.storageBackend.InspectNamedDatasetShard(channelName)
+		if dsStore == nil {
+			log.Printf("on disk %s can not inspect %s", readerName, channelName)
+			return fmt.Errorf("%s is not found or not completely written", channelName)
+		}
+	} else {
+		log.Printf("on disk %s waits for %s", readerName, channelName)
+		dsStore = as.storageBackend.WaitForNamedDatasetShard(channelName)
+		if dsStore == nil {
+			log.Printf("on disk %s can not read evicted %s", readerName, channelName)
+			return fmt.Errorf("%s was evicted to free the disk", channelName)
+		}
+	}
 
 	log.Printf("on disk %s starts reading %s", readerName, channelName)
 
 	var offset int64
 	var err error
+	defer func() {
+		as.storageBackend.DoneReadingNamedDatasetShard(channelName, readerName, err == nil && !isInspecting)
+	}()
 
 	var size int32
 	sizeBuf := make([]byte, 4)
@@ -75,11 +94,15 @@ func (as *AgentServer) handleReadConnection(conn net.Conn, readerName, channelNa
 		count += int64(size)
 
 	}
-	messageWriter.Flush()
+	if flushErr := messageWriter.Flush(); err == nil {
+		err = flushErr
+	}
+	as.metrics.shardBytesOut.WithLabelValues("disk").Add(float64(count))
 
 	if err != nil {
 		log.Printf("on disk %s finished reading %s %d bytes error: %v", readerName, channelName, count, err)
 	} else {
 		log.Printf("on disk %s finished reading %s %d bytes", readerName, channelName, count)
 	}
+	return err
 }
@@ -4,12 +4,11 @@ import (
 	"bufio"
 	"io"
 	"log"
-	"net"
 
 	"github.com/chrislusf/gleam/util"
 )
 
-func (as *AgentServer) handleInMemoryReadConnection(conn net.Conn, readerName, channelName string) {
+func (as *AgentServer) handleInMemoryReadConnection(conn io.Writer, readerName, channelName string) (err error) {
 
 	log.Printf("in memory %s waits for %s", readerName, channelName)
 
@@ -17,24 +16,30 @@ func (as *AgentServer) handleInMemoryReadConnection(conn net.Conn, readerName, c
 
 	if ch == nil {
 		log.Printf("in memory %s read an empty %s", readerName, channelName)
-		return
+		return nil
 	}
 
 	writer := bufio.NewWriter(conn)
-	defer writer.Flush()
+	defer func() {
+		if flushErr := writer.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
 
 	log.Printf("in memory %s start reading %s", readerName, channelName)
 	buf := make([]byte, util.BUFFER_SIZE)
 	count, err := io.CopyBuffer(writer, ch.Reader, buf)
+	as.metrics.shardBytesOut.WithLabelValues("memory").Add(float64(count))
 
 	if err == nil {
 		if ch.Error != nil {
 			log.Printf("in memory %s failed because writing to %s failed: %d %v", readerName, channelName, count, ch.Error)
+			return ch.Error
 		} else {
 			log.Printf("in memory %s finished reading %s %d bytes", readerName, channelName, count)
 		}
 	} else {
 		log.Printf("in memory %s failed reading %s %d bytes %v", readerName, channelName, count, err)
 	}
-
+	return err
 }
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+// the pushed messages are appended to the merged file in chunks of about this size
+const shuffleChunkSize = 1024 * 1024
+
+func (as *AgentServer) handleShuffleWriteConnection(reader io.Reader, writerName, channelName string, shuffle *pb.ShufflePartition) error {
+
+	writer, err := as.shuffles.BeginPush(channelName, shuffle)
+	if err != nil {
+		log.Printf("shuffle %s Failed to push %s to %s: %v", writerName, channelName, shuffle.GetName(), err)
+		return err
+	}
+
+	log.Printf("shuffle %s starts pushing %s to %s", writerName, channelName, shuffle.GetName())
+
+	var count int64
+	var chunk bytes.Buffer
+	for {
+		message, err := util.ReadMessage(reader)
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			err = util.WriteMessage(&chunk, message)
+		}
+		if err == nil && chunk.Len() >= shuffleChunkSize {
+			_, err = writer.Write(chunk.Bytes())
+			chunk.Reset()
+		}
+		if err != nil {
+			// the part is not committed, so the reduce tasks wait for the map task to retry
+			log.Printf("shuffle %s Failed to push %s to %s: %v", writerName, channelName, shuffle.GetName(), err)
+			return err
+		}
+		count += int64(len(message))
+	}
+	if chunk.Len() > 0 {
+		if _, err := writer.Write(chunk.Bytes()); err != nil {
+			log.Printf("shuffle %s Failed to push %s to %s: %v", writerName, channelName, shuffle.GetName(), err)
+			return err
+		}
+	}
+	writer.Commit()
+
+	as.metrics.shardBytesIn.WithLabelValues("shuffle").Add(float64(count))
+	log.Printf("shuffle %s finished pushing %s to %s %d bytes", writerName, channelName, shuffle.GetName(), count)
+	return nil
+}
+
+func (as *AgentServer) handleShuffleReadConnection(ctx context.Context, conn io.Writer, readerName, channelName string, shuffle *pb.ShufflePartition) error {
+
+	log.Printf("shuffle %s waits for %s in %s", readerName, channelName, shuffle.GetName())
+
+	file, chunks, release, err := as.shuffles.WaitForPush(ctx, shuffle)
+	if err != nil {
+		log.Printf("shuffle %s stops waiting for %s: %v", readerName, channelName, err)
+		return err
+	}
+	defer release()
+
+	log.Printf("shuffle %s starts reading %s in %s", readerName, channelName, shuffle.GetName())
+
+	var count int64
+	buf := make([]byte, util.BUFFER_SIZE)
+	for _, chunk := range chunks {
+		var n int64
+		n, err = io.CopyBuffer(conn, io.NewSectionReader(file, chunk.offset, chunk.size), buf)
+		count += n
+		if err != nil {
+			break
+		}
+	}
+	as.metrics.shardBytesOut.WithLabelValues("shuffle").Add(float64(count))
+
+	if err != nil {
+		log.Printf("shuffle %s finished reading %s %d bytes error: %v", readerName, channelName, count, err)
+	} else {
+		log.Printf("shuffle %s finished reading %s %d bytes", readerName, channelName, count)
+	}
+	return err
+}
+
+// readerContext is done once the reader closes the connection. The reader
+// sends nothing after the read request, so any read returns only then.
+func readerContext(conn io.Reader) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		conn.Read(make([]byte, 1))
+		cancel()
+	}()
+	return ctx
+}
@@ -7,9 +7,9 @@ import (
 	"github.com/chrislusf/gleam/util"
 )
 
-func (as *AgentServer) handleLocalWriteConnection(reader io.Reader, writerName, channelName string, readerCount int) {
+func (as *AgentServer) handleLocalWriteConnection(reader io.Reader, writerName, channelName string, readerCount int) error {
 
-	dsStore := as.storageBackend.CreateNamedDatasetShard(channelName)
+	dsStore := as.storageBackend.CreateNamedDatasetShard(channelName, readerCount)
 
 	log.Printf("on disk %s starts writing %s expected reader:%d", writerName, channelName, readerCount)
 
@@ -29,13 +29,18 @@ func (as *AgentServer) handleLocalWriteConnection(reader io.Reader, writerName,
 			messageWriter.WriteMessage(message)
 			// println("agent recv:", string(message.Bytes()))
 		} else {
+			// leave the dataset shard incomplete, and report the error back to the writer
 			log.Printf("on disk %s Failed to write to %s: %v", writerName, channelName, err)
+			messageWriter.Flush()
+			return err
 		}
 	}
 
 	messageWriter.Flush()
 	util.WriteEOFMessage(dsStore)
+	as.storageBackend.FinishNamedDatasetShard(channelName)
 
+	as.metrics.shardBytesIn.WithLabelValues("disk").Add(float64(count))
 	log.Printf("on disk %s finished writing %s %d bytes", writerName, channelName, count)
-
+	return nil
 }
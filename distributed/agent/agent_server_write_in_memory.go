@@ -8,7 +8,7 @@ import (
 	"github.com/chrislusf/gleam/util"
 )
 
-func (as *AgentServer) handleLocalInMemoryWriteConnection(r io.Reader, writerName, channelName string, readerCount int) {
+func (as *AgentServer) handleLocalInMemoryWriteConnection(r io.Reader, writerName, channelName string, readerCount int) error {
 
 	ch := as.inMemoryChannels.CreateNamedDatasetShard(channelName, readerCount)
 	defer func() {
@@ -26,10 +26,12 @@ func (as *AgentServer) handleLocalInMemoryWriteConnection(r io.Reader, writerNam
 
 	ch.incomingChannel.Error = err
 	ch.incomingChannel.Counter = count
+	as.metrics.shardBytesIn.WithLabelValues("memory").Add(float64(count))
 
 	if err != nil {
 		log.Printf("in memory %s finished writing %s %d bytes: %v", writerName, channelName, count, err)
 	} else {
 		log.Printf("in memory %s finished writing %s %d bytes", writerName, channelName, count)
 	}
+	return err
 }
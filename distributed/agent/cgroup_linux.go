@@ -0,0 +1,149 @@
+// +build linux
+
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+const (
+	// cpu.max period in microseconds
+	cgroupCpuPeriod = 100000
+	// how long to wait for the killed processes to exit before giving up the cgroup
+	cgroupRemoveTimeout = 10 * time.Second
+	cgroupPollInterval  = 50 * time.Millisecond
+)
+
+// initCgroup prepares the parent cgroup v2 folder for the executors,
+// and enables the memory and cpu controllers for the executor cgroups.
+func initCgroup(parent string) error {
+	root := filepath.Dir(parent)
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err != nil {
+		return fmt.Errorf("%s is not on a cgroup v2 file system: %v", parent, err)
+	}
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	if err := writeCgroupFile(root, "cgroup.subtree_control", "+memory +cpu"); err != nil {
+		return err
+	}
+	return writeCgroupFile(parent, "cgroup.subtree_control", "+memory +cpu")
+}
+
+// executorCgroup limits one executor and all its child processes.
+type executorCgroup struct {
+	dir string
+}
+
+func newExecutorCgroup(parent, name string, resource pb.ComputeResource) (*executorCgroup, error) {
+	c := &executorCgroup{dir: filepath.Join(parent, name)}
+	if err := os.Mkdir(c.dir, 0755); err != nil {
+		return nil, err
+	}
+	if resource.MemoryMb > 0 {
+		if err := writeCgroupFile(c.dir, "memory.max", strconv.FormatInt(resource.MemoryMb*1024*1024, 10)); err != nil {
+			c.remove()
+			return nil, err
+		}
+		// do not let the executor swap instead of being killed
+		writeCgroupFile(c.dir, "memory.swap.max", "0")
+	}
+	if resource.CpuCount > 0 {
+		quota := fmt.Sprintf("%d %d", int64(resource.CpuCount)*cgroupCpuPeriod, cgroupCpuPeriod)
+		if err := writeCgroupFile(c.dir, "cpu.max", quota); err != nil {
+			c.remove()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// addProcess moves the process into the cgroup. The processes started
+// by it later are in the same cgroup.
+func (c *executorCgroup) addProcess(pid int) error {
+	return writeCgroupFile(c.dir, "cgroup.procs", strconv.Itoa(pid))
+}
+
+// oomKilled checks whether any process in the cgroup was killed for running out of memory.
+func (c *executorCgroup) oomKilled() bool {
+	count, _ := readCgroupKey(c.dir, "memory.events", "oom_kill")
+	return count > 0
+}
+
+// isPopulated checks whether any process is still in the cgroup.
+func (c *executorCgroup) isPopulated() bool {
+	populated, found := readCgroupKey(c.dir, "cgroup.events", "populated")
+	return !found || populated != 0
+}
+
+// remove kills the remaining processes, waits for them to exit, and deletes the cgroup.
+// The cgroup can only be deleted after all its processes have exited.
+func (c *executorCgroup) remove() error {
+	deadline := time.Now().Add(cgroupRemoveTimeout)
+	// cgroup.kill is available since linux 5.14
+	hasKill := writeCgroupFile(c.dir, "cgroup.kill", "1") == nil
+	for c.isPopulated() && time.Now().Before(deadline) {
+		if !hasKill {
+			c.killProcesses()
+		}
+		time.Sleep(cgroupPollInterval)
+	}
+	for {
+		err := os.Remove(c.dir)
+		if err == nil || os.IsNotExist(err) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("remove cgroup %s: %v", c.dir, err)
+		}
+		time.Sleep(cgroupPollInterval)
+	}
+}
+
+// killProcesses kills the processes listed in cgroup.procs, for kernels without cgroup.kill.
+func (c *executorCgroup) killProcesses() {
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, "cgroup.procs"))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(line); err == nil {
+			syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+}
+
+// readCgroupKey reads the value of a "key value" line of the cgroup file.
+func readCgroupKey(dir, name, key string) (value int64, found bool) {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			value, err = strconv.ParseInt(fields[1], 10, 64)
+			return value, err == nil
+		}
+	}
+	return 0, false
+}
+
+func writeCgroupFile(dir, name, content string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		return fmt.Errorf("write %s to %s/%s: %v", content, dir, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// +build !linux
+
+package agent
+
+import (
+	"fmt"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+func initCgroup(parent string) error {
+	return fmt.Errorf("cgroup is only supported on linux")
+}
+
+type executorCgroup struct{}
+
+func newExecutorCgroup(parent, name string, resource pb.ComputeResource) (*executorCgroup, error) {
+	return nil, fmt.Errorf("cgroup is only supported on linux")
+}
+
+func (c *executorCgroup) addProcess(pid int) error {
+	return nil
+}
+
+func (c *executorCgroup) oomKilled() bool {
+	return false
+}
+
+func (c *executorCgroup) remove() error {
+	return nil
+}
@@ -0,0 +1,11 @@
+// +build !linux,!darwin,!freebsd
+
+package agent
+
+import (
+	"fmt"
+)
+
+func freeDiskBytes(dir string) (int64, error) {
+	return 0, fmt.Errorf("free disk space is only known on linux, darwin and freebsd")
+}
@@ -0,0 +1,16 @@
+// +build linux darwin freebsd
+
+package agent
+
+import (
+	"syscall"
+)
+
+// freeDiskBytes is the disk space available to the agent in the folder.
+func freeDiskBytes(dir string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return int64(uint64(stat.Bavail) * uint64(stat.Bsize)), nil
+}
@@ -2,6 +2,11 @@ package agent
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,13 +19,38 @@ type LocalDatasetShardsManager struct {
 	port           int
 	name2Store     map[string]store.DataStore
 	name2StoreCond *sync.Cond
+	// the dataset shards completely written
+	finished map[string]bool
+	// the readers of the dataset shards
+	readers map[string]*shardReaders
+	// the dataset shards removed to free the disk
+	evicted map[string]bool
 }
 
+// shardReaders tracks the reads of a dataset shard, so it is evicted only
+// after all the expected readers have read it.
+type shardReaders struct {
+	// the expected number of readers, 0 if unknown
+	expected int
+	// the names of the readers which have read the dataset shard completely
+	done map[string]bool
+	// the number of reads in progress
+	open       int
+	lastDoneAt time.Time
+}
+
+// A reader task may be retried after it has read a dataset shard, for up to
+// the retry time of the task group. The dataset shard is kept for that long.
+const evictAfterReadDelay = 3 * time.Minute
+
 func NewLocalDatasetShardsManager(dir string, port int) *LocalDatasetShardsManager {
 	m := &LocalDatasetShardsManager{
 		dir:        dir,
 		port:       port,
 		name2Store: make(map[string]store.DataStore),
+		finished:   make(map[string]bool),
+		readers:    make(map[string]*shardReaders),
+		evicted:    make(map[string]bool),
 	}
 	m.name2StoreCond = sync.NewCond(m)
 	return m
@@ -36,6 +66,8 @@ func (m *LocalDatasetShardsManager) doDelete(name string) {
 	}
 
 	delete(m.name2Store, name)
+	delete(m.finished, name)
+	delete(m.readers, name)
 
 	ds.Destroy()
 }
@@ -50,10 +82,13 @@ func (m *LocalDatasetShardsManager) DeleteNamedDatasetShard(name string) {
 	// println("locked LocalDatasetShardsManager to delete", name)
 
 	m.doDelete(name)
+	delete(m.evicted, name)
 
 }
 
-func (m *LocalDatasetShardsManager) CreateNamedDatasetShard(name string) store.DataStore {
+// CreateNamedDatasetShard creates the dataset shard to be read by the readers,
+// or by an unknown number of readers if readerCount is 0.
+func (m *LocalDatasetShardsManager) CreateNamedDatasetShard(name string, readerCount int) store.DataStore {
 
 	m.Lock()
 	defer m.Unlock()
@@ -66,6 +101,8 @@ func (m *LocalDatasetShardsManager) CreateNamedDatasetShard(name string) store.D
 	s := store.NewLocalFileDataStore(m.dir, fmt.Sprintf("%s-%d", name, m.port))
 
 	m.name2Store[name] = s
+	m.readers[name] = &shardReaders{expected: readerCount, done: make(map[string]bool)}
+	delete(m.evicted, name)
 	// println(name, "is broadcasting...")
 	m.name2StoreCond.Broadcast()
 
@@ -73,6 +110,9 @@ func (m *LocalDatasetShardsManager) CreateNamedDatasetShard(name string) store.D
 
 }
 
+// WaitForNamedDatasetShard waits for the dataset shard to be created, and
+// starts a read, which is kept from eviction until DoneReadingNamedDatasetShard.
+// It returns nil if the dataset shard is evicted.
 func (m *LocalDatasetShardsManager) WaitForNamedDatasetShard(name string) store.DataStore {
 
 	m.Lock()
@@ -80,14 +120,116 @@ func (m *LocalDatasetShardsManager) WaitForNamedDatasetShard(name string) store.
 
 	for {
 		if ds, ok := m.name2Store[name]; ok {
+			m.readers[name].open++
 			return ds
 		}
+		if m.evicted[name] {
+			return nil
+		}
 		// println(name, "is waiting to read...")
 		m.name2StoreCond.Wait()
 	}
 
 }
 
+// InspectNamedDatasetShard starts a read of the completely written dataset
+// shard without waiting for it, as when inspecting a dataset. It returns nil
+// if the dataset shard is not finished, or already removed.
+func (m *LocalDatasetShardsManager) InspectNamedDatasetShard(name string) store.DataStore {
+	m.Lock()
+	defer m.Unlock()
+
+	if !m.finished[name] {
+		return nil
+	}
+	m.readers[name].open++
+	return m.name2Store[name]
+}
+
+// DoneReadingNamedDatasetShard ends a read started by WaitForNamedDatasetShard
+// or InspectNamedDatasetShard. A complete read counts the reader as done.
+func (m *LocalDatasetShardsManager) DoneReadingNamedDatasetShard(name, readerName string, isComplete bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	r, ok := m.readers[name]
+	if !ok {
+		return
+	}
+	r.open--
+	if isComplete {
+		r.done[readerName] = true
+		r.lastDoneAt = time.Now()
+	}
+}
+
+// FinishNamedDatasetShard marks the dataset shard as completely written.
+func (m *LocalDatasetShardsManager) FinishNamedDatasetShard(name string) {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.name2Store[name]; ok {
+		m.finished[name] = true
+	}
+}
+
+// evictLeastRecentlyRead removes the finished dataset shards, least recently
+// read first, until the bytes are freed. Only the dataset shards read by all
+// their expected readers, and not being read, are evicted.
+func (m *LocalDatasetShardsManager) evictLeastRecentlyRead(bytes int64) (freed int64) {
+	m.Lock()
+	defer m.Unlock()
+
+	var names []string
+	for name := range m.finished {
+		if m.readers[name].isEvictable() {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return m.readers[names[i]].lastDoneAt.Before(m.readers[names[j]].lastDoneAt)
+	})
+
+	for _, name := range names {
+		if freed >= bytes {
+			break
+		}
+		if fi, err := os.Stat(m.datasetShardPath(name)); err == nil {
+			freed += fi.Size()
+		}
+		log.Printf("evicting dataset %s last read: %v", name, m.readers[name].lastDoneAt)
+		m.doDelete(name)
+		m.evicted[name] = true
+	}
+	m.name2StoreCond.Broadcast()
+	return freed
+}
+
+func (r *shardReaders) isEvictable() bool {
+	return r.expected > 0 && len(r.done) >= r.expected && r.open == 0 &&
+		time.Since(r.lastDoneAt) > evictAfterReadDelay
+}
+
+func (m *LocalDatasetShardsManager) datasetShardPath(name string) string {
+	return filepath.Join(m.dir, fmt.Sprintf("%s-%d.dat", name, m.port))
+}
+
+// flowDatasetShards returns the dataset shards of the flows, by name.
+func (m *LocalDatasetShardsManager) flowDatasetShards(flowHashCodes []uint32) map[string]store.DataStore {
+	m.Lock()
+	defer m.Unlock()
+
+	ret := make(map[string]store.DataStore)
+	for name, ds := range m.name2Store {
+		for _, flowHashCode := range flowHashCodes {
+			if strings.HasPrefix(name, fmt.Sprintf("f%d-", flowHashCode)) {
+				ret[name] = ds
+			}
+		}
+	}
+	return ret
+}
+
 // purge executor status older than 24 hours to save memory
 func (m *LocalDatasetShardsManager) purgeExpiredEntries() {
 	for {
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEvictOnlyShardsReadByAllReaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	m := NewLocalDatasetShardsManager(dir, 1)
+
+	ds := m.CreateNamedDatasetShard("f1-d1-s0", 2)
+	ds.Write([]byte("data"))
+	m.FinishNamedDatasetShard("f1-d1-s0")
+	unknown := m.CreateNamedDatasetShard("f1-d1-s1", 0)
+	unknown.Write([]byte("data"))
+	m.FinishNamedDatasetShard("f1-d1-s1")
+
+	isEvicted := func(name string) bool {
+		m.evictLeastRecentlyRead(1 << 30)
+		_, found := m.name2Store[name]
+		return !found
+	}
+	read := func(readerName string, isComplete bool) {
+		m.WaitForNamedDatasetShard("f1-d1-s0")
+		m.DoneReadingNamedDatasetShard("f1-d1-s0", readerName, isComplete)
+		// as if read before the retries of the reader are over
+		m.readers["f1-d1-s0"].lastDoneAt = time.Now().Add(-evictAfterReadDelay - time.Second)
+	}
+
+	read("reader1", true)
+	read("reader1", true)
+	read("reader2", false)
+	if isEvicted("f1-d1-s0") {
+		t.Errorf("evicted before all readers have read it")
+	}
+
+	read("reader2", true)
+	m.WaitForNamedDatasetShard("f1-d1-s0")
+	if isEvicted("f1-d1-s0") {
+		t.Errorf("evicted while being read")
+	}
+
+	m.DoneReadingNamedDatasetShard("f1-d1-s0", "inspect", false)
+	if !isEvicted("f1-d1-s0") {
+		t.Errorf("not evicted after all readers have read it")
+	}
+	if isEvicted("f1-d1-s1") {
+		t.Errorf("evicted with unknown readers")
+	}
+	if ds := m.WaitForNamedDatasetShard("f1-d1-s0"); ds != nil {
+		t.Errorf("read an evicted dataset shard")
+	}
+}
+
+func TestInspectOnlyFinishedShards(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	m := NewLocalDatasetShardsManager(dir, 1)
+
+	if ds := m.InspectNamedDatasetShard("f1-d1-s0"); ds != nil {
+		t.Errorf("inspected a dataset shard not created")
+	}
+	ds := m.CreateNamedDatasetShard("f1-d1-s0", 1)
+	ds.Write([]byte("data"))
+	if ds := m.InspectNamedDatasetShard("f1-d1-s0"); ds != nil {
+		t.Errorf("inspected a dataset shard being written")
+	}
+	m.FinishNamedDatasetShard("f1-d1-s0")
+
+	if ds := m.InspectNamedDatasetShard("f1-d1-s0"); ds == nil {
+		t.Fatalf("can not inspect a finished dataset shard")
+	}
+	m.DoneReadingNamedDatasetShard("f1-d1-s0", "inspect", false)
+	if r := m.readers["f1-d1-s0"]; r.open != 0 || len(r.done) != 0 || !r.lastDoneAt.IsZero() {
+		t.Errorf("inspecting counts as a reader: %+v", r)
+	}
+}
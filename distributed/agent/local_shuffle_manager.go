@@ -0,0 +1,364 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+// LocalShuffleManager merges the partitions pushed by the map tasks. The parts
+// of a reduce partition from all map tasks are appended, in chunks, to one
+// file. A reduce task reads the chunks of the map tasks in file order, after
+// the map tasks have pushed all of them, so the map tasks never wait for it.
+// The chunks of a dropped part, as from a failed attempt of a map task,
+// are reused by the later pushes.
+type LocalShuffleManager struct {
+	sync.Mutex
+	dir        string
+	port       int
+	partitions map[string]*shufflePartition
+	// the dataset shard pushed as a part of a reduce partition
+	shards map[string]*pb.ShufflePartition
+	// the flows cleaned up, to fail their late pushes and reads
+	cleanedFlows map[string]time.Time
+	cond         *sync.Cond
+}
+
+type shufflePartition struct {
+	file    *os.File
+	size    int64
+	mappers map[int32]*shuffleMapper
+	// the chunks of the dropped parts, in file order
+	free []shuffleChunk
+	// the chunks dropped while being read, freed after the reads
+	dropped      []shuffleChunk
+	readers      int
+	lastAccessAt time.Time
+}
+
+type shuffleMapper struct {
+	chunks    []shuffleChunk
+	committed bool
+}
+
+type shuffleChunk struct {
+	offset int64
+	size   int64
+}
+
+func NewLocalShuffleManager(dir string, port int) *LocalShuffleManager {
+	m := &LocalShuffleManager{
+		dir:          dir,
+		port:         port,
+		partitions:   make(map[string]*shufflePartition),
+		shards:       make(map[string]*pb.ShufflePartition),
+		cleanedFlows: make(map[string]time.Time),
+	}
+	m.cond = sync.NewCond(m)
+	return m
+}
+
+// shuffleWriter appends the chunks of one push of a map task.
+type shuffleWriter struct {
+	m         *LocalShuffleManager
+	name      string
+	partition *shufflePartition
+	mapperId  int32
+	mapper    *shuffleMapper
+}
+
+// BeginPush starts to push the dataset shard into its reduce partition,
+// dropping what an earlier attempt of the map task has pushed.
+func (m *LocalShuffleManager) BeginPush(shardName string, shuffle *pb.ShufflePartition) (*shuffleWriter, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.isCleaned(shuffle.GetName()) {
+		return nil, fmt.Errorf("the flow of %s is cleaned up", shuffle.GetName())
+	}
+
+	p, found := m.partitions[shuffle.GetName()]
+	if !found {
+		file, err := os.OpenFile(m.partitionPath(shuffle.GetName()), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, err
+		}
+		p = &shufflePartition{file: file, mappers: make(map[int32]*shuffleMapper)}
+		m.partitions[shuffle.GetName()] = p
+	}
+	p.lastAccessAt = time.Now()
+
+	if earlier, found := p.mappers[shuffle.GetMapper()]; found {
+		m.drop(p, earlier.chunks)
+	}
+	mapper := &shuffleMapper{}
+	p.mappers[shuffle.GetMapper()] = mapper
+	m.shards[shardName] = shuffle
+	m.cond.Broadcast()
+	return &shuffleWriter{m: m, name: shuffle.GetName(), partition: p, mapperId: shuffle.GetMapper(), mapper: mapper}, nil
+}
+
+func (m *LocalShuffleManager) partitionPath(name string) string {
+	return filepath.Join(m.dir, fmt.Sprintf("%s-%d.dat", name, m.port))
+}
+
+// Write appends a chunk of complete messages.
+func (w *shuffleWriter) Write(chunk []byte) (int, error) {
+	w.m.Lock()
+	if err := w.checkCurrent(); err != nil {
+		w.m.Unlock()
+		return 0, err
+	}
+	c := w.partition.allocate(int64(len(chunk)))
+	w.m.Unlock()
+
+	n, err := w.partition.file.WriteAt(chunk, c.offset)
+
+	w.m.Lock()
+	defer w.m.Unlock()
+	if err == nil {
+		err = w.checkCurrent()
+	}
+	if err != nil {
+		w.m.drop(w.partition, []shuffleChunk{c})
+		return n, err
+	}
+	// the chunk is only visible to the readers once written
+	w.mapper.chunks = append(w.mapper.chunks, c)
+	w.partition.lastAccessAt = time.Now()
+	return n, nil
+}
+
+// checkCurrent fails the push replaced by a later attempt, or dropped.
+func (w *shuffleWriter) checkCurrent() error {
+	if w.m.partitions[w.name] != w.partition || w.partition.mappers[w.mapperId] != w.mapper {
+		return fmt.Errorf("the push into %s is dropped", w.name)
+	}
+	return nil
+}
+
+// Commit lets the reduce tasks read the pushed chunks.
+func (w *shuffleWriter) Commit() {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.mapper.committed = true
+	w.m.cond.Broadcast()
+}
+
+// WaitForPush waits until the map tasks have pushed all of their parts, and
+// returns the chunks of the parts in file order. The parts of all the map
+// tasks in shuffle.Mappers are read at once, or else the part of shuffle.Mapper.
+// The release function must be called after reading the chunks. With a done
+// context, it only returns the parts already pushed, without waiting.
+func (m *LocalShuffleManager) WaitForPush(ctx context.Context, shuffle *pb.ShufflePartition) (file *os.File, chunks []shuffleChunk, release func(), err error) {
+	mapperIds := shuffle.GetMappers()
+	if len(mapperIds) == 0 {
+		mapperIds = []int32{shuffle.GetMapper()}
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.Lock()
+			m.cond.Broadcast()
+			m.Unlock()
+		case <-stop:
+		}
+	}()
+
+	m.Lock()
+	defer m.Unlock()
+
+	for {
+		if m.isCleaned(shuffle.GetName()) {
+			return nil, nil, nil, fmt.Errorf("the flow of %s is cleaned up", shuffle.GetName())
+		}
+		if p, found := m.partitions[shuffle.GetName()]; found {
+			if chunks, found = p.committedChunks(mapperIds); found {
+				p.readers++
+				p.lastAccessAt = time.Now()
+				return p.file, chunks, func() { m.endRead(p) }, nil
+			}
+		}
+		if err = ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		m.cond.Wait()
+	}
+}
+
+func (p *shufflePartition) committedChunks(mapperIds []int32) (chunks []shuffleChunk, found bool) {
+	for _, id := range mapperIds {
+		mapper, found := p.mappers[id]
+		if !found || !mapper.committed {
+			return nil, false
+		}
+		chunks = append(chunks, mapper.chunks...)
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].offset < chunks[j].offset
+	})
+	return chunks, true
+}
+
+func (m *LocalShuffleManager) endRead(p *shufflePartition) {
+	m.Lock()
+	defer m.Unlock()
+	p.readers--
+	if p.readers == 0 && len(p.dropped) > 0 {
+		dropped := p.dropped
+		p.dropped = nil
+		p.release(dropped)
+	}
+}
+
+// drop frees the chunks, once they are not being read.
+func (m *LocalShuffleManager) drop(p *shufflePartition, chunks []shuffleChunk) {
+	if p.readers > 0 {
+		p.dropped = append(p.dropped, chunks...)
+		return
+	}
+	p.release(chunks)
+}
+
+// allocate takes the first free chunk large enough, or else appends to the file.
+func (p *shufflePartition) allocate(size int64) shuffleChunk {
+	for i, c := range p.free {
+		if c.size < size {
+			continue
+		}
+		if c.size == size {
+			p.free = append(p.free[:i], p.free[i+1:]...)
+		} else {
+			p.free[i] = shuffleChunk{c.offset + size, c.size - size}
+		}
+		return shuffleChunk{c.offset, size}
+	}
+	c := shuffleChunk{p.size, size}
+	p.size += size
+	return c
+}
+
+// release returns the chunks to the free list, merging the adjacent ones,
+// and shrinks the file if the free space is at its end.
+func (p *shufflePartition) release(chunks []shuffleChunk) {
+	free := append(p.free, chunks...)
+	sort.Slice(free, func(i, j int) bool {
+		return free[i].offset < free[j].offset
+	})
+	p.free = free[:0]
+	for _, c := range free {
+		if n := len(p.free); n > 0 && p.free[n-1].offset+p.free[n-1].size == c.offset {
+			p.free[n-1].size += c.size
+			continue
+		}
+		p.free = append(p.free, c)
+	}
+	if n := len(p.free); n > 0 && p.free[n-1].offset+p.free[n-1].size == p.size {
+		p.size = p.free[n-1].offset
+		p.free = p.free[:n-1]
+		p.file.Truncate(p.size)
+	}
+}
+
+// DeleteNamedDatasetShard drops the part pushed as the dataset shard, and
+// the merged file once all parts are dropped.
+func (m *LocalShuffleManager) DeleteNamedDatasetShard(name string) {
+	m.Lock()
+	defer m.Unlock()
+
+	shuffle, found := m.shards[name]
+	if !found {
+		return
+	}
+	delete(m.shards, name)
+
+	p, found := m.partitions[shuffle.GetName()]
+	if !found {
+		return
+	}
+	if mapper, found := p.mappers[shuffle.GetMapper()]; found {
+		delete(p.mappers, shuffle.GetMapper())
+		m.drop(p, mapper.chunks)
+	}
+	if len(p.mappers) == 0 {
+		m.doDelete(shuffle.GetName())
+	}
+	m.cond.Broadcast()
+}
+
+// Cleanup removes the merged files of the flow.
+func (m *LocalShuffleManager) Cleanup(flowHashCode uint32) {
+	m.Lock()
+	defer m.Unlock()
+
+	prefix := fmt.Sprintf("f%d-", flowHashCode)
+	m.cleanedFlows[prefix] = time.Now()
+	for name := range m.shards {
+		if strings.HasPrefix(name, prefix) {
+			delete(m.shards, name)
+		}
+	}
+	for name := range m.partitions {
+		if strings.HasPrefix(name, prefix) {
+			m.doDelete(name)
+		}
+	}
+	m.cond.Broadcast()
+}
+
+func (m *LocalShuffleManager) isCleaned(name string) bool {
+	for prefix := range m.cleanedFlows {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *LocalShuffleManager) doDelete(name string) {
+	p, found := m.partitions[name]
+	if !found {
+		return
+	}
+	delete(m.partitions, name)
+	p.file.Close()
+	os.Remove(m.partitionPath(name))
+	m.cond.Broadcast()
+}
+
+// purge merged files not accessed for 24 hours
+func (m *LocalShuffleManager) purgeExpiredEntries() {
+	for {
+		m.Lock()
+		cutoverLimit := time.Now().Add(-24 * time.Hour)
+		for name, p := range m.partitions {
+			if p.readers == 0 && p.lastAccessAt.Before(cutoverLimit) {
+				println("purging shuffle partition", name, "last access:", p.lastAccessAt.String())
+				m.doDelete(name)
+			}
+		}
+		for name, shuffle := range m.shards {
+			if _, found := m.partitions[shuffle.GetName()]; !found {
+				delete(m.shards, name)
+			}
+		}
+		for prefix, cleanedAt := range m.cleanedFlows {
+			if cleanedAt.Before(cutoverLimit) {
+				delete(m.cleanedFlows, prefix)
+			}
+		}
+		m.cond.Broadcast()
+		m.Unlock()
+		time.Sleep(1 * time.Hour)
+	}
+}
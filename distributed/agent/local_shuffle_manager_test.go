@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+func newTestShuffleManager(t *testing.T) (*LocalShuffleManager, func()) {
+	dir, err := ioutil.TempDir("", "shuffle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewLocalShuffleManager(dir, 1), func() { os.RemoveAll(dir) }
+}
+
+func push(t *testing.T, m *LocalShuffleManager, shardName string, shuffle *pb.ShufflePartition, chunks ...string) {
+	w, err := m.BeginPush(shardName, shuffle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range chunks {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Commit()
+}
+
+func readPushed(t *testing.T, m *LocalShuffleManager, shuffle *pb.ShufflePartition) string {
+	file, chunks, release, err := m.WaitForPush(context.Background(), shuffle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	var data []byte
+	for _, c := range chunks {
+		buf := make([]byte, c.size)
+		if _, err := file.ReadAt(buf, c.offset); err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, buf...)
+	}
+	return string(data)
+}
+
+func TestShuffleMergedRead(t *testing.T) {
+	m, cleanup := newTestShuffleManager(t)
+	defer cleanup()
+
+	push(t, m, "f1-d1-s0", &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 0}, "a1", "a2")
+	push(t, m, "f1-d1-s1", &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 1}, "b1")
+
+	if data := readPushed(t, m, &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 1}); data != "b1" {
+		t.Errorf("read %q from one map task", data)
+	}
+	if data := readPushed(t, m, &pb.ShufflePartition{Name: "f1-d1-p0", Mappers: []int32{0, 1}}); data != "a1a2b1" {
+		t.Errorf("read %q from all map tasks", data)
+	}
+
+	// with a done context, only the parts already pushed are read
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, release, err := m.WaitForPush(done, &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 0}); err != nil {
+		t.Errorf("can not read a pushed part without waiting: %v", err)
+	} else {
+		release()
+	}
+	if _, _, _, err := m.WaitForPush(done, &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 2}); err != context.Canceled {
+		t.Errorf("expecting an error reading a part not pushed without waiting, got %v", err)
+	}
+}
+
+func TestShuffleReusesDroppedChunks(t *testing.T) {
+	m, cleanup := newTestShuffleManager(t)
+	defer cleanup()
+
+	shuffle := &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 0}
+	push(t, m, "f1-d1-s0", shuffle, "aaaa", "bbbb")
+	push(t, m, "f1-d1-s1", &pb.ShufflePartition{Name: "f1-d1-p0", Mapper: 1}, "cc")
+	// the retried map task replaces its earlier part
+	push(t, m, "f1-d1-s0", shuffle, "dddddddd")
+
+	p := m.partitions["f1-d1-p0"]
+	if p.size != 10 {
+		t.Errorf("merged file of %d bytes, expecting the retry to reuse the dropped chunks", p.size)
+	}
+	if data := readPushed(t, m, &pb.ShufflePartition{Name: "f1-d1-p0", Mappers: []int32{0, 1}}); data != "ddddddddcc" {
+		t.Errorf("read %q", data)
+	}
+
+	// the dropped chunks at the end shrink the file
+	m.DeleteNamedDatasetShard("f1-d1-s1")
+	if p.size != 8 {
+		t.Errorf("merged file of %d bytes after dropping the last part", p.size)
+	}
+}
+
+func TestShuffleWaitStops(t *testing.T) {
+	m, cleanup := newTestShuffleManager(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, _, err := m.WaitForPush(ctx, &pb.ShufflePartition{Name: "f1-d1-p0"}); err != context.DeadlineExceeded {
+		t.Errorf("expecting the wait to stop with the context, got %v", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, _, _, err := m.WaitForPush(context.Background(), &pb.ShufflePartition{Name: "f1-d1-p0"})
+		errChan <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	m.Cleanup(1)
+	select {
+	case err := <-errChan:
+		if err == nil {
+			t.Errorf("expecting an error after the flow is cleaned up")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("the wait does not stop after the flow is cleaned up")
+	}
+
+	if _, err := m.BeginPush("f1-d1-s0", &pb.ShufflePartition{Name: "f1-d1-p0"}); err == nil {
+		t.Errorf("expecting an error pushing after the flow is cleaned up")
+	}
+}
@@ -0,0 +1,64 @@
+package distributed
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/flow"
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Capacity hints the flow with the number of tasks the agents of the data center
+// can run at the same time, for Auto shard counts to scale to the cluster.
+// The capacity is left to the default if the masters can not be reached.
+//
+//	f := flow.New("wordcount")
+//	f.Hint(option.Capacity())
+func (o *DistributedOption) Capacity() flow.FlowHintOption {
+	if err := security.EnableTLS(o.TLSOption); err != nil {
+		log.Fatalf("Failed to enable TLS: %v", err)
+	}
+	var response *pb.CapacityResponse
+	var err error
+	for _, master := range strings.Split(o.Master, ",") {
+		master = strings.TrimSpace(master)
+		if response, err = o.getCapacity(master); err == nil {
+			break
+		}
+		log.Printf("%s Failed to get the capacity: %v", master, err)
+	}
+	if err != nil {
+		return func(c *flow.FlowConfig) {}
+	}
+
+	resource := response.GetResource()
+	capacity := int(resource.GetCpuCount())
+	if o.TaskMemoryMB > 0 {
+		if byMemory := int(resource.GetMemoryMb()) / o.TaskMemoryMB; byMemory < capacity {
+			capacity = byMemory
+		}
+	}
+	if capacity < 1 {
+		capacity = 1
+	}
+	return flow.Capacity(capacity)
+}
+
+func (o *DistributedOption) getCapacity(master string) (*pb.CapacityResponse, error) {
+	grpcConection, err := grpc.Dial(master, security.GrpcDialOptions(o.Credentials)...)
+	if err != nil {
+		return nil, fmt.Errorf("fail to dial %s: %v", master, err)
+	}
+	defer grpcConection.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := pb.NewGleamMasterClient(grpcConection)
+	return client.GetCapacity(ctx, &pb.CapacityRequest{DataCenter: o.DataCenter})
+}
@@ -3,18 +3,25 @@ package driver
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/chrislusf/gleam/distributed/driver/scheduler"
 	"github.com/chrislusf/gleam/distributed/plan"
 	"github.com/chrislusf/gleam/distributed/resource"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/flow"
+	"github.com/chrislusf/gleam/instruction"
 	"github.com/chrislusf/gleam/pb"
 	"github.com/chrislusf/gleam/util/on_interrupt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 type Option struct {
@@ -26,6 +33,13 @@ type Option struct {
 	FlowBid       float64
 	Module        string
 	IsProfiling   bool
+	TLSOption     *security.TLSOption
+	Credentials   *security.Credentials
+	Queue         string
+	Compression   string
+	PushShuffle   bool
+	// adapt to the actual partition sizes, aiming at partitions of this size
+	AdaptivePartitionMB int
 }
 
 type FlowDriver struct {
@@ -47,6 +61,17 @@ func NewFlowDriver(option *Option) *FlowDriver {
 // driver runs on local, controlling all tasks
 func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow) {
 
+	if err := security.EnableTLS(fcd.Option.TLSOption); err != nil {
+		log.Fatalf("Failed to enable TLS: %v", err)
+	}
+	// the executors get the credentials from the agents
+	security.SetAgentCredentials(fcd.Option.Credentials)
+
+	if fcd.Option.AdaptivePartitionMB > 0 {
+		// partitions are sized after they are all written
+		writePartitionsOnDisk(fc)
+	}
+
 	// task fusion to minimize disk IO
 	fcd.stepGroups, fcd.taskGroups = plan.GroupTasks(fc)
 	fcd.logExecutionPlan(fc)
@@ -55,14 +80,22 @@ func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow)
 	sched := scheduler.New(
 		fcd.Option.Master,
 		&scheduler.Option{
-			DataCenter:   fcd.Option.DataCenter,
-			Rack:         fcd.Option.Rack,
-			TaskMemoryMB: fcd.Option.TaskMemoryMB,
-			Module:       fcd.Option.Module,
-			FlowHashcode: fc.HashCode,
-			IsProfiling:  fcd.Option.IsProfiling,
+			DataCenter:          fcd.Option.DataCenter,
+			Rack:                fcd.Option.Rack,
+			TaskMemoryMB:        fcd.Option.TaskMemoryMB,
+			Module:              fcd.Option.Module,
+			FlowHashcode:        fc.HashCode,
+			IsProfiling:         fcd.Option.IsProfiling,
+			Credentials:         fcd.Option.Credentials,
+			Queue:               fcd.Option.Queue,
+			Compression:         fcd.Option.Compression,
+			PushShuffle:         fcd.Option.PushShuffle,
+			AdaptivePartitionMB: fcd.Option.AdaptivePartitionMB,
 		},
 	)
+	if fcd.Option.AdaptivePartitionMB > 0 {
+		sched.EnableAdaptive(fcd.stepGroups)
+	}
 
 	// best effort to clean data on agent disk
 	// this may need more improvements
@@ -77,30 +110,59 @@ func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow)
 		fcd.cleanup(sched, fc)
 	}, nil)
 
+	// ship the broadcast values together with other related files
+	relatedFiles := fcd.Option.RequiredFiles
+	if len(fc.BroadcastValues) > 0 {
+		dir, err := ioutil.TempDir("", "gleam")
+		if err != nil {
+			log.Fatalf("Failed to create folder for broadcast values: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		files, err := fc.SaveBroadcastValues(dir)
+		if err != nil {
+			log.Fatalf("Failed to save broadcast values: %v", err)
+		}
+		for _, file := range files {
+			relatedFiles = append(relatedFiles, resource.FileResource{FullPath: file, TargetFolder: fcd.Option.Module})
+		}
+		// each task group may append more files
+		relatedFiles = relatedFiles[:len(relatedFiles):len(relatedFiles)]
+	}
+
 	// schedule to run the steps
 	var wg, reportWg sync.WaitGroup
 	for _, taskGroup := range fcd.taskGroups {
 		wg.Add(1)
 		go func(taskGroup *plan.TaskGroup) {
 			sched.ExecuteTaskGroup(ctx, fc, fcd.GetTaskGroupStatus(taskGroup), &wg, taskGroup,
-				fcd.Option.FlowBid/float64(len(fcd.taskGroups)), fcd.Option.RequiredFiles)
+				fcd.Option.FlowBid/float64(len(fcd.taskGroups)), relatedFiles)
 		}(taskGroup)
 	}
 	go sched.Market.FetcherLoop()
 
 	stopChan := make(chan bool)
 	reportWg.Add(1)
-	go fcd.reportStatus(ctx, &reportWg, fcd.Option.Master, stopChan)
-
-	log.Printf("Start Job Status URL http://%s/job/%d", fcd.Option.Master, fcd.status.GetId())
+	go fcd.reportStatus(ctx, cancel, &reportWg, fcd.Option.Master, stopChan)
 
 	wg.Wait()
 
+	fcd.collectTaskStats()
+
 	stopChan <- true
 	reportWg.Wait()
 
 }
 
+// writePartitionsOnDisk lets the partitioning steps write to disk, so they complete
+// without waiting for the partitions to be read.
+func writePartitionsOnDisk(fc *flow.Flow) {
+	for _, step := range fc.Steps {
+		if _, ok := step.Instruction.(*instruction.ScatterPartitions); ok && !step.IsOnDriverSide {
+			step.OutputDataset.Meta.OnDisk = flow.ModeOnDisk
+		}
+	}
+}
+
 func (fcd *FlowDriver) cleanup(sched *scheduler.Scheduler, fc *flow.Flow) {
 	var wg sync.WaitGroup
 
@@ -146,11 +208,55 @@ func (fcd *FlowDriver) cleanup(sched *scheduler.Scheduler, fc *flow.Flow) {
 	wg.Wait()
 }
 
-func (fcd *FlowDriver) reportStatus(ctx context.Context, wg *sync.WaitGroup, master string, stopChan chan bool) {
-	grpcConection, err := grpc.Dial(master, grpc.WithInsecure())
+// reportStatus sends the flow status to the masters in turn, so a standby
+// master receives the status after the active master fails.
+// The flow is cancelled if the master rejects the status as cancelled.
+func (fcd *FlowDriver) reportStatus(ctx context.Context, cancel context.CancelFunc, wg *sync.WaitGroup, master string, stopChan chan bool) {
+	defer wg.Done()
+
+	masters := strings.Split(master, ",")
+	stopped, failuresAfterStop := false, 0
+	for i := 0; ; i++ {
+		m := strings.TrimSpace(masters[i%len(masters)])
+		streamCtx := ctx
+		if ctx.Err() != nil {
+			// still report the final status of a cancelled flow
+			streamCtx = context.Background()
+		}
+		err := fcd.reportStatusTo(streamCtx, m, stopChan, &stopped)
+		if err == nil {
+			return
+		}
+		if grpc.Code(err) == codes.Aborted && !stopped {
+			log.Printf("Job %d is cancelled by master %s", fcd.status.GetId(), m)
+			cancel()
+			<-stopChan
+			stopped = true
+			fcd.status.Driver.StopTime = time.Now().UnixNano()
+			i--
+			continue
+		}
+		log.Printf("Failed to update Job Status http://%s/job/%d : %v", m, fcd.status.GetId(), err)
+		if stopped {
+			if failuresAfterStop++; failuresAfterStop >= len(masters) {
+				return
+			}
+			continue
+		}
+		select {
+		case <-stopChan:
+			stopped = true
+			fcd.status.Driver.StopTime = time.Now().UnixNano()
+		case <-time.After(time.Second):
+		}
+	}
+
+}
+
+func (fcd *FlowDriver) reportStatusTo(ctx context.Context, master string, stopChan chan bool, stopped *bool) error {
+	grpcConection, err := grpc.Dial(master, security.GrpcDialOptions(fcd.Option.Credentials)...)
 	if err != nil {
-		log.Printf("Failed to dial: %v", err)
-		return
+		return fmt.Errorf("Failed to dial: %v", err)
 	}
 	defer func() {
 		// println("grpc closing....")
@@ -158,31 +264,50 @@ func (fcd *FlowDriver) reportStatus(ctx context.Context, wg *sync.WaitGroup, mas
 		if err := grpcConection.Close(); err != nil {
 			log.Printf("grpcConection.close error: %v", err)
 		}
-
-		wg.Done()
 	}()
 	client := pb.NewGleamMasterClient(grpcConection)
 
 	stream, err := client.SendFlowExecutionStatus(ctx)
 	if err != nil {
-		log.Printf("Failed to create stream on SendFlowExecutionStatus: %v", err)
-		return
+		return fmt.Errorf("Failed to create stream on SendFlowExecutionStatus: %v", err)
+	}
+
+	send := func() error {
+		err := stream.Send(fcd.status)
+		if err == io.EOF {
+			// the master has closed the stream, get the reason
+			err = stream.RecvMsg(&pb.Empty{})
+		}
+		return err
+	}
+
+	if !*stopped {
+		if err = send(); err != nil {
+			return err
+		}
+		// the master accepting the status is the active one
+		log.Printf("Start Job Status URL http://%s/job/%d", master, fcd.status.GetId())
 	}
 
 	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
 	for {
+		if *stopped {
+			if err = send(); err != nil {
+				return err
+			}
+			log.Printf("Saved Job Status URL http://%s/job/%d", master, fcd.status.GetId())
+			stream.CloseSend()
+			return nil
+		}
 		select {
 		case <-stopChan:
+			*stopped = true
 			fcd.status.Driver.StopTime = time.Now().UnixNano()
-			if err = stream.Send(fcd.status); err == nil {
-				log.Printf("Saved Job Status URL http://%s/job/%d", fcd.Option.Master, fcd.status.GetId())
-			} else {
-				log.Printf("Failed to update Job Status http://%s/job/%d : %v", fcd.Option.Master, fcd.status.GetId(), err)
-			}
-			stream.CloseSend()
-			return
 		case <-ticker.C:
-			stream.Send(fcd.status)
+			if err = send(); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -23,6 +23,25 @@ func (fcd *FlowDriver) GetTaskGroupStatus(taskGroup *plan.TaskGroup) *pb.FlowExe
 	return nil
 }
 
+// collectTaskStats copies the stats reported by the executors back to the flow tasks,
+// so the counters are still readable after the flow finishes.
+func (fcd *FlowDriver) collectTaskStats() {
+	for _, taskGroup := range fcd.taskGroups {
+		executions := fcd.GetTaskGroupStatus(taskGroup).GetExecutions()
+		if len(executions) == 0 {
+			continue
+		}
+		stats := executions[len(executions)-1].GetExecutionStat().GetStats()
+		for _, task := range taskGroup.Tasks {
+			for _, stat := range stats {
+				if stat.StepId == int32(task.Step.Id) {
+					task.Stat = stat
+				}
+			}
+		}
+	}
+}
+
 func (fcd *FlowDriver) logExecutionPlan(fc *flow.Flow) {
 
 	for _, step := range fc.Steps {
@@ -9,11 +9,16 @@ import (
 	"time"
 
 	"github.com/chrislusf/gleam/distributed/resource"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
+// maxErrorTail limits the executor stderr kept in the job status.
+const maxErrorTail = 4 * 1024
+
 func sendRelatedFile(ctx context.Context, client pb.GleamAgentClient, flowHashCode uint32, relatedFile resource.FileResource) error {
 	fh, err := resource.GenerateFileHash(relatedFile.FullPath)
 	if err != nil {
@@ -91,6 +96,39 @@ func sendRelatedFile(ctx context.Context, client pb.GleamAgentClient, flowHashCo
 
 }
 
+// OutOfMemoryError means the agent killed the executor
+// for using more memory than allocated.
+type OutOfMemoryError struct {
+	Server   string
+	Name     string
+	MemoryMb int64
+}
+
+func (e *OutOfMemoryError) Error() string {
+	return fmt.Sprintf("out of memory: %s on %s exceeded %d MB", e.Name, e.Server, e.MemoryMb)
+}
+
+// Retryable is false, since running again with the same memory fails the same way.
+func (e *OutOfMemoryError) Retryable() bool {
+	return false
+}
+
+// PreemptedError means the agent stopped the executor to release
+// the resource to a queue below its guaranteed share.
+type PreemptedError struct {
+	Server string
+	Name   string
+}
+
+func (e *PreemptedError) Error() string {
+	return fmt.Sprintf("preempted: %s on %s", e.Name, e.Server)
+}
+
+// Retryable is false, since the resource is no longer allocated to the flow.
+func (e *PreemptedError) Retryable() bool {
+	return false
+}
+
 func sendExecutionRequest(ctx context.Context,
 	_ *pb.FlowExecutionStatus_TaskGroup,
 	executionStatus *pb.FlowExecutionStatus_TaskGroup_Execution,
@@ -113,11 +151,24 @@ func sendExecutionRequest(ctx context.Context,
 			}
 			if err != nil {
 				log.Printf("sendExecutionRequest %v stream from %s: %v", request.GetInstructionSet().GetName(), server, err)
+				if grpc.Code(err) == codes.ResourceExhausted {
+					return &OutOfMemoryError{
+						Server:   server,
+						Name:     request.GetInstructionSet().GetName(),
+						MemoryMb: request.GetResource().GetMemoryMb(),
+					}
+				}
+				if grpc.Code(err) == codes.Aborted {
+					return &PreemptedError{
+						Server: server,
+						Name:   request.GetInstructionSet().GetName(),
+					}
+				}
 				break
 			}
 			if response.GetError() != nil {
 				log.Printf("%s %v>%s", server, request.InstructionSet.Name, string(response.GetError()))
-				executionStatus.Error = response.GetError()
+				executionStatus.Error = appendErrorTail(executionStatus.Error, response.GetError())
 			}
 			if response.GetOutput() != nil {
 				fmt.Fprintf(os.Stdout, "%s>%s\n", server, string(response.GetOutput()))
@@ -143,6 +194,15 @@ func sendExecutionRequest(ctx context.Context,
 	})
 }
 
+// appendErrorTail keeps the last maxErrorTail bytes of the executor stderr.
+func appendErrorTail(tail, data []byte) []byte {
+	tail = append(tail, data...)
+	if len(tail) > maxErrorTail {
+		tail = append([]byte(nil), tail[len(tail)-maxErrorTail:]...)
+	}
+	return tail
+}
+
 // merge existing stats with incoming stats
 func mergeStats(a, b []*pb.InstructionStat) (ret []*pb.InstructionStat) {
 	var nonOverlapping []*pb.InstructionStat
@@ -198,10 +258,8 @@ func SendCleanupRequest(server string, request *pb.CleanupRequest) error {
 }
 
 func withClient(server string, fn func(client pb.GleamAgentClient) error) error {
-	grpcConnection, err := grpc.Dial(server,
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
-	)
+	options := append(security.GrpcDialOptions(security.AgentCredentials()), grpc.WithBlock())
+	grpcConnection, err := grpc.Dial(server, options...)
 	if err != nil {
 		return fmt.Errorf("driver dial agent: %v", err)
 	}
@@ -1,19 +1,22 @@
 package scheduler
 
 import (
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
-func getResources(master string, request *pb.ComputeRequest) (*pb.AllocationResult, error) {
+func getResources(master string, creds *security.Credentials, request *pb.ComputeRequest) (*pb.AllocationResult, error) {
 
-	grpcConection, err := grpc.Dial(master, grpc.WithInsecure())
+	grpcConection, err := grpc.Dial(master, security.GrpcDialOptions(creds)...)
 	if err != nil {
-		log.Printf("fail to dial %s: %v", master, err)
+		return nil, fmt.Errorf("fail to dial %s: %v", master, err)
 	}
 	defer func() {
 		time.Sleep(50 * time.Millisecond)
@@ -24,3 +27,19 @@ func getResources(master string, request *pb.ComputeRequest) (*pb.AllocationResu
 
 	return client.GetResources(context.Background(), request)
 }
+
+// getResources asks the masters in turn, starting from the last working one,
+// so a standby master is used after the active master fails.
+func (s *Scheduler) getResources(request *pb.ComputeRequest) (result *pb.AllocationResult, err error) {
+	masters := strings.Split(s.Master, ",")
+	for i := 0; i < len(masters); i++ {
+		index := (s.masterIndex + i) % len(masters)
+		master := strings.TrimSpace(masters[index])
+		if result, err = getResources(master, s.Option.Credentials, request); err == nil {
+			s.masterIndex = index
+			return result, nil
+		}
+		log.Printf("%s Failed to allocate: %v", master, err)
+	}
+	return nil, err
+}
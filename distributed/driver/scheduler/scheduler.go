@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/chrislusf/gleam/distributed/driver/scheduler/market"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 )
 
@@ -15,10 +16,13 @@ type Scheduler struct {
 	sync.Mutex
 
 	Master       string
+	masterIndex  int
 	EventChan    chan interface{}
 	Market       *market.Market
 	Option       *Option
 	shardLocator *DatasetShardLocator
+	shuffles     *shuffleLocator
+	adaptive     *adaptiveExecution
 }
 
 type RemoteExecutorStatus struct {
@@ -41,6 +45,12 @@ type Option struct {
 	TaskMemoryMB int
 	Module       string
 	IsProfiling  bool
+	Credentials  *security.Credentials
+	Queue        string
+	Compression  string
+	PushShuffle  bool
+	// the target partition size when adapting to the actual partition sizes
+	AdaptivePartitionMB int
 }
 
 func New(leader string, option *Option) *Scheduler {
@@ -54,6 +64,7 @@ func New(leader string, option *Option) *Scheduler {
 		EventChan:    make(chan interface{}),
 		Market:       market.NewMarket(),
 		shardLocator: NewDatasetShardLocator(),
+		shuffles:     newShuffleLocator(),
 		Option:       option,
 	}
 	s.Market.SetScoreFunction(s.Score).SetFetchFunction(s.Fetch)
@@ -0,0 +1,526 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chrislusf/gleam/distributed/plan"
+	"github.com/chrislusf/gleam/flow"
+	"github.com/chrislusf/gleam/instruction"
+	"github.com/chrislusf/gleam/pb"
+)
+
+// adaptiveExecution adapts the task groups reading partitions to the actual
+// partition sizes, counted by the executors writing the partitions.
+// The decisions are made after all the partitions are written:
+//   - adjacent small partitions are coalesced into one task group,
+//   - an oversized partition is sorted in parts, which are then merged,
+//   - a sort-merge join hashes the smaller side if it is small enough,
+//     and fits in the memory of a task.
+type adaptiveExecution struct {
+	sync.Mutex
+	targetBytes  int64
+	taskMemoryMB int64
+	pushShuffle  bool
+	stepGroupOf  map[*flow.Step]*plan.StepGroup
+	shardBytes   map[string]int64
+
+	decidedStepGroups map[*plan.StepGroup]bool
+	decidedJoins      map[*flow.Step]bool
+	leaderOf          map[*plan.TaskGroup]*plan.TaskGroup
+	membersOf         map[*plan.TaskGroup][]*plan.TaskGroup
+	sortedParts       map[*plan.TaskGroup][][]*flow.DatasetShard
+	hashedJoins       map[int32]bool // join step id to whether the left side is hashed
+	unsortedSteps     map[int32]bool // local sorts not needed by the hashed joins
+}
+
+// EnableAdaptive lets the task groups reading partitions adapt to the actual
+// partition sizes, aiming at partitions of Option.AdaptivePartitionMB.
+func (s *Scheduler) EnableAdaptive(stepGroups []*plan.StepGroup) {
+	a := &adaptiveExecution{
+		targetBytes:       int64(s.Option.AdaptivePartitionMB) * 1024 * 1024,
+		taskMemoryMB:      int64(s.Option.TaskMemoryMB),
+		pushShuffle:       s.Option.PushShuffle,
+		stepGroupOf:       make(map[*flow.Step]*plan.StepGroup),
+		shardBytes:        make(map[string]int64),
+		decidedStepGroups: make(map[*plan.StepGroup]bool),
+		decidedJoins:      make(map[*flow.Step]bool),
+		leaderOf:          make(map[*plan.TaskGroup]*plan.TaskGroup),
+		membersOf:         make(map[*plan.TaskGroup][]*plan.TaskGroup),
+		sortedParts:       make(map[*plan.TaskGroup][][]*flow.DatasetShard),
+		hashedJoins:       make(map[int32]bool),
+		unsortedSteps:     make(map[int32]bool),
+	}
+	for _, stepGroup := range stepGroups {
+		for _, step := range stepGroup.Steps {
+			a.stepGroupOf[step] = stepGroup
+		}
+	}
+	s.adaptive = a
+}
+
+// adapt decides how the task group runs, waiting for the partitions it reads
+// to be written. It returns the leader task group if the task group is
+// coalesced into the leader.
+func (a *adaptiveExecution) adapt(taskGroup *plan.TaskGroup) *plan.TaskGroup {
+	for _, task := range taskGroup.Tasks {
+		if join := sortMergeJoinOf(task.Step); join != nil {
+			a.decideJoin(join)
+		}
+	}
+	if isCollectingPartitions(taskGroup.Tasks[0].Step) {
+		a.decidePartitions(taskGroup.ParentStepGroup)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	return a.leaderOf[taskGroup]
+}
+
+// recordOutputBytes keeps the bytes the last instruction wrote to each output location.
+func (a *adaptiveExecution) recordOutputBytes(stat *pb.ExecutionStat, lastInstruction *pb.Instruction, outputLocations []pb.DataLocation) {
+	a.Lock()
+	defer a.Unlock()
+
+	for _, instructionStat := range stat.GetStats() {
+		if instructionStat.StepId != lastInstruction.StepId || instructionStat.TaskId != lastInstruction.TaskId {
+			continue
+		}
+		if len(instructionStat.OutputShardBytes) != len(outputLocations) {
+			return
+		}
+		for i, location := range outputLocations {
+			a.shardBytes[location.Name] = instructionStat.OutputShardBytes[i]
+		}
+	}
+}
+
+// shardsOf returns the shards the task group reads and writes,
+// including the ones of the task groups coalesced into it.
+func (a *adaptiveExecution) shardsOf(taskGroup *plan.TaskGroup) (inputShards, outputShards []*flow.DatasetShard) {
+	a.Lock()
+	defer a.Unlock()
+
+	for _, tg := range append([]*plan.TaskGroup{taskGroup}, a.membersOf[taskGroup]...) {
+		inputShards = append(inputShards, tg.Tasks[0].InputShards...)
+		outputShards = append(outputShards, tg.Tasks[len(tg.Tasks)-1].OutputShards...)
+	}
+	return
+}
+
+// sortedPartsOf returns the input shards of each part of an oversized
+// partition, if the partition is sorted in parts.
+func (a *adaptiveExecution) sortedPartsOf(taskGroup *plan.TaskGroup) [][]*flow.DatasetShard {
+	a.Lock()
+	defer a.Unlock()
+	return a.sortedParts[taskGroup]
+}
+
+// adaptInstructions changes the joins decided to hash one side,
+// and the local sorts before them, which are not needed any more.
+func (a *adaptiveExecution) adaptInstructions(instructionSet *pb.InstructionSet) {
+	a.Lock()
+	defer a.Unlock()
+
+	for k, i := range instructionSet.Instructions {
+		if a.unsortedSteps[i.StepId] {
+			instructionSet.Instructions[k] = &pb.Instruction{
+				StepId:  i.StepId,
+				TaskId:  i.TaskId,
+				MergeTo: &pb.Instruction_MergeTo{},
+			}
+			continue
+		}
+		if isLeftHashed, found := a.hashedJoins[i.StepId]; found && i.GetJoinPartitionedSorted() != nil {
+			join := i.GetJoinPartitionedSorted()
+			instructionSet.Instructions[k] = &pb.Instruction{
+				StepId: i.StepId,
+				TaskId: i.TaskId,
+				JoinPartitionedHashed: &pb.Instruction_JoinPartitionedHashed{
+					Indexes:          join.GetIndexes(),
+					IsLeftOuterJoin:  join.GetIsLeftOuterJoin(),
+					IsRightOuterJoin: join.GetIsRightOuterJoin(),
+					IsLeftHashed:     isLeftHashed,
+				},
+			}
+		}
+	}
+}
+
+// decideJoin hashes the smaller side of the sort-merge join if the side
+// is not larger than the target partition size, and its rows fit in the
+// memory of a task.
+func (a *adaptiveExecution) decideJoin(join *flow.Step) {
+	a.Lock()
+	decided := a.decidedJoins[join]
+	a.Unlock()
+	if decided {
+		return
+	}
+
+	sorts, scatters, ok := sortMergeJoinInputs(join)
+	ok = ok && isOrderIgnored(join.OutputDataset)
+	for _, scatter := range scatters {
+		ok = ok && a.isWrittenOut(scatter)
+	}
+	if ok {
+		for _, scatter := range scatters {
+			a.stepGroupOf[scatter].WaitForAllTasksToComplete()
+		}
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if a.decidedJoins[join] {
+		return
+	}
+	a.decidedJoins[join] = true
+	if !ok {
+		return
+	}
+
+	leftBytes, leftFound := a.datasetBytes(scatters[0].OutputDataset)
+	rightBytes, rightFound := a.datasetBytes(scatters[len(scatters)-1].OutputDataset)
+	if !leftFound || !rightFound {
+		return
+	}
+	isLeftHashed, hashedBytes := leftBytes <= rightBytes, leftBytes
+	if !isLeftHashed {
+		hashedBytes = rightBytes
+	}
+	if hashedBytes > a.targetBytes {
+		return
+	}
+	hashedMB := (hashedBytes + 1024*1024 - 1) / (1024 * 1024)
+	memoryMB := instruction.NewJoinPartitionedHashed(false, false, isLeftHashed, nil).GetMemoryCostInMB(hashedMB)
+	if a.taskMemoryMB > 0 && memoryMB > a.taskMemoryMB {
+		log.Printf("%s keeps sorting, hashing %d bytes needs %d MB, more than the task memory of %d MB",
+			join.Name, hashedBytes, memoryMB, a.taskMemoryMB)
+		return
+	}
+	a.hashedJoins[int32(join.Id)] = isLeftHashed
+	for _, sortStep := range sorts {
+		a.unsortedSteps[int32(sortStep.Id)] = true
+	}
+	log.Printf("%s hashes the smaller side of %d bytes instead of sorting", join.Name, hashedBytes)
+}
+
+// decidePartitions coalesces the adjacent small partitions, and sorts
+// each oversized partition in parts, for the task groups collecting partitions.
+func (a *adaptiveExecution) decidePartitions(stepGroup *plan.StepGroup) {
+	a.Lock()
+	decided := a.decidedStepGroups[stepGroup]
+	a.Unlock()
+	if decided {
+		return
+	}
+
+	collect := stepGroup.Steps[0]
+	scatter := collect.InputDatasets[0].Step
+	ok := a.isWrittenOut(scatter)
+	if ok {
+		a.stepGroupOf[scatter].WaitForAllTasksToComplete()
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if a.decidedStepGroups[stepGroup] {
+		return
+	}
+	a.decidedStepGroups[stepGroup] = true
+	if !ok {
+		return
+	}
+
+	var sizes []int64
+	for _, taskGroup := range stepGroup.TaskGroups {
+		size, found := a.shardsBytes(taskGroup.Tasks[0].InputShards)
+		if !found {
+			return
+		}
+		sizes = append(sizes, size)
+	}
+
+	isSplittable := len(stepGroup.Steps) > 1 && isLocalSort(stepGroup.Steps[1])
+	for i, taskGroup := range stepGroup.TaskGroups {
+		inputShards := taskGroup.Tasks[0].InputShards
+		if !isSplittable || sizes[i] <= 2*a.targetBytes || len(inputShards) < 2 {
+			continue
+		}
+		partCount := int((sizes[i] + a.targetBytes - 1) / a.targetBytes)
+		if partCount > len(inputShards) {
+			partCount = len(inputShards)
+		}
+		a.sortedParts[taskGroup] = a.balanceShards(inputShards, partCount)
+		log.Printf("%s sorts %d bytes in %d parts", taskGroup, sizes[i], partCount)
+	}
+
+	if !isCoalescable(stepGroup) {
+		return
+	}
+	taskGroups := stepGroup.TaskGroups
+	for i := 0; i < len(taskGroups); {
+		j, total := i+1, sizes[i]
+		for j < len(taskGroups) && total+sizes[j] <= a.targetBytes {
+			total += sizes[j]
+			j++
+		}
+		if j-i > 1 {
+			leader := taskGroups[i]
+			for _, member := range taskGroups[i+1 : j] {
+				a.leaderOf[member] = leader
+				a.membersOf[leader] = append(a.membersOf[leader], member)
+			}
+			log.Printf("%s coalesces %d partitions of %d bytes", leader, j-i, total)
+		}
+		i = j
+	}
+}
+
+// sortInParts sorts the parts of an oversized partition one after another,
+// each into a sorted shard on disk, and returns the locations of the sorted
+// parts. Each part runs with the whole allocation of the task group.
+func (s *Scheduler) sortInParts(ctx context.Context, flowContext *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup, taskGroup *plan.TaskGroup,
+	instructionSet *pb.InstructionSet, parts [][]*flow.DatasetShard,
+	allocation *pb.Allocation) (partLocations []pb.DataLocation, err error) {
+
+	collect, localSort := instructionSet.Instructions[0], instructionSet.Instructions[1]
+	sortedShard := taskGroup.Tasks[1].OutputShards[0]
+
+	for j, part := range parts {
+		partLocation := pb.DataLocation{
+			Name:     fmt.Sprintf("%s-part%d", sortedShard.Name(), j),
+			Location: allocation.Location,
+			OnDisk:   true,
+		}
+		partLocations = append(partLocations, partLocation)
+
+		var inputLocations []pb.DataLocation
+		for _, shard := range part {
+			loc, _ := s.GetShardLocation(shard)
+			inputLocations = append(inputLocations, loc)
+		}
+		inputLocations = mergeShuffleLocations(inputLocations)
+		partCollect := &pb.Instruction{
+			StepId:            collect.StepId,
+			TaskId:            collect.TaskId,
+			CollectPartitions: collect.GetCollectPartitions(),
+		}
+		partSort := &pb.Instruction{
+			StepId:    localSort.StepId,
+			TaskId:    localSort.TaskId,
+			LocalSort: localSort.GetLocalSort(),
+		}
+		partCollect.SetInputLocations(inputLocations)
+		partSort.SetOutputLocations([]pb.DataLocation{partLocation})
+
+		request := &pb.ExecutionRequest{
+			InstructionSet: &pb.InstructionSet{
+				Instructions: []*pb.Instruction{partCollect, partSort},
+				ReaderCount:  1,
+				FlowHashCode: flowContext.HashCode,
+				IsProfiling:  s.Option.IsProfiling,
+				Compression:  s.Option.Compression,
+				Name:         fmt.Sprintf("%s-part%d", taskGroup, j),
+			},
+			Dir:      s.Option.Module,
+			Resource: allocation.Allocated,
+		}
+		if err = sendExecutionRequest(ctx, taskGroupStatus, &pb.FlowExecutionStatus_TaskGroup_Execution{}, allocation.Location.URL(), request); err != nil {
+			return partLocations, fmt.Errorf("Failed to sort in parts: %v", err)
+		}
+	}
+	return partLocations, nil
+}
+
+func (s *Scheduler) deleteParts(partLocations []pb.DataLocation) {
+	for _, partLocation := range partLocations {
+		if err := sendDeleteRequest(partLocation.Location.URL(), &pb.DeleteDatasetShardRequest{
+			Name: partLocation.Name,
+		}); err != nil {
+			println("Purging dataset error:", err.Error())
+		}
+	}
+}
+
+// isWrittenOut checks the partitioning step completes without waiting for
+// its readers, so the partition sizes are known before the readers start.
+func (a *adaptiveExecution) isWrittenOut(scatter *flow.Step) bool {
+	if _, found := a.stepGroupOf[scatter]; !found {
+		return false
+	}
+	return a.pushShuffle || scatter.OutputDataset.GetIsOnDiskIO()
+}
+
+func (a *adaptiveExecution) datasetBytes(d *flow.Dataset) (int64, bool) {
+	return a.shardsBytes(d.Shards)
+}
+
+func (a *adaptiveExecution) shardsBytes(shards []*flow.DatasetShard) (total int64, found bool) {
+	for _, shard := range shards {
+		n, found := a.shardBytes[shard.Name()]
+		if !found {
+			return 0, false
+		}
+		total += n
+	}
+	return total, true
+}
+
+// balanceShards spreads the shards into parts of similar sizes.
+func (a *adaptiveExecution) balanceShards(shards []*flow.DatasetShard, partCount int) [][]*flow.DatasetShard {
+	sorted := make([]*flow.DatasetShard, len(shards))
+	copy(sorted, shards)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return a.shardBytes[sorted[i].Name()] > a.shardBytes[sorted[j].Name()]
+	})
+	parts := make([][]*flow.DatasetShard, partCount)
+	partBytes := make([]int64, partCount)
+	for _, shard := range sorted {
+		smallest := 0
+		for k := range partBytes {
+			if partBytes[k] < partBytes[smallest] {
+				smallest = k
+			}
+		}
+		parts[smallest] = append(parts[smallest], shard)
+		partBytes[smallest] += a.shardBytes[shard.Name()]
+	}
+	return parts
+}
+
+func isCollectingPartitions(step *flow.Step) bool {
+	if _, ok := step.Instruction.(*instruction.CollectPartitions); !ok || step.NetworkType != flow.LinkedNShardToOneShard {
+		return false
+	}
+	_, ok := step.InputDatasets[0].Step.Instruction.(*instruction.ScatterPartitions)
+	return ok
+}
+
+func isLocalSort(step *flow.Step) bool {
+	_, ok := step.Instruction.(*instruction.LocalSort)
+	return ok && !step.IsOnDriverSide
+}
+
+// isCoalescable checks the partitions can be coalesced, with the coalesced
+// rows written to the leader's output shard and nothing to the others.
+func isCoalescable(stepGroup *plan.StepGroup) bool {
+	for _, step := range stepGroup.Steps[1:] {
+		if step.NetworkType != flow.OneShardToOneShard || isLimitingShards(step) {
+			return false
+		}
+	}
+	for _, taskGroup := range stepGroup.TaskGroups {
+		if len(taskGroup.Tasks[len(taskGroup.Tasks)-1].OutputShards) != 1 {
+			return false
+		}
+	}
+	last := stepGroup.Steps[len(stepGroup.Steps)-1]
+	return last.OutputDataset != nil && isPartitioningIgnored(last.OutputDataset)
+}
+
+// isPartitioningIgnored checks the rows of the dataset do not need to stay
+// in their partitions, i.e., they are partitioned again or collected together.
+func isPartitioningIgnored(d *flow.Dataset) bool {
+	for _, step := range d.ReadingSteps {
+		switch step.NetworkType {
+		case flow.OneShardToOneShard:
+			if isLimitingShards(step) {
+				return false
+			}
+			if step.OutputDataset != nil && !isPartitioningIgnored(step.OutputDataset) {
+				return false
+			}
+		case flow.MergeTwoShardToOneShard:
+			return false
+		}
+	}
+	return true
+}
+
+func isLimitingShards(step *flow.Step) bool {
+	switch step.Instruction.(type) {
+	case *instruction.LocalLimit, *instruction.LocalTop:
+		return true
+	}
+	return false
+}
+
+// sortMergeJoinOf returns the sort-merge join step, if the step is the join,
+// or the local sort before the join.
+func sortMergeJoinOf(step *flow.Step) *flow.Step {
+	if _, ok := step.Instruction.(*instruction.JoinPartitionedSorted); ok {
+		return step
+	}
+	if !isLocalSort(step) || step.OutputDataset == nil {
+		return nil
+	}
+	for _, readingStep := range step.OutputDataset.ReadingSteps {
+		if _, ok := readingStep.Instruction.(*instruction.JoinPartitionedSorted); ok {
+			return readingStep
+		}
+	}
+	return nil
+}
+
+// sortMergeJoinInputs returns the local sorts and the partitioning steps of
+// both sides of the join, if both sides are partitioned and sorted only for the join.
+func sortMergeJoinInputs(join *flow.Step) (sorts, scatters []*flow.Step, ok bool) {
+	for _, input := range join.InputDatasets {
+		sortStep := input.Step
+		if !isLocalSort(sortStep) {
+			return nil, nil, false
+		}
+		for _, readingStep := range input.ReadingSteps {
+			if readingStep != join {
+				return nil, nil, false
+			}
+		}
+		collect := sortStep.InputDatasets[0].Step
+		if !isCollectingPartitions(collect) {
+			return nil, nil, false
+		}
+		if len(sorts) == 0 || sorts[0] != sortStep {
+			sorts = append(sorts, sortStep)
+			scatters = append(scatters, collect.InputDatasets[0].Step)
+		}
+	}
+	return sorts, scatters, len(sorts) > 0
+}
+
+// isOrderIgnored checks the steps reading the dataset, and the steps after
+// them, do not rely on the rows being sorted.
+func isOrderIgnored(d *flow.Dataset) bool {
+	for _, step := range d.ReadingSteps {
+		switch step.Instruction.(type) {
+		case *instruction.LocalSort, *instruction.ScatterPartitions:
+			continue
+		case *instruction.Select:
+			if !isOrderIgnored(step.OutputDataset) {
+				return false
+			}
+			continue
+		}
+		if step.IsOnDriverSide && step.OutputDataset == nil {
+			continue
+		}
+		if isGoMapper(step) {
+			if !isOrderIgnored(step.OutputDataset) {
+				return false
+			}
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isGoMapper(step *flow.Step) bool {
+	return step.IsGoCode && step.Command != nil &&
+		strings.Contains(strings.Join(step.Command.Args, " "), "-gleam.mapper=")
+}
@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"fmt"
+	"log"
 	"sync"
 
 	"github.com/chrislusf/gleam/distributed/plan"
@@ -30,6 +32,15 @@ func (s *Scheduler) DeleteOutput(taskGroup *plan.TaskGroup) {
 	wg.Wait()
 }
 
+// shardsOf returns the shards the task group reads and writes.
+func (s *Scheduler) shardsOf(taskGroup *plan.TaskGroup) (inputShards, outputShards []*flow.DatasetShard) {
+	if s.adaptive != nil {
+		return s.adaptive.shardsOf(taskGroup)
+	}
+	tasks := taskGroup.Tasks
+	return tasks[0].InputShards, tasks[len(tasks)-1].OutputShards
+}
+
 func needsInputFromDriver(task *flow.Task) bool {
 	for _, shard := range task.InputShards {
 		if shard.Dataset.Step.IsOnDriverSide {
@@ -48,6 +59,29 @@ func isInputOnDisk(task *flow.Task) bool {
 	return true
 }
 
+// shuffleOf is the reduce partition the shard is pushed into, if the push
+// shuffle is enabled and the shard is written by a partitioning step.
+func (s *Scheduler) shuffleOf(shard *flow.DatasetShard) *pb.ShufflePartition {
+	step := shard.Dataset.Step
+	if !s.Option.PushShuffle || step.IsOnDriverSide {
+		return nil
+	}
+	if step.NetworkType != flow.OneShardToEveryNShard && step.NetworkType != flow.AllShardTOAllShard {
+		return nil
+	}
+	for _, task := range step.Tasks {
+		for partition, outputShard := range task.OutputShards {
+			if outputShard == shard {
+				return &pb.ShufflePartition{
+					Name:   fmt.Sprintf("f%d-d%d-p%d", shard.Dataset.Flow.HashCode, shard.Dataset.Id, partition),
+					Mapper: int32(task.Id),
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func isRestartableTasks(tasks []*flow.Task) bool {
 	for _, task := range tasks {
 		if !task.Step.Meta.IsRestartable {
@@ -65,3 +99,12 @@ func (s *Scheduler) GetShardLocation(shard *flow.DatasetShard) (pb.DataLocation,
 func (s *Scheduler) setShardLocation(shard *flow.DatasetShard, loc pb.DataLocation) {
 	s.shardLocator.SetShardLocation(shard.Name(), loc)
 }
+
+// moveShardLocation points to the new location of a dataset shard
+// migrated away from a drained agent.
+func (s *Scheduler) moveShardLocation(loc pb.DataLocation) {
+	if current, found := s.shardLocator.GetShardLocation(loc.Name); found && current.Location.URL() != loc.Location.URL() {
+		log.Printf("dataset shard %s moved from %s to %s", loc.Name, current.Location.URL(), loc.Location.URL())
+		s.shardLocator.SetShardLocation(loc.Name, loc)
+	}
+}
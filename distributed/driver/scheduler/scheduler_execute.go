@@ -25,12 +25,9 @@ func (s *Scheduler) remoteExecuteOnLocation(ctx context.Context,
 	// fmt.Printf("allocated %s on %v\n", tasks[0].Name(), allocation.Location)
 	// create reqeust
 	instructionSet := plan.TranslateToInstructionSet(taskGroup)
-	firstInstruction := instructionSet.GetInstructions()[0]
-	lastInstruction := instructionSet.GetInstructions()[len(instructionSet.GetInstructions())-1]
-	firstTask := taskGroup.Tasks[0]
-	lastTask := taskGroup.Tasks[len(taskGroup.Tasks)-1]
+	inputShards, outputShards := s.shardsOf(taskGroup)
 	var inputLocations, outputLocations []pb.DataLocation
-	for _, shard := range firstTask.InputShards {
+	for _, shard := range inputShards {
 		loc, hasLocation := s.GetShardLocation(shard)
 		if !hasLocation {
 			log.Printf("The shard is missing?: %s", shard.Name())
@@ -38,26 +35,52 @@ func (s *Scheduler) remoteExecuteOnLocation(ctx context.Context,
 		}
 		inputLocations = append(inputLocations, loc)
 	}
+	if instructionSet.Instructions[0].GetCollectPartitions() != nil {
+		inputLocations = mergeShuffleLocations(inputLocations)
+	}
 
-	for _, shard := range lastTask.OutputShards {
-		outputLocations = append(outputLocations, pb.DataLocation{
-			Name:     shard.Name(),
-			Location: allocation.Location,
-			OnDisk:   shard.Dataset.GetIsOnDiskIO(),
-		})
+	for _, shard := range outputShards {
+		outputLocations = append(outputLocations, s.outputLocation(shard, allocation))
 	}
 
+	if s.adaptive != nil {
+		s.adaptive.adaptInstructions(instructionSet)
+		if parts := s.adaptive.sortedPartsOf(taskGroup); len(parts) > 0 && instructionSet.Instructions[1].GetLocalSort() != nil {
+			partLocations, err := s.sortInParts(ctx, flowContext, taskGroupStatus, taskGroup, instructionSet, parts, allocation)
+			defer s.deleteParts(partLocations)
+			if err != nil {
+				return err
+			}
+			// merge the sorted parts, instead of collecting and sorting the partition
+			localSort := instructionSet.Instructions[1]
+			instructionSet.Instructions[1] = &pb.Instruction{
+				StepId: localSort.StepId,
+				TaskId: localSort.TaskId,
+				MergeSortedTo: &pb.Instruction_MergeSortedTo{
+					OrderBys: localSort.GetLocalSort().GetOrderBys(),
+				},
+			}
+			instructionSet.Instructions = instructionSet.Instructions[1:]
+			inputLocations = partLocations
+		}
+	}
+
+	firstInstruction := instructionSet.GetInstructions()[0]
+	lastInstruction := instructionSet.GetInstructions()[len(instructionSet.GetInstructions())-1]
 	firstInstruction.SetInputLocations(inputLocations)
 	lastInstruction.SetOutputLocations(outputLocations)
 
 	instructionSet.FlowHashCode = flowContext.HashCode
 	instructionSet.IsProfiling = s.Option.IsProfiling
+	instructionSet.Compression = s.Option.Compression
 	instructionSet.Name = taskGroup.String()
 
 	request := &pb.ExecutionRequest{
 		InstructionSet: instructionSet,
 		Dir:            s.Option.Module,
 		Resource:       allocation.Allocated,
+		// the inputs can be read again only if they are on disk
+		IsPreemptible: isRestartableTasks(taskGroup.Tasks) && !needsInputFromDriver(taskGroup.Tasks[0]),
 	}
 	taskGroupStatus.Request = request
 	taskGroupStatus.Allocation = allocation
@@ -69,6 +92,10 @@ func (s *Scheduler) remoteExecuteOnLocation(ctx context.Context,
 		return err
 	}
 
+	if s.adaptive != nil {
+		s.adaptive.recordOutputBytes(executionStatus.GetExecutionStat(), lastInstruction, outputLocations)
+	}
+
 	return nil
 }
 
@@ -89,7 +116,9 @@ func (s *Scheduler) localExecuteSource(ctx context.Context,
 	executionStatus *pb.FlowExecutionStatus_TaskGroup_Execution,
 	task *flow.Task,
 	wg *sync.WaitGroup) error {
-	s.shardLocator.waitForOutputDatasetShardLocations(task)
+	if err := s.shardLocator.waitForOutputDatasetShardLocations(ctx, task); err != nil {
+		return err
+	}
 
 	instructionStat := &pb.InstructionStat{
 		StepId: int32(task.Step.Id),
@@ -106,7 +135,7 @@ func (s *Scheduler) localExecuteSource(ctx context.Context,
 		wg.Add(1)
 		go func(shard *flow.DatasetShard) {
 			// println(task.Step.Name, "writing to", shard.Name(), "at", location.Location.URL())
-			if err := netchan.DialWriteChannel(ctx, wg, "driver_input", location.Location.URL(), shard.Name(), shard.Dataset.GetIsOnDiskIO(), shard.IncomingChan.Reader, len(shard.ReadingTasks)); err != nil {
+			if err := netchan.DialWriteChannel(ctx, wg, "driver_input", location.Location.URL(), shard.Name(), shard.Dataset.GetIsOnDiskIO(), s.Option.Compression, shard.IncomingChan.Reader, len(shard.ReadingTasks)); err != nil {
 				println("starting:", task.Step.Name, "output location:", location.Location.URL(), shard.Name(), "error:", err.Error())
 			}
 		}(shard)
@@ -119,7 +148,9 @@ func (s *Scheduler) localExecuteSource(ctx context.Context,
 }
 
 func (s *Scheduler) localExecuteOutput(ctx context.Context, flowContext *flow.Flow, task *flow.Task, wg *sync.WaitGroup) error {
-	s.shardLocator.waitForInputDatasetShardLocations(task)
+	if err := s.shardLocator.waitForInputDatasetShardLocations(ctx, task); err != nil {
+		return err
+	}
 
 	for i, shard := range task.InputShards {
 		location, _ := s.GetShardLocation(shard)
@@ -127,7 +158,13 @@ func (s *Scheduler) localExecuteOutput(ctx context.Context, flowContext *flow.Fl
 		wg.Add(1)
 		go func(shard *flow.DatasetShard) {
 			// println(task.Step.Name, "reading from", shard.Name(), "at", location.Location.URL(), "to", inChan, "onDisk", shard.Dataset.GetIsOnDiskIO())
-			if err := netchan.DialReadChannel(ctx, wg, "driver_output", location.Location.URL(), shard.Name(), shard.Dataset.GetIsOnDiskIO(), inChan.Writer); err != nil {
+			var err error
+			if location.Shuffle != nil {
+				err = netchan.DialShuffleReadChannel(ctx, wg, "driver_output", location.Location.URL(), shard.Name(), location.Shuffle, s.Option.Compression, inChan.Writer)
+			} else {
+				err = netchan.DialReadChannel(ctx, wg, "driver_output", location.Location.URL(), shard.Name(), shard.Dataset.GetIsOnDiskIO(), s.Option.Compression, inChan.Writer)
+			}
+			if err != nil {
 				println("starting:", task.Step.Name, "input location:", location.Location.URL(), shard.Name(), "error:", err.Error())
 			}
 		}(shard)
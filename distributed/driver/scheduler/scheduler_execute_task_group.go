@@ -33,13 +33,20 @@ func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 		if err := taskGroupStatus.Track(func(exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution) error {
 			return s.localExecute(ctx, fc, exeStatus, lastTask, wg)
 		}); err != nil {
+			if ctx.Err() != nil {
+				taskGroup.MarkStop(err)
+				return
+			}
 			log.Fatalf("Failed to execute on driver side: %v", err)
 		}
 		return
 	}
 	if !needsInputFromDriver(tasks[0]) {
 		// wait until inputs are registed
-		s.shardLocator.waitForInputDatasetShardLocations(tasks[0])
+		if err := s.shardLocator.waitForInputDatasetShardLocations(ctx, tasks[0]); err != nil {
+			taskGroup.MarkStop(err)
+			return
+		}
 	}
 	if isInputOnDisk(tasks[0]) && !isRestartableTasks(tasks) {
 		// for non-restartable taskGroup, wait until on disk inputs are completed
@@ -48,15 +55,66 @@ func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 		}
 	}
 
-	// fmt.Printf("inputs of %s is %s\n", tasks[0].Name(), s.allInputLocations(tasks[0]))
+	if s.adaptive != nil {
+		if leader := s.adaptive.adapt(taskGroup); leader != nil {
+			// the leader task group reads the inputs and writes the outputs of this one
+			leader.WaitToComplete()
+			taskGroup.MarkStop(nil)
+			return
+		}
+	}
+	// send driver code only when using go mapper reducer
+	var hasGoCode bool
+	for _, t := range tasks {
+		hasGoCode = hasGoCode || t.Step.IsGoCode
+	}
+	if hasGoCode {
+		relatedFiles = append(relatedFiles, resource.FileResource{FullPath: os.Args[0], TargetFolder: "."})
+	}
+
+	for {
+		pickedServerChan := make(chan market.Supply, 1)
+		s.Market.AddDemand(market.Requirement(taskGroup), bid, pickedServerChan)
+
+		// get assigned executor location
+		var supply market.Supply
+		select {
+		case supply = <-pickedServerChan:
+		case <-ctx.Done():
+			// give back the allocation if the demand is met later
+			go func() {
+				if supply, ok := <-pickedServerChan; ok {
+					s.Market.ReturnSupply(supply)
+				}
+			}()
+			taskGroup.MarkStop(ctx.Err())
+			return
+		}
+		allocation := supply.Object.(*pb.Allocation)
+
+		err := s.executeOnAllocation(ctx, fc, taskGroupStatus, wg, taskGroup, allocation, relatedFiles)
+		if _, isPreempted := err.(*PreemptedError); !isPreempted {
+			s.Market.ReturnSupply(supply)
+			return
+		}
+		// the resource went to another queue, so drop the allocation and bid again
+		log.Printf("%v, bidding again", err)
+		s.deleteOutputs(taskGroup, allocation)
+	}
+
+}
 
-	pickedServerChan := make(chan market.Supply, 1)
-	s.Market.AddDemand(market.Requirement(taskGroup), bid, pickedServerChan)
+// executeOnAllocation runs the task group on the allocated executor.
+func (s *Scheduler) executeOnAllocation(ctx context.Context,
+	fc *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	wg *sync.WaitGroup,
+	taskGroup *plan.TaskGroup,
+	allocation *pb.Allocation,
+	relatedFiles []resource.FileResource) error {
 
-	// get assigned executor location
-	supply := <-pickedServerChan
-	allocation := supply.Object.(*pb.Allocation)
-	defer s.Market.ReturnSupply(supply)
+	tasks := taskGroup.Tasks
+	_, outputShards := s.shardsOf(taskGroup)
 
 	if needsInputFromDriver(tasks[0]) {
 		// tell the driver to write to me
@@ -70,22 +128,9 @@ func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 		}
 	}
 
-	for _, shard := range lastTask.OutputShards {
+	for _, shard := range outputShards {
 		// println("registering", shard.Name(), "at", allocation.Location.URL(), "onDisk", shard.Dataset.GetIsOnDiskIO())
-		s.setShardLocation(shard, pb.DataLocation{
-			Name:     shard.Name(),
-			Location: allocation.Location,
-			OnDisk:   shard.Dataset.GetIsOnDiskIO(),
-		})
-	}
-
-	// send driver code only when using go mapper reducer
-	var hasGoCode bool
-	for _, t := range tasks {
-		hasGoCode = hasGoCode || t.Step.IsGoCode
-	}
-	if hasGoCode {
-		relatedFiles = append(relatedFiles, resource.FileResource{os.Args[0], "."})
+		s.setShardLocation(shard, s.outputLocation(shard, allocation))
 	}
 
 	if len(relatedFiles) > 0 {
@@ -111,11 +156,13 @@ func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 		if err != nil {
 			log.Printf("Failed to remoteExecuteOnLocation %v: %v", allocation, err)
 		}
-		taskGroup.MarkStop(err)
+		if _, isPreempted := err.(*PreemptedError); !isPreempted {
+			taskGroup.MarkStop(err)
+		}
 		return err
 	}
 
-	util.ExecuteWithCleanup(
+	return util.ExecuteWithCleanup(
 		ctx,
 		func() error {
 			if isRestartableTasks(tasks) {
@@ -125,21 +172,28 @@ func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 			}
 		},
 		func() {
-			var w sync.WaitGroup
-			for _, shard := range lastTask.OutputShards {
-				w.Add(1)
-				// println("deleting", shard.Name(), "from", allocation.Location.URL())
-				go func(shard *flow.DatasetShard) {
-					defer w.Done()
-					if err := sendDeleteRequest(allocation.Location.URL(), &pb.DeleteDatasetShardRequest{
-						Name: shard.Name(),
-					}); err != nil {
-						println("Purging dataset error:", err.Error())
-					}
-				}(shard)
-			}
-			w.Wait()
+			s.deleteOutputs(taskGroup, allocation)
 		},
 	)
 
 }
+
+// deleteOutputs removes the output shards the task group wrote on the allocation.
+func (s *Scheduler) deleteOutputs(taskGroup *plan.TaskGroup, allocation *pb.Allocation) {
+	_, outputShards := s.shardsOf(taskGroup)
+	var w sync.WaitGroup
+	for _, shard := range outputShards {
+		w.Add(1)
+		location := s.outputLocation(shard, allocation)
+		// println("deleting", shard.Name(), "from", location.Location.URL())
+		go func(shard *flow.DatasetShard) {
+			defer w.Done()
+			if err := sendDeleteRequest(location.Location.URL(), &pb.DeleteDatasetShardRequest{
+				Name: shard.Name(),
+			}); err != nil {
+				println("Purging dataset error:", err.Error())
+			}
+		}(shard)
+	}
+	w.Wait()
+}
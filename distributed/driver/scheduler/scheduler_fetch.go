@@ -12,38 +12,80 @@ import (
 
 // Requirement is TaskGroup
 // Object is Agent's Location
+// The task groups hinted to run in other data centers are requested separately.
 func (s *Scheduler) Fetch(demands []market.Demand) {
-	var request pb.ComputeRequest
-	request.Username = s.Option.Username
-	request.Hostname = s.Option.Hostname
-	request.FlowHashCode = s.Option.FlowHashcode
-	request.DataCenter = s.Option.DataCenter
+	var dataCenters []string
+	requests := make(map[string]*pb.ComputeRequest)
 	for _, d := range demands {
 		taskGroup := d.Requirement.(*plan.TaskGroup)
+		preferred := preferredLocation(taskGroup)
+		dataCenter := preferred.DataCenter
+		if dataCenter == "" {
+			dataCenter = s.Option.DataCenter
+		}
+		request, found := requests[dataCenter]
+		if !found {
+			request = &pb.ComputeRequest{
+				Username:     s.Option.Username,
+				Hostname:     s.Option.Hostname,
+				FlowHashCode: s.Option.FlowHashcode,
+				DataCenter:   dataCenter,
+				Queue:        s.Option.Queue,
+			}
+			requests[dataCenter] = request
+			dataCenters = append(dataCenters, dataCenter)
+		}
 		requiredResource := taskGroup.RequiredResources()
 		request.ComputeResources = append(request.ComputeResources, requiredResource)
+		request.PreferredLocations = append(request.PreferredLocations, preferred)
 	}
 
-	result, err := getResources(s.Master, &request)
-	if err != nil {
-		log.Printf("%s Failed to allocate: %v", s.Master, err)
-		time.Sleep(time.Millisecond * time.Duration(15000+rand.Int63n(5000)))
-	} else {
-		if len(result.Allocations) == 0 {
-			// log.Printf("%s No more new executors.", s.Master)
-			time.Sleep(time.Millisecond * time.Duration(2000+rand.Int63n(1000)))
-		} else {
-			if s.Option.DataCenter == "" {
-				s.Option.DataCenter = result.Allocations[0].Location.DataCenter
-			}
-			var allocatedMemory int64
-			for _, allocation := range result.Allocations {
-				s.Market.AddSupply(market.Supply{
-					Object: allocation,
-				})
-				allocatedMemory += allocation.Allocated.MemoryMb
-			}
-			// log.Printf("%s allocated %d executors with %d MB memory.", s.Master, len(result.Allocations), allocatedMemory)
+	var allocationCount, errorCount int
+	for _, dataCenter := range dataCenters {
+		count, err := s.fetch(requests[dataCenter])
+		if err != nil {
+			errorCount++
 		}
+		allocationCount += count
+	}
+
+	if errorCount == len(dataCenters) {
+		time.Sleep(time.Millisecond * time.Duration(15000+rand.Int63n(5000)))
+	} else if allocationCount == 0 {
+		// log.Printf("%s No more new executors.", s.Master)
+		time.Sleep(time.Millisecond * time.Duration(2000+rand.Int63n(1000)))
+	}
+}
+
+// fetch requests the resources, and falls back to the flow's data center
+// if the hinted data center can not be used.
+func (s *Scheduler) fetch(request *pb.ComputeRequest) (int, error) {
+	result, err := s.getResources(request)
+	if err != nil && request.DataCenter != s.Option.DataCenter {
+		log.Printf("Failed to get resources in data center %s: %v", request.DataCenter, err)
+		request.DataCenter = s.Option.DataCenter
+		result, err = s.getResources(request)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, location := range result.MigratedShards {
+		s.moveShardLocation(*location)
+	}
+	if len(result.Allocations) == 0 {
+		return 0, nil
+	}
+	if s.Option.DataCenter == "" && request.DataCenter == "" {
+		s.Option.DataCenter = result.Allocations[0].Location.DataCenter
+	}
+	var allocatedMemory int64
+	for _, allocation := range result.Allocations {
+		s.Market.AddSupply(market.Supply{
+			Object: allocation,
+		})
+		allocatedMemory += allocation.Allocated.MemoryMb
 	}
+	// log.Printf("%s allocated %d executors with %d MB memory.", s.Master, len(result.Allocations), allocatedMemory)
+	return len(result.Allocations), nil
 }
@@ -25,9 +25,59 @@ func (s *Scheduler) Score(r market.Requirement, bid float64, obj market.Object)
 		}
 		cost += dataLocation.Location.Distance(loc)
 	}
+	cost += localityCost(tg, loc)
 	return float64(bid) / cost
 }
 
+// localityCost is the distance to the hosts storing the source data,
+// and to the hinted data center and rack.
+func localityCost(tg *plan.TaskGroup, loc *pb.Location) (cost float64) {
+	preferred := preferredLocation(tg)
+	if preferred.DataCenter != "" && preferred.DataCenter != loc.DataCenter {
+		cost += 1000
+	}
+	if preferred.Rack != "" && preferred.Rack != loc.Rack {
+		cost += 100
+	}
+	for _, input := range tg.Tasks[0].InputShards {
+		hosts := input.PreferredHosts()
+		if len(hosts) == 0 {
+			continue
+		}
+		isLocal := false
+		for _, host := range hosts {
+			isLocal = isLocal || loc.IsOnHost(host)
+		}
+		if !isLocal {
+			cost += 10
+		}
+	}
+	return
+}
+
+// preferredLocation is where the task group should run: the data center and
+// rack hinted for its datasets, and a host storing its source data.
+func preferredLocation(tg *plan.TaskGroup) *pb.Location {
+	preferred := &pb.Location{}
+	for _, t := range tg.Tasks {
+		if output := t.Step.OutputDataset; output != nil {
+			if output.Meta.DataCenter != "" {
+				preferred.DataCenter = output.Meta.DataCenter
+			}
+			if output.Meta.Rack != "" {
+				preferred.Rack = output.Meta.Rack
+			}
+		}
+	}
+	for _, input := range tg.Tasks[0].InputShards {
+		if hosts := input.PreferredHosts(); len(hosts) > 0 {
+			preferred.Server = hosts[0]
+			break
+		}
+	}
+	return preferred
+}
+
 func memoryCost(tg *plan.TaskGroup) (cost int64) {
 	for _, t := range tg.Tasks {
 		if t.Step.Instruction != nil && t.Step.OutputDataset != nil {
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"sync"
+
+	"github.com/chrislusf/gleam/flow"
+	"github.com/chrislusf/gleam/pb"
+)
+
+// shuffleLocator places the merged file of each reduce partition on one agent.
+// All map tasks push their parts of the partition to that agent, and the
+// reduce task reads the merged file there in one sequential read.
+type shuffleLocator struct {
+	sync.Mutex
+	// the agents of the allocations so far, in the order they are seen
+	agents     []*pb.Location
+	partitions map[string]*pb.Location
+	// the number of partitions placed on each agent
+	counts map[string]int
+}
+
+func newShuffleLocator() *shuffleLocator {
+	return &shuffleLocator{
+		partitions: make(map[string]*pb.Location),
+		counts:     make(map[string]int),
+	}
+}
+
+// locate returns the agent of the reduce partition. A new partition goes to
+// the agent with the fewest partitions, preferring the agent of the map task.
+func (l *shuffleLocator) locate(partition string, local *pb.Location) *pb.Location {
+	l.Lock()
+	defer l.Unlock()
+
+	if location, found := l.partitions[partition]; found {
+		return location
+	}
+	if _, found := l.counts[local.URL()]; !found {
+		l.counts[local.URL()] = 0
+		l.agents = append(l.agents, local)
+	}
+	picked := local
+	for _, agent := range l.agents {
+		if l.counts[agent.URL()] < l.counts[picked.URL()] {
+			picked = agent
+		}
+	}
+	l.partitions[partition] = picked
+	l.counts[picked.URL()]++
+	return picked
+}
+
+// outputLocation is where the task group on the allocation writes the shard.
+// A shard pushed into a reduce partition goes to the agent of the partition.
+func (s *Scheduler) outputLocation(shard *flow.DatasetShard, allocation *pb.Allocation) pb.DataLocation {
+	location := pb.DataLocation{
+		Name:     shard.Name(),
+		Location: allocation.Location,
+		OnDisk:   shard.Dataset.GetIsOnDiskIO(),
+	}
+	if shuffle := s.shuffleOf(shard); shuffle != nil {
+		location.Location = s.shuffles.locate(shuffle.Name, allocation.Location)
+		location.Shuffle = shuffle
+	}
+	return location
+}
+
+// mergeShuffleLocations reads all the parts pushed into the same reduce
+// partition in one read of the merged file, instead of one read per map task.
+// It is only for the instructions taking the union of their inputs.
+func mergeShuffleLocations(locations []pb.DataLocation) (merged []pb.DataLocation) {
+	partitions := make(map[string]int)
+	for _, location := range locations {
+		if location.Shuffle == nil {
+			merged = append(merged, location)
+			continue
+		}
+		key := location.Shuffle.Name + "@" + location.Location.URL()
+		i, found := partitions[key]
+		if !found {
+			i = len(merged)
+			partitions[key] = i
+			merged = append(merged, pb.DataLocation{
+				Name:     location.Shuffle.Name,
+				Location: location.Location,
+				Shuffle:  &pb.ShufflePartition{Name: location.Shuffle.Name},
+			})
+		}
+		merged[i].Shuffle.Mappers = append(merged[i].Shuffle.Mappers, location.Shuffle.Mapper)
+	}
+	return merged
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"bytes"
+	"context"
 	"sync"
 
 	"github.com/chrislusf/gleam/flow"
@@ -52,26 +53,40 @@ func (l *DatasetShardLocator) isDatasetShardRegistered(shard *flow.DatasetShard)
 	return true
 }
 
-func (l *DatasetShardLocator) waitForInputDatasetShardLocations(task *flow.Task) {
-	l.Lock()
-	defer l.Unlock()
+func (l *DatasetShardLocator) waitForInputDatasetShardLocations(ctx context.Context, task *flow.Task) error {
+	return l.waitForDatasetShardLocations(ctx, task.InputShards)
+}
 
-	for _, input := range task.InputShards {
-		for !l.isDatasetShardRegistered(input) {
-			l.waitForAllInputs.Wait()
-		}
-	}
+func (l *DatasetShardLocator) waitForOutputDatasetShardLocations(ctx context.Context, task *flow.Task) error {
+	return l.waitForDatasetShardLocations(ctx, task.OutputShards)
 }
 
-func (l *DatasetShardLocator) waitForOutputDatasetShardLocations(task *flow.Task) {
+// waitForDatasetShardLocations waits until all shards are registered, or the context is done.
+func (l *DatasetShardLocator) waitForDatasetShardLocations(ctx context.Context, shards []*flow.DatasetShard) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Lock()
+			l.waitForAllInputs.Broadcast()
+			l.Unlock()
+		case <-stop:
+		}
+	}()
+
 	l.Lock()
 	defer l.Unlock()
 
-	for _, output := range task.OutputShards {
-		for !l.isDatasetShardRegistered(output) {
+	for _, shard := range shards {
+		for !l.isDatasetShardRegistered(shard) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			l.waitForAllInputs.Wait()
 		}
 	}
+	return nil
 }
 
 func (l *DatasetShardLocator) allInputLocations(task *flow.Task) string {
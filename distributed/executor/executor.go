@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/chrislusf/gleam/distributed/netchan"
@@ -39,8 +40,8 @@ func NewExecutor(option *ExecutorOption, instructions *pb.InstructionSet) *Execu
 
 func (exe *Executor) ExecuteInstructionSet() error {
 
-	// start a listener for stats
-	listener, err := net.Listen("tcp", ":0")
+	// start a listener for stats, only reachable by the local child processes
+	listener, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
@@ -113,7 +114,7 @@ func (exe *Executor) ExecuteInstructionSet() error {
 }
 
 func setupReaders(ctx context.Context, wg *sync.WaitGroup, ioErrChan chan error,
-	i *pb.Instruction, inPiper *util.Piper, isFirst bool) (readers []io.Reader) {
+	i *pb.Instruction, inPiper *util.Piper, isFirst bool, compression string) (readers []io.Reader) {
 
 	if !isFirst {
 		readers = append(readers, inPiper.Reader)
@@ -123,7 +124,12 @@ func setupReaders(ctx context.Context, wg *sync.WaitGroup, ioErrChan chan error,
 			inChan := util.NewPiper()
 			// println(i.GetName(), "connecting to", inputLocation.Address(), "to read", inputLocation.GetName())
 			go func(inputLocation *pb.DatasetShardLocation) {
-				err := netchan.DialReadChannel(ctx, wg, i.GetName(), inputLocation.Address(), inputLocation.GetName(), inputLocation.GetOnDisk(), inChan.Writer)
+				var err error
+				if inputLocation.GetShuffle() != nil {
+					err = netchan.DialShuffleReadChannel(ctx, wg, i.GetName(), inputLocation.Address(), inputLocation.GetName(), inputLocation.GetShuffle(), compression, inChan.Writer)
+				} else {
+					err = netchan.DialReadChannel(ctx, wg, i.GetName(), inputLocation.Address(), inputLocation.GetName(), inputLocation.GetOnDisk(), compression, inChan.Writer)
+				}
 				if err != nil {
 					ioErrChan <- fmt.Errorf("Failed %s reading %s from %s: %v", i.GetName(), inputLocation.GetName(), inputLocation.Address(), err)
 				}
@@ -134,22 +140,28 @@ func setupReaders(ctx context.Context, wg *sync.WaitGroup, ioErrChan chan error,
 	return
 }
 func setupWriters(ctx context.Context, wg *sync.WaitGroup, ioErrChan chan error,
-	i *pb.Instruction, outPiper *util.Piper, isLast bool, readerCount int) (writers []io.Writer) {
+	i *pb.Instruction, outPiper *util.Piper, isLast bool, readerCount int, compression string, stat *pb.InstructionStat) (writers []io.Writer) {
 
 	if !isLast {
 		writers = append(writers, outPiper.Writer)
 	} else {
-		for _, outputLocation := range i.GetOutputShardLocations() {
+		stat.OutputShardBytes = make([]int64, len(i.GetOutputShardLocations()))
+		for k, outputLocation := range i.GetOutputShardLocations() {
 			wg.Add(1)
 			outChan := util.NewPiper()
 			// println(i.GetName(), "connecting to", outputLocation.Address(), "to write", outputLocation.GetName(), "readerCount", readerCount)
 			go func(outputLocation *pb.DatasetShardLocation) {
-				err := netchan.DialWriteChannel(ctx, wg, i.GetName(), outputLocation.Address(), outputLocation.GetName(), outputLocation.GetOnDisk(), outChan.Reader, readerCount)
+				var err error
+				if outputLocation.GetShuffle() != nil {
+					err = netchan.DialShuffleWriteChannel(ctx, wg, i.GetName(), outputLocation.Address(), outputLocation.GetName(), outputLocation.GetShuffle(), compression, outChan.Reader)
+				} else {
+					err = netchan.DialWriteChannel(ctx, wg, i.GetName(), outputLocation.Address(), outputLocation.GetName(), outputLocation.GetOnDisk(), compression, outChan.Reader, readerCount)
+				}
 				if err != nil {
 					ioErrChan <- fmt.Errorf("Failed %s writing %s to %s: %v", i.GetName(), outputLocation.GetName(), outputLocation.Address(), err)
 				}
 			}(outputLocation)
-			writers = append(writers, outChan.Writer)
+			writers = append(writers, &countingWriter{outChan.Writer, &stat.OutputShardBytes[k]})
 		}
 	}
 	return
@@ -163,8 +175,8 @@ func (exe *Executor) executeInstruction(ctx context.Context, wg *sync.WaitGroup,
 
 	defer wg.Done()
 
-	readers := setupReaders(ctx, wg, ioErrChan, i, inChan, isFirst)
-	writers := setupWriters(ctx, wg, ioErrChan, i, outChan, isLast, readerCount)
+	readers := setupReaders(ctx, wg, ioErrChan, i, inChan, isFirst, is.GetCompression())
+	writers := setupWriters(ctx, wg, ioErrChan, i, outChan, isLast, readerCount, is.GetCompression(), stat)
 
 	defer func() {
 		for _, writer := range writers {
@@ -235,3 +247,16 @@ func (exe *Executor) executeInstruction(ctx context.Context, wg *sync.WaitGroup,
 	})
 
 }
+
+// countingWriter counts the bytes written to an output shard,
+// so the driver can adapt the later steps to the actual shard sizes.
+type countingWriter struct {
+	*io.PipeWriter
+	count *int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.PipeWriter.Write(p)
+	atomic.AddInt64(w.count, int64(n))
+	return n, err
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -74,10 +75,8 @@ func (exe *Executor) reportStatus() {
 }
 
 func withClient(server string, fn func(client pb.GleamAgentClient) error) error {
-	grpcConnection, err := grpc.Dial(server,
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
-	)
+	options := append(security.GrpcDialOptions(security.AgentCredentials()), grpc.WithBlock())
+	grpcConnection, err := grpc.Dial(server, options...)
 	if err != nil {
 		return fmt.Errorf("executor dial agent: %v", err)
 	}
@@ -18,6 +18,7 @@ import (
 	exe "github.com/chrislusf/gleam/distributed/executor"
 	m "github.com/chrislusf/gleam/distributed/master"
 	"github.com/chrislusf/gleam/distributed/netchan"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"github.com/chrislusf/gleam/util"
 	"github.com/chrislusf/gleam/util/on_interrupt"
@@ -27,46 +28,106 @@ import (
 var (
 	app = kingpin.New("gleam", "distributed gleam, acts as master, agent, or executor")
 
-	master        = app.Command("master", "Start a master process")
-	masterAddress = master.Flag("address", "listening address host:port").Default(":45326").String()
-	masterLogDir  = master.Flag("logDirectory", "a directory to store execution logs").Default(os.TempDir()).String()
+	tlsOption = &security.TLSOption{}
+
+	master       = app.Command("master", "Start a master process")
+	masterOption = &m.MasterOption{
+		Address:            master.Flag("address", "listening address host:port").Default(":45326").String(),
+		LogDirectory:       master.Flag("logDirectory", "a directory to store the job statuses and allocations").Default(os.TempDir()).String(),
+		AuthTokenFile:      master.Flag("auth.tokens", "a file of \"token username\" lines to authenticate").Default("").String(),
+		AuthHtpasswdFile:   master.Flag("auth.htpasswd", "an htpasswd file with bcrypt or SHA1 passwords to authenticate").Default("").String(),
+		AuthPermissionFile: master.Flag("auth.permissions", "a file of \"username: submit,view,cancel,agent,peer\" lines, \"*\" for other users").Default("").String(),
+		QueueFile:          master.Flag("queues", "a file of \"name guaranteedPercent maxPercent [user1,user2]\" lines to share the cluster").Default("").String(),
+		Peer:               master.Flag("peer", "the other master address, to run as active and standby masters").Default("").String(),
+		PeerToken:          master.Flag("peer.token", "token to authenticate to the peer master").Default("").String(),
+		AgentToken:         master.Flag("agent.token", "token to authenticate to the agents").Default("").String(),
+		HistoryMaxAge:      master.Flag("history.maxAge", "how long to keep completed jobs, 0 to keep forever").Default("168h").Duration(),
+		HistoryMaxJobs:     master.Flag("history.maxJobs", "max number of completed jobs to keep, 0 for no limit").Default("10000").Int(),
+	}
 
 	executor     = app.Command("execute", "Execute an instruction set")
 	executorNote = executor.Flag("note", "description").String()
 
 	agent       = app.Command("agent", "Agent that can accept read, write requests, manage executors")
 	agentOption = &a.AgentServerOption{
-		Dir:          agent.Flag("dir", "agent folder to store computed data").Default(os.TempDir()).String(),
-		Host:         agent.Flag("host", "agent listening host address. Required in 2-way SSL mode.").Default("localhost").String(),
-		Port:         agent.Flag("port", "agent listening port").Default("45327").Int32(),
-		Master:       agent.Flag("master", "master address").Default("localhost:45326").String(),
-		DataCenter:   agent.Flag("dataCenter", "data center name").Default("defaultDataCenter").String(),
-		Rack:         agent.Flag("rack", "rack name").Default("defaultRack").String(),
-		MaxExecutor:  agent.Flag("executor.max", "upper limit of executors").Default(strconv.Itoa(runtime.NumCPU())).Int32(),
-		CPULevel:     agent.Flag("executor.cpu.level", "relative computing power of single cpu core").Default("1").Int32(),
-		MemoryMB:     agent.Flag("memory", "memory limit in MB").Default("1024").Int64(),
-		CleanRestart: agent.Flag("clean.restart", "clean up previous dataset files").Default("true").Bool(),
+		Dir:                agent.Flag("dir", "agent folder to store computed data").Default(os.TempDir()).String(),
+		Host:               agent.Flag("host", "agent listening host address. Required in 2-way SSL mode.").Default("localhost").String(),
+		Port:               agent.Flag("port", "agent listening port").Default("45327").Int32(),
+		Master:             agent.Flag("master", "master address, or comma separated active and standby master addresses").Default("localhost:45326").String(),
+		DataCenter:         agent.Flag("dataCenter", "data center name").Default("defaultDataCenter").String(),
+		Rack:               agent.Flag("rack", "rack name").Default("defaultRack").String(),
+		MaxExecutor:        agent.Flag("executor.max", "upper limit of executors").Default(strconv.Itoa(runtime.NumCPU())).Int32(),
+		CPULevel:           agent.Flag("executor.cpu.level", "relative computing power of single cpu core").Default("1").Int32(),
+		MemoryMB:           agent.Flag("memory", "memory limit in MB").Default("1024").Int64(),
+		CleanRestart:       agent.Flag("clean.restart", "clean up previous dataset files").Default("true").Bool(),
+		TLSOption:          tlsOption,
+		MasterToken:        agent.Flag("master.token", "token to authenticate to the master and other agents").Default("").String(),
+		AuthTokenFile:      agent.Flag("auth.tokens", "a file of \"token username\" lines to authenticate").Default("").String(),
+		AuthHtpasswdFile:   agent.Flag("auth.htpasswd", "an htpasswd file with bcrypt or SHA1 passwords to authenticate").Default("").String(),
+		AuthPermissionFile: agent.Flag("auth.permissions", "a file of \"username: submit,agent\" lines, \"*\" for other users").Default("").String(),
+		CgroupDir:          agent.Flag("executor.cgroup", "a cgroup v2 folder, e.g. /sys/fs/cgroup/gleam, to limit the memory and cpu of each executor").Default("").String(),
+		LogMaxMB:           agent.Flag("log.maxMB", "size in MB of an executor log file before it is rotated").Default("10").Int64(),
+		LogMaxAge:          agent.Flag("log.maxAge", "how long to keep the executor logs of a job").Default("72h").Duration(),
+		DiskMaxMB:          agent.Flag("disk.max", "disk limit in MB for the dataset files in --dir, 0 to use all free disk space").Default("0").Int64(),
+		MigrateCompression: agent.Flag("migrate.compression", "\"none\", \"snappy\" or \"flate\" to move dataset shards to other agents when draining, which need to support compressed transfers").Default("snappy").String(),
 	}
 	cpuProfile = agent.Flag("cpuprofile", "cpu profile output file").Default("").String()
 
+	agentRunner  = agent.Command("run", "Start an agent").Default()
+	agentDrainer = agent.Command("drain", "Stop allocating to the agent at --host:--port, wait for its executors, and remove it from the master")
+	drainMigrate = agentDrainer.Flag("migrate", "move on disk dataset shards of running jobs to other agents").Default("false").Bool()
+
 	writer             = app.Command("write", "Write data to a topic, input from console")
 	writeTopic         = writer.Flag("topic", "Name of a topic").Required().String()
 	writerAgentAddress = writer.Flag("agent", "agent host:port").Default("localhost:45327").String()
 	writeToDisk        = writer.Flag("onDisk", "write to memory").Default("false").Bool()
 
+	canceler          = app.Command("cancel", "Cancel a running job")
+	cancelJobId       = canceler.Flag("job", "job id, as shown on the master").Required().Uint32()
+	cancelMaster      = canceler.Flag("master", "master address, or comma separated active and standby master addresses").Default("localhost:45326").String()
+	cancelMasterToken = canceler.Flag("master.token", "token to authenticate to the master").Default("").String()
+
+	logger          = app.Command("logs", "Show the executor logs of a job")
+	logsJobId       = logger.Flag("job", "job id, as shown on the master").Required().Uint32()
+	logsStepIds     = logger.Flag("step", "only the task groups running the step, can be repeated").Int32List()
+	logsTail        = logger.Flag("tail", "show only the last bytes of each log, 0 for all up to 3MB in total").Default("0").Int64()
+	logsMaster      = logger.Flag("master", "master address, or comma separated active and standby master addresses").Default("localhost:45326").String()
+	logsMasterToken = logger.Flag("master.token", "token to authenticate to the master").Default("").String()
+
+	inspector          = app.Command("inspect", "Show the rows of a dataset of a job")
+	inspectJobId       = inspector.Flag("job", "job id, as shown on the master").Required().Uint32()
+	inspectDatasetId   = inspector.Flag("dataset", "dataset id, as d<id> in the dataset shard names").Required().Int32()
+	inspectLimit       = inspector.Flag("limit", "number of rows to show").Default("10").Int()
+	inspectSample      = inspector.Flag("sample", "show a random sample of all rows instead of the first rows").Default("false").Bool()
+	inspectTimeout     = inspector.Flag("timeout", "time limit to read each dataset shard").Default("1m").Duration()
+	inspectMaster      = inspector.Flag("master", "master address, or comma separated active and standby master addresses").Default("localhost:45326").String()
+	inspectMasterToken = inspector.Flag("master.token", "token to authenticate to the master and agents").Default("").String()
+
 	reader             = app.Command("read", "Read data from a topic, output to console")
 	readTopic          = reader.Flag("topic", "Name of a source topic").Required().String()
 	readerAgentAddress = reader.Flag("agent", "agent host:port").Default("localhost:45327").String()
 	readFromDisk       = reader.Flag("onDisk", "read from memory").Default("false").Bool()
 )
 
+func init() {
+	app.Flag("tls.cert", "certificate file to enable mutual TLS").StringVar(&tlsOption.CertFile)
+	app.Flag("tls.key", "private key file of the certificate").StringVar(&tlsOption.KeyFile)
+	app.Flag("tls.ca", "CA certificate file to verify the peers").StringVar(&tlsOption.CAFile)
+}
+
 func main() {
 
-	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	command := kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	if err := security.EnableTLS(tlsOption); err != nil {
+		log.Fatalf("Failed to enable TLS: %v", err)
+	}
+
+	switch command {
 
 	case master.FullCommand():
-		println("master listening on", *masterAddress)
-		m.RunMaster(*masterAddress, *masterLogDir)
+		println("master listening on", *masterOption.Address)
+		m.RunMaster(masterOption)
 
 	case executor.FullCommand():
 
@@ -92,6 +153,14 @@ func main() {
 			defer pprof.StopCPUProfile()
 		}
 
+		if authorization := os.Getenv(security.AuthorizationEnv); authorization != "" {
+			creds, err := security.ParseAuthorization(authorization)
+			if err != nil {
+				log.Fatalf("Failed to read the credentials: %v", err)
+			}
+			security.SetAgentCredentials(creds)
+		}
+
 		if err := exe.NewExecutor(&exe.ExecutorOption{
 			AgentAddress: instructionSet.AgentAddress,
 		}, &instructionSet).ExecuteInstructionSet(); err != nil {
@@ -103,7 +172,7 @@ func main() {
 		inChan := util.NewPiper()
 		var wg sync.WaitGroup
 		wg.Add(1)
-		go netchan.DialWriteChannel(context.Background(), &wg, "stdin", *writerAgentAddress, *writeTopic, *writeToDisk, inChan.Reader, 1)
+		go netchan.DialWriteChannel(context.Background(), &wg, "stdin", *writerAgentAddress, *writeTopic, *writeToDisk, "", inChan.Reader, 1)
 		wg.Add(1)
 		go util.LineReaderToChannel(&wg, &pb.InstructionStat{}, "stdin", os.Stdin, inChan.Writer, true, os.Stderr)
 		wg.Wait()
@@ -113,12 +182,58 @@ func main() {
 		outChan := util.NewPiper()
 		var wg sync.WaitGroup
 		wg.Add(1)
-		go netchan.DialReadChannel(context.Background(), &wg, "stdout", *readerAgentAddress, *readTopic, *readFromDisk, outChan.Writer)
+		go netchan.DialReadChannel(context.Background(), &wg, "stdout", *readerAgentAddress, *readTopic, *readFromDisk, "", outChan.Writer)
 		wg.Add(1)
 		util.ChannelToLineWriter(&wg, &pb.InstructionStat{}, "stdout", outChan.Reader, os.Stdout, os.Stderr)
 		wg.Wait()
 
-	case agent.FullCommand():
+	case canceler.FullCommand():
+
+		if err := cancelJob(*cancelMaster, *cancelMasterToken, *cancelJobId); err != nil {
+			log.Fatalf("Failed to cancel job %d: %v", *cancelJobId, err)
+		}
+		println("cancelled job", *cancelJobId)
+
+	case logger.FullCommand():
+
+		taskLogs, err := jobLogs(*logsMaster, *logsMasterToken, *logsJobId, *logsStepIds, *logsTail)
+		if err != nil {
+			log.Fatalf("Failed to get logs of job %d: %v", *logsJobId, err)
+		}
+		for _, t := range taskLogs {
+			fmt.Printf("==> %s on %s <==\n", t.GetName(), t.GetAgent())
+			if t.GetError() != "" {
+				fmt.Printf("%s\n\n", t.GetError())
+				continue
+			}
+			os.Stdout.Write(t.GetContent())
+			fmt.Println()
+		}
+
+	case inspector.FullCommand():
+
+		security.SetAgentCredentials(&security.Credentials{Token: *inspectMasterToken})
+		locations, err := datasetLocations(*inspectMaster, *inspectMasterToken, *inspectJobId, *inspectDatasetId)
+		if err != nil {
+			log.Fatalf("Failed to locate dataset %d of job %d: %v", *inspectDatasetId, *inspectJobId, err)
+		}
+		if err := inspectDataset(locations, *inspectLimit, *inspectSample, *inspectTimeout, os.Stdout); err != nil {
+			log.Fatalf("Failed to inspect dataset %d of job %d: %v", *inspectDatasetId, *inspectJobId, err)
+		}
+
+	case agentDrainer.FullCommand():
+
+		agentAddress := fmt.Sprintf("%s:%d", *agentOption.Host, *agentOption.Port)
+		migrated, err := drainAgent(*agentOption.Master, *agentOption.MasterToken, agentAddress, *drainMigrate)
+		if err != nil {
+			log.Fatalf("Failed to drain agent %s: %v", agentAddress, err)
+		}
+		for _, location := range migrated {
+			println("moved", location.GetName(), "to", location.GetLocation().URL())
+		}
+		println("drained agent", agentAddress)
+
+	case agentRunner.FullCommand():
 
 		if *cpuProfile != "" {
 			f, err := os.Create(*cpuProfile)
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/netchan"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+var errEnoughRows = errors.New("enough rows")
+
+// inspectDataset prints the first rows of the dataset shards, or a random
+// sample of all rows, which reads the dataset shards completely. Reading each
+// dataset shard fails after the timeout.
+func inspectDataset(locations []*pb.DatasetShardLocation, limit int, sample bool, timeout time.Duration, writer io.Writer) error {
+	for _, location := range locations {
+		if !location.GetOnDisk() && location.GetShuffle() == nil {
+			return fmt.Errorf("%s is in memory, only datasets on disk can be inspected", location.GetName())
+		}
+	}
+
+	var rows []*util.Row
+	var count int
+	for _, location := range locations {
+		if !sample && len(rows) >= limit {
+			break
+		}
+		err := readDatasetShard(location, timeout, func(row *util.Row) error {
+			count++
+			if !sample {
+				rows = append(rows, row)
+				if len(rows) >= limit {
+					return errEnoughRows
+				}
+				return nil
+			}
+			// reservoir sampling, each row is kept with the same probability
+			if len(rows) < limit {
+				rows = append(rows, row)
+			} else if i := rand.Intn(count); i < limit {
+				rows[i] = row
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("read %s from %s: %v", location.GetName(), location.Address(), err)
+		}
+	}
+
+	for _, row := range rows {
+		if err := util.FprintRow(writer, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDatasetShard(location *pb.DatasetShardLocation, timeout time.Duration, fn func(*util.Row) error) error {
+	reader, err := netchan.OpenInspectChannel("inspect", location.Address(), location.GetName(), location.GetShuffle(), "none")
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// the streams have no deadlines, closing the reader stops a stuck read
+	timedOut := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() {
+		close(timedOut)
+		reader.Close()
+	})
+	defer timer.Stop()
+
+	err = util.ProcessMessage(reader, func(encodedBytes []byte) error {
+		row, err := util.DecodeRow(encodedBytes)
+		if err != nil {
+			return err
+		}
+		return fn(row)
+	})
+	select {
+	case <-timedOut:
+		return fmt.Errorf("timed out after %v", timeout)
+	default:
+	}
+	if err == errEnoughRows {
+		return nil
+	}
+	return err
+}
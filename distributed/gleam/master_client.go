@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// withMasterClient calls fn with the masters in turn, until one is not a standby master.
+func withMasterClient(masters, token string, fn func(client pb.GleamMasterClient) error) (err error) {
+	for _, master := range strings.Split(masters, ",") {
+		master = strings.TrimSpace(master)
+		grpcConnection, dialErr := grpc.Dial(master, security.GrpcDialOptions(&security.Credentials{Token: token})...)
+		if dialErr != nil {
+			err = fmt.Errorf("fail to dial %s: %v", master, dialErr)
+			continue
+		}
+		err = fn(pb.NewGleamMasterClient(grpcConnection))
+		grpcConnection.Close()
+		if grpc.Code(err) != codes.Unavailable {
+			return err
+		}
+	}
+	return err
+}
+
+func cancelJob(masters, token string, id uint32) error {
+	return withMasterClient(masters, token, func(client pb.GleamMasterClient) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, err := client.CancelFlow(ctx, &pb.CancelFlowRequest{Id: id})
+		return err
+	})
+}
+
+// drainAgent waits until the agent is drained, so there is no timeout.
+func drainAgent(masters, token, agent string, migrate bool) (migrated []*pb.DataLocation, err error) {
+	err = withMasterClient(masters, token, func(client pb.GleamMasterClient) error {
+		response, err := client.DrainAgent(context.Background(), &pb.DrainAgentRequest{
+			Agent:   agent,
+			Migrate: migrate,
+		})
+		migrated = response.GetMigratedShards()
+		return err
+	})
+	return
+}
+
+func jobLogs(masters, token string, id uint32, stepIds []int32, tailBytes int64) (taskLogs []*pb.TaskLogs, err error) {
+	err = withMasterClient(masters, token, func(client pb.GleamMasterClient) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		response, err := client.GetJobLogs(ctx, &pb.JobLogsRequest{
+			Id:        id,
+			StepIds:   stepIds,
+			TailBytes: tailBytes,
+		})
+		taskLogs = response.GetTaskLogs()
+		return err
+	})
+	return
+}
+
+func datasetLocations(masters, token string, id uint32, datasetId int32) (locations []*pb.DatasetShardLocation, err error) {
+	err = withMasterClient(masters, token, func(client pb.GleamMasterClient) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		response, err := client.GetDatasetLocations(ctx, &pb.DatasetLocationsRequest{
+			Id:        id,
+			DatasetId: datasetId,
+		})
+		locations = response.GetLocations()
+		return err
+	})
+	return
+}
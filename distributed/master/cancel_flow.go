@@ -0,0 +1,132 @@
+package master
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// CancelFlow stops a running flow. The agents kill the executors of the flow
+// and remove its data, and the driver is told to stop when it reports the status.
+func (s *MasterServer) CancelFlow(ctx context.Context, in *pb.CancelFlowRequest) (*pb.Empty, error) {
+	status := s.findJob(in.GetId())
+	if status == nil {
+		return nil, grpc.Errorf(codes.NotFound, "job %d not found", in.GetId())
+	}
+	if !s.auth.CanAccessJob(security.UsernameFromContext(ctx), security.PermissionCancel, status) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "no permission to cancel job %d", in.GetId())
+	}
+	if status.GetDriver().GetStopTime() != 0 {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "job %d has already stopped", in.GetId())
+	}
+	s.cancelFlow(status)
+	return &pb.Empty{}, nil
+}
+
+func (s *MasterServer) jobCancelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "expecting POST", http.StatusMethodNotAllowed)
+		return
+	}
+	// browsers only send custom headers from the same origin, unlike plain form posts
+	if r.Header.Get("X-Requested-With") == "" {
+		http.Error(w, "expecting the X-Requested-With header", http.StatusForbidden)
+		return
+	}
+	vars := mux.Vars(r)
+	jobId, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "invalid job id "+vars["id"], http.StatusBadRequest)
+		return
+	}
+	_, err = s.CancelFlow(r.Context(), &pb.CancelFlowRequest{Id: uint32(jobId)})
+	switch grpc.Code(err) {
+	case codes.OK:
+		http.Redirect(w, r, "/job/"+vars["id"], http.StatusSeeOther)
+	case codes.NotFound:
+		http.Error(w, grpc.ErrorDesc(err), http.StatusNotFound)
+	case codes.PermissionDenied:
+		http.Error(w, grpc.ErrorDesc(err), http.StatusForbidden)
+	default:
+		http.Error(w, grpc.ErrorDesc(err), http.StatusConflict)
+	}
+}
+
+func (s *MasterServer) cancelFlow(status *pb.FlowExecutionStatus) {
+	flowHashCode := status.GetId()
+	log.Printf("cancelling job %d", flowHashCode)
+
+	s.cancelledLock.Lock()
+	s.cancelledFlows[flowHashCode] = true
+	s.cancelledLock.Unlock()
+
+	var wg sync.WaitGroup
+	for _, agent := range s.flowAgents(status) {
+		wg.Add(1)
+		go func(agent string) {
+			defer wg.Done()
+			err := withAgentClient(agent, func(client pb.GleamAgentClient) error {
+				_, err := client.Cleanup(context.Background(), &pb.CleanupRequest{FlowHashCode: flowHashCode})
+				return err
+			})
+			if err != nil {
+				log.Printf("Failed to cancel job %d on %s: %v", flowHashCode, agent, err)
+			}
+		}(agent)
+	}
+	wg.Wait()
+}
+
+// isCancelled checks whether the driver should stop the flow.
+// The mark is removed after the driver reports the flow has stopped.
+func (s *MasterServer) isCancelled(status *pb.FlowExecutionStatus) bool {
+	s.cancelledLock.Lock()
+	defer s.cancelledLock.Unlock()
+
+	if !s.cancelledFlows[status.GetId()] {
+		return false
+	}
+	if status.GetDriver().GetStopTime() != 0 {
+		delete(s.cancelledFlows, status.GetId())
+		return false
+	}
+	return true
+}
+
+// flowAgents returns the agents running the flow's task groups, or
+// reporting resources allocated to the flow.
+func (s *MasterServer) flowAgents(status *pb.FlowExecutionStatus) (agents []string) {
+	seen := make(map[string]bool)
+	add := func(agent string) {
+		if !seen[agent] {
+			seen[agent] = true
+			agents = append(agents, agent)
+		}
+	}
+	for _, tg := range status.GetTaskGroups() {
+		if location := tg.GetAllocation().GetLocation(); location != nil {
+			add(location.URL())
+		}
+	}
+
+	qm := s.queueManager
+	qm.Lock()
+	defer qm.Unlock()
+	if f, found := qm.flows[status.GetId()]; found {
+		for agent := range f.reported {
+			add(agent)
+		}
+		for _, g := range f.grants {
+			add(g.agent)
+		}
+	}
+	return
+}
@@ -0,0 +1,153 @@
+package master
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCancelFlow(t *testing.T) {
+	ms, closeStore := newTestHistory(t)
+	defer closeStore()
+
+	tests := []struct {
+		name string
+		id   uint32
+		code codes.Code
+	}{
+		{"missing job", 99, codes.NotFound},
+		{"stopped job", 1, codes.FailedPrecondition},
+		{"running job", 3, codes.OK},
+	}
+	for _, test := range tests {
+		if _, err := ms.CancelFlow(context.Background(), &pb.CancelFlowRequest{Id: test.id}); grpc.Code(err) != test.code {
+			t.Errorf("%s: cancelled with %v, expecting %v", test.name, err, test.code)
+		}
+	}
+
+	running := ms.findJob(3)
+	if !ms.isCancelled(running) {
+		t.Fatalf("the running job is not cancelled")
+	}
+	stopped := newTestJob(3, "alice", "join", time.Hour, time.Minute)
+	if ms.isCancelled(stopped) {
+		t.Errorf("the driver is told to stop after reporting the stop")
+	}
+	if ms.isCancelled(running) || len(ms.cancelledFlows) != 0 {
+		t.Errorf("the cancellation is kept after the driver reported the stop")
+	}
+}
+
+func TestJobCancelHandler(t *testing.T) {
+	ms, closeStore := newTestHistory(t)
+	defer closeStore()
+	ms.auth = newTestAuth(t, []string{"alice-token alice", "bob-token bob", "admin-token admin"},
+		[]string{"admin: cancel", "*: view"})
+
+	r := mux.NewRouter()
+	r.HandleFunc("/job/{id:[0-9]+}/cancel", ms.jobCancelHandler)
+	handler := ms.auth.HttpHandler(r)
+
+	tests := []struct {
+		name        string
+		method      string
+		token       string
+		id          string
+		isAjax      bool
+		code        int
+		isCancelled bool
+	}{
+		{"GET", "GET", "alice-token", "3", true, http.StatusMethodNotAllowed, false},
+		{"form post from another site", "POST", "alice-token", "3", false, http.StatusForbidden, false},
+		{"no permission", "POST", "bob-token", "3", true, http.StatusForbidden, false},
+		{"unauthenticated", "POST", "eve-token", "3", true, http.StatusUnauthorized, false},
+		{"stopped job", "POST", "alice-token", "1", true, http.StatusConflict, false},
+		{"missing job", "POST", "alice-token", "99", true, http.StatusNotFound, false},
+		{"owner", "POST", "alice-token", "3", true, http.StatusSeeOther, true},
+		{"granted permission", "POST", "admin-token", "3", true, http.StatusSeeOther, true},
+	}
+	for _, test := range tests {
+		ms.cancelledFlows = make(map[uint32]bool)
+		req := httptest.NewRequest(test.method, "/job/"+test.id+"/cancel", nil)
+		req.Header.Set("Authorization", "Bearer "+test.token)
+		if test.isAjax {
+			req.Header.Set("X-Requested-With", "XMLHttpRequest")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("%s: status %d %s, expecting %d", test.name, w.Code, strings.TrimSpace(w.Body.String()), test.code)
+		}
+		if isCancelled := ms.cancelledFlows[3]; isCancelled != test.isCancelled {
+			t.Errorf("%s: cancelled %v, expecting %v", test.name, isCancelled, test.isCancelled)
+		}
+	}
+}
+
+// newTestAuth authenticates the tokens, over TLS enabled with a self-signed certificate.
+func newTestAuth(t *testing.T, tokens, permissions []string) *security.Auth {
+	dir, err := ioutil.TempDir("", "gleam-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name string, lines []string) string {
+		fileName := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(fileName, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return fileName
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gleam test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	certData, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyData, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := write("cert.pem", []string{string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certData}))})
+	keyFile := write("key.pem", []string{string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyData}))})
+	if err := security.EnableTLS(&security.TLSOption{CertFile: certFile, KeyFile: keyFile, CAFile: certFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := security.NewAuth(write("tokens", tokens), "", write("permissions", permissions), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
@@ -0,0 +1,45 @@
+package master
+
+import (
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// GetCapacity sums up the resources of the agents not drained, for the
+// drivers to size the flows to the cluster.
+func (s *MasterServer) GetCapacity(ctx context.Context, in *pb.CapacityRequest) (*pb.CapacityResponse, error) {
+	var dataCenters []*DataCenter
+	if in.GetDataCenter() == "" {
+		for _, dc := range s.Topology.GetDataCenters() {
+			dataCenters = append(dataCenters, dc)
+		}
+	} else {
+		dc, found := s.Topology.GetDataCenter(in.GetDataCenter())
+		if !found {
+			return nil, grpc.Errorf(codes.NotFound, "data center %s not found", in.GetDataCenter())
+		}
+		dataCenters = append(dataCenters, dc)
+	}
+
+	s.Topology.RLock()
+	defer s.Topology.RUnlock()
+
+	var resource, allocated pb.ComputeResource
+	response := &pb.CapacityResponse{}
+	for _, dc := range dataCenters {
+		for _, rack := range dc.GetRacks() {
+			for _, ai := range rack.GetAgents() {
+				if ai.Draining {
+					continue
+				}
+				resource = resource.Plus(ai.Resource)
+				allocated = allocated.Plus(ai.Allocated)
+				response.AgentCount++
+			}
+		}
+	}
+	response.Resource, response.Allocated = &resource, &allocated
+	return response, nil
+}
@@ -0,0 +1,69 @@
+package master
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// GetDatasetLocations finds where the shards of a dataset are written or read,
+// from the instructions sent to the agents. Only a running job has them.
+func (s *MasterServer) GetDatasetLocations(ctx context.Context, in *pb.DatasetLocationsRequest) (*pb.DatasetLocationsResponse, error) {
+	status := s.findJob(in.GetId())
+	if status == nil {
+		return nil, grpc.Errorf(codes.NotFound, "job %d not found", in.GetId())
+	}
+	if !s.auth.CanAccessJob(security.UsernameFromContext(ctx), security.PermissionView, status) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "no permission to view job %d", in.GetId())
+	}
+	// the driver cleans up the dataset shards on the agents when the job stops
+	if status.GetDriver().GetStopTime() != 0 {
+		return nil, grpc.Errorf(codes.NotFound, "job %d has stopped, its dataset shards are cleaned up", in.GetId())
+	}
+
+	prefix := fmt.Sprintf("f%d-d%d-s", status.GetId(), in.GetDatasetId())
+	shards := make(map[int]*pb.DatasetShardLocation)
+	for _, tg := range status.GetTaskGroups() {
+		for _, instruction := range tg.GetRequest().GetInstructionSet().GetInstructions() {
+			for _, locations := range [][]*pb.DatasetShardLocation{
+				instruction.GetOutputShardLocations(),
+				instruction.GetInputShardLocations(),
+			} {
+				for _, location := range locations {
+					if !strings.HasPrefix(location.GetName(), prefix) {
+						continue
+					}
+					shardId, err := strconv.Atoi(strings.TrimPrefix(location.GetName(), prefix))
+					if err != nil {
+						continue
+					}
+					if _, found := shards[shardId]; !found {
+						shards[shardId] = location
+					}
+				}
+			}
+		}
+	}
+	if len(shards) == 0 {
+		return nil, grpc.Errorf(codes.NotFound, "no shards of dataset %d in job %d", in.GetDatasetId(), in.GetId())
+	}
+
+	var shardIds []int
+	for shardId := range shards {
+		shardIds = append(shardIds, shardId)
+	}
+	sort.Ints(shardIds)
+
+	response := &pb.DatasetLocationsResponse{}
+	for _, shardId := range shardIds {
+		response.Locations = append(response.Locations, shards[shardId])
+	}
+	return response, nil
+}
@@ -0,0 +1,96 @@
+package master
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// DrainAgent stops allocating to the agent, waits for its executors to finish,
+// and deregisters it from the topology. With migrate, the on disk dataset shards
+// of the running flows are moved to the other agents in the same data center,
+// and the drivers learn the new locations when requesting resources.
+// If the drain fails, the agent gets allocations again.
+func (s *MasterServer) DrainAgent(ctx context.Context, in *pb.DrainAgentRequest) (*pb.DrainAgentResponse, error) {
+	ai, found := s.Topology.findAgentByAddress(in.GetAgent())
+	if !found {
+		return nil, grpc.Errorf(codes.NotFound, "agent %s not found", in.GetAgent())
+	}
+	location := ai.Location
+
+	request := &pb.DrainRequest{Migrate: in.GetMigrate()}
+	if in.GetMigrate() {
+		for _, target := range s.Topology.agentLocations(location.DataCenter) {
+			if target.URL() != location.URL() {
+				request.Targets = append(request.Targets, target)
+			}
+		}
+		if len(request.Targets) == 0 {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "no other agent in %s to migrate to", location.DataCenter)
+		}
+		request.FlowHashCodes = s.runningFlows()
+	}
+
+	s.Topology.setDraining(ai, true)
+	log.Printf("draining agent %s", location.URL())
+
+	var response *pb.DrainResponse
+	err := withAgentClient(location.URL(), func(client pb.GleamAgentClient) (err error) {
+		response, err = client.Drain(ctx, request)
+		return err
+	})
+	if err != nil {
+		s.Topology.setDraining(ai, false)
+		return nil, grpc.Errorf(codes.Internal, "drain agent %s: %v", location.URL(), grpc.ErrorDesc(err))
+	}
+
+	s.addMigratedShards(response.GetMigratedShards())
+	s.Topology.deleteAgentInformation(&location)
+	s.queueManager.removeAgent(&location)
+	log.Printf("drained agent %s, migrated %d dataset shards", location.URL(), len(response.GetMigratedShards()))
+
+	return &pb.DrainAgentResponse{MigratedShards: response.GetMigratedShards()}, nil
+}
+
+// runningFlows lists the flows not stopped yet.
+func (s *MasterServer) runningFlows() (ret []uint32) {
+	for _, key := range s.statusCache.Keys() {
+		if status, ok := s.statusCache.Peek(key); ok && status.(*pb.FlowExecutionStatus).GetDriver().GetStopTime() == 0 {
+			ret = append(ret, key.(uint32))
+		}
+	}
+	return
+}
+
+func (s *MasterServer) addMigratedShards(locations []*pb.DataLocation) {
+	s.migratedLock.Lock()
+	defer s.migratedLock.Unlock()
+
+	for _, location := range locations {
+		var flowHashCode uint32
+		if _, err := fmt.Sscanf(location.GetName(), "f%d-", &flowHashCode); err != nil {
+			log.Printf("Unexpected dataset shard name %s: %v", location.GetName(), err)
+			continue
+		}
+		s.migratedShards[flowHashCode] = append(s.migratedShards[flowHashCode], location)
+	}
+}
+
+// getMigratedShards returns the new locations of the flow's dataset shards.
+func (s *MasterServer) getMigratedShards(flowHashCode uint32) []*pb.DataLocation {
+	s.migratedLock.Lock()
+	defer s.migratedLock.Unlock()
+
+	return s.migratedShards[flowHashCode]
+}
+
+func (s *MasterServer) forgetMigratedShards(flowHashCode uint32) {
+	s.migratedLock.Lock()
+	defer s.migratedLock.Unlock()
+
+	delete(s.migratedShards, flowHashCode)
+}
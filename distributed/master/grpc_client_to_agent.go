@@ -0,0 +1,24 @@
+package master
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"google.golang.org/grpc"
+)
+
+func withAgentClient(server string, fn func(client pb.GleamAgentClient) error) error {
+	grpcConnection, err := grpc.Dial(server, security.GrpcDialOptions(security.AgentCredentials())...)
+	if err != nil {
+		return fmt.Errorf("master dial agent: %v", err)
+	}
+	defer func() {
+		time.Sleep(50 * time.Millisecond)
+		grpcConnection.Close()
+	}()
+	client := pb.NewGleamAgentClient(grpcConnection)
+
+	return fn(client)
+}
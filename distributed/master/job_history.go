@@ -0,0 +1,451 @@
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/gorilla/mux"
+)
+
+const (
+	historyPurgeInterval = 10 * time.Minute
+	defaultJobListLimit  = 100
+)
+
+type jobSummary struct {
+	Id         uint32     `json:"id"`
+	Name       string     `json:"name,omitempty"`
+	Username   string     `json:"user,omitempty"`
+	Hostname   string     `json:"host,omitempty"`
+	Executable string     `json:"executable,omitempty"`
+	State      string     `json:"state"`
+	StartTime  time.Time  `json:"startTime"`
+	StopTime   *time.Time `json:"stopTime,omitempty"`
+	DurationMs int64      `json:"durationMs"`
+}
+
+type stepSummary struct {
+	Id          int32               `json:"id"`
+	Name        string              `json:"name"`
+	TaskCount   int                 `json:"tasks"`
+	StartTime   *time.Time          `json:"startTime,omitempty"`
+	StopTime    *time.Time          `json:"stopTime,omitempty"`
+	DurationMs  int64               `json:"durationMs"`
+	InputCount  int64               `json:"inputCount"`
+	OutputCount int64               `json:"outputCount"`
+	Counters    map[string]int64    `json:"counters,omitempty"`
+	Histograms  []*histogramSummary `json:"histograms,omitempty"`
+	Errors      []string            `json:"errors,omitempty"`
+}
+
+type histogramSummary struct {
+	Name  string  `json:"name"`
+	Count int64   `json:"count"`
+	Min   float64 `json:"min"`
+	Mean  float64 `json:"mean"`
+	Max   float64 `json:"max"`
+}
+
+type executionSummary struct {
+	StartTime  *time.Time     `json:"startTime,omitempty"`
+	StopTime   *time.Time     `json:"stopTime,omitempty"`
+	DurationMs int64          `json:"durationMs"`
+	UserTime   float64        `json:"userTime,omitempty"`
+	SystemTime float64        `json:"systemTime,omitempty"`
+	Stats      []*taskSummary `json:"stats,omitempty"`
+	Stderr     string         `json:"stderr,omitempty"`
+}
+
+type taskSummary struct {
+	StepId      int32               `json:"stepId"`
+	TaskId      int32               `json:"taskId"`
+	InputCount  int64               `json:"inputCount"`
+	OutputCount int64               `json:"outputCount"`
+	Counters    map[string]int64    `json:"counters,omitempty"`
+	Histograms  []*histogramSummary `json:"histograms,omitempty"`
+}
+
+type taskGroupSummary struct {
+	Id         int                 `json:"id"`
+	Name       string              `json:"name,omitempty"`
+	StepIds    []int32             `json:"stepIds"`
+	State      string              `json:"state"`
+	Agent      string              `json:"agent,omitempty"`
+	MemoryMb   int64               `json:"memoryMb,omitempty"`
+	Executions []*executionSummary `json:"executions"`
+}
+
+type jobDetail struct {
+	jobSummary
+	Steps      []*stepSummary      `json:"steps"`
+	TaskGroups []*taskGroupSummary `json:"taskGroups"`
+}
+
+// apiJobsHandler lists the recent and completed jobs, newest first, filtered by
+// "user", "name" substring, "state", and "since", which can be a duration like 24h,
+// a RFC3339 time, or unix seconds. "limit" defaults to 100.
+func (ms *MasterServer) apiJobsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var since time.Time
+	if s := query.Get("since"); s != "" {
+		var err error
+		if since, err = parseSince(s, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	limit := defaultJobListLimit
+	if l := query.Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+			http.Error(w, "invalid limit "+l, http.StatusBadRequest)
+			return
+		}
+	}
+
+	username := security.UsernameFromContext(r.Context())
+	var jobs []*jobSummary
+	for _, job := range ms.allJobSummaries() {
+		if !ms.auth.CanAccessJobOf(username, security.PermissionView, job.Username) {
+			continue
+		}
+		if u := query.Get("user"); u != "" && job.Username != u {
+			continue
+		}
+		if n := query.Get("name"); n != "" && !strings.Contains(job.Name, n) {
+			continue
+		}
+		if s := query.Get("state"); s != "" && job.State != s {
+			continue
+		}
+		if !since.IsZero() && job.StartTime.Before(since) {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartTime.After(jobs[j].StartTime)
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	if jobs == nil {
+		jobs = []*jobSummary{}
+	}
+	writeJson(w, jobs)
+}
+
+// apiJobHandler shows one job with the timings and counters of each step.
+func (ms *MasterServer) apiJobHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	jobId, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "invalid job id "+vars["id"], http.StatusBadRequest)
+		return
+	}
+	status := ms.findJob(uint32(jobId))
+	if status == nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	if !ms.auth.CanAccessJob(security.UsernameFromContext(r.Context()), security.PermissionView, status) {
+		http.Error(w, "no permission to view this job", http.StatusForbidden)
+		return
+	}
+	writeJson(w, newJobDetail(status))
+}
+
+// findJob looks up the recent jobs first, then the job history.
+func (ms *MasterServer) findJob(id uint32) *pb.FlowExecutionStatus {
+	if status, ok := ms.statusCache.Get(id); ok {
+		return status.(*pb.FlowExecutionStatus)
+	}
+	status, err := ms.store.GetHistoryJob(id)
+	if err != nil {
+		log.Printf("Failed to read job %d from history: %v", id, err)
+	}
+	return status
+}
+
+// allJobSummaries merges the job history with the recent jobs, which may still be running.
+func (ms *MasterServer) allJobSummaries() map[uint32]*jobSummary {
+	jobs := make(map[uint32]*jobSummary)
+	ms.historyLock.RLock()
+	for id, job := range ms.historyIndex {
+		jobs[id] = job
+	}
+	ms.historyLock.RUnlock()
+	for _, key := range ms.statusCache.Keys() {
+		if status, ok := ms.statusCache.Peek(key); ok {
+			jobs[key.(uint32)] = newJobSummary(status.(*pb.FlowExecutionStatus))
+		}
+	}
+	return jobs
+}
+
+// loadHistoryIndex reads the job history once, to list the jobs without reading it again.
+func (ms *MasterServer) loadHistoryIndex() {
+	index := make(map[uint32]*jobSummary)
+	if err := ms.store.ForEachHistoryJob(func(status *pb.FlowExecutionStatus) {
+		index[status.GetId()] = newJobSummary(status)
+	}); err != nil {
+		log.Printf("Failed to read job history: %v", err)
+	}
+	ms.historyLock.Lock()
+	ms.historyIndex = index
+	ms.historyLock.Unlock()
+}
+
+// addToHistoryIndex indexes a job just added to the history.
+func (ms *MasterServer) addToHistoryIndex(status *pb.FlowExecutionStatus) {
+	ms.historyLock.Lock()
+	ms.historyIndex[status.GetId()] = newJobSummary(status)
+	ms.historyLock.Unlock()
+}
+
+// purgeHistory removes the jobs beyond the retention limits periodically.
+func (ms *MasterServer) purgeHistory(maxAge time.Duration, maxJobs int) {
+	for {
+		time.Sleep(historyPurgeInterval)
+		if ms.isActive() {
+			ms.purgeHistoryOnce(maxAge, maxJobs, time.Now())
+		}
+	}
+}
+
+func (ms *MasterServer) purgeHistoryOnce(maxAge time.Duration, maxJobs int, now time.Time) {
+	var cutoff time.Time
+	if maxAge > 0 {
+		cutoff = now.Add(-maxAge)
+	}
+	ids := ms.expiredHistoryJobs(cutoff, maxJobs)
+	if len(ids) == 0 {
+		return
+	}
+	if err := ms.store.DeleteHistoryJobs(ids); err != nil {
+		log.Printf("Failed to purge job history: %v", err)
+		return
+	}
+	ms.historyLock.Lock()
+	for _, id := range ids {
+		delete(ms.historyIndex, id)
+	}
+	ms.historyLock.Unlock()
+	log.Printf("purged %d jobs from history", len(ids))
+}
+
+// expiredHistoryJobs returns the completed jobs stopped before the cutoff time,
+// and the oldest jobs beyond maxJobs. Zero values disable the limits.
+func (ms *MasterServer) expiredHistoryJobs(cutoff time.Time, maxJobs int) (ids []uint32) {
+	ms.historyLock.RLock()
+	jobs := make([]*jobSummary, 0, len(ms.historyIndex))
+	for _, job := range ms.historyIndex {
+		jobs = append(jobs, job)
+	}
+	ms.historyLock.RUnlock()
+
+	stopTime := func(job *jobSummary) time.Time {
+		if job.StopTime == nil {
+			return job.StartTime
+		}
+		return *job.StopTime
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return stopTime(jobs[i]).After(stopTime(jobs[j]))
+	})
+	for i, job := range jobs {
+		expired := !cutoff.IsZero() && stopTime(job).Before(cutoff)
+		if expired || (maxJobs > 0 && i >= maxJobs) {
+			ids = append(ids, job.Id)
+		}
+	}
+	return
+}
+
+func newJobSummary(status *pb.FlowExecutionStatus) *jobSummary {
+	driver := status.GetDriver()
+	job := &jobSummary{
+		Id:         status.GetId(),
+		Name:       driver.GetName(),
+		Username:   driver.GetUsername(),
+		Hostname:   driver.GetHostname(),
+		Executable: driver.GetExecutable(),
+		State:      "running",
+		StartTime:  time.Unix(0, driver.GetStartTime()),
+	}
+	stopTime := driver.GetStopTime()
+	if stopTime == 0 {
+		job.DurationMs = int64(time.Since(job.StartTime) / time.Millisecond)
+		return job
+	}
+	job.StopTime = timeOf(stopTime)
+	job.DurationMs = (stopTime - driver.GetStartTime()) / int64(time.Millisecond)
+	job.State = "completed"
+	for _, tg := range status.GetTaskGroups() {
+		if executions := tg.GetExecutions(); len(executions) > 0 && len(executions[len(executions)-1].GetError()) > 0 {
+			job.State = "failed"
+		}
+	}
+	return job
+}
+
+func newJobDetail(status *pb.FlowExecutionStatus) *jobDetail {
+	detail := &jobDetail{jobSummary: *newJobSummary(status)}
+	steps := make(map[int32]*stepSummary)
+	startTimes, stopTimes := make(map[int32]int64), make(map[int32]int64)
+	for _, step := range status.GetSteps() {
+		stat := status.GetStepStat(step.GetId())
+		s := &stepSummary{
+			Id:          step.GetId(),
+			Name:        step.GetName(),
+			TaskCount:   len(step.GetTaskIds()),
+			InputCount:  stat.GetInputCounter(),
+			OutputCount: stat.GetOutputCounter(),
+			Counters:    countersOf(stat),
+			Histograms:  histogramsOf(stat),
+		}
+		steps[step.GetId()] = s
+		detail.Steps = append(detail.Steps, s)
+	}
+	for _, tg := range status.GetTaskGroups() {
+		executions := tg.GetExecutions()
+		if len(executions) == 0 {
+			continue
+		}
+		// only count the last attempt of the task group
+		execution := executions[len(executions)-1]
+		for _, stepId := range tg.GetStepIds() {
+			s, found := steps[stepId]
+			if !found {
+				continue
+			}
+			if start := execution.GetStartTime(); start != 0 && (startTimes[stepId] == 0 || start < startTimes[stepId]) {
+				startTimes[stepId] = start
+			}
+			if stop := execution.GetStopTime(); stop > stopTimes[stepId] {
+				stopTimes[stepId] = stop
+			}
+			if len(execution.GetError()) > 0 {
+				s.Errors = append(s.Errors, string(execution.GetError()))
+			}
+		}
+	}
+	for i, tg := range status.GetTaskGroups() {
+		detail.TaskGroups = append(detail.TaskGroups, newTaskGroupSummary(i, tg))
+	}
+	for id, s := range steps {
+		if startTimes[id] == 0 {
+			continue
+		}
+		s.StartTime = timeOf(startTimes[id])
+		if stopTimes[id] != 0 {
+			s.StopTime = timeOf(stopTimes[id])
+			s.DurationMs = (stopTimes[id] - startTimes[id]) / int64(time.Millisecond)
+		}
+	}
+	return detail
+}
+
+func newTaskGroupSummary(id int, tg *pb.FlowExecutionStatus_TaskGroup) *taskGroupSummary {
+	summary := &taskGroupSummary{
+		Id:         id,
+		Name:       tg.GetRequest().GetInstructionSet().GetName(),
+		StepIds:    tg.GetStepIds(),
+		State:      tg.State(),
+		Executions: []*executionSummary{},
+	}
+	if allocation := tg.GetAllocation(); allocation.GetLocation() != nil {
+		summary.Agent = allocation.GetLocation().URL()
+		summary.MemoryMb = allocation.GetAllocated().GetMemoryMb()
+	}
+	for _, execution := range tg.GetExecutions() {
+		e := &executionSummary{
+			UserTime:   execution.GetUserTime(),
+			SystemTime: execution.GetSystemTime(),
+			Stderr:     string(execution.GetError()),
+		}
+		if start := execution.GetStartTime(); start != 0 {
+			e.StartTime = timeOf(start)
+			stop := execution.GetStopTime()
+			if stop != 0 {
+				e.StopTime = timeOf(stop)
+			} else {
+				stop = time.Now().UnixNano()
+			}
+			e.DurationMs = (stop - start) / int64(time.Millisecond)
+		}
+		for _, stat := range execution.GetExecutionStat().GetStats() {
+			e.Stats = append(e.Stats, &taskSummary{
+				StepId:      stat.GetStepId(),
+				TaskId:      stat.GetTaskId(),
+				InputCount:  stat.GetInputCounter(),
+				OutputCount: stat.GetOutputCounter(),
+				Counters:    countersOf(stat),
+				Histograms:  histogramsOf(stat),
+			})
+		}
+		summary.Executions = append(summary.Executions, e)
+	}
+	return summary
+}
+
+// parseSince accepts a duration before now, a RFC3339 time, or unix seconds.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %s, expecting a duration, a RFC3339 time, or unix seconds", s)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+func countersOf(stat *pb.InstructionStat) map[string]int64 {
+	if len(stat.GetCounters()) == 0 {
+		return nil
+	}
+	counters := make(map[string]int64)
+	for _, c := range stat.GetCounters() {
+		counters[c.GetName()] += c.GetValue()
+	}
+	return counters
+}
+
+func histogramsOf(stat *pb.InstructionStat) (ret []*histogramSummary) {
+	for _, h := range stat.GetHistograms() {
+		ret = append(ret, &histogramSummary{
+			Name:  h.GetName(),
+			Count: h.GetCount(),
+			Min:   h.GetMin(),
+			Mean:  h.Mean(),
+			Max:   h.GetMax(),
+		})
+	}
+	return
+}
+
+func timeOf(unixNano int64) *time.Time {
+	t := time.Unix(0, unixNano)
+	return &t
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("Failed to write json: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package master
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/gorilla/mux"
+)
+
+// newTestJob is a job of the user started some time ago, and still running if duration is 0.
+func newTestJob(id uint32, username, name string, startedAgo, duration time.Duration) *pb.FlowExecutionStatus {
+	start := time.Now().Add(-startedAgo)
+	driver := &pb.FlowExecutionStatus_DriverInfo{
+		Username:  username,
+		Name:      name,
+		StartTime: start.UnixNano(),
+	}
+	if duration > 0 {
+		driver.StopTime = start.Add(duration).UnixNano()
+	}
+	return &pb.FlowExecutionStatus{Id: id, Driver: driver}
+}
+
+// newTestHistory has the completed jobs 1 and 2 in the history, and the running job 3.
+func newTestHistory(t *testing.T) (*MasterServer, func()) {
+	store, closeStore := newTestStore(t)
+	ms := newMasterServer("", nil, nil, store)
+
+	first := newTestJob(1, "alice", "wordcount", 48*time.Hour, time.Minute)
+	second := newTestJob(2, "bob", "wordcount-large", 2*time.Hour, time.Minute)
+	second.Steps = []*pb.FlowExecutionStatus_Step{{Id: 0, Name: "read", TaskIds: []int32{0, 1}}}
+	second.TaskGroups = []*pb.FlowExecutionStatus_TaskGroup{{
+		StepIds: []int32{0},
+		Executions: []*pb.FlowExecutionStatus_TaskGroup_Execution{{
+			StartTime: second.Driver.StartTime,
+			StopTime:  second.Driver.StopTime,
+			ExecutionStat: &pb.ExecutionStat{Stats: []*pb.InstructionStat{
+				{StepId: 0, TaskId: 0, InputCounter: 3, OutputCounter: 2,
+					Counters:   []*pb.InstructionStat_Counter{{Name: "lines", Value: 3}},
+					Histograms: []*pb.InstructionStat_Histogram{{Name: "length", Count: 2, Sum: 6, Min: 1, Max: 5}},
+				},
+				{StepId: 0, TaskId: 1, InputCounter: 4, OutputCounter: 1,
+					Counters:   []*pb.InstructionStat_Counter{{Name: "lines", Value: 4}},
+					Histograms: []*pb.InstructionStat_Histogram{{Name: "length", Count: 1, Sum: 9, Min: 9, Max: 9}},
+				},
+			}},
+		}},
+	}}
+	for _, status := range []*pb.FlowExecutionStatus{first, second} {
+		ms.saveJob(status)
+	}
+	ms.statusCache.Add(uint32(3), newTestJob(3, "alice", "join", time.Hour, 0))
+	return ms, closeStore
+}
+
+func serveApi(ms *MasterServer, url string) *httptest.ResponseRecorder {
+	r := mux.NewRouter()
+	r.HandleFunc("/api/jobs", ms.apiJobsHandler)
+	r.HandleFunc("/api/jobs/{id:[0-9]+}", ms.apiJobHandler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	return w
+}
+
+func TestApiJobs(t *testing.T) {
+	ms, closeStore := newTestHistory(t)
+	defer closeStore()
+
+	tests := []struct {
+		query string
+		ids   []uint32
+	}{
+		{"", []uint32{3, 2, 1}},
+		{"?user=alice", []uint32{3, 1}},
+		{"?name=wordcount", []uint32{2, 1}},
+		{"?name=wordcount&user=alice", []uint32{1}},
+		{"?state=running", []uint32{3}},
+		{"?state=completed", []uint32{2, 1}},
+		{"?since=24h", []uint32{3, 2}},
+		{"?since=" + time.Now().Add(-90*time.Minute).Format(time.RFC3339), []uint32{3}},
+		{"?since=1", []uint32{3, 2, 1}},
+		{"?limit=2", []uint32{3, 2}},
+		{"?user=carol", []uint32{}},
+	}
+	for _, test := range tests {
+		w := serveApi(ms, "/api/jobs"+test.query)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status %d %s", test.query, w.Code, w.Body.String())
+			continue
+		}
+		var jobs []*jobSummary
+		if err := json.Unmarshal(w.Body.Bytes(), &jobs); err != nil {
+			t.Errorf("%s: %v", test.query, err)
+			continue
+		}
+		ids := []uint32{}
+		for _, job := range jobs {
+			ids = append(ids, job.Id)
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%s: listed jobs %v, expecting %v", test.query, ids, test.ids)
+		}
+	}
+
+	for _, query := range []string{"?since=yesterday", "?limit=0", "?limit=many"} {
+		if w := serveApi(ms, "/api/jobs"+query); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, expecting %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestApiJob(t *testing.T) {
+	ms, closeStore := newTestHistory(t)
+	defer closeStore()
+
+	w := serveApi(ms, "/api/jobs/2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d %s", w.Code, w.Body.String())
+	}
+	var job jobDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Id != 2 || job.Username != "bob" || job.State != "completed" || job.DurationMs != time.Minute.Nanoseconds()/1e6 {
+		t.Errorf("job %+v", job.jobSummary)
+	}
+	if len(job.Steps) != 1 || job.Steps[0].TaskCount != 2 || job.Steps[0].InputCount != 7 || job.Steps[0].OutputCount != 3 {
+		t.Errorf("steps %+v, expecting step read with 2 tasks, 7 inputs and 3 outputs", job.Steps)
+	}
+	if len(job.TaskGroups) != 1 || len(job.TaskGroups[0].Executions) != 1 || len(job.TaskGroups[0].Executions[0].Stats) != 2 {
+		t.Fatalf("task groups %+v, expecting one execution with 2 task stats", job.TaskGroups)
+	}
+	if counters := job.Steps[0].Counters; !reflect.DeepEqual(counters, map[string]int64{"lines": 7}) {
+		t.Errorf("step counters %v, expecting lines: 7", counters)
+	}
+	expected := []*histogramSummary{{Name: "length", Count: 3, Min: 1, Mean: 5, Max: 9}}
+	if histograms := job.Steps[0].Histograms; !reflect.DeepEqual(histograms, expected) {
+		t.Errorf("step histograms %+v, expecting %+v", histograms, expected[0])
+	}
+	expected = []*histogramSummary{{Name: "length", Count: 2, Min: 1, Mean: 3, Max: 5}}
+	if histograms := job.TaskGroups[0].Executions[0].Stats[0].Histograms; !reflect.DeepEqual(histograms, expected) {
+		t.Errorf("task histograms %+v, expecting %+v", histograms, expected[0])
+	}
+
+	if w := serveApi(ms, "/api/jobs/3"); w.Code != http.StatusOK {
+		t.Errorf("running job: status %d", w.Code)
+	}
+	if w := serveApi(ms, "/api/jobs/99"); w.Code != http.StatusNotFound {
+		t.Errorf("missing job: status %d, expecting %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestPurgeHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		maxJobs int
+		left    []uint32
+	}{
+		{"no limits", 0, 0, []uint32{1, 2}},
+		{"by age", 24 * time.Hour, 0, []uint32{2}},
+		{"by count, the newest kept", 0, 1, []uint32{2}},
+		{"by age and count", 24 * time.Hour, 5, []uint32{2}},
+		{"young enough", 72 * time.Hour, 5, []uint32{1, 2}},
+	}
+	for _, test := range tests {
+		ms, closeStore := newTestHistory(t)
+		ms.purgeHistoryOnce(test.maxAge, test.maxJobs, time.Now())
+		for _, id := range []uint32{1, 2} {
+			isLeft := false
+			for _, left := range test.left {
+				isLeft = isLeft || left == id
+			}
+			job, err := ms.store.GetHistoryJob(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (job != nil) != isLeft {
+				t.Errorf("%s: job %d in the store %v, expecting %v", test.name, id, job != nil, isLeft)
+			}
+			if _, found := ms.historyIndex[id]; found != isLeft {
+				t.Errorf("%s: job %d in the index %v, expecting %v", test.name, id, found, isLeft)
+			}
+		}
+		// the running job is never purged
+		if w := serveApi(ms, "/api/jobs/3"); w.Code != http.StatusOK {
+			t.Errorf("%s: the running job is purged", test.name)
+		}
+		closeStore()
+	}
+}
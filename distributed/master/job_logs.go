@@ -0,0 +1,142 @@
+package master
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// the logs are returned in one grpc message, below the default 4MB limit
+const maxJobLogsBytes = 3 << 20
+
+// GetJobLogs reads the executor logs of the job's task groups from the agents
+// running them, only the named task group, or the task groups running one of
+// the steps if any is given. Each log is limited to the last tail bytes, and
+// to its share of maxJobLogsBytes.
+func (s *MasterServer) GetJobLogs(ctx context.Context, in *pb.JobLogsRequest) (*pb.JobLogsResponse, error) {
+	status := s.findJob(in.GetId())
+	if status == nil {
+		return nil, grpc.Errorf(codes.NotFound, "job %d not found", in.GetId())
+	}
+	if !s.auth.CanAccessJob(security.UsernameFromContext(ctx), security.PermissionView, status) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "no permission to view job %d", in.GetId())
+	}
+
+	response := &pb.JobLogsResponse{}
+	for _, tg := range status.GetTaskGroups() {
+		name := tg.GetRequest().GetInstructionSet().GetName()
+		location := tg.GetAllocation().GetLocation()
+		// the task groups run by the driver have no executor logs
+		if name == "" || location == nil || !runsAnyStep(tg, in.GetStepIds()) {
+			continue
+		}
+		if in.GetName() != "" && name != in.GetName() {
+			continue
+		}
+		response.TaskLogs = append(response.TaskLogs, &pb.TaskLogs{Name: name, Agent: location.URL()})
+	}
+	if len(response.TaskLogs) == 0 {
+		return response, nil
+	}
+
+	tailBytes := in.GetTailBytes()
+	if share := maxJobLogsBytes / int64(len(response.TaskLogs)); tailBytes <= 0 || tailBytes > share {
+		tailBytes = share
+	}
+	var wg sync.WaitGroup
+	for _, taskLogs := range response.TaskLogs {
+		wg.Add(1)
+		go func(taskLogs *pb.TaskLogs) {
+			defer wg.Done()
+			err := withAgentClient(taskLogs.Agent, func(client pb.GleamAgentClient) error {
+				logs, err := client.GetLogs(ctx, &pb.GetLogsRequest{
+					FlowHashCode: status.GetId(),
+					Name:         taskLogs.Name,
+					TailBytes:    tailBytes,
+				})
+				taskLogs.Content = logs.GetContent()
+				return err
+			})
+			if err != nil {
+				taskLogs.Error = grpc.ErrorDesc(err)
+			}
+		}(taskLogs)
+	}
+	wg.Wait()
+
+	return response, nil
+}
+
+func runsAnyStep(tg *pb.FlowExecutionStatus_TaskGroup, stepIds []int32) bool {
+	if len(stepIds) == 0 {
+		return true
+	}
+	for _, stepId := range tg.GetStepIds() {
+		for _, s := range stepIds {
+			if stepId == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jobLogsHandler shows the logs of the task group "name", or of the task groups
+// running the "step", or of all task groups, limited to the last "tail" bytes if given,
+// and to 3MB in total.
+func (s *MasterServer) jobLogsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	jobId, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "invalid job id "+vars["id"], http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	request := &pb.JobLogsRequest{Id: uint32(jobId), Name: query.Get("name")}
+	if step := query.Get("step"); step != "" {
+		stepId, err := strconv.ParseInt(step, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid step "+step, http.StatusBadRequest)
+			return
+		}
+		request.StepIds = []int32{int32(stepId)}
+	}
+	if tail := query.Get("tail"); tail != "" {
+		if request.TailBytes, err = strconv.ParseInt(tail, 10, 64); err != nil {
+			http.Error(w, "invalid tail "+tail, http.StatusBadRequest)
+			return
+		}
+	}
+
+	response, err := s.GetJobLogs(r.Context(), request)
+	switch grpc.Code(err) {
+	case codes.OK:
+	case codes.NotFound:
+		http.Error(w, grpc.ErrorDesc(err), http.StatusNotFound)
+		return
+	case codes.PermissionDenied:
+		http.Error(w, grpc.ErrorDesc(err), http.StatusForbidden)
+		return
+	default:
+		http.Error(w, grpc.ErrorDesc(err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, t := range response.GetTaskLogs() {
+		w.Write([]byte("==> " + t.GetName() + " on " + t.GetAgent() + " <==\n"))
+		if t.GetError() != "" {
+			w.Write([]byte(t.GetError() + "\n\n"))
+			continue
+		}
+		w.Write(t.GetContent())
+		w.Write([]byte("\n"))
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"time"
 
+	"github.com/chrislusf/gleam/distributed/master/ui"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	router "github.com/gorilla/mux"
 	"github.com/soheilhy/cmux"
@@ -14,32 +17,105 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+type MasterOption struct {
+	Address            *string
+	LogDirectory       *string
+	AuthTokenFile      *string
+	AuthHtpasswdFile   *string
+	AuthPermissionFile *string
+	QueueFile          *string
+	Peer               *string
+	PeerToken          *string
+	AgentToken         *string
+	HistoryMaxAge      *time.Duration
+	HistoryMaxJobs     *int
+}
+
 var masterServer *MasterServer
 
-func RunMaster(listenOn string, logDirectory string) {
+// methodPermissions lists the permission required by each GleamMaster rpc.
+var methodPermissions = map[string]security.Permission{
+	"/pb.GleamMaster/GetResources":            security.PermissionSubmit,
+	"/pb.GleamMaster/SendHeartbeat":           security.PermissionAgent,
+	"/pb.GleamMaster/SendFlowExecutionStatus": security.PermissionSubmit,
+	"/pb.GleamMaster/GetMasterState":          security.PermissionPeer,
+	"/pb.GleamMaster/DrainAgent":              security.PermissionAgent,
+	"/pb.GleamMaster/GetCapacity":             security.PermissionSubmit,
+}
+
+func RunMaster(option *MasterOption) {
+
+	auth, err := security.NewAuth(*option.AuthTokenFile, *option.AuthHtpasswdFile, *option.AuthPermissionFile, methodPermissions)
+	if err != nil {
+		log.Fatalf("master server fails to load authentication: %v", err)
+	}
+
+	var queues []*Queue
+	if *option.QueueFile != "" {
+		if queues, err = LoadQueues(*option.QueueFile); err != nil {
+			log.Fatalf("master server fails to load queues: %v", err)
+		}
+	}
+
+	store, err := NewStateStore(*option.LogDirectory)
+	if err != nil {
+		log.Fatalf("master server fails to open state store: %v", err)
+	}
+	defer store.Close()
 
-	masterServer = newMasterServer(logDirectory)
+	if *option.AgentToken != "" {
+		security.SetAgentCredentials(&security.Credentials{Token: *option.AgentToken})
+	}
+
+	masterServer = newMasterServer(*option.LogDirectory, auth, queues, store)
+	masterServer.peer, masterServer.peerToken = *option.Peer, *option.PeerToken
+	store.keepDeletions = masterServer.peer != ""
+	if masterServer.peer == "" {
+		masterServer.becomeActive(0)
+	} else {
+		peerConnection, err := masterServer.dialPeer()
+		if err != nil {
+			log.Fatalf("master server fails to dial peer master %s: %v", masterServer.peer, err)
+		}
+		log.Printf("master is standby, following peer master %s", masterServer.peer)
+		go masterServer.runWithPeer(peerConnection)
+	}
+	go masterServer.purgeHistory(*option.HistoryMaxAge, *option.HistoryMaxJobs)
 
-	listener, err := net.Listen("tcp", listenOn)
+	listener, err := net.Listen("tcp", *option.Address)
 	if err != nil {
-		log.Fatalf("master server fails to listen on %s: %v", listenOn, err)
+		log.Fatalf("master server fails to listen on %s: %v", *option.Address, err)
 	}
 	defer listener.Close()
 
-	m := cmux.New(listener)
+	m := cmux.New(security.NewListener(listener))
 
 	grpcL := m.Match(cmux.HTTP2HeaderField("content-type", "application/grpc"))
 	httpL := m.Match(cmux.Any())
 
 	// Create your protocol servers.
-	grpcS := grpc.NewServer()
+	grpcS := grpc.NewServer(
+		grpc.UnaryInterceptor(masterServer.UnaryServerInterceptor),
+		grpc.StreamInterceptor(masterServer.StreamServerInterceptor),
+	)
 	pb.RegisterGleamMasterServer(grpcS, masterServer)
 	reflection.Register(grpcS)
 
 	r := router.NewRouter()
 	r.HandleFunc("/", masterServer.uiStatusHandler)
 	r.HandleFunc("/job/{id:[0-9]+}", masterServer.jobStatusHandler)
-	httpS := &http.Server{Handler: r}
+	r.HandleFunc("/job/{id:[0-9]+}/dag.svg", masterServer.jobDagHandler)
+	r.HandleFunc("/job/{id:[0-9]+}/cancel", masterServer.jobCancelHandler)
+	r.HandleFunc("/job/{id:[0-9]+}/logs", masterServer.jobLogsHandler)
+	r.HandleFunc("/ui/{file}", ui.StaticHandler)
+	r.HandleFunc("/api/jobs", masterServer.apiJobsHandler)
+	r.HandleFunc("/api/jobs/{id:[0-9]+}", masterServer.apiJobHandler)
+	r.Handle("/metrics", masterServer.metricsHandler())
+	var handler http.Handler = r
+	if auth != nil {
+		handler = auth.HttpHandler(r)
+	}
+	httpS := &http.Server{Handler: masterServer.HttpHandler(handler)}
 
 	go grpcS.Serve(grpcL)
 	go httpS.Serve(httpL)
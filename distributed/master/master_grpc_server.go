@@ -1,62 +1,85 @@
 package master
 
 import (
-	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
-	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/golang-lru"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
+// the running job statuses without state changes are saved at most this often
+const jobSaveInterval = 30 * time.Second
+
 type MasterServer struct {
 	Topology     *Topology
 	statusCache  *lru.Cache
 	logDirectory string
 	startTime    time.Time
+	auth         *security.Auth
+	queueManager *QueueManager
+	store        *StateStore
+	// the other master in active/standby mode
+	peer      string
+	peerToken string
+	// 1 if this master is serving requests, 0 if standby
+	active int32
+	// the term this master became active in
+	term uint64
+	// flows cancelled but the drivers are not stopped yet
+	cancelledFlows map[uint32]bool
+	cancelledLock  sync.Mutex
+	metrics        *masterMetrics
+	// dataset shards moved from drained agents, by flow
+	migratedShards map[uint32][]*pb.DataLocation
+	migratedLock   sync.Mutex
+	// job id => summary of the completed job in the history
+	historyIndex map[uint32]*jobSummary
+	historyLock  sync.RWMutex
 }
 
-func newMasterServer(logDirectory string) *MasterServer {
+func newMasterServer(logDirectory string, auth *security.Auth, queues []*Queue, store *StateStore) *MasterServer {
 	m := &MasterServer{
-		Topology:     NewTopology(),
-		logDirectory: logDirectory,
-		startTime:    time.Now(),
+		Topology:       NewTopology(),
+		logDirectory:   logDirectory,
+		startTime:      time.Now(),
+		auth:           auth,
+		queueManager:   NewQueueManager(queues, store),
+		store:          store,
+		cancelledFlows: make(map[uint32]bool),
+		metrics:        newMasterMetrics(),
+		migratedShards: make(map[uint32][]*pb.DataLocation),
+		historyIndex:   make(map[uint32]*jobSummary),
 	}
 	m.statusCache, _ = lru.NewWithEvict(512, m.onCacheEvict)
 	if strings.HasSuffix(m.logDirectory, "/") {
 		m.logDirectory = strings.TrimSuffix(m.logDirectory, "/")
 	}
-	m.onStartup()
 	return m
 }
 
 func (s *MasterServer) GetResources(ctx context.Context, in *pb.ComputeRequest) (*pb.AllocationResult, error) {
-	var err error
-	dcName := in.GetDataCenter()
-	if dcName == "" {
-		dcName, err = s.Topology.allocateDataCenter(in.GetComputeResources())
-		if err != nil {
-			return nil, err
-		}
+	if username := security.UsernameFromContext(ctx); username != "" {
+		in.Username = username
 	}
-	dc, hasDc := s.Topology.GetDataCenter(dcName)
-	if !hasDc {
-		return nil, fmt.Errorf("Failed to find existing data center: %s", dcName)
+	allocations, err := s.allocateResources(in)
+	if err != nil {
+		return nil, err
 	}
 
-	allocations := s.Topology.findServers(dc, in.GetComputeResources())
-
 	log.Printf("%v requests %+v, allocated %+v", in.FlowHashCode, in.GetComputeResources(), allocations)
 
 	return &pb.AllocationResult{
-		Allocations: allocations,
+		Allocations:    allocations,
+		MigratedShards: s.getMigratedShards(in.GetFlowHashCode()),
 	}, nil
 
 }
@@ -73,6 +96,7 @@ func (s *MasterServer) SendHeartbeat(stream pb.GleamMaster_SendHeartbeatServer)
 		} else {
 			if location != nil {
 				s.Topology.deleteAgentInformation(location)
+				s.queueManager.removeAgent(location)
 			}
 			log.Printf("lost agent: %v", location)
 
@@ -84,10 +108,23 @@ func (s *MasterServer) SendHeartbeat(stream pb.GleamMaster_SendHeartbeatServer)
 			}
 		}
 		s.Topology.UpdateAgentInformation(heartbeat)
+		s.queueManager.updateAgentReport(heartbeat)
 	}
 }
 
 func (s *MasterServer) SendFlowExecutionStatus(stream pb.GleamMaster_SendFlowExecutionStatusServer) error {
+	username := security.UsernameFromContext(stream.Context())
+	// job id => checked to be owned by the user
+	owned := make(map[uint32]bool)
+	// job id => when the status was last saved
+	savedAt := make(map[uint32]time.Time)
+	// the latest statuses not saved yet, saved when the stream ends
+	unsaved := make(map[uint32]*pb.FlowExecutionStatus)
+	defer func() {
+		for _, status := range unsaved {
+			s.saveJob(status)
+		}
+	}()
 	for {
 		status, err := stream.Recv()
 
@@ -99,30 +136,104 @@ func (s *MasterServer) SendFlowExecutionStatus(stream pb.GleamMaster_SendFlowExe
 			return err
 		}
 
+		if username != "" && !owned[status.GetId()] {
+			if owner := s.findJob(status.GetId()).GetDriver().GetUsername(); owner != "" && owner != username {
+				return grpc.Errorf(codes.PermissionDenied, "job %d belongs to %s", status.GetId(), owner)
+			}
+			owned[status.GetId()] = true
+		}
+		if username != "" && status.Driver != nil {
+			status.Driver.Username = username
+		}
+
+		previous, _ := s.statusCache.Peek(status.GetId())
+		previousStatus, _ := previous.(*pb.FlowExecutionStatus)
+		s.metrics.observeJob(previousStatus, status)
+
 		s.statusCache.Add(status.GetId(), status)
 
-		if status.Driver.GetStopTime() != 0 {
-			data, _ := proto.Marshal(status)
-			ioutil.WriteFile(fmt.Sprintf("%s/f%d.log", s.logDirectory, status.GetId()), data, 0644)
+		// the drivers report every few seconds, but only state changes are saved right away
+		if isJobStateChanged(previousStatus, status) || time.Since(savedAt[status.GetId()]) > jobSaveInterval {
+			s.saveJob(status)
+			savedAt[status.GetId()] = time.Now()
+			delete(unsaved, status.GetId())
+		} else {
+			unsaved[status.GetId()] = status
+		}
+
+		if status.GetDriver().GetStopTime() != 0 {
+			s.forgetMigratedShards(status.GetId())
+		}
+
+		if s.isCancelled(status) {
+			return grpc.Errorf(codes.Aborted, "job %d is cancelled", status.GetId())
 		}
 	}
 }
 
-func (s *MasterServer) onStartup() {
-	files, _ := filepath.Glob(fmt.Sprintf("%s/f[0-9]*\\.log", s.logDirectory))
-	for _, f := range files {
-		data, _ := ioutil.ReadFile(f)
-		status := &pb.FlowExecutionStatus{}
-		if err := proto.Unmarshal(data, status); err == nil {
-			// println("loading", f, "for", status.GetId())
-			s.statusCache.Add(status.GetId(), status)
-		} else {
-			os.Remove(f)
+func (s *MasterServer) saveJob(status *pb.FlowExecutionStatus) {
+	if err := s.store.SaveJob(status); err != nil {
+		log.Printf("Failed to save job %d: %v", status.GetId(), err)
+		return
+	}
+	if status.GetDriver().GetStopTime() != 0 {
+		s.addToHistoryIndex(status)
+	}
+}
+
+// isJobStateChanged checks whether the job has stopped, or any task group
+// has started, stopped, or failed, since the previous status.
+func isJobStateChanged(previous, status *pb.FlowExecutionStatus) bool {
+	if previous == nil || len(previous.GetTaskGroups()) != len(status.GetTaskGroups()) {
+		return true
+	}
+	if previous.GetDriver().GetStopTime() != status.GetDriver().GetStopTime() {
+		return true
+	}
+	for i, taskGroup := range status.GetTaskGroups() {
+		before, after := previous.GetTaskGroups()[i].GetExecutions(), taskGroup.GetExecutions()
+		if len(before) != len(after) {
+			return true
+		}
+		if len(after) == 0 {
+			continue
 		}
+		b, a := before[len(before)-1], after[len(after)-1]
+		if b.GetStopTime() != a.GetStopTime() || len(b.GetError()) != len(a.GetError()) {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreState loads the job statuses and flow allocations saved by
+// the previous run, or replicated from the previous active master.
+func (s *MasterServer) restoreState() {
+	var stats []*pb.FlowExecutionStatus
+	if err := s.store.LoadJobs(func(status *pb.FlowExecutionStatus) {
+		stats = append(stats, status)
+	}); err != nil {
+		log.Printf("Failed to load job statuses: %v", err)
+	}
+	// add the latest jobs last, so that the oldest jobs are evicted
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].GetDriver().GetStartTime() < stats[j].GetDriver().GetStartTime()
+	})
+	for _, status := range stats {
+		s.statusCache.Add(status.GetId(), status)
 	}
+
+	s.loadHistoryIndex()
+
+	if err := s.queueManager.restore(); err != nil {
+		log.Printf("Failed to load flow allocations: %v", err)
+	}
+	log.Printf("restored %d jobs", len(stats))
 }
 
 func (s *MasterServer) onCacheEvict(key interface{}, value interface{}) {
 	id := key.(uint32)
-	os.Remove(fmt.Sprintf("%s/f%d.log", s.logDirectory, id))
+	if err := s.store.DeleteJob(id); err != nil {
+		log.Printf("Failed to delete job %d: %v", id, err)
+	}
 }
@@ -0,0 +1,204 @@
+package master
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// how often a standby master pulls the state changes from the active master
+	replicationInterval = 2 * time.Second
+	// a standby master takes over if the active master is unreachable for this long
+	failoverTimeout = 10 * time.Second
+)
+
+func (s *MasterServer) isActive() bool {
+	return atomic.LoadInt32(&s.active) == 1
+}
+
+// becomeActive restores the saved state and starts serving requests,
+// in a term later than the terms of both masters so far.
+func (s *MasterServer) becomeActive(peerTerm uint64) {
+	term, err := s.store.Term()
+	if err != nil {
+		log.Printf("Failed to read the master term: %v", err)
+	}
+	if peerTerm > term {
+		term = peerTerm
+	}
+	term++
+	if err := s.store.SaveTerm(term); err != nil {
+		log.Printf("Failed to save the master term: %v", err)
+	}
+	atomic.StoreUint64(&s.term, term)
+	s.restoreState()
+	atomic.StoreInt32(&s.active, 1)
+	log.Printf("master is active in term %d", term)
+}
+
+// stepDown stops serving requests, if the peer master took over in a later term.
+func (s *MasterServer) stepDown(peerTerm uint64) {
+	atomic.StoreInt32(&s.active, 0)
+	log.Printf("master steps down, peer master %s is active in term %d", s.peer, peerTerm)
+}
+
+// GetMasterState returns the state changes since the version to a standby master.
+func (s *MasterServer) GetMasterState(ctx context.Context, in *pb.MasterStateRequest) (*pb.MasterState, error) {
+	state := &pb.MasterState{
+		IsActive:  s.isActive(),
+		StartTime: s.startTime.UnixNano(),
+		Term:      atomic.LoadUint64(&s.term),
+	}
+	if !state.IsActive {
+		return state, nil
+	}
+	sinceVersion := in.GetSinceVersion()
+	records, version, isSnapshot, err := s.store.Changes(sinceVersion)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "read state changes: %v", err)
+	}
+	// the standby master has replicated the deletions up to its version
+	if sinceVersion > 0 && sinceVersion <= version {
+		if err := s.store.CompactDeletions(sinceVersion); err != nil {
+			log.Printf("Failed to compact the deletions: %v", err)
+		}
+	}
+	state.Version = version
+	state.Records = records
+	state.IsSnapshot = isSnapshot
+	return state, nil
+}
+
+// dialPeer connects to the peer master. The connection is established in
+// the background, so an error is in the configuration, e.g. the TLS options.
+func (s *MasterServer) dialPeer() (*grpc.ClientConn, error) {
+	return grpc.Dial(s.peer, security.GrpcDialOptions(&security.Credentials{Token: s.peerToken})...)
+}
+
+// runWithPeer follows the peer master while standby, and watches it while active.
+func (s *MasterServer) runWithPeer(grpcConnection *grpc.ClientConn) {
+	defer grpcConnection.Close()
+	client := pb.NewGleamMasterClient(grpcConnection)
+
+	for {
+		if s.isActive() {
+			s.watchPeer(client)
+		} else {
+			s.followPeer(client)
+		}
+	}
+}
+
+// followPeer replicates the state from the active peer master, until the
+// peer is unreachable for failoverTimeout. If both masters are standby,
+// the one started earlier takes over.
+func (s *MasterServer) followPeer(client pb.GleamMasterClient) {
+	var version, peerTerm uint64
+	lastContact := time.Now()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), replicationInterval)
+		state, err := client.GetMasterState(ctx, &pb.MasterStateRequest{SinceVersion: version})
+		cancel()
+
+		now := time.Now()
+		switch {
+		case err != nil:
+			if now.Sub(lastContact) > failoverTimeout {
+				log.Printf("peer master %s is unreachable: %v", s.peer, err)
+				s.becomeActive(peerTerm)
+				return
+			}
+		case state.GetIsActive():
+			lastContact = now
+			peerTerm = state.GetTerm()
+			if state.GetVersion() < version {
+				// the peer lost its state, replicate from the beginning
+				version = 0
+			} else if err := s.store.Apply(state.GetRecords(), state.GetIsSnapshot()); err != nil {
+				log.Printf("Failed to apply state from peer master %s: %v", s.peer, err)
+			} else {
+				version = state.GetVersion()
+			}
+		default:
+			lastContact = now
+			if s.startTime.UnixNano() < state.GetStartTime() {
+				s.becomeActive(state.GetTerm())
+				return
+			}
+		}
+		time.Sleep(replicationInterval)
+	}
+}
+
+// watchPeer fences an active master cut off from its peer: once the peer
+// is reachable again and active in a later term, this master steps down,
+// so that only one master accepts jobs and allocates the agents.
+func (s *MasterServer) watchPeer(client pb.GleamMasterClient) {
+	for {
+		time.Sleep(replicationInterval)
+
+		ctx, cancel := context.WithTimeout(context.Background(), replicationInterval)
+		state, err := client.GetMasterState(ctx, &pb.MasterStateRequest{SinceVersion: math.MaxUint64})
+		cancel()
+		if err != nil || !state.GetIsActive() {
+			continue
+		}
+		term := atomic.LoadUint64(&s.term)
+		if state.GetTerm() > term || (state.GetTerm() == term && state.GetStartTime() < s.startTime.UnixNano()) {
+			s.stepDown(state.GetTerm())
+			return
+		}
+	}
+}
+
+// UnaryServerInterceptor rejects requests to a standby master, so clients
+// try the next master. The state replication is always allowed.
+func (s *MasterServer) UnaryServerInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkActive(info.FullMethod); err != nil {
+		return nil, err
+	}
+	if s.auth != nil {
+		return s.auth.UnaryServerInterceptor(ctx, req, info, handler)
+	}
+	return handler(ctx, req)
+}
+
+func (s *MasterServer) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkActive(info.FullMethod); err != nil {
+		return err
+	}
+	if s.auth != nil {
+		return s.auth.StreamServerInterceptor(srv, stream, info, handler)
+	}
+	return handler(srv, stream)
+}
+
+func (s *MasterServer) checkActive(method string) error {
+	if s.isActive() || method == "/pb.GleamMaster/GetMasterState" || !strings.HasPrefix(method, "/pb.GleamMaster/") {
+		return nil
+	}
+	return grpc.Errorf(codes.Unavailable, "standby master, the active master is %s", s.peer)
+}
+
+// HttpHandler shows the active master to http requests on a standby master.
+func (s *MasterServer) HttpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isActive() {
+			http.Error(w, "standby master, the active master is "+s.peer, http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
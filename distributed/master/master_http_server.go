@@ -3,12 +3,11 @@ package master
 import (
 	"log"
 	"net/http"
-	"sort"
 	"strconv"
 	"time"
 
 	"github.com/chrislusf/gleam/distributed/master/ui"
-	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/golang-lru"
 )
@@ -17,27 +16,16 @@ func (ms *MasterServer) uiStatusHandler(w http.ResponseWriter, r *http.Request)
 	infos := make(map[string]interface{})
 	infos["Version"] = 0.01
 
-	var stats []*pb.FlowExecutionStatus
-	for _, key := range ms.statusCache.Keys() {
-		stat, _ := ms.statusCache.Get(key.(uint32))
-		stats = append(stats, stat.(*pb.FlowExecutionStatus))
-	}
-	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].Driver.StartTime > stats[j].Driver.StartTime
-	})
-
 	args := struct {
 		Version   string
 		Topology  interface{}
 		StartTime time.Time
 		Logs      *lru.Cache
-		Stats     []*pb.FlowExecutionStatus
 	}{
 		"0.01",
 		ms.Topology,
 		ms.startTime,
 		ms.statusCache,
-		stats,
 	}
 	ui.MasterStatusTpl.Execute(w, args)
 }
@@ -49,11 +37,15 @@ func (ms *MasterServer) jobStatusHandler(w http.ResponseWriter, r *http.Request)
 		log.Printf("Failed to parse job id %s", vars["id"])
 		return
 	}
-	status, ok := ms.statusCache.Get(uint32(jobId))
-	if !ok {
+	status := ms.findJob(uint32(jobId))
+	if status == nil {
 		log.Printf("Failed to find job status for %d", jobId)
 		return
 	}
+	if !ms.auth.CanAccessJob(security.UsernameFromContext(r.Context()), security.PermissionView, status) {
+		http.Error(w, "no permission to view this job", http.StatusForbidden)
+		return
+	}
 
 	args := struct {
 		Version   string
@@ -65,10 +57,31 @@ func (ms *MasterServer) jobStatusHandler(w http.ResponseWriter, r *http.Request)
 	}{
 		"0.01",
 		ms.Topology,
-		status.(*pb.FlowExecutionStatus),
-		ui.GenSvg(status.(*pb.FlowExecutionStatus)),
+		status,
+		ui.GenSvg(status),
 		ms.startTime,
 		ms.statusCache,
 	}
 	ui.JobStatusTpl.Execute(w, args)
 }
+
+// jobDagHandler renders the step DAG of the job, coloured by the task group states.
+func (ms *MasterServer) jobDagHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	jobId, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "invalid job id "+vars["id"], http.StatusBadRequest)
+		return
+	}
+	status := ms.findJob(uint32(jobId))
+	if status == nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	if !ms.auth.CanAccessJob(security.UsernameFromContext(r.Context()), security.PermissionView, status) {
+		http.Error(w, "no permission to view this job", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write([]byte(ui.GenSvg(status)))
+}
@@ -0,0 +1,117 @@
+package master
+
+import (
+	"net/http"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// masterMetrics are updated when jobs finish. Other metrics are read from
+// the master state when scraped.
+type masterMetrics struct {
+	jobDuration  *prometheus.HistogramVec
+	taskFailures prometheus.Counter
+}
+
+func newMasterMetrics() *masterMetrics {
+	return &masterMetrics{
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gleam_job_duration_seconds",
+			Help:    "Duration of finished jobs.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"state"}),
+		taskFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gleam_task_failures_total",
+			Help: "Task groups failed in finished jobs.",
+		}),
+	}
+}
+
+// observeJob records the metrics when a job is reported as finished for the first time.
+func (m *masterMetrics) observeJob(previous, status *pb.FlowExecutionStatus) {
+	if status.GetDriver().GetStopTime() == 0 || previous.GetDriver().GetStopTime() != 0 {
+		return
+	}
+	job := newJobSummary(status)
+	m.jobDuration.WithLabelValues(job.State).Observe(float64(job.DurationMs) / 1000)
+	for _, tg := range status.GetTaskGroups() {
+		if executions := tg.GetExecutions(); len(executions) > 0 && len(executions[len(executions)-1].GetError()) > 0 {
+			m.taskFailures.Inc()
+		}
+	}
+}
+
+var (
+	agentLabels = []string{"agent", "data_center", "rack"}
+
+	agentsDesc = prometheus.NewDesc("gleam_master_agents",
+		"Number of agents sending heartbeats.", nil, nil)
+	agentCpuDesc = prometheus.NewDesc("gleam_agent_cpu_count",
+		"Number of executors the agent can run.", agentLabels, nil)
+	agentMemoryDesc = prometheus.NewDesc("gleam_agent_memory_mb",
+		"Memory in MB the agent can allocate.", agentLabels, nil)
+	agentAllocatedCpuDesc = prometheus.NewDesc("gleam_agent_allocated_cpu_count",
+		"Number of executors allocated on the agent.", agentLabels, nil)
+	agentAllocatedMemoryDesc = prometheus.NewDesc("gleam_agent_allocated_memory_mb",
+		"Memory in MB allocated on the agent.", agentLabels, nil)
+	runningJobsDesc = prometheus.NewDesc("gleam_master_running_jobs",
+		"Number of jobs still running.", nil, nil)
+	queueUsageCpuDesc = prometheus.NewDesc("gleam_queue_used_cpu_count",
+		"Number of executors used by the flows in the queue.", []string{"queue"}, nil)
+	queueUsageMemoryDesc = prometheus.NewDesc("gleam_queue_used_memory_mb",
+		"Memory in MB used by the flows in the queue.", []string{"queue"}, nil)
+)
+
+// masterCollector reads the topology, jobs and queues when scraped.
+type masterCollector struct {
+	s *MasterServer
+}
+
+func (c *masterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- agentsDesc
+	ch <- agentCpuDesc
+	ch <- agentMemoryDesc
+	ch <- agentAllocatedCpuDesc
+	ch <- agentAllocatedMemoryDesc
+	ch <- runningJobsDesc
+	ch <- queueUsageCpuDesc
+	ch <- queueUsageMemoryDesc
+}
+
+func (c *masterCollector) Collect(ch chan<- prometheus.Metric) {
+	agentCount := 0
+	for _, dc := range c.s.Topology.GetDataCenters() {
+		for _, rack := range dc.GetRacks() {
+			for _, a := range rack.GetAgents() {
+				agentCount++
+				labels := []string{a.Location.URL(), dc.Name, rack.Name}
+				ch <- prometheus.MustNewConstMetric(agentCpuDesc, prometheus.GaugeValue, float64(a.Resource.CpuCount), labels...)
+				ch <- prometheus.MustNewConstMetric(agentMemoryDesc, prometheus.GaugeValue, float64(a.Resource.MemoryMb), labels...)
+				ch <- prometheus.MustNewConstMetric(agentAllocatedCpuDesc, prometheus.GaugeValue, float64(a.Allocated.CpuCount), labels...)
+				ch <- prometheus.MustNewConstMetric(agentAllocatedMemoryDesc, prometheus.GaugeValue, float64(a.Allocated.MemoryMb), labels...)
+			}
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(agentsDesc, prometheus.GaugeValue, float64(agentCount))
+
+	ch <- prometheus.MustNewConstMetric(runningJobsDesc, prometheus.GaugeValue, float64(len(c.s.runningFlows())))
+
+	for queue, used := range c.s.queueManager.usageByQueue() {
+		ch <- prometheus.MustNewConstMetric(queueUsageCpuDesc, prometheus.GaugeValue, float64(used.CpuCount), queue)
+		ch <- prometheus.MustNewConstMetric(queueUsageMemoryDesc, prometheus.GaugeValue, float64(used.MemoryMb), queue)
+	}
+}
+
+func (s *MasterServer) metricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		s.metrics.jobDuration,
+		s.metrics.taskFailures,
+		&masterCollector{s},
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
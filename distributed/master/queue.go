@@ -0,0 +1,339 @@
+package master
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+	"github.com/chrislusf/gleam/pb"
+)
+
+const (
+	DefaultQueueName = "default"
+
+	// a flow is active if it requested resources recently
+	activeFlowTimeout = 30 * time.Second
+	// a granted allocation counts towards the usage until the agent reports it
+	grantTimeout = 30 * time.Second
+	// do not preempt the same flow again too soon
+	preemptInterval = 30 * time.Second
+)
+
+// Queue is a named share of the cluster resources, usually for one team.
+type Queue struct {
+	Name string
+	// the percentage of cluster cpu and memory this queue can always get, by preempting other queues
+	GuaranteedPercent float64
+	// the percentage of cluster cpu and memory this queue can use at most
+	MaxPercent float64
+	// the users allowed to submit to this queue, empty for all users
+	Users []string
+}
+
+func (q *Queue) allowsUser(username string) bool {
+	if len(q.Users) == 0 || username == "" {
+		return true
+	}
+	for _, u := range q.Users {
+		if u == username || u == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type grant struct {
+	agent    string
+	location pb.Location
+	resource pb.ComputeResource
+	time     time.Time
+}
+
+type agentReport struct {
+	allocated pb.ComputeResource
+	time      time.Time
+}
+
+// flowUsage tracks the resources used by one flow.
+type flowUsage struct {
+	flowHashCode  uint32
+	queue         string
+	lastRequest   time.Time
+	lastPreempted time.Time
+	// agent url => allocated resources reported by the agent
+	reported map[string]agentReport
+	// allocations granted but not yet reported by agents
+	grants []grant
+}
+
+func (f *flowUsage) usage(now time.Time) (total pb.ComputeResource) {
+	for _, r := range f.reported {
+		total = total.Plus(r.allocated)
+	}
+	for _, g := range f.grants {
+		if now.Sub(g.time) > grantTimeout {
+			continue
+		}
+		if r, found := f.reported[g.agent]; found && r.time.After(g.time) {
+			continue
+		}
+		total = total.Plus(g.resource)
+	}
+	return
+}
+
+func (f *flowUsage) isActive(now time.Time) bool {
+	return now.Sub(f.lastRequest) < activeFlowTimeout || !f.usage(now).IsZero()
+}
+
+// QueueManager tracks the resource usage of each queue and flow.
+type QueueManager struct {
+	sync.Mutex
+	queues map[string]*Queue
+	flows  map[uint32]*flowUsage
+	// persists the flow queues and grants, can be nil
+	store *StateStore
+}
+
+func NewQueueManager(queues []*Queue, store *StateStore) *QueueManager {
+	qm := &QueueManager{
+		queues: make(map[string]*Queue),
+		flows:  make(map[uint32]*flowUsage),
+		store:  store,
+	}
+	for _, q := range queues {
+		qm.queues[q.Name] = q
+	}
+	if _, found := qm.queues[DefaultQueueName]; !found {
+		qm.queues[DefaultQueueName] = &Queue{Name: DefaultQueueName, MaxPercent: 100}
+	}
+	return qm
+}
+
+// LoadQueues reads lines of "name guaranteedPercent maxPercent [user1,user2]".
+func LoadQueues(fileName string) ([]*Queue, error) {
+	var queues []*Queue
+	err := security.ReadConfigLines(fileName, func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) != 3 && len(fields) != 4 {
+			return fmt.Errorf("expecting name, guaranteed percent, max percent, and optional users: %s", line)
+		}
+		guaranteed, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+		if err != nil {
+			return fmt.Errorf("guaranteed percent of %s: %v", fields[0], err)
+		}
+		max, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+		if err != nil {
+			return fmt.Errorf("max percent of %s: %v", fields[0], err)
+		}
+		if guaranteed > max || max > 100 || guaranteed < 0 {
+			return fmt.Errorf("queue %s should have 0 <= guaranteed <= max <= 100", fields[0])
+		}
+		q := &Queue{Name: fields[0], GuaranteedPercent: guaranteed, MaxPercent: max}
+		if len(fields) == 4 {
+			q.Users = strings.Split(fields[3], ",")
+		}
+		queues = append(queues, q)
+		return nil
+	})
+	return queues, err
+}
+
+// getFlow returns the flow usage, and records the request time.
+// The queue is decided on the first request of a flow.
+func (qm *QueueManager) getFlow(flowHashCode uint32, queueName, username string) (*flowUsage, *Queue, error) {
+	if queueName == "" {
+		queueName = DefaultQueueName
+	}
+	q, found := qm.queues[queueName]
+	if !found {
+		return nil, nil, fmt.Errorf("unknown queue %s", queueName)
+	}
+	if !q.allowsUser(username) {
+		return nil, nil, fmt.Errorf("user %s is not allowed to use queue %s", username, queueName)
+	}
+	f, found := qm.flows[flowHashCode]
+	if !found {
+		f = &flowUsage{
+			flowHashCode: flowHashCode,
+			queue:        queueName,
+			reported:     make(map[string]agentReport),
+		}
+		qm.flows[flowHashCode] = f
+	}
+	f.lastRequest = time.Now()
+	return f, qm.queues[f.queue], nil
+}
+
+func (qm *QueueManager) queueUsage(queueName string, now time.Time) (total pb.ComputeResource, activeFlows int) {
+	for _, f := range qm.flows {
+		if f.queue != queueName {
+			continue
+		}
+		total = total.Plus(f.usage(now))
+		if f.isActive(now) {
+			activeFlows++
+		}
+	}
+	return
+}
+
+// usageByQueue returns the resources used by the flows of each queue.
+func (qm *QueueManager) usageByQueue() map[string]pb.ComputeResource {
+	qm.Lock()
+	defer qm.Unlock()
+
+	now := time.Now()
+	usage := make(map[string]pb.ComputeResource)
+	for name := range qm.queues {
+		usage[name], _ = qm.queueUsage(name, now)
+	}
+	return usage
+}
+
+func (qm *QueueManager) addGrants(f *flowUsage, allocations []*pb.Allocation) {
+	now := time.Now()
+	for _, a := range allocations {
+		f.grants = append(f.grants, grant{
+			agent:    a.GetLocation().URL(),
+			location: *a.GetLocation(),
+			resource: *a.GetAllocated(),
+			time:     now,
+		})
+	}
+	qm.saveFlow(f)
+}
+
+func (qm *QueueManager) saveFlow(f *flowUsage) {
+	if qm.store == nil {
+		return
+	}
+	var allocations []*pb.Allocation
+	for _, g := range f.grants {
+		location, resource := g.location, g.resource
+		allocations = append(allocations, &pb.Allocation{
+			Location:  &location,
+			Allocated: &resource,
+		})
+	}
+	if err := qm.store.SaveFlow(f.flowHashCode, f.queue, allocations); err != nil {
+		log.Printf("Failed to save flow %d: %v", f.flowHashCode, err)
+	}
+}
+
+// restore loads the flows saved by the previous master. The saved grants
+// count towards the usage until the agents report again.
+func (qm *QueueManager) restore() error {
+	if qm.store == nil {
+		return nil
+	}
+	qm.Lock()
+	defer qm.Unlock()
+
+	now := time.Now()
+	return qm.store.LoadFlows(func(flowHashCode uint32, queue string, allocations []*pb.Allocation) {
+		if _, found := qm.queues[queue]; !found {
+			queue = DefaultQueueName
+		}
+		f := &flowUsage{
+			flowHashCode: flowHashCode,
+			queue:        queue,
+			lastRequest:  now,
+			reported:     make(map[string]agentReport),
+		}
+		for _, a := range allocations {
+			f.grants = append(f.grants, grant{
+				agent:    a.GetLocation().URL(),
+				location: *a.GetLocation(),
+				resource: *a.GetAllocated(),
+				time:     now,
+			})
+		}
+		qm.flows[flowHashCode] = f
+	})
+}
+
+// updateAgentReport records the allocated resources of each flow on one agent.
+func (qm *QueueManager) updateAgentReport(heartbeat *pb.Heartbeat) {
+	qm.Lock()
+	defer qm.Unlock()
+
+	now := time.Now()
+	agent := heartbeat.GetLocation().URL()
+	reportedFlows := make(map[uint32]bool)
+	for _, fa := range heartbeat.GetFlowAllocated() {
+		reportedFlows[fa.GetFlowHashCode()] = true
+		f, found := qm.flows[fa.GetFlowHashCode()]
+		if !found {
+			// the flow was submitted before this master started
+			f = &flowUsage{
+				flowHashCode: fa.GetFlowHashCode(),
+				queue:        DefaultQueueName,
+				reported:     make(map[string]agentReport),
+			}
+			qm.flows[fa.GetFlowHashCode()] = f
+		}
+		f.reported[agent] = agentReport{allocated: *fa.GetAllocated(), time: now}
+	}
+	for flowHashCode, f := range qm.flows {
+		if !reportedFlows[flowHashCode] {
+			if _, found := f.reported[agent]; found {
+				f.reported[agent] = agentReport{time: now}
+			}
+		}
+	}
+	qm.purgeInactiveFlows(now)
+}
+
+// removeAgent forgets the usage on a lost agent.
+func (qm *QueueManager) removeAgent(location *pb.Location) {
+	qm.Lock()
+	defer qm.Unlock()
+
+	agent := location.URL()
+	for _, f := range qm.flows {
+		delete(f.reported, agent)
+	}
+}
+
+func (qm *QueueManager) purgeInactiveFlows(now time.Time) {
+	for flowHashCode, f := range qm.flows {
+		if !f.isActive(now) {
+			delete(qm.flows, flowHashCode)
+			if qm.store != nil {
+				qm.store.DeleteFlow(flowHashCode)
+			}
+			continue
+		}
+		var grants []grant
+		for _, g := range f.grants {
+			if now.Sub(g.time) <= grantTimeout {
+				grants = append(grants, g)
+			}
+		}
+		f.grants = grants
+	}
+}
+
+// percentOf returns the percent of the cpu and memory of the total resource.
+func percentOf(total pb.ComputeResource, percent float64) pb.ComputeResource {
+	return pb.ComputeResource{
+		CpuCount: int32(float64(total.CpuCount) * percent / 100),
+		MemoryMb: int64(float64(total.MemoryMb) * percent / 100),
+	}
+}
+
+// dividedBy returns the resource shared by n users.
+func dividedBy(r pb.ComputeResource, n int) pb.ComputeResource {
+	if n <= 1 {
+		return r
+	}
+	return pb.ComputeResource{
+		CpuCount: r.CpuCount / int32(n),
+		MemoryMb: r.MemoryMb / int64(n),
+	}
+}
@@ -0,0 +1,322 @@
+package master
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+// cpus returns a resource of the cpu count and 1GB memory per cpu.
+func cpus(n int) pb.ComputeResource {
+	return pb.ComputeResource{CpuCount: int32(n), MemoryMb: int64(n) * 1024}
+}
+
+// newTestMaster has one agent with 10 cpus, of which the flows use the allocated ones.
+func newTestMaster(queues []*Queue, flows ...*flowUsage) *MasterServer {
+	s := &MasterServer{
+		Topology:     NewTopology(),
+		queueManager: NewQueueManager(queues, nil),
+	}
+	var allocated pb.ComputeResource
+	for _, f := range flows {
+		s.queueManager.flows[f.flowHashCode] = f
+		allocated = allocated.Plus(f.usage(time.Now()))
+	}
+	resource := cpus(10)
+	s.Topology.UpdateAgentInformation(&pb.Heartbeat{
+		Location:  &pb.Location{DataCenter: "dc", Rack: "rack", Server: "127.0.0.1", Port: 1},
+		Resource:  &resource,
+		Allocated: &allocated,
+	})
+	return s
+}
+
+// usingFlow is a flow in the queue, with the resources reported by the agent.
+func usingFlow(flowHashCode uint32, queue, agent string, used pb.ComputeResource) *flowUsage {
+	return &flowUsage{
+		flowHashCode: flowHashCode,
+		queue:        queue,
+		reported:     map[string]agentReport{agent: {allocated: used, time: time.Now()}},
+	}
+}
+
+func TestLoadQueues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		queues  []*Queue
+		isError bool
+	}{
+		{
+			name:    "queues",
+			content: "# name guaranteed max users\nbatch 20 50%\n\nadhoc 10% 100 alice,bob\n",
+			queues: []*Queue{
+				{Name: "batch", GuaranteedPercent: 20, MaxPercent: 50},
+				{Name: "adhoc", GuaranteedPercent: 10, MaxPercent: 100, Users: []string{"alice", "bob"}},
+			},
+		},
+		{name: "missing max percent", content: "batch 20\n", isError: true},
+		{name: "too many fields", content: "batch 20 50 alice bob\n", isError: true},
+		{name: "bad guaranteed percent", content: "batch twenty 50\n", isError: true},
+		{name: "bad max percent", content: "batch 20 5O\n", isError: true},
+		{name: "guaranteed over max", content: "batch 60 50\n", isError: true},
+		{name: "max over 100", content: "batch 20 150\n", isError: true},
+		{name: "negative guaranteed", content: "batch -10 50\n", isError: true},
+	}
+	for _, test := range tests {
+		f, err := ioutil.TempFile("", "gleam-queues")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(test.content)
+		f.Close()
+		queues, err := LoadQueues(f.Name())
+		os.Remove(f.Name())
+		if test.isError {
+			if err == nil {
+				t.Errorf("%s: expecting an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if !reflect.DeepEqual(queues, test.queues) {
+			t.Errorf("%s: loaded %+v, expecting %+v", test.name, queues, test.queues)
+		}
+	}
+}
+
+func TestAllocateResources(t *testing.T) {
+	agent := "127.0.0.1:1"
+	tests := []struct {
+		name      string
+		queues    []*Queue
+		flows     []*flowUsage
+		queue     string
+		requested int
+		allocated int
+		// the flow expected to be preempted, 0 for none
+		preempted uint32
+	}{
+		{
+			name:      "within the max share",
+			queues:    []*Queue{{Name: "batch", MaxPercent: 100}},
+			queue:     "batch",
+			requested: 8,
+			allocated: 8,
+		},
+		{
+			name:      "limited by the max share",
+			queues:    []*Queue{{Name: "batch", MaxPercent: 50}},
+			queue:     "batch",
+			requested: 8,
+			allocated: 5,
+		},
+		{
+			name:      "max share used by another flow",
+			queues:    []*Queue{{Name: "batch", MaxPercent: 50}},
+			flows:     []*flowUsage{usingFlow(2, "batch", agent, cpus(4))},
+			queue:     "batch",
+			requested: 8,
+			allocated: 1,
+		},
+		{
+			name:      "fair share with another active flow",
+			queues:    []*Queue{{Name: "batch", MaxPercent: 100}},
+			flows:     []*flowUsage{usingFlow(2, "batch", agent, cpus(2))},
+			queue:     "batch",
+			requested: 8,
+			allocated: 5,
+		},
+		{
+			name:      "flows in other queues do not share",
+			queues:    []*Queue{{Name: "batch", MaxPercent: 100}},
+			flows:     []*flowUsage{usingFlow(2, DefaultQueueName, agent, cpus(2))},
+			queue:     "batch",
+			requested: 8,
+			allocated: 8,
+		},
+		{
+			name: "preempt for the guaranteed share",
+			queues: []*Queue{
+				{Name: "batch", GuaranteedPercent: 50, MaxPercent: 100},
+				{Name: "adhoc", MaxPercent: 100},
+			},
+			flows:     []*flowUsage{usingFlow(2, "adhoc", agent, cpus(10))},
+			queue:     "batch",
+			requested: 3,
+			allocated: 0,
+			preempted: 2,
+		},
+		{
+			name: "no preemption above the guaranteed share",
+			queues: []*Queue{
+				{Name: "batch", GuaranteedPercent: 20, MaxPercent: 100},
+				{Name: "adhoc", MaxPercent: 100},
+			},
+			flows: []*flowUsage{
+				usingFlow(2, "adhoc", agent, cpus(7)),
+				usingFlow(3, "batch", agent, cpus(3)),
+			},
+			queue:     "batch",
+			requested: 3,
+			allocated: 0,
+		},
+	}
+	for _, test := range tests {
+		s := newTestMaster(test.queues, test.flows...)
+		request := &pb.ComputeRequest{
+			DataCenter:   "dc",
+			FlowHashCode: 1,
+			Queue:        test.queue,
+		}
+		for i := 0; i < test.requested; i++ {
+			r := cpus(1)
+			request.ComputeResources = append(request.ComputeResources, &r)
+		}
+		allocations, err := s.allocateResources(request)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(allocations) != test.allocated {
+			t.Errorf("%s: allocated %d, expecting %d", test.name, len(allocations), test.allocated)
+		}
+		for _, f := range test.flows {
+			if isPreempted := !f.lastPreempted.IsZero(); isPreempted != (f.flowHashCode == test.preempted) {
+				t.Errorf("%s: flow %d preempted %v", test.name, f.flowHashCode, isPreempted)
+			}
+		}
+	}
+}
+
+func TestAllocateResourcesRejectsQueue(t *testing.T) {
+	s := newTestMaster([]*Queue{{Name: "batch", MaxPercent: 100, Users: []string{"alice"}}})
+	r := cpus(1)
+	for _, request := range []*pb.ComputeRequest{
+		{FlowHashCode: 1, Queue: "missing", ComputeResources: []*pb.ComputeResource{&r}},
+		{FlowHashCode: 1, Queue: "batch", Username: "bob", ComputeResources: []*pb.ComputeResource{&r}},
+	} {
+		if _, err := s.allocateResources(request); err == nil {
+			t.Errorf("expecting an error for queue %s and user %s", request.Queue, request.Username)
+		}
+	}
+}
+
+func TestFindPreemptions(t *testing.T) {
+	type victim struct {
+		flowHashCode uint32
+		agent        string
+		cpus         int32
+	}
+	tests := []struct {
+		name string
+		// flows recently preempted
+		recent  []uint32
+		victims []victim
+	}{
+		{
+			name: "the largest flows of the queues most over their guaranteed shares first",
+			victims: []victim{
+				{2, "agent2:1", 25},
+				{3, "agent3:1", 5},
+				{4, "agent4:1", 5},
+			},
+		},
+		{
+			name:   "skip flows recently preempted",
+			recent: []uint32{2},
+			victims: []victim{
+				{3, "agent3:1", 15},
+				{4, "agent4:1", 10},
+			},
+		},
+	}
+	for _, test := range tests {
+		qm := NewQueueManager([]*Queue{
+			{Name: "needy", GuaranteedPercent: 50, MaxPercent: 100},
+			{Name: "large", GuaranteedPercent: 10, MaxPercent: 100},
+			{Name: "small", GuaranteedPercent: 10, MaxPercent: 100},
+		}, nil)
+		for _, f := range []*flowUsage{
+			// the needy queue is over its guaranteed share, but is not preempted
+			usingFlow(1, "needy", "agent1:1", cpus(55)),
+			// 30 over the guaranteed share
+			usingFlow(2, "large", "agent2:1", cpus(25)),
+			usingFlow(3, "large", "agent3:1", cpus(15)),
+			// 10 over the guaranteed share
+			usingFlow(4, "small", "agent4:1", cpus(20)),
+		} {
+			qm.flows[f.flowHashCode] = f
+		}
+		now := time.Now()
+		for _, flowHashCode := range test.recent {
+			qm.flows[flowHashCode].lastPreempted = now
+		}
+
+		var victims []victim
+		for _, p := range qm.findPreemptions("needy", cpus(35), cpus(100), now) {
+			resource := p.request.GetResource()
+			if resource.GetMemoryMb() != int64(resource.GetCpuCount())*1024 {
+				t.Errorf("%s: preempting %+v of flow %d", test.name, resource, p.request.GetFlowHashCode())
+			}
+			victims = append(victims, victim{p.request.GetFlowHashCode(), p.agent, resource.GetCpuCount()})
+		}
+		if !reflect.DeepEqual(victims, test.victims) {
+			t.Errorf("%s: preempted %v, expecting %v", test.name, victims, test.victims)
+		}
+	}
+}
+
+func TestGrantExpiry(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		grant  time.Time
+		report time.Time
+		usage  pb.ComputeResource
+	}{
+		{"granted", now.Add(-time.Second), time.Time{}, cpus(3)},
+		{"expired grant", now.Add(-grantTimeout - time.Second), time.Time{}, cpus(0)},
+		{"reported after the grant", now.Add(-2 * time.Second), now.Add(-time.Second), cpus(1)},
+		{"reported before the grant", now.Add(-time.Second), now.Add(-2 * time.Second), cpus(4)},
+	}
+	for _, test := range tests {
+		f := &flowUsage{reported: make(map[string]agentReport)}
+		if !test.report.IsZero() {
+			f.reported["agent:1"] = agentReport{allocated: cpus(1), time: test.report}
+		}
+		f.grants = []grant{{agent: "agent:1", resource: cpus(3), time: test.grant}}
+		if usage := f.usage(now); usage != test.usage {
+			t.Errorf("%s: usage %+v, expecting %+v", test.name, usage, test.usage)
+		}
+	}
+
+	qm := NewQueueManager(nil, nil)
+	qm.flows[1] = &flowUsage{
+		flowHashCode: 1,
+		lastRequest:  now,
+		reported:     make(map[string]agentReport),
+		grants: []grant{
+			{agent: "agent:1", resource: cpus(1), time: now.Add(-grantTimeout - time.Second)},
+			{agent: "agent:1", resource: cpus(2), time: now},
+		},
+	}
+	qm.flows[2] = &flowUsage{
+		flowHashCode: 2,
+		lastRequest:  now.Add(-activeFlowTimeout - time.Second),
+		reported:     make(map[string]agentReport),
+		grants:       []grant{{agent: "agent:1", resource: cpus(1), time: now.Add(-grantTimeout - time.Second)}},
+	}
+	qm.purgeInactiveFlows(now)
+	if _, found := qm.flows[2]; found {
+		t.Errorf("the inactive flow with only expired grants is kept")
+	}
+	if f, found := qm.flows[1]; !found || len(f.grants) != 1 || f.grants[0].resource != cpus(2) {
+		t.Errorf("expecting only the recent grant kept")
+	}
+}
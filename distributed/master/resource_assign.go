@@ -2,12 +2,197 @@ package master
 
 import (
 	"fmt"
+	"log"
 	"math/rand"
 	"sort"
+	"time"
 
 	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
 )
 
+// allocateResources allocates the requested resources for a flow. The allocations
+// are limited by the queue's max share, and the flow's fair share among
+// the active flows in the same queue. If the cluster is full but the queue
+// is below its guaranteed share, flows in over-quota queues are preempted.
+func (s *MasterServer) allocateResources(in *pb.ComputeRequest) ([]*pb.Allocation, error) {
+	qm := s.queueManager
+	qm.Lock()
+	defer qm.Unlock()
+
+	f, q, err := qm.getFlow(in.GetFlowHashCode(), in.GetQueue(), in.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	total := s.Topology.Resource
+	queueUsed, activeFlows := qm.queueUsage(q.Name, now)
+	flowUsed := f.usage(now)
+	queueLimit := percentOf(total, q.MaxPercent)
+	flowLimit := dividedBy(queueLimit, activeFlows)
+
+	var requests []*pb.ComputeResource
+	var preferred []*pb.Location
+	var requested pb.ComputeResource
+	for i, r := range in.GetComputeResources() {
+		// a flow can always start one executor if the queue limit allows
+		if !flowUsed.Plus(requested).IsZero() && !flowLimit.Covers(flowUsed.Plus(requested).Plus(*r)) {
+			continue
+		}
+		if !queueLimit.Covers(queueUsed.Plus(requested).Plus(*r)) {
+			continue
+		}
+		requests = append(requests, r)
+		if i < len(in.GetPreferredLocations()) {
+			preferred = append(preferred, in.GetPreferredLocations()[i])
+		} else {
+			preferred = append(preferred, nil)
+		}
+		requested = requested.Plus(*r)
+	}
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	var allocations []*pb.Allocation
+	dcName := in.GetDataCenter()
+	if dcName == "" {
+		dcName, err = s.Topology.allocateDataCenter(requests)
+	}
+	if err == nil {
+		dc, hasDc := s.Topology.GetDataCenter(dcName)
+		if !hasDc {
+			return nil, fmt.Errorf("Failed to find existing data center: %s", dcName)
+		}
+		allocations = s.Topology.findServers(dc, requests, preferred)
+		qm.addGrants(f, allocations)
+	}
+
+	// the cluster is full, take back resources for the guaranteed share
+	if len(allocations) < len(requests) {
+		var allocated pb.ComputeResource
+		for _, a := range allocations {
+			allocated = allocated.Plus(*a.GetAllocated())
+		}
+		queueUsed = queueUsed.Plus(allocated)
+		guaranteed := percentOf(total, q.GuaranteedPercent)
+		if !queueUsed.Covers(guaranteed) {
+			needed := minResource(requested.Minus(allocated), nonNegative(guaranteed.Minus(queueUsed)))
+			if preemptions := qm.findPreemptions(q.Name, needed, total, now); len(preemptions) > 0 {
+				go s.preempt(preemptions)
+			}
+		}
+	}
+
+	return allocations, err
+}
+
+type preemption struct {
+	agent   string
+	request *pb.PreemptRequest
+}
+
+// findPreemptions picks the flows to preempt, from the queues most over their guaranteed shares.
+func (qm *QueueManager) findPreemptions(needyQueue string, needed, total pb.ComputeResource, now time.Time) (ret []preemption) {
+
+	type overQuota struct {
+		queue string
+		over  pb.ComputeResource
+	}
+	var victims []overQuota
+	for name, q := range qm.queues {
+		if name == needyQueue {
+			continue
+		}
+		used, _ := qm.queueUsage(name, now)
+		over := nonNegative(used.Minus(percentOf(total, q.GuaranteedPercent)))
+		if !over.IsZero() {
+			victims = append(victims, overQuota{name, over})
+		}
+	}
+	sort.Slice(victims, func(i, j int) bool {
+		return isLarger(victims[i].over, victims[j].over)
+	})
+
+	for _, v := range victims {
+		var flows []*flowUsage
+		for _, f := range qm.flows {
+			if f.queue == v.queue && now.Sub(f.lastPreempted) > preemptInterval {
+				flows = append(flows, f)
+			}
+		}
+		sort.Slice(flows, func(i, j int) bool {
+			return isLarger(flows[i].usage(now), flows[j].usage(now))
+		})
+		for _, f := range flows {
+			for agent, r := range f.reported {
+				if needed.IsZero() {
+					return
+				}
+				if v.over.IsZero() {
+					break
+				}
+				amount := minResource(minResource(r.allocated, needed), v.over)
+				if amount.IsZero() {
+					continue
+				}
+				ret = append(ret, preemption{agent, &pb.PreemptRequest{
+					FlowHashCode: f.flowHashCode,
+					Resource:     &amount,
+				}})
+				f.lastPreempted = now
+				needed = nonNegative(needed.Minus(amount))
+				v.over = nonNegative(v.over.Minus(amount))
+			}
+		}
+	}
+	return
+}
+
+func (s *MasterServer) preempt(preemptions []preemption) {
+	for _, p := range preemptions {
+		err := withAgentClient(p.agent, func(client pb.GleamAgentClient) error {
+			response, err := client.Preempt(context.Background(), p.request)
+			if err == nil {
+				log.Printf("preempted %+v of flow %d on %s", response.GetPreempted(), p.request.GetFlowHashCode(), p.agent)
+			}
+			return err
+		})
+		if err != nil {
+			log.Printf("Failed to preempt flow %d on %s: %v", p.request.GetFlowHashCode(), p.agent, err)
+		}
+	}
+}
+
+// isLarger orders the resources by memory, then by cpu count.
+func isLarger(a, b pb.ComputeResource) bool {
+	if a.MemoryMb != b.MemoryMb {
+		return a.MemoryMb > b.MemoryMb
+	}
+	return a.CpuCount > b.CpuCount
+}
+
+func minResource(a, b pb.ComputeResource) pb.ComputeResource {
+	if b.CpuCount < a.CpuCount {
+		a.CpuCount = b.CpuCount
+	}
+	if b.MemoryMb < a.MemoryMb {
+		a.MemoryMb = b.MemoryMb
+	}
+	return a
+}
+
+func nonNegative(a pb.ComputeResource) pb.ComputeResource {
+	if a.CpuCount < 0 {
+		a.CpuCount = 0
+	}
+	if a.MemoryMb < 0 {
+		a.MemoryMb = 0
+	}
+	return a
+}
+
 func (tp *Topology) allocateDataCenter(requests []*pb.ComputeResource) (string, error) {
 
 	var total pb.ComputeResource
@@ -32,6 +217,9 @@ func (tp *Topology) allocateServersOnRack(dc *DataCenter, rack *Rack, requests [
 	allocated []*pb.Allocation, remainingRequests []*pb.ComputeResource) {
 
 	agents := rack.GetAgents()
+	if len(agents) == 0 {
+		return nil, requests
+	}
 	start := rand.Intn(len(agents)) - 1
 	for _, req := range requests {
 		request := req
@@ -44,19 +232,8 @@ func (tp *Topology) allocateServersOnRack(dc *DataCenter, rack *Rack, requests [
 			}
 			agent := agents[start]
 
-			available := agent.Resource.Minus(agent.Allocated)
-
-			// fmt.Printf("available %v, requested %v\n", available, request.GetMemoryMb())
-			if available.Covers(*request) {
-				allocated = append(allocated, &pb.Allocation{
-					Location:  &agent.Location,
-					Allocated: request,
-				})
-				agent.Allocated = agent.Allocated.Plus(*request)
-				rack.Allocated = rack.Allocated.Plus(*request)
-				dc.Allocated = dc.Allocated.Plus(*request)
-				tp.Allocated = tp.Allocated.Plus(*request)
-				available = available.Minus(*request)
+			if allocation := tp.allocateOnAgent(dc, rack, agent, request); allocation != nil {
+				allocated = append(allocated, allocation)
 				hasAllocation = true
 				break
 			}
@@ -71,7 +248,88 @@ func (tp *Topology) allocateServersOnRack(dc *DataCenter, rack *Rack, requests [
 	return
 }
 
-func (tp *Topology) findServers(dc *DataCenter, requests []*pb.ComputeResource) (ret []*pb.Allocation) {
+func (tp *Topology) allocateOnAgent(dc *DataCenter, rack *Rack, agent *AgentInformation, request *pb.ComputeResource) *pb.Allocation {
+	if agent.Draining || agent.isDiskFull() {
+		return nil
+	}
+
+	available := agent.Resource.Minus(agent.Allocated)
+
+	// fmt.Printf("available %v, requested %v\n", available, request.GetMemoryMb())
+	if !available.Covers(*request) {
+		return nil
+	}
+	agent.Allocated = agent.Allocated.Plus(*request)
+	rack.Allocated = rack.Allocated.Plus(*request)
+	dc.Allocated = dc.Allocated.Plus(*request)
+	tp.Allocated = tp.Allocated.Plus(*request)
+	return &pb.Allocation{
+		Location:  &agent.Location,
+		Allocated: request,
+	}
+}
+
+// an agent with less free disk for the dataset files gets no more executors
+const minFreeDiskPercent = 5
+
+// isDiskFull checks the disk reported as the resource and allocated disk_mb.
+func (ai *AgentInformation) isDiskFull() bool {
+	capacity := ai.Resource.DiskMb
+	return capacity > 0 && (capacity-ai.Allocated.DiskMb)*100 < capacity*minFreeDiskPercent
+}
+
+// allocatePreferred allocates on the preferred server, or else on the
+// preferred rack, or the rack of the preferred server.
+func (tp *Topology) allocatePreferred(dc *DataCenter, request *pb.ComputeResource, preferred *pb.Location) *pb.Allocation {
+	rackName := preferred.GetRack()
+	if preferred.GetServer() != "" {
+		for _, rack := range dc.GetRacks() {
+			for _, agent := range rack.GetAgents() {
+				if !agent.Location.IsOnHost(preferred.GetServer()) {
+					continue
+				}
+				if allocation := tp.allocateOnAgent(dc, rack, agent, request); allocation != nil {
+					return allocation
+				}
+				if rackName == "" {
+					rackName = rack.Name
+				}
+			}
+		}
+	}
+
+	if rackName == "" {
+		return nil
+	}
+	rack, hasRack := dc.GetRack(rackName)
+	if !hasRack {
+		return nil
+	}
+	allocated, _ := tp.allocateServersOnRack(dc, rack, []*pb.ComputeResource{request})
+	if len(allocated) == 0 {
+		return nil
+	}
+	return allocated[0]
+}
+
+// findServers allocates the requests with locality preferences first,
+// then the others on the racks with the most available resources.
+func (tp *Topology) findServers(dc *DataCenter, requests []*pb.ComputeResource, preferred []*pb.Location) (ret []*pb.Allocation) {
+
+	var remaining []*pb.ComputeResource
+	for i, request := range requests {
+		if i < len(preferred) && (preferred[i].GetServer() != "" || preferred[i].GetRack() != "") {
+			if allocation := tp.allocatePreferred(dc, request, preferred[i]); allocation != nil {
+				ret = append(ret, allocation)
+				continue
+			}
+		}
+		remaining = append(remaining, request)
+	}
+	requests = remaining
+	if len(requests) == 0 {
+		return
+	}
 
 	// sort racks by unallocated resources
 	var racks []*Rack
@@ -83,7 +341,8 @@ func (tp *Topology) findServers(dc *DataCenter, requests []*pb.ComputeResource)
 	sort.Sort(byRequestedResources(requests))
 
 	for _, rack := range racks {
-		allocated, requests := tp.allocateServersOnRack(dc, rack, requests)
+		var allocated []*pb.Allocation
+		allocated, requests = tp.allocateServersOnRack(dc, rack, requests)
 		ret = append(ret, allocated...)
 		if len(requests) == 0 {
 			break
@@ -24,23 +24,32 @@ func (tp *Topology) UpdateAgentInformation(ai *pb.Heartbeat) {
 	// fmt.Printf("hasOldInfo %+v, oldInfo %+v\n", hasOldInfo, oldInfo)
 	if hasOldInfo {
 		deltaResource = deltaResource.Minus(oldInfo.Resource)
-		if !deltaResource.IsZero() {
+		if !deltaResource.IsZero() || deltaResource.DiskMb != 0 {
 			oldInfo.Resource = *ai.Resource
 		}
+		// the agent stopped draining, if the drain is cancelled or failed
+		if ai.Draining {
+			oldInfo.Draining = true
+		} else if oldInfo.reportedDraining {
+			oldInfo.Draining = false
+		}
+		oldInfo.reportedDraining = ai.Draining
 		oldInfo.LastHeartBeat = time.Now()
 	} else {
 		rack.AddAgent(&AgentInformation{
-			Location:      *ai.Location,
-			LastHeartBeat: time.Now(),
-			Resource:      *ai.Resource,
-			Allocated:     *ai.Allocated,
+			Location:         *ai.Location,
+			LastHeartBeat:    time.Now(),
+			Resource:         *ai.Resource,
+			Allocated:        *ai.Allocated,
+			Draining:         ai.Draining,
+			reportedDraining: ai.Draining,
 		})
 	}
 
 	tp.Lock()
 	defer tp.Unlock()
 
-	if !deltaResource.IsZero() {
+	if !deltaResource.IsZero() || deltaResource.DiskMb != 0 {
 		rack.Resource = rack.Resource.Plus(deltaResource)
 		dc.Resource = dc.Resource.Plus(deltaResource)
 		tp.Resource = tp.Resource.Plus(deltaResource)
@@ -50,7 +59,7 @@ func (tp *Topology) UpdateAgentInformation(ai *pb.Heartbeat) {
 		deltaAllocated := ai.Allocated.Minus(oldInfo.Allocated)
 		oldInfo.Allocated = *ai.Allocated
 		// fmt.Printf("deltaAllocated %+v\n", deltaAllocated)
-		if !deltaAllocated.IsZero() {
+		if !deltaAllocated.IsZero() || deltaAllocated.DiskMb != 0 {
 			rack.Allocated = rack.Allocated.Plus(deltaAllocated)
 			dc.Allocated = dc.Allocated.Plus(deltaAllocated)
 			tp.Allocated = tp.Allocated.Plus(deltaAllocated)
@@ -110,3 +119,43 @@ func (tp *Topology) findAgentInformation(location *pb.Location) (*AgentInformati
 	ai, ok := r.GetAgent(location.URL())
 	return ai, ok
 }
+
+// findAgentByAddress looks up the agent by its "server:port" address.
+func (tp *Topology) findAgentByAddress(address string) (*AgentInformation, bool) {
+	for _, dc := range tp.GetDataCenters() {
+		for _, rack := range dc.GetRacks() {
+			if ai, ok := rack.GetAgent(address); ok {
+				return ai, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (tp *Topology) setDraining(ai *AgentInformation, draining bool) {
+	tp.Lock()
+	defer tp.Unlock()
+
+	ai.Draining = draining
+}
+
+// agentLocations lists the agents in the data center that are not drained.
+func (tp *Topology) agentLocations(dataCenter string) (ret []*pb.Location) {
+	dc, hasDc := tp.GetDataCenter(dataCenter)
+	if !hasDc {
+		return nil
+	}
+
+	tp.RLock()
+	defer tp.RUnlock()
+
+	for _, rack := range dc.GetRacks() {
+		for _, ai := range rack.GetAgents() {
+			if !ai.Draining {
+				location := ai.Location
+				ret = append(ret, &location)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package master
+
+import (
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+func TestDrainingAgent(t *testing.T) {
+	tp := NewTopology()
+	location := &pb.Location{DataCenter: "dc", Rack: "rack", Server: "127.0.0.1", Port: 1}
+	heartbeat := func(draining bool) {
+		resource, allocated := cpus(10), cpus(0)
+		tp.UpdateAgentInformation(&pb.Heartbeat{
+			Location:  location,
+			Resource:  &resource,
+			Allocated: &allocated,
+			Draining:  draining,
+		})
+	}
+	heartbeat(false)
+	ai, _ := tp.findAgentInformation(location)
+
+	steps := []struct {
+		name string
+		// mark by the master, or a heartbeat
+		mark, isHeartbeat, draining bool
+		expected                    bool
+	}{
+		{"master drains the agent", true, false, true, true},
+		{"heartbeat before the agent starts draining", false, true, false, true},
+		{"agent is draining", false, true, true, true},
+		{"agent stopped draining", false, true, false, false},
+		{"master drains the agent again", true, false, true, true},
+		{"master stops draining the agent", true, false, false, false},
+		{"heartbeat after the drain failed", false, true, false, false},
+	}
+	for _, step := range steps {
+		if step.mark {
+			tp.setDraining(ai, step.draining)
+		}
+		if step.isHeartbeat {
+			heartbeat(step.draining)
+		}
+		if ai.Draining != step.expected {
+			t.Errorf("%s: draining %v, expecting %v", step.name, ai.Draining, step.expected)
+		}
+	}
+}
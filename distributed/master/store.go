@@ -0,0 +1,454 @@
+package master
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	jobsBucket        = []byte("jobs")
+	historyBucket     = []byte("history")
+	flowQueuesBucket  = []byte("flowQueues")
+	allocationsBucket = []byte("allocations")
+	// bucket name + 0 + key => version of the last change
+	versionsBucket = []byte("versions")
+	// version => bucket name + 0 + key, to read the changes in version order
+	changesBucket = []byte("changes")
+	// version => bucket name + 0 + key deleted in that version
+	deletionsBucket = []byte("deletions")
+	metaBucket      = []byte("meta")
+	versionKey      = []byte("version")
+	termKey         = []byte("term")
+	// the last version of the deletions no longer kept
+	compactedKey = []byte("compacted")
+
+	replicatedBuckets = [][]byte{jobsBucket, historyBucket, flowQueuesBucket, allocationsBucket}
+)
+
+// the state file is locked by the master using it
+const storeOpenTimeout = 5 * time.Second
+
+// StateStore persists the master state in a local bolt file, so a restarted
+// or standby master can continue with the job statuses and allocations.
+type StateStore struct {
+	db *bolt.DB
+	// keep the deleted keys until the standby master has replicated them
+	keepDeletions bool
+}
+
+func NewStateStore(dir string) (*StateStore, error) {
+	fileName := filepath.Join(dir, "master.db")
+	db, err := bolt.Open(fileName, 0644, &bolt.Options{Timeout: storeOpenTimeout})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("open master state %s: locked by another master process", fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open master state in %s: %v", dir, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		hasChanges := tx.Bucket(changesBucket) != nil
+		for _, name := range append(replicatedBuckets, versionsBucket, changesBucket, deletionsBucket, metaBucket) {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if !hasChanges {
+			return indexChanges(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create buckets in master state: %v", err)
+	}
+	store := &StateStore{db: db}
+	store.importLegacyLogs(dir)
+	return store, nil
+}
+
+func (store *StateStore) Close() error {
+	return store.db.Close()
+}
+
+// importLegacyLogs moves the job statuses saved as f<id>.log files into the store.
+// A file which is not a job status is renamed to .bad, and a file failed to
+// save is kept to import again.
+func (store *StateStore) importLegacyLogs(dir string) {
+	files, _ := filepath.Glob(fmt.Sprintf("%s/f[0-9]*\\.log", dir))
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			log.Printf("Failed to import job status %s: %v", f, err)
+			continue
+		}
+		status := &pb.FlowExecutionStatus{}
+		if err := proto.Unmarshal(data, status); err != nil {
+			log.Printf("Failed to import job status %s: %v", f, err)
+			os.Rename(f, f+".bad")
+			continue
+		}
+		if err := store.put(jobsBucket, uint32Key(status.GetId()), data); err != nil {
+			log.Printf("Failed to import job status %s: %v", f, err)
+			continue
+		}
+		if err := store.put(historyBucket, uint32Key(status.GetId()), data); err != nil {
+			log.Printf("Failed to import job status %s: %v", f, err)
+			continue
+		}
+		os.Remove(f)
+	}
+}
+
+// SaveJob saves the job status, and also adds completed jobs to the history.
+func (store *StateStore) SaveJob(status *pb.FlowExecutionStatus) error {
+	data, err := proto.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err = store.put(jobsBucket, uint32Key(status.GetId()), data); err != nil {
+		return err
+	}
+	if status.GetDriver().GetStopTime() == 0 {
+		return nil
+	}
+	return store.put(historyBucket, uint32Key(status.GetId()), data)
+}
+
+// DeleteJob removes the job from the recent jobs. The job history is kept.
+func (store *StateStore) DeleteJob(id uint32) error {
+	return store.delete(jobsBucket, uint32Key(id))
+}
+
+func (store *StateStore) LoadJobs(fn func(status *pb.FlowExecutionStatus)) error {
+	return store.forEachJob(jobsBucket, fn)
+}
+
+// GetHistoryJob returns the completed job, or nil if not found.
+func (store *StateStore) GetHistoryJob(id uint32) (status *pb.FlowExecutionStatus, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(historyBucket).Get(uint32Key(id))
+		if data == nil {
+			return nil
+		}
+		status = &pb.FlowExecutionStatus{}
+		return proto.Unmarshal(data, status)
+	})
+	return
+}
+
+func (store *StateStore) ForEachHistoryJob(fn func(status *pb.FlowExecutionStatus)) error {
+	return store.forEachJob(historyBucket, fn)
+}
+
+// DeleteHistoryJobs removes the completed jobs from the history in one transaction.
+func (store *StateStore) DeleteHistoryJobs(ids []uint32) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		for _, id := range ids {
+			if err := store.deleteInTx(tx, historyBucket, uint32Key(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (store *StateStore) forEachJob(bucket []byte, fn func(status *pb.FlowExecutionStatus)) error {
+	return store.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+			status := &pb.FlowExecutionStatus{}
+			if err := proto.Unmarshal(v, status); err == nil {
+				fn(status)
+			}
+			return nil
+		})
+	})
+}
+
+// SaveFlow records the queue of a flow, and the allocations recently granted to it.
+func (store *StateStore) SaveFlow(flowHashCode uint32, queue string, allocations []*pb.Allocation) error {
+	data, err := proto.Marshal(&pb.AllocationResult{Allocations: allocations})
+	if err != nil {
+		return err
+	}
+	if err = store.put(flowQueuesBucket, uint32Key(flowHashCode), []byte(queue)); err != nil {
+		return err
+	}
+	return store.put(allocationsBucket, uint32Key(flowHashCode), data)
+}
+
+func (store *StateStore) DeleteFlow(flowHashCode uint32) error {
+	if err := store.delete(flowQueuesBucket, uint32Key(flowHashCode)); err != nil {
+		return err
+	}
+	return store.delete(allocationsBucket, uint32Key(flowHashCode))
+}
+
+func (store *StateStore) LoadFlows(fn func(flowHashCode uint32, queue string, allocations []*pb.Allocation)) error {
+	return store.db.View(func(tx *bolt.Tx) error {
+		allocationsB := tx.Bucket(allocationsBucket)
+		return tx.Bucket(flowQueuesBucket).ForEach(func(k, v []byte) error {
+			result := &pb.AllocationResult{}
+			if data := allocationsB.Get(k); data != nil {
+				proto.Unmarshal(data, result)
+			}
+			fn(binary.BigEndian.Uint32(k), string(v), result.GetAllocations())
+			return nil
+		})
+	})
+}
+
+// Changes returns the records changed or deleted after the version, and the
+// current version. It returns all the records instead, as a snapshot, for a
+// new standby master, or if the deletions after the version are compacted.
+func (store *StateStore) Changes(sinceVersion uint64) (records []*pb.MasterState_Record, version uint64, isSnapshot bool, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		version = readVersion(tx)
+		isSnapshot = sinceVersion == 0 || sinceVersion < readMeta(tx, compactedKey)
+		if !isSnapshot && sinceVersion >= version {
+			return nil
+		}
+		if isSnapshot {
+			sinceVersion = 0
+		}
+		c := tx.Bucket(changesBucket).Cursor()
+		for k, v := c.Seek(uint64Key(sinceVersion + 1)); k != nil; k, v = c.Next() {
+			sep := bytes.IndexByte(v, 0)
+			if sep < 0 {
+				continue
+			}
+			bucket, key := v[:sep], v[sep+1:]
+			value := tx.Bucket(bucket).Get(key)
+			if value == nil && isSnapshot {
+				continue
+			}
+			records = append(records, &pb.MasterState_Record{
+				Bucket:  string(bucket),
+				Key:     append([]byte(nil), key...),
+				Value:   append([]byte(nil), value...),
+				Deleted: value == nil,
+			})
+		}
+		return nil
+	})
+	return
+}
+
+// CompactDeletions forgets the keys deleted up to the version, once the
+// standby master has replicated them.
+func (store *StateStore) CompactDeletions(upToVersion uint64) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		var versions [][]byte
+		c := tx.Bucket(deletionsBucket).Cursor()
+		for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) <= upToVersion; k, _ = c.Next() {
+			versions = append(versions, append([]byte(nil), k...))
+		}
+		for _, versionBytes := range versions {
+			if err := forgetVersion(tx, versionBytes); err != nil {
+				return err
+			}
+		}
+		if len(versions) == 0 {
+			return nil
+		}
+		return tx.Bucket(metaBucket).Put(compactedKey, versions[len(versions)-1])
+	})
+}
+
+// Apply saves the records replicated from the active master. A snapshot
+// replaces all the replicated records.
+func (store *StateStore) Apply(records []*pb.MasterState_Record, isSnapshot bool) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		if isSnapshot {
+			if err := clearReplicated(tx); err != nil {
+				return err
+			}
+		}
+		for _, r := range records {
+			if !isReplicatedBucket(r.GetBucket()) {
+				return fmt.Errorf("unknown bucket %s", r.GetBucket())
+			}
+			if r.GetDeleted() {
+				if err := store.deleteInTx(tx, []byte(r.GetBucket()), r.GetKey()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := putInTx(tx, []byte(r.GetBucket()), r.GetKey(), r.GetValue()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// clearReplicated removes the replicated records, and their versions.
+func clearReplicated(tx *bolt.Tx) error {
+	for _, name := range append(replicatedBuckets, versionsBucket, changesBucket, deletionsBucket) {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *StateStore) put(bucket, key, value []byte) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return putInTx(tx, bucket, key, value)
+	})
+}
+
+func (store *StateStore) delete(bucket, key []byte) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return store.deleteInTx(tx, bucket, key)
+	})
+}
+
+func putInTx(tx *bolt.Tx, bucket, key, value []byte) error {
+	if _, err := bumpVersion(tx, bucket, key); err != nil {
+		return err
+	}
+	return tx.Bucket(bucket).Put(key, value)
+}
+
+// deleteInTx keeps the version of the deleted key, to replicate the deletion,
+// until it is compacted.
+func (store *StateStore) deleteInTx(tx *bolt.Tx, bucket, key []byte) error {
+	if tx.Bucket(bucket).Get(key) == nil {
+		return nil
+	}
+	versionBytes, err := bumpVersion(tx, bucket, key)
+	if err != nil {
+		return err
+	}
+	if store.keepDeletions {
+		err = tx.Bucket(deletionsBucket).Put(versionBytes, versionedKey(bucket, key))
+	} else if err = forgetVersion(tx, versionBytes); err == nil {
+		err = tx.Bucket(metaBucket).Put(compactedKey, versionBytes)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(bucket).Delete(key)
+}
+
+// bumpVersion records the key changed in a new version, and returns the version.
+func bumpVersion(tx *bolt.Tx, bucket, key []byte) ([]byte, error) {
+	versionBytes := uint64Key(readVersion(tx) + 1)
+	if err := tx.Bucket(metaBucket).Put(versionKey, versionBytes); err != nil {
+		return nil, err
+	}
+	k := versionedKey(bucket, key)
+	if oldVersion := tx.Bucket(versionsBucket).Get(k); oldVersion != nil {
+		if err := forgetVersion(tx, append([]byte(nil), oldVersion...)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Bucket(versionsBucket).Put(k, versionBytes); err != nil {
+		return nil, err
+	}
+	return versionBytes, tx.Bucket(changesBucket).Put(versionBytes, k)
+}
+
+// forgetVersion removes the change in the version.
+func forgetVersion(tx *bolt.Tx, versionBytes []byte) error {
+	changes := tx.Bucket(changesBucket)
+	if k := changes.Get(versionBytes); k != nil {
+		if err := tx.Bucket(versionsBucket).Delete(append([]byte(nil), k...)); err != nil {
+			return err
+		}
+	}
+	if err := changes.Delete(versionBytes); err != nil {
+		return err
+	}
+	return tx.Bucket(deletionsBucket).Delete(versionBytes)
+}
+
+// indexChanges orders the changes by version, in a store saved before the
+// changes were indexed. The deletions recorded so far are kept.
+func indexChanges(tx *bolt.Tx) error {
+	changes, deletions := tx.Bucket(changesBucket), tx.Bucket(deletionsBucket)
+	return tx.Bucket(versionsBucket).ForEach(func(k, v []byte) error {
+		if err := changes.Put(v, k); err != nil {
+			return err
+		}
+		sep := bytes.IndexByte(k, 0)
+		if sep < 0 {
+			return nil
+		}
+		if b := tx.Bucket(k[:sep]); b != nil && b.Get(k[sep+1:]) != nil {
+			return nil
+		}
+		return deletions.Put(v, k)
+	})
+}
+
+// Term returns the term saved when this master became active.
+func (store *StateStore) Term() (term uint64, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(metaBucket).Get(termKey); len(v) == 8 {
+			term = binary.BigEndian.Uint64(v)
+		}
+		return nil
+	})
+	return
+}
+
+func (store *StateStore) SaveTerm(term uint64) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		termBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(termBytes, term)
+		return tx.Bucket(metaBucket).Put(termKey, termBytes)
+	})
+}
+
+func readVersion(tx *bolt.Tx) uint64 {
+	return readMeta(tx, versionKey)
+}
+
+func readMeta(tx *bolt.Tx, key []byte) uint64 {
+	if v := tx.Bucket(metaBucket).Get(key); len(v) == 8 {
+		return binary.BigEndian.Uint64(v)
+	}
+	return 0
+}
+
+func versionedKey(bucket, key []byte) []byte {
+	k := make([]byte, 0, len(bucket)+1+len(key))
+	k = append(k, bucket...)
+	k = append(k, 0)
+	return append(k, key...)
+}
+
+func isReplicatedBucket(name string) bool {
+	for _, b := range replicatedBuckets {
+		if string(b) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func uint32Key(x uint32) []byte {
+	k := make([]byte, 4)
+	binary.BigEndian.PutUint32(k, x)
+	return k
+}
+
+func uint64Key(x uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, x)
+	return k
+}
@@ -0,0 +1,164 @@
+package master
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+// newTestStore opens a store in a temporary directory, removed by the returned function.
+func newTestStore(t *testing.T) (*StateStore, func()) {
+	dir, err := ioutil.TempDir("", "gleam-master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStateStore(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// describeRecords lists the records as "key=value", or "key deleted".
+func describeRecords(records []*pb.MasterState_Record) (ret []string) {
+	for _, r := range records {
+		if r.GetDeleted() {
+			ret = append(ret, string(r.GetKey())+" deleted")
+		} else {
+			ret = append(ret, string(r.GetKey())+"="+string(r.GetValue()))
+		}
+	}
+	return
+}
+
+func TestStoreReplication(t *testing.T) {
+	active, closeActive := newTestStore(t)
+	defer closeActive()
+	active.keepDeletions = true
+	standby, closeStandby := newTestStore(t)
+	defer closeStandby()
+
+	active.put(jobsBucket, []byte("a"), []byte("1"))
+	active.put(jobsBucket, []byte("b"), []byte("1"))
+	active.put(jobsBucket, []byte("c"), []byte("1"))
+	standby.put(jobsBucket, []byte("stale"), []byte("1"))
+
+	// a new standby master replaces its state with a snapshot
+	records, version, isSnapshot, err := active.Changes(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSnapshot || version != 3 {
+		t.Errorf("changes since 0 at version %d, snapshot %v, expecting a snapshot at version 3", version, isSnapshot)
+	}
+	if err := standby.Apply(records, isSnapshot); err != nil {
+		t.Fatal(err)
+	}
+	checkJobKeys(t, standby, []string{"a", "b", "c"})
+
+	// the changes are in version order, including the deletions
+	active.put(jobsBucket, []byte("a"), []byte("2"))
+	active.delete(jobsBucket, []byte("b"))
+	records, version, isSnapshot, err = active.Changes(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a=2", "b deleted"}; isSnapshot || version != 5 || !reflect.DeepEqual(describeRecords(records), expected) {
+		t.Errorf("changes since 3: %v at version %d, snapshot %v, expecting %v at version 5", describeRecords(records), version, isSnapshot, expected)
+	}
+	if err := standby.Apply(records, isSnapshot); err != nil {
+		t.Fatal(err)
+	}
+	checkJobKeys(t, standby, []string{"a", "c"})
+
+	// the deletions replicated by the standby master are compacted
+	if err := active.CompactDeletions(5); err != nil {
+		t.Fatal(err)
+	}
+	if records, _, isSnapshot, _ := active.Changes(5); isSnapshot || len(records) != 0 {
+		t.Errorf("changes since 5 after compaction: %v, snapshot %v", describeRecords(records), isSnapshot)
+	}
+	if _, _, isSnapshot, _ := active.Changes(4); !isSnapshot {
+		t.Errorf("expecting a snapshot for a standby master behind the compacted deletions")
+	}
+	if count := bucketSize(active, deletionsBucket); count != 0 {
+		t.Errorf("%d deletions kept after compaction", count)
+	}
+	if count := bucketSize(active, versionsBucket); count != 2 {
+		t.Errorf("%d versions kept, expecting the 2 keys left", count)
+	}
+}
+
+func TestStoreForgetsDeletionsWithoutStandby(t *testing.T) {
+	store, closeStore := newTestStore(t)
+	defer closeStore()
+
+	store.put(jobsBucket, []byte("a"), []byte("1"))
+	store.put(jobsBucket, []byte("b"), []byte("1"))
+	store.delete(jobsBucket, []byte("a"))
+	for _, bucket := range [][]byte{versionsBucket, changesBucket} {
+		if count := bucketSize(store, bucket); count != 1 {
+			t.Errorf("%d records in %s, expecting only the version of b", count, bucket)
+		}
+	}
+	if _, _, isSnapshot, _ := store.Changes(2); !isSnapshot {
+		t.Errorf("expecting a snapshot, as the deletion is not kept")
+	}
+}
+
+func TestImportLegacyLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gleam-master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data, _ := proto.Marshal(&pb.FlowExecutionStatus{Id: 7})
+	ioutil.WriteFile(filepath.Join(dir, "f7.log"), data, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "f8.log"), []byte("not a job status"), 0644)
+
+	store, err := NewStateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if job, err := store.GetHistoryJob(7); err != nil || job == nil {
+		t.Errorf("job 7 is not imported: %v", err)
+	}
+	for name, exists := range map[string]bool{"f7.log": false, "f8.log": false, "f8.log.bad": true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != exists {
+			t.Errorf("%s exists %v, expecting %v", name, err == nil, exists)
+		}
+	}
+}
+
+func checkJobKeys(t *testing.T, store *StateStore, expected []string) {
+	var keys []string
+	store.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(k, v []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("jobs %v, expecting %v", keys, expected)
+	}
+}
+
+func bucketSize(store *StateStore, bucket []byte) (count int) {
+	store.db.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket(bucket).Stats().KeyN
+		return nil
+	})
+	return
+}
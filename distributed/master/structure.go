@@ -12,6 +12,10 @@ type AgentInformation struct {
 	LastHeartBeat time.Time
 	Resource      pb.ComputeResource
 	Allocated     pb.ComputeResource
+	// no more allocations while the agent is drained
+	Draining bool
+	// the agent reported draining in the last heartbeat
+	reportedDraining bool
 }
 
 type Rack struct {
@@ -8,9 +8,10 @@ var JobStatusTpl = template.Must(template.New("job").Funcs(funcMap).Parse(`<!DOC
 <html>
   <head>
     <title>Job {{ .Status.Id }}</title>
-    <link rel="stylesheet" href="https://maxcdn.bootstrapcdn.com/bootstrap/3.3.1/css/bootstrap.min.css">
+    <link rel="stylesheet" href="/ui/gleam.css">
+    <script src="/ui/gleam.js"></script>
   </head>
-  <body>
+  <body data-page="job" data-job="{{ .Status.Id }}">
     <div class="container">
       <div class="page-header">
 	    <h1>
@@ -38,22 +39,29 @@ var JobStatusTpl = template.Must(template.New("job").Funcs(funcMap).Parse(`<!DOC
                 <th>Executable</th>
                 <td style="max-width:150px;word-wrap:break-word;">{{ .Executable }}</td>
               </tr>
+              <tr>
+                <th>State</th>
+                <td id="job-state"></td>
+              </tr>
               <tr>
                 <th>Start</th>
                 <td>{{ unix .StartTime }}</td>
               </tr>
-              {{ with .StopTime }}
               <tr>
                 <th>Stop</th>
-                <td>{{ unix . }}</td>
+                <td id="job-stop">{{ with .StopTime }}{{ unix . }}{{ end }}</td>
               </tr>
-              {{ end}}
               <tr>
                 <th>Duration</th>
-                <td>{{ duration .StopTime $start}}</td>
+                <td id="job-duration">{{ duration .StopTime $start}}</td>
               </tr>
             </tbody>
           </table>
+          {{ if not .StopTime }}
+          <form id="job-cancel" method="post" action="/job/{{ $.Status.Id }}/cancel">
+            <button type="submit" class="btn btn-danger">Cancel</button>
+          </form>
+          {{ end }}
           {{ end }}
         </div>
 
@@ -74,78 +82,42 @@ var JobStatusTpl = template.Must(template.New("job").Funcs(funcMap).Parse(`<!DOC
 
       <div class="row">
         <div class="col-sm-6">
-          <p>{{.Svg}}</p>
+          <h2>Steps</h2>
+          <div id="dag" class="dag">{{.Svg}}</div>
         </div>
         <div class="col-sm-6">
-          {{ with .Status.Steps }}
-            <h2>Steps</h2>
-            <table class="table table-striped">
-              <thead>
-                <tr>
-                  <th>Step</th>
-                  <th>Name</th>
-                </tr>
-              </thead>
-              <tbody>
-              {{ range $step_index, $step := . }}
-                <tr>
-                  <td>{{ $step.Id }}</td>
-                  <td>{{ $step.Name }}</td>
-                </tr>
-              {{ end }}
-              </tbody>
-            </table>
-          {{ end }}
+          <h2>&nbsp;</h2>
+          <table class="table table-striped">
+            <thead>
+              <tr>
+                <th>Step</th>
+                <th>Name</th>
+                <th>Duration</th>
+                <th>Counters</th>
+                <th>Errors</th>
+              </tr>
+            </thead>
+            <tbody id="steps"></tbody>
+          </table>
         </div>
       </div>
 
-      {{ with .Status.TaskGroups }}
       <div class="row">
-        <h2>Task Group</h2>
-        <table class="table table-striped">
+        <h2>Task Groups</h2>
+        <table class="table">
           <thead>
             <tr>
+              <th>Id</th>
               <th>Steps</th>
-              <th>Allocation</th>
-              <th>Execution</th>
+              <th>State</th>
+              <th>Agent</th>
+              <th>Log</th>
+              <th>Executions</th>
             </tr>
           </thead>
-          <tbody>
-          {{ range $tg_index, $tg := . }}
-            <tr>
-              <td>{{ $tg.StepIds }}</td>
-              <td>
-                {{with $tg.Allocation}}
-                    {{.Allocated.MemoryMb}}MB {{.Location.DataCenter}}-{{.Location.Rack}}-{{.Location.Server}}:{{.Location.Port}}
-                {{end}}
-                <br/>
-                
-                {{with $tg.Request}}{{with .InstructionSet}}
-                {{ range $inst_index, $inst := .Instructions }}
-                    {{with .InputShardLocations}}Input: <ul>{{ range . }}<li>{{.Name}}@{{.Host}}:{{.Port}}</li>{{end}}</ul>{{end}}
-                    {{with .OutputShardLocations}}Output:<ul>{{ range . }}<li>{{.Name}}@{{.Host}}:{{.Port}}</li>{{end}}{{end}}
-                {{end}}
-                {{ end }}{{ end }}
-              </td>
-              <td><ul>{{range .Executions}}
-                   <li>
-                     {{with .StartTime}} start: {{ duration . $start}} {{end}}
-                     {{with .StopTime}} stop: {{ duration . $start}} {{end}}
-                     {{with .ExecutionStat}}
-                     <ul>
-                       {{range .Stats}}
-                          <li>{{.StepId}}:{{.TaskId}} {{.InputCounter}}=>{{.OutputCounter}}</li>
-                       {{end}}
-                     </ul>
-                     {{end}}
-                   </li>
-                  {{end}}</ul></td>
-            </tr>
-          {{ end }}
-          </tbody>
+          <tbody id="task-groups"></tbody>
         </table>
       </div>
-      {{ end }}
 
     </div>
   </body>
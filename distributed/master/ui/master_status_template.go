@@ -8,9 +8,10 @@ var MasterStatusTpl = template.Must(template.New("master").Funcs(funcMap).Parse(
 <html>
   <head>
     <title>Gleam {{ .Version }}</title>
-    <link rel="stylesheet" href="https://maxcdn.bootstrapcdn.com/bootstrap/3.3.1/css/bootstrap.min.css">
+    <link rel="stylesheet" href="/ui/gleam.css">
+    <script src="/ui/gleam.js"></script>
   </head>
-  <body>
+  <body data-page="master">
     <div class="container">
       <div class="page-header">
 	    <h1>
@@ -73,7 +74,7 @@ var MasterStatusTpl = template.Must(template.New("master").Funcs(funcMap).Parse(
               <td>{{ $rack.Name }}</td>
               <td>{{ $agent.Location.Server }}</td>
               <td>{{ $agent.Location.Port }}</td>
-              <td>{{ $agent.LastHeartBeat }}</td>
+              <td>{{ $agent.LastHeartBeat }}{{ if $agent.Draining }} <span class="label label-warning">draining</span>{{ end }}</td>
               <td>{{ $agent.Resource }}</td>
               <td>{{ $agent.Allocated }}</td>
             </tr>
@@ -94,21 +95,11 @@ var MasterStatusTpl = template.Must(template.New("master").Funcs(funcMap).Parse(
               <th>Driver</th>
               <th>User</th>
               <th>Host</th>
+              <th>State</th>
               <th>Duration</th>
             </tr>
           </thead>
-          <tbody>
-            {{ range $idx, $stat := $.Stats }}
-            <tr>
-              <td><a href="/job/{{$stat.Id}}">{{ $stat.Id }}</a></td>
-              <td>{{ $stat.Driver.Name }}</td>
-              <td>{{ $stat.Driver.Executable }}</td>
-              <td>{{ $stat.Driver.Username }}</td>
-              <td>{{ $stat.Driver.Hostname }}</td>
-              <td>{{ duration $stat.Driver.StopTime $stat.Driver.StartTime }}</td>
-            </tr>
-            {{ end }}
-          </tbody>
+          <tbody id="jobs"></tbody>
         </table>
       </div>
 
@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// the style sheet and the script are embedded, so the master UI works without internet access
+var staticFiles = map[string]struct {
+	contentType string
+	content     string
+}{
+	"gleam.css": {"text/css; charset=utf-8", styleSheet},
+	"gleam.js":  {"application/javascript; charset=utf-8", script},
+}
+
+var staticModTime = time.Now()
+
+// StaticHandler serves the embedded files under /ui/.
+func StaticHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/ui/")
+	file, found := staticFiles[name]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", file.contentType)
+	http.ServeContent(w, r, name, staticModTime, strings.NewReader(file.content))
+}
+
+const styleSheet = `
+body { margin: 0; font-family: "Helvetica Neue", Helvetica, Arial, sans-serif; font-size: 14px; line-height: 1.43; color: #333; }
+a { color: #337ab7; text-decoration: none; }
+a:hover { text-decoration: underline; }
+h1, h2 { font-weight: 500; line-height: 1.1; }
+h1 { font-size: 36px; margin: 20px 0 10px; }
+h2 { font-size: 30px; margin: 20px 0 10px; }
+small { font-size: 65%; color: #777; }
+code { padding: 2px 4px; font-size: 90%; color: #c7254e; background: #f9f2f4; border-radius: 4px; }
+pre { padding: 9px; margin: 0; font-size: 12px; background: #f5f5f5; border: 1px solid #ccc; border-radius: 4px; white-space: pre-wrap; word-wrap: break-word; max-height: 300px; overflow: auto; }
+ul { margin: 0; padding-left: 20px; }
+.container { max-width: 1170px; margin: 0 auto; padding: 0 15px; }
+.page-header { padding-bottom: 9px; margin: 40px 0 20px; border-bottom: 1px solid #eee; }
+.row { margin: 0 -15px; overflow: hidden; }
+.row > h2, .row > table { margin-left: 15px; margin-right: 15px; width: calc(100% - 30px); }
+.col-sm-6 { box-sizing: border-box; padding: 0 15px; width: 100%; }
+@media (min-width: 768px) { .col-sm-6 { float: left; width: 50%; } }
+.table { width: 100%; max-width: 100%; margin-bottom: 20px; border-collapse: collapse; }
+.table th, .table td { padding: 8px; text-align: left; vertical-align: top; border-top: 1px solid #ddd; }
+.table thead th { border-bottom: 2px solid #ddd; border-top: 0; }
+.table-condensed th, .table-condensed td { padding: 5px; }
+.table-striped > tbody > tr:nth-of-type(odd) { background: #f9f9f9; }
+.btn { display: inline-block; padding: 6px 12px; font-size: 14px; border: 1px solid transparent; border-radius: 4px; cursor: pointer; }
+.btn-danger { color: #fff; background: #d9534f; border-color: #d43f3a; }
+.label { display: inline-block; padding: 2px 6px; font-size: 75%; font-weight: bold; color: #fff; border-radius: 3px; }
+.label-warning, .label-running { background: #f0ad4e; }
+.label-pending { background: #777; }
+.label-finished, .label-completed { background: #5cb85c; }
+.label-failed { background: #d9534f; }
+.clickable { cursor: pointer; }
+.detail > td { background: #fff; }
+.dag { overflow: auto; }
+.dag .step-group { cursor: pointer; }
+.dag .step-group.selected rect { stroke-width: 3; }
+`
+
+const script = `(function() {
+  "use strict";
+
+  var refreshMs = 2000;
+
+  function get(url, done) {
+    var xhr = new XMLHttpRequest();
+    xhr.open("GET", url);
+    xhr.onload = function() {
+      if (xhr.status === 200) {
+        done(xhr.responseText);
+      }
+    };
+    xhr.send();
+  }
+
+  // post sends the header the master requires, which a cross site form can not send
+  function post(url, done) {
+    var xhr = new XMLHttpRequest();
+    xhr.open("POST", url);
+    xhr.setRequestHeader("X-Requested-With", "XMLHttpRequest");
+    xhr.onload = function() {
+      if (xhr.status >= 200 && xhr.status < 300) {
+        done(xhr.responseText);
+      } else {
+        alert(xhr.responseText);
+      }
+    };
+    xhr.send();
+  }
+
+  // el creates an element, with the text content set safely
+  function el(tag, attrs, children) {
+    var e = document.createElement(tag);
+    for (var name in attrs || {}) {
+      e.setAttribute(name, attrs[name]);
+    }
+    (children || []).forEach(function(child) {
+      if (child === null || child === undefined) {
+        return;
+      }
+      e.appendChild(typeof child === "object" ? child : document.createTextNode(String(child)));
+    });
+    return e;
+  }
+
+  function replaceRows(tbody, rows) {
+    while (tbody.firstChild) {
+      tbody.removeChild(tbody.firstChild);
+    }
+    rows.forEach(function(row) {
+      tbody.appendChild(row);
+    });
+  }
+
+  function label(state) {
+    return el("span", {"class": "label label-" + state}, [state]);
+  }
+
+  function duration(ms) {
+    if (ms < 1000) {
+      return ms + "ms";
+    }
+    var s = ms / 1000;
+    if (s < 60) {
+      return s.toFixed(1) + "s";
+    }
+    var m = Math.floor(s / 60);
+    if (m < 60) {
+      return m + "m" + Math.floor(s % 60) + "s";
+    }
+    return Math.floor(m / 60) + "h" + (m % 60) + "m";
+  }
+
+  function time(t) {
+    return t ? new Date(t).toLocaleString() : "";
+  }
+
+  function counters(c) {
+    var names = Object.keys(c || {}).sort();
+    if (names.length === 0) {
+      return null;
+    }
+    return el("ul", {}, names.map(function(name) {
+      return el("li", {}, [name + ": " + c[name]]);
+    }));
+  }
+
+  function histograms(hs) {
+    if (!hs || hs.length === 0) {
+      return null;
+    }
+    return el("ul", {}, hs.map(function(h) {
+      return el("li", {}, [h.name + ": count " + h.count + " min " + h.min + " mean " + h.mean.toFixed(2) + " max " + h.max]);
+    }));
+  }
+
+  // the master page lists the jobs
+  function masterPage() {
+    var tbody = document.getElementById("jobs");
+    function refresh() {
+      get("/api/jobs", function(text) {
+        replaceRows(tbody, JSON.parse(text).map(function(job) {
+          return el("tr", {}, [
+            el("td", {}, [el("a", {href: "/job/" + job.id}, [job.id])]),
+            el("td", {}, [job.name]),
+            el("td", {}, [job.executable]),
+            el("td", {}, [job.user]),
+            el("td", {}, [job.host]),
+            el("td", {}, [label(job.state)]),
+            el("td", {}, [duration(job.durationMs)])
+          ]);
+        }));
+        setTimeout(refresh, refreshMs);
+      });
+    }
+    refresh();
+  }
+
+  // the job page refreshes the DAG, the steps and the task groups until the job stops
+  function jobPage(id) {
+    var expanded = {};
+    var selectedStep = null;
+    var job = null;
+
+    function executionDetail(e, index) {
+      return el("li", {}, [
+        "attempt " + (index + 1) + ": " + time(e.startTime) +
+          (e.stopTime ? " - " + time(e.stopTime) : " running") +
+          ", " + duration(e.durationMs) +
+          (e.userTime ? ", user " + e.userTime.toFixed(2) + "s system " + e.systemTime.toFixed(2) + "s" : ""),
+        el("ul", {}, (e.stats || []).map(function(t) {
+          return el("li", {}, ["step " + t.stepId + " task " + t.taskId + ": " + t.inputCount + " => " + t.outputCount, counters(t.counters), histograms(t.histograms)]);
+        })),
+        e.stderr ? el("pre", {}, [e.stderr]) : null
+      ]);
+    }
+
+    function renderTaskGroups() {
+      var rows = [];
+      job.taskGroups.forEach(function(tg) {
+        if (selectedStep !== null && tg.stepIds[0] !== selectedStep) {
+          return;
+        }
+        var row = el("tr", {"class": "clickable"}, [
+          el("td", {}, [tg.id]),
+          el("td", {}, [tg.stepIds.join(", ")]),
+          el("td", {}, [label(tg.state)]),
+          el("td", {}, [tg.agent ? tg.agent + (tg.memoryMb ? " " + tg.memoryMb + "MB" : "") : ""]),
+          el("td", {}, [tg.name && tg.agent ? el("a", {href: "/job/" + id + "/logs?name=" + encodeURIComponent(tg.name)}, ["log"]) : null]),
+          el("td", {}, [tg.executions.length])
+        ]);
+        row.onclick = function() {
+          expanded[tg.id] = !expanded[tg.id];
+          renderTaskGroups();
+        };
+        rows.push(row);
+        if (expanded[tg.id]) {
+          rows.push(el("tr", {"class": "detail"}, [
+            el("td", {colspan: 6}, [
+              tg.executions.length ? el("ul", {}, tg.executions.map(executionDetail)) : "not started"
+            ])
+          ]));
+        }
+      });
+      replaceRows(document.getElementById("task-groups"), rows);
+    }
+
+    function renderSteps() {
+      replaceRows(document.getElementById("steps"), job.steps.map(function(s) {
+        return el("tr", {}, [
+          el("td", {}, [s.id]),
+          el("td", {}, [s.name]),
+          el("td", {}, [s.durationMs ? duration(s.durationMs) : ""]),
+          el("td", {}, [s.inputCount + " => " + s.outputCount, counters(s.counters), histograms(s.histograms)]),
+          el("td", {}, s.errors ? [el("pre", {}, [s.errors.join("\n")])] : [])
+        ]);
+      }));
+    }
+
+    function renderDag(svg) {
+      var dag = document.getElementById("dag");
+      dag.innerHTML = svg;
+      Array.prototype.forEach.call(dag.querySelectorAll(".step-group"), function(g) {
+        var step = parseInt(g.getAttribute("data-step"), 10);
+        if (step === selectedStep) {
+          g.setAttribute("class", "step-group selected");
+        }
+        g.onclick = function() {
+          selectedStep = selectedStep === step ? null : step;
+          renderDag(svg);
+          renderTaskGroups();
+        };
+      });
+    }
+
+    function refresh() {
+      get("/api/jobs/" + id, function(text) {
+        job = JSON.parse(text);
+        var state = document.getElementById("job-state");
+        replaceRows(state, [label(job.state)]);
+        document.getElementById("job-duration").textContent = duration(job.durationMs);
+        if (job.stopTime) {
+          document.getElementById("job-stop").textContent = time(job.stopTime);
+          var cancel = document.getElementById("job-cancel");
+          if (cancel) {
+            cancel.style.display = "none";
+          }
+        }
+        renderSteps();
+        renderTaskGroups();
+        get("/job/" + id + "/dag.svg", function(svg) {
+          renderDag(svg);
+          if (job.state === "running") {
+            setTimeout(refresh, refreshMs);
+          }
+        });
+      });
+    }
+    var cancelForm = document.getElementById("job-cancel");
+    if (cancelForm) {
+      cancelForm.addEventListener("submit", function(e) {
+        e.preventDefault();
+        post(cancelForm.getAttribute("action"), function() {
+          window.location.reload();
+        });
+      });
+    }
+    refresh();
+  }
+
+  document.addEventListener("DOMContentLoaded", function() {
+    var page = document.body.getAttribute("data-page");
+    if (page === "master") {
+      masterPage();
+    } else if (page === "job") {
+      jobPage(document.body.getAttribute("data-job"));
+    }
+  });
+})();
+`
@@ -18,6 +18,13 @@ var (
 	VerticalGap     = 4 * m
 )
 
+var stateColors = map[string]string{
+	"pending":  "#fff",
+	"running":  "#F9E79F",
+	"finished": "#D7DBDD",
+	"failed":   "#F5B7B1",
+}
+
 type stepGroupPosition struct {
 	input  point
 	output point
@@ -82,7 +89,7 @@ func doFlowExecutionStatus(canvas *svg.SVG, status *pb.FlowExecutionStatus, widt
 					positions[stepGroupId].input.y,
 				}
 				lastStepId := getLastStepId(status, status.StepGroups[parentId])
-				outputDatasetId := status.Steps[lastStepId].OutputDatasetId
+				outputDatasetId := findStep(status, lastStepId).GetOutputDatasetId()
 				outputDatasetSize := collectStepOutputDatasetSize(status, lastStepId)
 				connect(canvas, positions[parentId].output, acceptor,
 					fmt.Sprintf("d%d %d", outputDatasetId, outputDatasetSize))
@@ -120,15 +127,21 @@ func doStepGroup(canvas *svg.SVG, input point, status *pb.FlowExecutionStatus, s
 	x, y := input.x-WidthStep/2, input.y
 	w, h := WidthStep, len(stepGroup.GetStepIds())*(HightStep+SmallMargin)+SmallMargin
 
+	// the page script selects the task groups of the step group on click
+	state := stepGroupState(status, stepGroup)
+	canvas.Group(`class="step-group"`, fmt.Sprintf(`data-step="%d"`, stepGroup.StepIds[0]))
+	canvas.Title(state)
+
 	stepOut := point{input.x, input.y}
 	for _, stepId := range stepGroup.StepIds {
 		step := status.GetStep(stepId)
-		stepOut = doStep(canvas, stepOut, step, isStepGroupFinished(status, stepGroup))
+		stepOut = doStep(canvas, stepOut, step, stateColors[state])
 	}
 
 	rectstyle := fmt.Sprintf("stroke:%s;stroke-width:1;fill:%s", "black", "none")
 
 	canvas.Rect(x, y, w, h, rectstyle)
+	canvas.Gend()
 
 	output.x = input.x
 	output.y = input.y + h
@@ -136,7 +149,7 @@ func doStepGroup(canvas *svg.SVG, input point, status *pb.FlowExecutionStatus, s
 	return
 }
 
-func doStep(canvas *svg.SVG, input point, step *pb.FlowExecutionStatus_Step, hasFinished bool) (output point) {
+func doStep(canvas *svg.SVG, input point, step *pb.FlowExecutionStatus_Step, color string) (output point) {
 	output.x = input.x
 	output.y = input.y + HightStep + SmallMargin
 
@@ -145,11 +158,6 @@ func doStep(canvas *svg.SVG, input point, step *pb.FlowExecutionStatus_Step, has
 	fs := 14
 	w2 := 3
 
-	color := "#fff"
-	if hasFinished {
-		color = "#D7DBDD"
-	}
-
 	name := fmt.Sprintf("%d.%s", step.Id, step.Name)
 	canvas.Rect(x, y, w, h, fmt.Sprintf("stroke:%s;stroke-width:1;fill:%s", "black", color))
 	canvas.Gstyle(fmt.Sprintf("font-size:%dpx", fs))
@@ -163,22 +171,36 @@ func getLastStepId(status *pb.FlowExecutionStatus, stepGroup *pb.FlowExecutionSt
 	return stepGroup.StepIds[len(stepGroup.StepIds)-1]
 }
 
-func isStepGroupFinished(status *pb.FlowExecutionStatus, stepGroup *pb.FlowExecutionStatus_StepGroup) bool {
-	isFinished := true
+// findStep looks up the step by id, since the optimizer may leave gaps in the ids.
+func findStep(status *pb.FlowExecutionStatus, stepId int32) *pb.FlowExecutionStatus_Step {
+	for _, step := range status.Steps {
+		if step.Id == stepId {
+			return step
+		}
+	}
+	return nil
+}
+
+// stepGroupState is "failed" if any of its task groups failed, "running" if
+// any has started, "finished" if all have finished, and "pending" otherwise.
+func stepGroupState(status *pb.FlowExecutionStatus, stepGroup *pb.FlowExecutionStatus_StepGroup) string {
+	counts := make(map[string]int)
+	total := 0
 	for _, tg := range status.TaskGroups {
 		if tg.StepIds[0] == stepGroup.StepIds[0] {
-			isThisTaskGroupFinished := false
-			for _, exe := range tg.Executions {
-				if exe.StopTime != 0 {
-					isThisTaskGroupFinished = true
-				}
-			}
-			if !isThisTaskGroupFinished {
-				isFinished = false
-			}
+			counts[tg.State()]++
+			total++
 		}
 	}
-	return isFinished
+	switch {
+	case counts["failed"] > 0:
+		return "failed"
+	case total > 0 && counts["finished"] == total:
+		return "finished"
+	case counts["running"] > 0 || counts["finished"] > 0:
+		return "running"
+	}
+	return "pending"
 }
 
 // may be more efficient to map stepId=>size
@@ -0,0 +1,253 @@
+package netchan
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+)
+
+// A block stream batches the length-prefixed messages into blocks of up to
+// blockSize bytes. Each block has a header of the codec, the uncompressed
+// and the compressed length, and the CRC-32C of the uncompressed bytes.
+// A header with zero lengths ends the stream, so a truncated stream is
+// detected as well as a corrupted block.
+//
+// The blocks are only used if the control message of the transfer asks for
+// a compression. The agent then confirms the compression before the blocks.
+// Without a compression, the transfer stays a plain stream of the messages,
+// as understood by the agents before the blocks.
+const (
+	blockSize       = 128 * 1024
+	blockHeaderSize = 13
+	maxBlockSize    = 64 * 1024 * 1024
+)
+
+const (
+	codecNone byte = iota
+	codecSnappy
+	codecFlate
+)
+
+var compressions = map[string]byte{
+	"none":   codecNone,
+	"snappy": codecSnappy,
+	"flate":  codecFlate,
+}
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// IsSupportedCompression checks the compression can be used for the shard transfers.
+// "none" only adds the checksums.
+func IsSupportedCompression(compression string) bool {
+	_, found := compressions[compression]
+	return found
+}
+
+type blockWriter struct {
+	w       io.Writer
+	codec   byte
+	buf     []byte
+	encoded []byte
+	flater  *flate.Writer
+	flated  bytes.Buffer
+	header  [blockHeaderSize]byte
+}
+
+// NewBlockWriter batches the writes into compressed blocks. Close writes the
+// pending block and ends the stream, without closing w.
+func NewBlockWriter(w io.Writer, compression string) io.WriteCloser {
+	return &blockWriter{
+		w:     w,
+		codec: compressions[compression],
+		buf:   make([]byte, 0, blockSize),
+	}
+}
+
+func (b *blockWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		c := copy(b.buf[len(b.buf):cap(b.buf)], p)
+		b.buf = b.buf[:len(b.buf)+c]
+		n += c
+		p = p[c:]
+		if len(b.buf) == cap(b.buf) {
+			if err = b.Flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Flush writes the pending bytes as a block.
+func (b *blockWriter) Flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	codec, payload, err := b.encode(b.buf)
+	if err != nil {
+		return err
+	}
+	if err = b.writeBlock(codec, len(b.buf), payload, crc32.Checksum(b.buf, crcTable)); err != nil {
+		return err
+	}
+	b.buf = b.buf[:0]
+	return nil
+}
+
+func (b *blockWriter) Close() error {
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	return b.writeBlock(codecNone, 0, nil, 0)
+}
+
+// encode compresses the data, or keeps it as is if it does not get smaller.
+func (b *blockWriter) encode(data []byte) (byte, []byte, error) {
+	var encoded []byte
+	switch b.codec {
+	case codecSnappy:
+		b.encoded = snappy.Encode(b.encoded[:cap(b.encoded)], data)
+		encoded = b.encoded
+	case codecFlate:
+		b.flated.Reset()
+		if b.flater == nil {
+			var err error
+			if b.flater, err = flate.NewWriter(&b.flated, flate.BestSpeed); err != nil {
+				return 0, nil, err
+			}
+		} else {
+			b.flater.Reset(&b.flated)
+		}
+		if _, err := b.flater.Write(data); err != nil {
+			return 0, nil, err
+		}
+		if err := b.flater.Close(); err != nil {
+			return 0, nil, err
+		}
+		encoded = b.flated.Bytes()
+	}
+	if encoded == nil || len(encoded) >= len(data) {
+		return codecNone, data, nil
+	}
+	return b.codec, encoded, nil
+}
+
+func (b *blockWriter) writeBlock(codec byte, size int, payload []byte, checksum uint32) error {
+	b.header[0] = codec
+	binary.LittleEndian.PutUint32(b.header[1:], uint32(size))
+	binary.LittleEndian.PutUint32(b.header[5:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(b.header[9:], checksum)
+	if _, err := b.w.Write(b.header[:]); err != nil {
+		return err
+	}
+	_, err := b.w.Write(payload)
+	return err
+}
+
+type blockReader struct {
+	r       io.Reader
+	header  [blockHeaderSize]byte
+	payload []byte
+	decoded []byte
+	pending []byte
+	err     error
+}
+
+// NewBlockReader reads the bytes written by a block writer. It fails with
+// io.ErrUnexpectedEOF if the stream ends before the end block, and on any
+// block not matching its checksum. Close closes r if it is an io.Closer.
+func NewBlockReader(r io.Reader) io.ReadCloser {
+	return &blockReader{r: r}
+}
+
+func (b *blockReader) Read(p []byte) (n int, err error) {
+	for len(b.pending) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+		b.err = b.readBlock()
+	}
+	n = copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+func (b *blockReader) readBlock() error {
+	if _, err := io.ReadFull(b.r, b.header[:]); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	codec := b.header[0]
+	size := int(binary.LittleEndian.Uint32(b.header[1:]))
+	encodedSize := int(binary.LittleEndian.Uint32(b.header[5:]))
+	checksum := binary.LittleEndian.Uint32(b.header[9:])
+	if size == 0 && encodedSize == 0 {
+		return io.EOF
+	}
+	if size > maxBlockSize || encodedSize > maxBlockSize {
+		return fmt.Errorf("block of %d bytes, %d bytes encoded, is too large", size, encodedSize)
+	}
+
+	if cap(b.payload) < encodedSize {
+		b.payload = make([]byte, encodedSize)
+	}
+	b.payload = b.payload[:encodedSize]
+	if _, err := io.ReadFull(b.r, b.payload); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	data, err := b.decode(codec, size)
+	if err != nil {
+		return err
+	}
+	if len(data) != size {
+		return fmt.Errorf("block decoded to %d bytes, expecting %d", len(data), size)
+	}
+	if crc32.Checksum(data, crcTable) != checksum {
+		return fmt.Errorf("block of %d bytes does not match its checksum", size)
+	}
+	b.pending = data
+	return nil
+}
+
+func (b *blockReader) decode(codec byte, size int) ([]byte, error) {
+	switch codec {
+	case codecNone:
+		return b.payload, nil
+	case codecSnappy:
+		// snappy allocates the decoded length written in the payload
+		if n, err := snappy.DecodedLen(b.payload); err != nil || n != size {
+			return nil, fmt.Errorf("snappy block decodes to %d bytes, expecting %d: %v", n, size, err)
+		}
+		if cap(b.decoded) < size {
+			b.decoded = make([]byte, size)
+		}
+		return snappy.Decode(b.decoded[:cap(b.decoded)], b.payload)
+	case codecFlate:
+		// stop inflating a corrupted block beyond its size
+		data, err := ioutil.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(b.payload)), int64(size)+1))
+		if err == nil && len(data) > size {
+			err = fmt.Errorf("flate block decodes to more than %d bytes", size)
+		}
+		return data, err
+	}
+	return nil, fmt.Errorf("unknown block codec %d", codec)
+}
+
+func (b *blockReader) Close() error {
+	if c, ok := b.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package netchan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func testBlockData() map[string][]byte {
+	random := make([]byte, 3*blockSize+17)
+	rand.New(rand.NewSource(1)).Read(random)
+	return map[string][]byte{
+		"empty":        nil,
+		"small":        []byte("hello"),
+		"block":        bytes.Repeat([]byte("a"), blockSize),
+		"compressible": bytes.Repeat([]byte("gleam "), blockSize),
+		"random":       random,
+	}
+}
+
+func writeBlocks(t *testing.T, data []byte, compression string) []byte {
+	var buf bytes.Buffer
+	w := NewBlockWriter(&buf, compression)
+	// write in uneven pieces, to cross the block boundaries
+	for p := data; len(p) > 0; {
+		n := 1000
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestBlockRoundTrip(t *testing.T) {
+	for compression := range compressions {
+		for name, data := range testBlockData() {
+			encoded := writeBlocks(t, data, compression)
+			decoded, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(encoded)))
+			if err != nil {
+				t.Errorf("%s %s: %v", compression, name, err)
+				continue
+			}
+			if !bytes.Equal(decoded, data) {
+				t.Errorf("%s %s: decoded %d bytes, expecting %d", compression, name, len(decoded), len(data))
+			}
+		}
+	}
+}
+
+func TestBlockCompresses(t *testing.T) {
+	data := testBlockData()["compressible"]
+	plain := writeBlocks(t, data, "none")
+	for _, compression := range []string{"snappy", "flate"} {
+		if encoded := writeBlocks(t, data, compression); len(encoded) >= len(plain) {
+			t.Errorf("%s: %d bytes, not smaller than %d bytes", compression, len(encoded), len(plain))
+		}
+	}
+}
+
+func TestBlockCorruption(t *testing.T) {
+	data := testBlockData()["compressible"]
+	for compression := range compressions {
+		encoded := writeBlocks(t, data, compression)
+
+		corrupted := append([]byte(nil), encoded...)
+		corrupted[blockHeaderSize+1] ^= 0xff
+		if _, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(corrupted))); err == nil {
+			t.Errorf("%s: expecting an error on a corrupted block", compression)
+		}
+
+		corrupted = append([]byte(nil), encoded...)
+		corrupted[9] ^= 0xff
+		_, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(corrupted)))
+		if err == nil || !strings.Contains(err.Error(), "checksum") {
+			t.Errorf("%s: expecting a checksum error, got %v", compression, err)
+		}
+
+		truncated := encoded[:len(encoded)-blockHeaderSize]
+		if _, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(truncated))); err != io.ErrUnexpectedEOF {
+			t.Errorf("%s: expecting %v without the end block, got %v", compression, io.ErrUnexpectedEOF, err)
+		}
+
+		truncated = encoded[:blockHeaderSize+10]
+		if _, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(truncated))); err != io.ErrUnexpectedEOF {
+			t.Errorf("%s: expecting %v in a truncated block, got %v", compression, io.ErrUnexpectedEOF, err)
+		}
+	}
+}
+
+func TestBlockInvalidHeader(t *testing.T) {
+	encoded := writeBlocks(t, []byte("hello"), "none")
+
+	unknownCodec := append([]byte(nil), encoded...)
+	unknownCodec[0] = 0xff
+	if _, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(unknownCodec))); err == nil {
+		t.Errorf("expecting an error on an unknown codec")
+	}
+
+	tooLarge := append([]byte(nil), encoded...)
+	tooLarge[4] = 0xff
+	if _, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(tooLarge))); err == nil {
+		t.Errorf("expecting an error on a too large block")
+	}
+}
+
+func TestBlockDecodesBeyondSize(t *testing.T) {
+	data := testBlockData()["block"]
+	for _, compression := range []string{"snappy", "flate"} {
+		encoded := writeBlocks(t, data, compression)
+		if encoded[0] == codecNone {
+			t.Fatalf("%s: the block is not compressed", compression)
+		}
+		// the header claims a smaller block than the payload decodes to
+		understated := append([]byte(nil), encoded...)
+		binary.LittleEndian.PutUint32(understated[1:], 10)
+		_, err := ioutil.ReadAll(NewBlockReader(bytes.NewReader(understated)))
+		if err == nil || !strings.Contains(err.Error(), "decodes to") {
+			t.Errorf("%s: expecting an error decoding beyond the block size, got %v", compression, err)
+		}
+	}
+}
+
+func BenchmarkBlockWriter(b *testing.B) {
+	data := testBlockData()["compressible"]
+	for compression := range compressions {
+		b.Run(compression, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			w := NewBlockWriter(ioutil.Discard, compression)
+			for i := 0; i < b.N; i++ {
+				w.Write(data)
+			}
+			w.Close()
+		})
+	}
+}
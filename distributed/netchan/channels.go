@@ -1,76 +1,145 @@
 // Package netchan creates network channels. The network channels are managed by
-// glow agent.
+// glow agent. The channels to the same agent share one multiplexed connection.
 package netchan
 
 import (
 	"context"
 	"fmt"
 	"io"
-	"net"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/pb"
 	"github.com/chrislusf/gleam/util"
 	"github.com/golang/protobuf/proto"
 )
 
-func DialReadChannel(ctx context.Context, wg *sync.WaitGroup, readerName string, address string, channelName string, onDisk bool, outChan io.WriteCloser) error {
+// DialReadChannel reads the named channel from the agent. With a compression,
+// the data is transferred in checksummed blocks, see IsSupportedCompression.
+func DialReadChannel(ctx context.Context, wg *sync.WaitGroup, readerName string, address string, channelName string, onDisk bool, compression string, outChan io.WriteCloser) error {
+	return dialRead(ctx, wg, address, channelName, &pb.ControlMessage{
+		IsOnDiskIO: onDisk,
+		ReadRequest: &pb.ReadRequest{
+			ChannelName: channelName,
+			ReaderName:  readerName,
+		},
+		Compression: compression,
+	}, outChan)
+}
 
-	conn, err := net.Dial("tcp", address)
+// DialShuffleReadChannel reads the part a map task pushed into a reduce
+// partition, after the map task has pushed all of it.
+func DialShuffleReadChannel(ctx context.Context, wg *sync.WaitGroup, readerName string, address string, channelName string, shuffle *pb.ShufflePartition, compression string, outChan io.WriteCloser) error {
+	return dialRead(ctx, wg, address, channelName, &pb.ControlMessage{
+		ReadRequest: &pb.ReadRequest{
+			ChannelName: channelName,
+			ReaderName:  readerName,
+		},
+		Compression: compression,
+		Shuffle:     shuffle,
+	}, outChan)
+}
+
+func dialRead(ctx context.Context, wg *sync.WaitGroup, address string, channelName string, command *pb.ControlMessage, outChan io.WriteCloser) error {
+	reader, err := openRead(address, command)
 	if err != nil {
 		wg.Done()
-		return fmt.Errorf("Fail to dial read %s: %v", address, err)
-	}
-	defer conn.Close()
-	conn.SetDeadline(time.Time{})
-	if c, ok := conn.(*net.TCPConn); ok {
-		c.SetKeepAlive(true)
+		return err
 	}
+	return util.ReaderToChannel(wg, channelName, reader, outChan, true, os.Stderr)
+}
 
-	data, err := proto.Marshal(&pb.ControlMessage{
-		IsOnDiskIO: onDisk,
+// OpenInspectChannel starts to read the dataset shard on disk, or the shuffle
+// partition part if shuffle is set, from the agent. Unlike the readers of a
+// flow, it does not wait for the data, which fails to read if it is not
+// completely written or already removed. Closing the reader stops the reading.
+func OpenInspectChannel(readerName string, address string, channelName string, shuffle *pb.ShufflePartition, compression string) (io.ReadCloser, error) {
+	return openRead(address, &pb.ControlMessage{
+		IsOnDiskIO: true,
 		ReadRequest: &pb.ReadRequest{
-			ChannelName: channelName,
-			ReaderName:  readerName,
+			ChannelName:  channelName,
+			ReaderName:   readerName,
+			IsInspecting: true,
 		},
+		Compression: compression,
+		Shuffle:     shuffle,
 	})
+}
+
+func openRead(address string, command *pb.ControlMessage) (io.ReadCloser, error) {
 
+	conn, err := dialStream(address)
 	if err != nil {
-		wg.Done()
-		return fmt.Errorf("Fail to marshal ReadRequest: %v", err)
+		return nil, fmt.Errorf("Fail to dial read %s: %v", address, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	command.Authorization = security.AgentCredentials().Authorization()
+	data, err := proto.Marshal(command)
+
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Fail to marshal ReadRequest: %v", err)
 	}
 
 	if err = util.WriteMessage(conn, data); err != nil {
-		wg.Done()
-		return fmt.Errorf("Fail to write ReadRequest: %v", err)
+		conn.Close()
+		return nil, fmt.Errorf("Fail to write ReadRequest: %v", err)
 	}
 
-	return util.ReaderToChannel(wg, channelName, conn, outChan, true, os.Stderr)
+	if command.GetCompression() == "" {
+		return conn, nil
+	}
+	if _, err = readConfirmedCompression(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return NewBlockReader(conn), nil
 }
 
-func DialWriteChannel(ctx context.Context, wg *sync.WaitGroup, writerName string, address string, channelName string, onDisk bool, inChan io.Reader, readerCount int) error {
+// DialWriteChannel writes the named channel to the agent. With a compression,
+// the data is transferred in checksummed blocks, and the agent reports whether
+// all blocks are received intact.
+func DialWriteChannel(ctx context.Context, wg *sync.WaitGroup, writerName string, address string, channelName string, onDisk bool, compression string, inChan io.Reader, readerCount int) error {
+	return dialWrite(ctx, wg, address, channelName, &pb.ControlMessage{
+		IsOnDiskIO: onDisk,
+		WriteRequest: &pb.WriteRequest{
+			ChannelName: channelName,
+			ReaderCount: int32(readerCount),
+			WriterName:  writerName,
+		},
+		Compression: compression,
+	}, inChan)
+}
+
+// DialShuffleWriteChannel pushes the part of a reduce partition written by a
+// map task. The agent appends it to the merged file of the partition, so the
+// map task does not wait for the reduce task to connect.
+func DialShuffleWriteChannel(ctx context.Context, wg *sync.WaitGroup, writerName string, address string, channelName string, shuffle *pb.ShufflePartition, compression string, inChan io.Reader) error {
+	return dialWrite(ctx, wg, address, channelName, &pb.ControlMessage{
+		WriteRequest: &pb.WriteRequest{
+			ChannelName: channelName,
+			WriterName:  writerName,
+		},
+		Compression: compression,
+		Shuffle:     shuffle,
+	}, inChan)
+}
 
-	conn, err := net.Dial("tcp", address)
+func dialWrite(ctx context.Context, wg *sync.WaitGroup, address string, channelName string, command *pb.ControlMessage, inChan io.Reader) error {
+
+	conn, err := dialStream(address)
 	if err != nil {
 		wg.Done()
 		return fmt.Errorf("Fail to dial write %s: %v", address, err)
 	}
 	defer conn.Close()
 	conn.SetDeadline(time.Time{})
-	if c, ok := conn.(*net.TCPConn); ok {
-		c.SetKeepAlive(true)
-	}
 
-	data, err := proto.Marshal(&pb.ControlMessage{
-		IsOnDiskIO: onDisk,
-		WriteRequest: &pb.WriteRequest{
-			ChannelName: channelName,
-			ReaderCount: int32(readerCount),
-			WriterName:  writerName,
-		},
-	})
+	command.Authorization = security.AgentCredentials().Authorization()
+	data, err := proto.Marshal(command)
 
 	if err != nil {
 		wg.Done()
@@ -82,6 +151,42 @@ func DialWriteChannel(ctx context.Context, wg *sync.WaitGroup, writerName string
 		return fmt.Errorf("Fail to write WriteRequest: %v", err)
 	}
 
-	return util.ChannelToWriter(wg, channelName, inChan, conn, os.Stderr)
+	if command.GetCompression() == "" {
+		return util.ChannelToWriter(wg, channelName, inChan, conn, os.Stderr)
+	}
+
+	defer wg.Done()
+	compression, err := readConfirmedCompression(conn)
+	if err != nil {
+		return err
+	}
+	blockWriter := NewBlockWriter(conn, compression)
+	buf := make([]byte, util.BUFFER_SIZE)
+	if _, err = io.CopyBuffer(blockWriter, inChan, buf); err != nil {
+		return fmt.Errorf("Fail to write %s: %v", channelName, err)
+	}
+	if err = blockWriter.Close(); err != nil {
+		return fmt.Errorf("Fail to end %s: %v", channelName, err)
+	}
+	result, err := util.ReadMessage(conn)
+	if err != nil {
+		return fmt.Errorf("Fail to read the result of writing %s: %v", channelName, err)
+	}
+	if len(result) > 0 {
+		return fmt.Errorf("Fail to write %s: %s", channelName, result)
+	}
+	return nil
+}
 
+// readConfirmedCompression reads the compression the agent chooses.
+func readConfirmedCompression(conn io.Reader) (string, error) {
+	data, err := util.ReadMessage(conn)
+	if err != nil {
+		return "", fmt.Errorf("Fail to read confirmed compression: %v", err)
+	}
+	compression := string(data)
+	if !IsSupportedCompression(compression) {
+		return "", fmt.Errorf("Unknown confirmed compression %q", compression)
+	}
+	return compression, nil
 }
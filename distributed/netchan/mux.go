@@ -0,0 +1,451 @@
+package netchan
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// A session multiplexes the shard transfers between a process and an agent
+// over one connection. The client sends MuxPreface, then each side sends
+// frames of a 9 byte header, the frame type, the stream id and the length,
+// followed by the data for data frames.
+//
+// Each stream direction has a window of muxWindowSize bytes. The sender
+// stops when the window is used up, and the receiver gives the window back
+// as the data is read, so a slow reader does not hold up the other streams.
+// The receiving loop never writes to the connection itself, so the two
+// sides can not block each other when both are sending.
+const (
+	MuxPreface = "GLEAMMUX"
+
+	muxHeaderSize   = 9
+	muxMaxFrameSize = 32 * 1024
+	muxWindowSize   = 256 * 1024
+)
+
+const (
+	frameOpen byte = iota
+	frameData
+	frameWindow
+	frameClose
+)
+
+var errSessionClosed = errors.New("multiplexed session closed")
+
+type Session struct {
+	conn      net.Conn
+	isClient  bool
+	writeLock sync.Mutex
+
+	sync.Mutex
+	streams  map[uint32]*Stream
+	nextId   uint32
+	err      error
+	accepted chan *Stream
+	// called when the last stream is removed
+	onIdle func(*Session)
+}
+
+// NewClientSession starts a session on a connection dialed to an agent.
+// onIdle, if not nil, is called whenever the last open stream is removed.
+func NewClientSession(conn net.Conn, onIdle func(*Session)) (*Session, error) {
+	if _, err := conn.Write([]byte(MuxPreface)); err != nil {
+		return nil, err
+	}
+	s := newSession(conn, true)
+	s.onIdle = onIdle
+	go s.recvLoop()
+	return s, nil
+}
+
+// NewServerSession starts a session on an accepted connection, after
+// checking the preface.
+func NewServerSession(conn net.Conn) (*Session, error) {
+	preface := make([]byte, len(MuxPreface))
+	if _, err := io.ReadFull(conn, preface); err != nil {
+		return nil, err
+	}
+	if string(preface) != MuxPreface {
+		return nil, fmt.Errorf("unexpected preface %q", preface)
+	}
+	s := newSession(conn, false)
+	go s.recvLoop()
+	return s, nil
+}
+
+func newSession(conn net.Conn, isClient bool) *Session {
+	return &Session{
+		conn:     conn,
+		isClient: isClient,
+		streams:  make(map[uint32]*Stream),
+		nextId:   1,
+		accepted: make(chan *Stream, 16),
+	}
+}
+
+// Open starts a new stream to the server.
+func (s *Session) Open() (*Stream, error) {
+	s.Lock()
+	if s.err != nil {
+		s.Unlock()
+		return nil, s.err
+	}
+	stream := newStream(s, s.nextId)
+	s.nextId += 2
+	s.streams[stream.id] = stream
+	s.Unlock()
+
+	if err := s.writeFrame(frameOpen, stream.id, nil); err != nil {
+		s.removeStream(stream.id)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Accept waits for the next stream opened by the client.
+func (s *Session) Accept() (*Stream, error) {
+	stream, ok := <-s.accepted
+	if !ok {
+		return nil, s.Err()
+	}
+	return stream, nil
+}
+
+// Err is the reason the session is closed, or nil.
+func (s *Session) Err() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.err
+}
+
+// NumStreams counts the streams not closed yet.
+func (s *Session) NumStreams() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.streams)
+}
+
+func (s *Session) Close() error {
+	s.fail(errSessionClosed)
+	return nil
+}
+
+// fail closes the connection and wakes up all streams with the error.
+func (s *Session) fail(err error) {
+	s.Lock()
+	if s.err != nil {
+		s.Unlock()
+		return
+	}
+	s.err = err
+	streams := s.streams
+	s.streams = make(map[uint32]*Stream)
+	close(s.accepted)
+	s.Unlock()
+
+	s.conn.Close()
+	for _, stream := range streams {
+		stream.fail(err)
+	}
+}
+
+func (s *Session) writeFrame(frameType byte, id uint32, data []byte) error {
+	var header [muxHeaderSize]byte
+	header[0] = frameType
+	binary.LittleEndian.PutUint32(header[1:], id)
+	binary.LittleEndian.PutUint32(header[5:], uint32(len(data)))
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	if _, err := s.conn.Write(header[:]); err != nil {
+		s.fail(err)
+		return err
+	}
+	if len(data) > 0 {
+		if _, err := s.conn.Write(data); err != nil {
+			s.fail(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Session) sendWindow(id uint32, size int) error {
+	var data [4]byte
+	binary.LittleEndian.PutUint32(data[:], uint32(size))
+	return s.writeFrame(frameWindow, id, data[:])
+}
+
+func (s *Session) recvLoop() {
+	var header [muxHeaderSize]byte
+	for {
+		if _, err := io.ReadFull(s.conn, header[:]); err != nil {
+			// the open streams must not take it as their end
+			if err == io.EOF {
+				err = errSessionClosed
+			}
+			s.fail(err)
+			return
+		}
+		frameType := header[0]
+		id := binary.LittleEndian.Uint32(header[1:])
+		length := binary.LittleEndian.Uint32(header[5:])
+		if length > muxMaxFrameSize {
+			s.fail(fmt.Errorf("frame of %d bytes is too large", length))
+			return
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(s.conn, data); err != nil {
+			s.fail(err)
+			return
+		}
+		if err := s.handleFrame(frameType, id, data); err != nil {
+			s.fail(err)
+			return
+		}
+	}
+}
+
+func (s *Session) handleFrame(frameType byte, id uint32, data []byte) error {
+	if frameType == frameOpen {
+		if s.isClient {
+			return fmt.Errorf("unexpected stream %d opened by the server", id)
+		}
+		s.Lock()
+		defer s.Unlock()
+		if s.err != nil {
+			return s.err
+		}
+		if _, found := s.streams[id]; found {
+			return fmt.Errorf("stream %d opened twice", id)
+		}
+		stream := newStream(s, id)
+		s.streams[id] = stream
+		s.accepted <- stream
+		return nil
+	}
+
+	s.Lock()
+	stream, found := s.streams[id]
+	s.Unlock()
+	if !found {
+		// the stream is closed locally and forgotten, drop the data but give back the window
+		if frameType == frameData {
+			go s.sendWindow(id, len(data))
+		}
+		return nil
+	}
+
+	switch frameType {
+	case frameData:
+		return stream.receive(data)
+	case frameWindow:
+		if len(data) != 4 {
+			return fmt.Errorf("window update of %d bytes", len(data))
+		}
+		stream.addSendWindow(int(binary.LittleEndian.Uint32(data)))
+	case frameClose:
+		stream.remoteClose()
+	default:
+		return fmt.Errorf("unknown frame type %d", frameType)
+	}
+	return nil
+}
+
+func (s *Session) removeStream(id uint32) {
+	s.Lock()
+	if _, found := s.streams[id]; !found {
+		s.Unlock()
+		return
+	}
+	delete(s.streams, id)
+	idle := len(s.streams) == 0 && s.err == nil
+	onIdle := s.onIdle
+	s.Unlock()
+
+	if idle && onIdle != nil {
+		onIdle(s)
+	}
+}
+
+// Stream is one shard transfer in a session. It implements net.Conn,
+// with no deadlines.
+type Stream struct {
+	session *Session
+	id      uint32
+
+	sync.Mutex
+	cond          *sync.Cond
+	buf           []byte
+	consumed      int
+	sendWindow    int
+	localClosed   bool
+	remoteClosed  bool
+	writeShutdown bool
+	err           error
+}
+
+func newStream(session *Session, id uint32) *Stream {
+	stream := &Stream{
+		session:    session,
+		id:         id,
+		sendWindow: muxWindowSize,
+	}
+	stream.cond = sync.NewCond(&stream.Mutex)
+	return stream
+}
+
+func (stream *Stream) Read(p []byte) (n int, err error) {
+	stream.Lock()
+	for len(stream.buf) == 0 {
+		if stream.remoteClosed || stream.localClosed {
+			stream.Unlock()
+			return 0, io.EOF
+		}
+		if stream.err != nil {
+			err = stream.err
+			stream.Unlock()
+			return 0, err
+		}
+		stream.cond.Wait()
+	}
+	n = copy(p, stream.buf)
+	stream.buf = stream.buf[n:]
+	stream.consumed += n
+	var credit int
+	if stream.consumed >= muxWindowSize/2 {
+		credit, stream.consumed = stream.consumed, 0
+	}
+	stream.Unlock()
+
+	if credit > 0 {
+		if err = stream.session.sendWindow(stream.id, credit); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (stream *Stream) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		stream.Lock()
+		for stream.sendWindow == 0 && stream.err == nil && !stream.writeShutdown {
+			stream.cond.Wait()
+		}
+		if stream.err != nil {
+			err = stream.err
+			stream.Unlock()
+			return n, err
+		}
+		if stream.writeShutdown {
+			stream.Unlock()
+			return n, io.ErrClosedPipe
+		}
+		size := len(p)
+		if size > stream.sendWindow {
+			size = stream.sendWindow
+		}
+		if size > muxMaxFrameSize {
+			size = muxMaxFrameSize
+		}
+		stream.sendWindow -= size
+		stream.Unlock()
+
+		if err = stream.session.writeFrame(frameData, stream.id, p[:size]); err != nil {
+			return n, err
+		}
+		n += size
+		p = p[size:]
+	}
+	return n, nil
+}
+
+// CloseWrite tells the other side no more data is coming.
+func (stream *Stream) CloseWrite() error {
+	stream.Lock()
+	if stream.writeShutdown || stream.err != nil {
+		stream.Unlock()
+		return nil
+	}
+	stream.writeShutdown = true
+	stream.cond.Broadcast()
+	stream.Unlock()
+	return stream.session.writeFrame(frameClose, stream.id, nil)
+}
+
+// Close ends the writing and drops the data not read yet.
+func (stream *Stream) Close() error {
+	err := stream.CloseWrite()
+
+	stream.Lock()
+	stream.localClosed = true
+	unread := len(stream.buf) + stream.consumed
+	stream.buf = nil
+	stream.consumed = 0
+	remoteClosed := stream.remoteClosed
+	stream.cond.Broadcast()
+	stream.Unlock()
+
+	// let the other side finish writing
+	if unread > 0 && !remoteClosed {
+		stream.session.sendWindow(stream.id, unread)
+	}
+	if remoteClosed {
+		stream.session.removeStream(stream.id)
+	}
+	return err
+}
+
+func (stream *Stream) receive(data []byte) error {
+	stream.Lock()
+	defer stream.Unlock()
+	if stream.localClosed {
+		go stream.session.sendWindow(stream.id, len(data))
+		return nil
+	}
+	if len(stream.buf)+len(data) > muxWindowSize {
+		return fmt.Errorf("stream %d received more than its window", stream.id)
+	}
+	stream.buf = append(stream.buf, data...)
+	stream.cond.Broadcast()
+	return nil
+}
+
+func (stream *Stream) addSendWindow(size int) {
+	stream.Lock()
+	stream.sendWindow += size
+	stream.cond.Broadcast()
+	stream.Unlock()
+}
+
+func (stream *Stream) remoteClose() {
+	stream.Lock()
+	stream.remoteClosed = true
+	localClosed := stream.localClosed
+	stream.cond.Broadcast()
+	stream.Unlock()
+
+	if localClosed {
+		stream.session.removeStream(stream.id)
+	}
+}
+
+func (stream *Stream) fail(err error) {
+	stream.Lock()
+	if stream.err == nil {
+		stream.err = err
+	}
+	stream.cond.Broadcast()
+	stream.Unlock()
+}
+
+func (stream *Stream) LocalAddr() net.Addr                { return stream.session.conn.LocalAddr() }
+func (stream *Stream) RemoteAddr() net.Addr               { return stream.session.conn.RemoteAddr() }
+func (stream *Stream) SetDeadline(t time.Time) error      { return nil }
+func (stream *Stream) SetReadDeadline(t time.Time) error  { return nil }
+func (stream *Stream) SetWriteDeadline(t time.Time) error { return nil }
@@ -0,0 +1,75 @@
+package netchan
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleam/distributed/security"
+)
+
+// a session without streams is closed after muxIdleTimeout
+const muxIdleTimeout = 30 * time.Second
+
+type sessionPool struct {
+	sync.Mutex
+	entries map[string]*sessionEntry
+}
+
+type sessionEntry struct {
+	sync.Mutex
+	session *Session
+}
+
+var sessions = &sessionPool{entries: make(map[string]*sessionEntry)}
+
+// dialStream opens a stream to the agent, on the session shared by all
+// shard transfers of this process to the agent.
+func dialStream(address string) (net.Conn, error) {
+	sessions.Lock()
+	entry, found := sessions.entries[address]
+	if !found {
+		entry = &sessionEntry{}
+		sessions.entries[address] = entry
+	}
+	sessions.Unlock()
+
+	entry.Lock()
+	defer entry.Unlock()
+
+	if entry.session != nil {
+		if stream, err := entry.session.Open(); err == nil {
+			return stream, nil
+		}
+		// the session is broken, start another one
+		entry.session.Close()
+		entry.session = nil
+	}
+
+	conn, err := security.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	session, err := NewClientSession(conn, func(session *Session) {
+		time.AfterFunc(muxIdleTimeout, func() {
+			entry.closeIdle(session)
+		})
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	entry.session = session
+
+	return session.Open()
+}
+
+func (entry *sessionEntry) closeIdle(session *Session) {
+	entry.Lock()
+	defer entry.Unlock()
+	if entry.session == session && session.NumStreams() == 0 {
+		session.Close()
+		entry.session = nil
+	}
+}
@@ -0,0 +1,210 @@
+package netchan
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestSessions(t *testing.T, onIdle func(*Session)) (client, server *Session) {
+	clientConn, serverConn := net.Pipe()
+	serverChan := make(chan *Session, 1)
+	go func() {
+		server, err := NewServerSession(serverConn)
+		if err != nil {
+			t.Error(err)
+		}
+		serverChan <- server
+	}()
+	client, err := NewClientSession(clientConn, onIdle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = <-serverChan
+	if server == nil {
+		t.FailNow()
+	}
+	return client, server
+}
+
+// echo sends back everything read on each accepted stream.
+func echo(server *Session) {
+	for {
+		stream, err := server.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer stream.Close()
+			io.Copy(stream, stream)
+		}()
+	}
+}
+
+func TestMuxConcurrentStreams(t *testing.T) {
+	client, server := newTestSessions(t, nil)
+	defer client.Close()
+	defer server.Close()
+	go echo(server)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// larger than the window, so the streams take turns
+			data := bytes.Repeat([]byte(fmt.Sprintf("stream %d ", i)), 3*muxWindowSize/8)
+			stream, err := client.Open()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer stream.Close()
+			go func() {
+				stream.Write(data)
+				stream.CloseWrite()
+			}()
+			received, err := ioutil.ReadAll(stream)
+			if err != nil {
+				t.Errorf("stream %d: %v", i, err)
+				return
+			}
+			if !bytes.Equal(received, data) {
+				t.Errorf("stream %d: received %d bytes, expecting %d", i, len(received), len(data))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestMuxWindowExhaustion(t *testing.T) {
+	client, server := newTestSessions(t, nil)
+	defer client.Close()
+	defer server.Close()
+
+	slow, err := client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slowAccepted, err := server.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var written int64
+	writeDone := make(chan struct{})
+	go func() {
+		defer close(writeDone)
+		chunk := make([]byte, 1024)
+		for i := 0; i < 2*muxWindowSize/len(chunk); i++ {
+			n, err := slow.Write(chunk)
+			atomic.AddInt64(&written, int64(n))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		slow.CloseWrite()
+	}()
+
+	// the writer stops when the window is used up
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt64(&written); n != muxWindowSize {
+		t.Errorf("written %d bytes without a reader, expecting the window of %d bytes", n, muxWindowSize)
+	}
+
+	// the other streams are not held up
+	go echo(server)
+	fast, err := client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast.Write([]byte("hello"))
+	fast.CloseWrite()
+	if received, err := ioutil.ReadAll(fast); err != nil || string(received) != "hello" {
+		t.Errorf("received %q, %v from the other stream", received, err)
+	}
+	fast.Close()
+
+	// reading gives the window back
+	received, err := ioutil.ReadAll(slowAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2*muxWindowSize {
+		t.Errorf("received %d bytes, expecting %d", len(received), 2*muxWindowSize)
+	}
+	<-writeDone
+}
+
+func TestMuxSessionClose(t *testing.T) {
+	idle := make(chan *Session, 1)
+	client, server := newTestSessions(t, func(s *Session) { idle <- s })
+	defer server.Close()
+
+	stream, err := client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accepted, err := server.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// closing both sides of the last stream makes the session idle
+	stream.Close()
+	accepted.Close()
+	select {
+	case s := <-idle:
+		if s != client {
+			t.Errorf("idle is called with another session")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("idle is not called after the last stream is closed")
+	}
+
+	stream, err = client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accepted, err = server.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := ioutil.ReadAll(accepted)
+		readErr <- err
+	}()
+
+	client.Close()
+
+	// a broken session is not taken as the end of the streams
+	select {
+	case err := <-readErr:
+		if err == nil {
+			t.Errorf("expecting an error reading a stream of a closed session")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("reading a stream of a closed session does not return")
+	}
+	if _, err := stream.Write([]byte("hello")); err == nil {
+		t.Errorf("expecting an error writing a stream of a closed session")
+	}
+	if _, err := client.Open(); err == nil {
+		t.Errorf("expecting an error opening a stream on a closed session")
+	}
+	if _, err := server.Accept(); err == nil {
+		t.Errorf("expecting an error accepting on a closed session")
+	}
+	select {
+	case <-idle:
+		t.Errorf("idle is called on a closed session")
+	default:
+	}
+}
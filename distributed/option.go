@@ -5,6 +5,7 @@ import (
 
 	"github.com/chrislusf/gleam/distributed/driver"
 	"github.com/chrislusf/gleam/distributed/resource"
+	"github.com/chrislusf/gleam/distributed/security"
 	"github.com/chrislusf/gleam/flow"
 )
 
@@ -17,6 +18,13 @@ type DistributedOption struct {
 	FlowBid       float64
 	Module        string
 	IsProfiling   bool
+	TLSOption     *security.TLSOption
+	Credentials   *security.Credentials
+	Queue         string
+	Compression   string
+	PushShuffle   bool
+	// adapt to the actual partition sizes, aiming at partitions of this size
+	AdaptivePartitionMB int
 }
 
 func Option() *DistributedOption {
@@ -30,14 +38,20 @@ func Option() *DistributedOption {
 
 func (o *DistributedOption) GetFlowRunner() flow.FlowRunner {
 	return driver.NewFlowDriver(&driver.Option{
-		RequiredFiles: o.RequiredFiles,
-		Master:        o.Master,
-		DataCenter:    o.DataCenter,
-		Rack:          o.Rack,
-		TaskMemoryMB:  o.TaskMemoryMB,
-		FlowBid:       o.FlowBid,
-		Module:        o.Module,
-		IsProfiling:   o.IsProfiling,
+		RequiredFiles:       o.RequiredFiles,
+		Master:              o.Master,
+		DataCenter:          o.DataCenter,
+		Rack:                o.Rack,
+		TaskMemoryMB:        o.TaskMemoryMB,
+		FlowBid:             o.FlowBid,
+		Module:              o.Module,
+		IsProfiling:         o.IsProfiling,
+		TLSOption:           o.TLSOption,
+		Credentials:         o.Credentials,
+		Queue:               o.Queue,
+		Compression:         o.Compression,
+		PushShuffle:         o.PushShuffle,
+		AdaptivePartitionMB: o.AdaptivePartitionMB,
 	})
 }
 
@@ -46,6 +60,8 @@ func (o *DistributedOption) SetDataCenter(dataCenter string) *DistributedOption
 	return o
 }
 
+// SetMaster sets the master address, or comma separated addresses
+// of the active and standby masters.
 func (o *DistributedOption) SetMaster(master string) *DistributedOption {
 	o.Master = master
 	return o
@@ -56,6 +72,70 @@ func (o *DistributedOption) SetProfiling(isProfiling bool) *DistributedOption {
 	return o
 }
 
+// SetTLS enables mutual TLS to the master and agents.
+// The certificates should be signed by the same CA as the master's and agents'.
+func (o *DistributedOption) SetTLS(certFile, keyFile, caFile string) *DistributedOption {
+	o.TLSOption = &security.TLSOption{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+	}
+	return o
+}
+
+// SetQueue submits the flow to a named queue on the master.
+func (o *DistributedOption) SetQueue(queue string) *DistributedOption {
+	o.Queue = queue
+	return o
+}
+
+// SetCompression sets how the dataset shards are compressed when moved
+// between the agents, executors and the driver: "snappy", "flate", or "none".
+// The moved data is checked by CRC in all cases.
+//
+// By default the shards are moved as plain streams, which all agents
+// understand. The compressed, checksummed blocks change the transfer
+// protocol, so set a compression only when all agents support it. An older
+// agent ignores the compression and the transfers to it fail.
+func (o *DistributedOption) SetCompression(compression string) *DistributedOption {
+	o.Compression = compression
+	return o
+}
+
+// SetPushShuffle lets the partitioning steps push their partitions to the
+// agents, which merge them into one file per partition. The partitions are
+// spread over the agents running the flow, and each collecting task reads its
+// partition from one file at once. The partitioning tasks then finish without
+// waiting for the tasks reading the partitions.
+func (o *DistributedOption) SetPushShuffle(pushShuffle bool) *DistributedOption {
+	o.PushShuffle = pushShuffle
+	return o
+}
+
+// SetAdaptive lets the driver adapt the steps reading partitions to the
+// actual partition sizes, counted when the partitions are written.
+// Small partitions are coalesced, oversized ones are sorted in parts,
+// and a join hashes its smaller side if the side is within partitionMB,
+// in which case the joined rows are not sorted.
+// The partitions are written to disk, so all of them are sized
+// before any of them is read. 0 turns it off.
+func (o *DistributedOption) SetAdaptive(partitionMB int) *DistributedOption {
+	o.AdaptivePartitionMB = partitionMB
+	return o
+}
+
+// SetToken authenticates to the master with a token.
+func (o *DistributedOption) SetToken(token string) *DistributedOption {
+	o.Credentials = &security.Credentials{Token: token}
+	return o
+}
+
+// SetPassword authenticates to the master with a username and password.
+func (o *DistributedOption) SetPassword(username, password string) *DistributedOption {
+	o.Credentials = &security.Credentials{Username: username, Password: password}
+	return o
+}
+
 // WithFile sends any related file over to gleam agents
 // so the task can still access these files on gleam agents.
 // The files are placed on the executed task's current working directory.
@@ -108,3 +108,14 @@ func (s *StepGroup) WaitForAllTasksToComplete() {
 		}
 	}
 }
+
+// WaitToComplete waits until the task group completes without errors.
+func (t *TaskGroup) WaitToComplete() {
+	s := t.ParentStepGroup
+	s.Lock()
+	defer s.Unlock()
+
+	for t.StopAt.IsZero() || t.Error != nil {
+		s.waitForAllTasks.Wait()
+	}
+}
@@ -57,8 +57,13 @@ func findAncestorStepId(step *flow.Step) (int, bool) {
 
 // group local steps into one step group
 func translateToStepGroups(fc *flow.Flow) []*StepGroup {
-	// use array instead of map to ensure consistent ordering
-	stepId2StepGroup := make([]*StepGroup, len(fc.Steps))
+	// use array instead of map to ensure consistent ordering,
+	// the step ids have gaps where the optimizer removed steps
+	var stepCount int
+	if n := len(fc.Steps); n > 0 {
+		stepCount = fc.Steps[n-1].Id + 1
+	}
+	stepId2StepGroup := make([]*StepGroup, stepCount)
 	for _, step := range fc.Steps {
 		// println("step:", step.Name, step.Id, "starting...")
 		ancestorStepId, foundStepId := findAncestorStepId(step)
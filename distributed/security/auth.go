@@ -0,0 +1,270 @@
+package security
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type Permission string
+
+const (
+	// submit flows to run
+	PermissionSubmit Permission = "submit"
+	// view jobs of other users. Users can always view their own jobs.
+	PermissionView Permission = "view"
+	// cancel jobs of other users. Users can always cancel their own jobs.
+	PermissionCancel Permission = "cancel"
+	// join the cluster as an agent
+	PermissionAgent Permission = "agent"
+	// replicate all jobs as the standby master
+	PermissionPeer Permission = "peer"
+)
+
+var allPermissions = []Permission{PermissionSubmit, PermissionView, PermissionCancel, PermissionAgent, PermissionPeer}
+
+// Authenticator verifies the credentials and returns the user name.
+type Authenticator interface {
+	Authenticate(creds *Credentials) (username string, ok bool)
+}
+
+type userContextKey struct{}
+
+// Auth authenticates the requests to the master or an agent, and checks the user permissions.
+// A nil *Auth allows all requests.
+type Auth struct {
+	authenticators []Authenticator
+	// username => permissions, "*" for all other users
+	permissions map[string][]Permission
+	// grpc full method name => the required permission
+	methodPermissions map[string]Permission
+}
+
+// NewAuth loads the token, htpasswd and permission files.
+// It returns nil if neither the token nor the htpasswd file is set.
+// The credentials are only sent over TLS, so authentication requires TLS.
+func NewAuth(tokenFile, htpasswdFile, permissionFile string, methodPermissions map[string]Permission) (*Auth, error) {
+	auth := &Auth{methodPermissions: methodPermissions}
+	if tokenFile != "" {
+		authenticator, err := NewTokenAuthenticator(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		auth.authenticators = append(auth.authenticators, authenticator)
+	}
+	if htpasswdFile != "" {
+		authenticator, err := NewHtpasswdAuthenticator(htpasswdFile)
+		if err != nil {
+			return nil, err
+		}
+		auth.authenticators = append(auth.authenticators, authenticator)
+	}
+	if len(auth.authenticators) == 0 {
+		return nil, nil
+	}
+	if !IsTLSEnabled() {
+		return nil, fmt.Errorf("authentication requires TLS, to not send the credentials in plain text")
+	}
+	if permissionFile != "" {
+		permissions, err := loadPermissions(permissionFile)
+		if err != nil {
+			return nil, err
+		}
+		auth.permissions = permissions
+	}
+	return auth, nil
+}
+
+func (auth *Auth) authenticate(creds *Credentials) (string, error) {
+	for _, authenticator := range auth.authenticators {
+		if username, ok := authenticator.Authenticate(creds); ok {
+			return username, nil
+		}
+	}
+	return "", fmt.Errorf("invalid credentials")
+}
+
+// HasPermission checks whether the user has the permission.
+// Without the permission file, all authenticated users have all permissions.
+func (auth *Auth) HasPermission(username string, permission Permission) bool {
+	if auth == nil || auth.permissions == nil {
+		return true
+	}
+	permissions, found := auth.permissions[username]
+	if !found {
+		permissions = auth.permissions["*"]
+	}
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessJob checks whether the user can view or cancel the job.
+func (auth *Auth) CanAccessJob(username string, permission Permission, status *pb.FlowExecutionStatus) bool {
+	return auth.CanAccessJobOf(username, permission, status.GetDriver().GetUsername())
+}
+
+// CanAccessJobOf checks whether the user can view or cancel a job submitted by the owner.
+func (auth *Auth) CanAccessJobOf(username string, permission Permission, owner string) bool {
+	if auth == nil {
+		return true
+	}
+	if owner == username {
+		return true
+	}
+	return auth.HasPermission(username, permission)
+}
+
+func (auth *Auth) authorizeGrpc(ctx context.Context, method string) (context.Context, error) {
+	creds, err := CredentialsFromContext(ctx)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unauthenticated, "%v", err)
+	}
+	username, err := auth.authenticate(creds)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unauthenticated, "%v", err)
+	}
+	if permission, found := auth.methodPermissions[method]; found && !auth.HasPermission(username, permission) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "%s has no %s permission", username, permission)
+	}
+	return context.WithValue(ctx, userContextKey{}, username), nil
+}
+
+// Authorize authenticates the value of an "Authorization" header,
+// and requires the user to have any of the permissions.
+func (auth *Auth) Authorize(authorization string, permissions ...Permission) (string, error) {
+	if auth == nil {
+		return "", nil
+	}
+	creds, err := ParseAuthorization(authorization)
+	if err != nil {
+		return "", err
+	}
+	username, err := auth.authenticate(creds)
+	if err != nil {
+		return "", err
+	}
+	for _, permission := range permissions {
+		if auth.HasPermission(username, permission) {
+			return username, nil
+		}
+	}
+	return "", fmt.Errorf("%s has no %v permission", username, permissions)
+}
+
+func (auth *Auth) UnaryServerInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := auth.authorizeGrpc(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (auth *Auth) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := auth.authorizeGrpc(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedServerStream{stream, ctx})
+}
+
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// HttpHandler requires authentication with http basic auth or a bearer token.
+func (auth *Auth) HttpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds, err := CredentialsFromRequest(r)
+		if err == nil {
+			var username string
+			if username, err = auth.authenticate(creds); err == nil {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, username)))
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="gleam"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	})
+}
+
+// UsernameFromContext returns the authenticated user, or "" if authentication is disabled.
+func UsernameFromContext(ctx context.Context) string {
+	username, _ := ctx.Value(userContextKey{}).(string)
+	return username
+}
+
+// loadPermissions reads lines of "username: permission1,permission2".
+func loadPermissions(fileName string) (map[string][]Permission, error) {
+	permissions := make(map[string][]Permission)
+	err := ReadConfigLines(fileName, func(line string) error {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("expecting username: permissions, but got %s", line)
+		}
+		username := strings.TrimSpace(parts[0])
+		for _, p := range strings.Split(parts[1], ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			if p == "all" {
+				permissions[username] = append(permissions[username], allPermissions...)
+				continue
+			}
+			if !isKnownPermission(Permission(p)) {
+				return fmt.Errorf("unknown permission %s for %s", p, username)
+			}
+			permissions[username] = append(permissions[username], Permission(p))
+		}
+		return nil
+	})
+	return permissions, err
+}
+
+func isKnownPermission(permission Permission) bool {
+	for _, p := range allPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadConfigLines calls fn for each line, skipping empty lines and comments starting with "#".
+func ReadConfigLines(fileName string, fn func(line string) error) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("Failed to open %s: %v", fileName, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("%s: %v", fileName, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,48 @@
+package security
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HtpasswdAuthenticator authenticates username and password
+// from a file created by "htpasswd -B" (bcrypt) or "htpasswd -s" (SHA1).
+type HtpasswdAuthenticator struct {
+	// username => hashed password
+	passwords map[string]string
+}
+
+func NewHtpasswdAuthenticator(fileName string) (*HtpasswdAuthenticator, error) {
+	a := &HtpasswdAuthenticator{passwords: make(map[string]string)}
+	err := ReadConfigLines(fileName, func(line string) error {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("expecting username:password")
+		}
+		hash := parts[1]
+		if !strings.HasPrefix(hash, "$2") && !strings.HasPrefix(hash, "{SHA}") {
+			return fmt.Errorf("unsupported password hash for %s, use bcrypt or SHA1", parts[0])
+		}
+		a.passwords[parts[0]] = hash
+		return nil
+	})
+	return a, err
+}
+
+func (a *HtpasswdAuthenticator) Authenticate(creds *Credentials) (string, bool) {
+	hash, found := a.passwords[creds.Username]
+	if !found {
+		return "", false
+	}
+	if strings.HasPrefix(hash, "{SHA}") {
+		sum := sha1.Sum([]byte(creds.Password))
+		expected := "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+		return creds.Username, subtle.ConstantTimeCompare([]byte(hash), []byte(expected)) == 1
+	}
+	return creds.Username, bcrypt.CompareHashAndPassword([]byte(hash), []byte(creds.Password)) == nil
+}
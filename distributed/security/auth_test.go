@@ -0,0 +1,215 @@
+package security
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// writeConfig writes the lines into a temporary file, and returns its name.
+func writeConfig(t *testing.T, lines ...string) string {
+	f, err := ioutil.TempFile("", "gleam-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	fileName := writeConfig(t, "# token username", "", "secret1 alice", "secret2 bob")
+	defer os.Remove(fileName)
+	a, err := NewTokenAuthenticator(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		creds    *Credentials
+		username string
+		ok       bool
+	}{
+		{"bearer token", &Credentials{Token: "secret2"}, "bob", true},
+		{"token as the basic auth password", &Credentials{Username: "anyone", Password: "secret1"}, "alice", true},
+		{"unknown token", &Credentials{Token: "secret3"}, "", false},
+		{"prefix of a token", &Credentials{Token: "secret"}, "", false},
+		{"empty credentials", &Credentials{}, "", false},
+	}
+	for _, test := range tests {
+		if username, ok := a.Authenticate(test.creds); username != test.username || ok != test.ok {
+			t.Errorf("%s: authenticated %q %v, expecting %q %v", test.name, username, ok, test.username, test.ok)
+		}
+	}
+
+	badFile := writeConfig(t, "secret1 alice extra")
+	defer os.Remove(badFile)
+	if _, err := NewTokenAuthenticator(badFile); err == nil {
+		t.Errorf("expecting an error for a line of 3 fields")
+	}
+	if _, err := NewTokenAuthenticator(filepath.Join(os.TempDir(), "gleam-missing-tokens")); err == nil {
+		t.Errorf("expecting an error for a missing token file")
+	}
+}
+
+func TestHtpasswdAuthenticator(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("bcrypt-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum([]byte("sha-password"))
+	shaHash := "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+
+	fileName := writeConfig(t, "alice:"+string(bcryptHash), "bob:"+shaHash)
+	defer os.Remove(fileName)
+	a, err := NewHtpasswdAuthenticator(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		creds *Credentials
+		ok    bool
+	}{
+		{"bcrypt password", &Credentials{Username: "alice", Password: "bcrypt-password"}, true},
+		{"wrong bcrypt password", &Credentials{Username: "alice", Password: "sha-password"}, false},
+		{"SHA1 password", &Credentials{Username: "bob", Password: "sha-password"}, true},
+		{"wrong SHA1 password", &Credentials{Username: "bob", Password: "bcrypt-password"}, false},
+		{"unknown user", &Credentials{Username: "carol", Password: "sha-password"}, false},
+	}
+	for _, test := range tests {
+		if _, ok := a.Authenticate(test.creds); ok != test.ok {
+			t.Errorf("%s: authenticated %v, expecting %v", test.name, ok, test.ok)
+		}
+	}
+
+	for _, line := range []string{"alice", "alice:$apr1$plain$md5", "alice:plaintext"} {
+		badFile := writeConfig(t, line)
+		if _, err := NewHtpasswdAuthenticator(badFile); err == nil {
+			t.Errorf("expecting an error for %q", line)
+		}
+		os.Remove(badFile)
+	}
+}
+
+func TestLoadPermissions(t *testing.T) {
+	fileName := writeConfig(t,
+		"# username: permissions",
+		"alice: all",
+		"bob: submit, view,",
+		"*: view",
+	)
+	defer os.Remove(fileName)
+	permissions, err := loadPermissions(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]Permission{
+		"alice": allPermissions,
+		"bob":   {PermissionSubmit, PermissionView},
+		"*":     {PermissionView},
+	}
+	if !reflect.DeepEqual(permissions, expected) {
+		t.Errorf("loaded %v, expecting %v", permissions, expected)
+	}
+
+	for _, line := range []string{"alice submit", "alice: submit, admin"} {
+		badFile := writeConfig(t, line)
+		if _, err := loadPermissions(badFile); err == nil {
+			t.Errorf("expecting an error for %q", line)
+		}
+		os.Remove(badFile)
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	auth := &Auth{permissions: map[string][]Permission{
+		"alice": {PermissionSubmit, PermissionCancel},
+		"*":     {PermissionView},
+	}}
+	job := &pb.FlowExecutionStatus{Driver: &pb.FlowExecutionStatus_DriverInfo{Username: "bob"}}
+
+	tests := []struct {
+		name       string
+		auth       *Auth
+		username   string
+		permission Permission
+		canAccess  bool
+	}{
+		{"own job", auth, "bob", PermissionCancel, true},
+		{"granted permission", auth, "alice", PermissionCancel, true},
+		{"listed user without the permission", auth, "alice", PermissionView, false},
+		{"permission of all other users", auth, "carol", PermissionView, true},
+		{"permission not given to all other users", auth, "carol", PermissionCancel, false},
+		{"no permission file", &Auth{}, "carol", PermissionCancel, true},
+		{"authentication disabled", nil, "", PermissionCancel, true},
+	}
+	for _, test := range tests {
+		if canAccess := test.auth.CanAccessJob(test.username, test.permission, job); canAccess != test.canAccess {
+			t.Errorf("%s: can access %v, expecting %v", test.name, canAccess, test.canAccess)
+		}
+	}
+	if auth.HasPermission("alice", PermissionView) {
+		t.Errorf("a listed user does not take the permissions of all other users")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	fileName := writeConfig(t, "secret1 alice", "secret2 bob")
+	defer os.Remove(fileName)
+	authenticator, err := NewTokenAuthenticator(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &Auth{
+		authenticators: []Authenticator{authenticator},
+		permissions:    map[string][]Permission{"alice": {PermissionAgent}},
+	}
+
+	if username, err := auth.Authorize("Bearer secret1", PermissionSubmit, PermissionAgent); err != nil || username != "alice" {
+		t.Errorf("authorized %q %v, expecting alice", username, err)
+	}
+	basic := (&Credentials{Username: "alice", Password: "secret1"}).Authorization()
+	if username, err := auth.Authorize(basic, PermissionAgent); err != nil || username != "alice" {
+		t.Errorf("authorized %q %v with basic auth, expecting alice", username, err)
+	}
+	for _, authorization := range []string{"Bearer secret2", "Bearer secret3", "secret1", "Digest secret1", ""} {
+		if _, err := auth.Authorize(authorization, PermissionAgent); err == nil {
+			t.Errorf("expecting an error authorizing %q", authorization)
+		}
+	}
+}
+
+func TestNewAuthRequiresTLS(t *testing.T) {
+	fileName := writeConfig(t, "secret1 alice")
+	defer os.Remove(fileName)
+
+	if auth, err := NewAuth("", "", "", nil); auth != nil || err != nil {
+		t.Errorf("expecting no authentication without the token or htpasswd file, got %v %v", auth, err)
+	}
+	if _, err := NewAuth(fileName, "", "", nil); err == nil {
+		t.Errorf("expecting an error without TLS")
+	}
+
+	enableTestTLS(t)
+	defer disableTLS()
+	auth, err := NewAuth(fileName, "", "", nil)
+	if err != nil || auth == nil {
+		t.Fatalf("expecting authentication with TLS, got %v %v", auth, err)
+	}
+	if _, err := auth.Authorize("Bearer secret1", PermissionView); err != nil {
+		t.Errorf("authorizing: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package security
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// TokenAuthenticator authenticates static tokens.
+type TokenAuthenticator struct {
+	// token => username
+	tokens map[string]string
+}
+
+// NewTokenAuthenticator reads lines of "token username".
+func NewTokenAuthenticator(fileName string) (*TokenAuthenticator, error) {
+	a := &TokenAuthenticator{tokens: make(map[string]string)}
+	err := ReadConfigLines(fileName, func(line string) error {
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			return fmt.Errorf("expecting token and username, but got %d fields", len(parts))
+		}
+		a.tokens[parts[0]] = parts[1]
+		return nil
+	})
+	return a, err
+}
+
+// Authenticate accepts a bearer token, or a token as the password of http basic auth.
+func (a *TokenAuthenticator) Authenticate(creds *Credentials) (string, bool) {
+	token := creds.Token
+	if token == "" {
+		token = creds.Password
+	}
+	for t, username := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return username, true
+		}
+	}
+	return "", false
+}
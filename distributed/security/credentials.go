@@ -0,0 +1,128 @@
+package security
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// the metadata key carrying the credentials to the master or an agent
+const authorizationKey = "authorization"
+
+// AuthorizationEnv passes the credentials of the driver to the executors,
+// to read and write the dataset shards on the agents.
+const AuthorizationEnv = "GLEAM_AUTHORIZATION"
+
+// the credentials sent to the agents by this process
+var agentCredentials *Credentials
+
+// Credentials identifies a user to the master, either by a token,
+// or by a username and password.
+type Credentials struct {
+	Token    string
+	Username string
+	Password string
+}
+
+func (c *Credentials) IsEmpty() bool {
+	return c == nil || (c.Token == "" && c.Username == "")
+}
+
+// GetRequestMetadata implements grpc credentials.PerRPCCredentials.
+func (c *Credentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{authorizationKey: c.Authorization()}, nil
+}
+
+// RequireTransportSecurity implements grpc credentials.PerRPCCredentials.
+// The credentials are never sent in plain text, so they require TLS.
+func (c *Credentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// Authorization returns the value of an "Authorization" header, or "" for empty credentials.
+func (c *Credentials) Authorization() string {
+	if c.IsEmpty() {
+		return ""
+	}
+	if c.Token != "" {
+		return "Bearer " + c.Token
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password))
+}
+
+// GrpcDialOptions returns the options for grpc.Dial to the master or an agent,
+// sending the credentials with each call if not empty.
+func GrpcDialOptions(creds *Credentials) []grpc.DialOption {
+	options := []grpc.DialOption{GrpcDialOption()}
+	if !creds.IsEmpty() {
+		options = append(options, grpc.WithPerRPCCredentials(creds))
+	}
+	return options
+}
+
+// SetAgentCredentials sets the credentials for the agent rpcs and
+// the dataset shard transfers made by this process.
+func SetAgentCredentials(creds *Credentials) {
+	agentCredentials = creds
+}
+
+// AgentCredentials returns the credentials to call the agents, nil if not set.
+func AgentCredentials() *Credentials {
+	return agentCredentials
+}
+
+// AuthorizationFromContext returns the "Authorization" value sent from the grpc client.
+func AuthorizationFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[authorizationKey]) == 0 {
+		return ""
+	}
+	return md[authorizationKey][0]
+}
+
+// ParseAuthorization parses the value of an "Authorization" header
+// in the form of "Bearer <token>" or "Basic <base64 of username:password>".
+func ParseAuthorization(value string) (*Credentials, error) {
+	parts := strings.SplitN(value, " ", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("unknown authorization format")
+	}
+	switch strings.ToLower(parts[0]) {
+	case "bearer":
+		return &Credentials{Token: strings.TrimSpace(parts[1])}, nil
+	case "basic":
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode basic authorization: %v", err)
+		}
+		userPassword := strings.SplitN(string(data), ":", 2)
+		if len(userPassword) != 2 {
+			return nil, fmt.Errorf("basic authorization should be username:password")
+		}
+		return &Credentials{Username: userPassword[0], Password: userPassword[1]}, nil
+	}
+	return nil, fmt.Errorf("unknown authorization scheme %s", parts[0])
+}
+
+// CredentialsFromContext reads the credentials sent from the grpc client.
+func CredentialsFromContext(ctx context.Context) (*Credentials, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[authorizationKey]) == 0 {
+		return nil, fmt.Errorf("missing credentials")
+	}
+	return ParseAuthorization(md[authorizationKey][0])
+}
+
+// CredentialsFromRequest reads the credentials from the http request.
+func CredentialsFromRequest(r *http.Request) (*Credentials, error) {
+	value := r.Header.Get("Authorization")
+	if value == "" {
+		return nil, fmt.Errorf("missing credentials")
+	}
+	return ParseAuthorization(value)
+}
@@ -0,0 +1,114 @@
+// Package security enables mutual TLS among gleam master, agents, executors
+// and drivers, for both the gRPC services and the raw shard transfer protocol,
+// and authenticates the users of the master and the agents.
+package security
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type TLSOption struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+var (
+	serverConfig *tls.Config
+	clientConfig *tls.Config
+)
+
+// IsEnabled returns true if any of the certificate files is set.
+func (o *TLSOption) IsEnabled() bool {
+	return o != nil && (o.CertFile != "" || o.KeyFile != "" || o.CAFile != "")
+}
+
+// Args returns the command line flags to pass the same option to a child "gleam" process.
+func (o *TLSOption) Args() []string {
+	if !o.IsEnabled() {
+		return nil
+	}
+	return []string{
+		"--tls.cert", o.CertFile,
+		"--tls.key", o.KeyFile,
+		"--tls.ca", o.CAFile,
+	}
+}
+
+// EnableTLS loads the certificates. Afterwards all gleam connections
+// made or accepted by this process require mutual TLS, with
+// the peer certificates signed by the CA.
+func EnableTLS(option *TLSOption) error {
+	if !option.IsEnabled() {
+		return nil
+	}
+	if option.CertFile == "" || option.KeyFile == "" || option.CAFile == "" {
+		return fmt.Errorf("mutual TLS requires all of the certificate, key and CA files")
+	}
+
+	cert, err := tls.LoadX509KeyPair(option.CertFile, option.KeyFile)
+	if err != nil {
+		return fmt.Errorf("Failed to load key pair %s %s: %v", option.CertFile, option.KeyFile, err)
+	}
+	caData, err := ioutil.ReadFile(option.CAFile)
+	if err != nil {
+		return fmt.Errorf("Failed to read CA file %s: %v", option.CAFile, err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caData) {
+		return fmt.Errorf("No certificates found in CA file %s", option.CAFile)
+	}
+
+	serverConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		// gRPC clients only offer "h2", while the http handlers
+		// behind cmux can only serve "http/1.1"
+		NextProtos: []string{"http/1.1", "h2"},
+		MinVersion: tls.VersionTLS12,
+	}
+	clientConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	return nil
+}
+
+func IsTLSEnabled() bool {
+	return serverConfig != nil
+}
+
+// NewListener wraps the listener to accept only mutual TLS connections if TLS is enabled.
+func NewListener(listener net.Listener) net.Listener {
+	if serverConfig == nil {
+		return listener
+	}
+	return tls.NewListener(listener, serverConfig)
+}
+
+// Dial connects to the tcp address, with mutual TLS if TLS is enabled.
+func Dial(address string) (net.Conn, error) {
+	dialer := &net.Dialer{KeepAlive: 30 * time.Second}
+	if clientConfig == nil {
+		return dialer.Dial("tcp", address)
+	}
+	return tls.DialWithDialer(dialer, "tcp", address, clientConfig)
+}
+
+// GrpcDialOption returns the transport option for grpc.Dial.
+func GrpcDialOption() grpc.DialOption {
+	if clientConfig == nil {
+		return grpc.WithInsecure()
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(clientConfig))
+}
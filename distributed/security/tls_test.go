@@ -0,0 +1,157 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertificates writes a CA, and a certificate for 127.0.0.1 signed by it.
+func writeTestCertificates(t *testing.T, dir string) *TLSOption {
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	writePem := func(name, blockType string, data []byte) string {
+		fileName := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(fileName, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return fileName
+	}
+
+	caKey := newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gleam test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caData, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := newKey()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "gleam"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	certData, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyData, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &TLSOption{
+		CertFile: writePem("cert.pem", "CERTIFICATE", certData),
+		KeyFile:  writePem("key.pem", "EC PRIVATE KEY", keyData),
+		CAFile:   writePem("ca.pem", "CERTIFICATE", caData),
+	}
+}
+
+func enableTestTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gleam-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := EnableTLS(writeTestCertificates(t, dir)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func disableTLS() {
+	serverConfig, clientConfig = nil, nil
+}
+
+func TestEnableTLSRequiresAllFiles(t *testing.T) {
+	defer disableTLS()
+
+	if err := EnableTLS(&TLSOption{}); err != nil || IsTLSEnabled() {
+		t.Errorf("expecting TLS disabled without the files, got %v", err)
+	}
+	if err := EnableTLS(&TLSOption{CertFile: "cert.pem", KeyFile: "key.pem"}); err == nil {
+		t.Errorf("expecting an error without the CA file")
+	}
+	if err := EnableTLS(&TLSOption{CertFile: "missing.pem", KeyFile: "missing.pem", CAFile: "missing.pem"}); err == nil {
+		t.Errorf("expecting an error with missing files")
+	}
+	if IsTLSEnabled() {
+		t.Errorf("TLS is enabled after the errors")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	enableTestTLS(t)
+	defer disableTLS()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener = NewListener(listener)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 5)
+				if _, err := conn.Read(buf); err == nil {
+					conn.Write(buf)
+				}
+			}()
+		}
+	}()
+
+	conn, err := Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("hello"))
+	buf := make([]byte, 5)
+	if _, err := conn.Read(buf); err != nil || string(buf) != "hello" {
+		t.Errorf("read %q %v over mutual TLS", buf, err)
+	}
+
+	// a client without a certificate is rejected
+	plain, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+	plain.Write([]byte("hello"))
+	plain.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, err := plain.Read(buf); err == nil && string(buf[:n]) == "hello" {
+		t.Errorf("a plain connection is accepted")
+	}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleam/flow"
+)
+
+// TestConcurrentLocalRuns runs flows at the same time on the local runner,
+// each with its own broadcast and stat folders.
+func TestConcurrentLocalRuns(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var data, expected []string
+			for j := 0; j < 100; j++ {
+				line := fmt.Sprintf("run%d-%03d", i, j)
+				data = append(data, line)
+				// the lines come back from the pipe as bytes
+				expected = append(expected, fmt.Sprint([]interface{}{[]byte(line)}, []interface{}(nil)))
+			}
+
+			f := flow.New(fmt.Sprintf("run%d", i))
+			f.BroadcastValue("run", i)
+			rows := collectRows(f.Strings(data).Pipe("sort", "sort"))
+			f.Run()
+
+			sort.Strings(*rows)
+			if !reflect.DeepEqual(*rows, expected) {
+				t.Errorf("run %d: %d rows, expected %d rows of its own", i, len(*rows), len(expected))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/chrislusf/gleam/flow"
+)
+
+func TestSemiAntiAndFullOuterJoins(t *testing.T) {
+	// keys k0 to k9 on the left and k5 to k14 on the right, each twice
+	var left, right [][]interface{}
+	for i := 0; i < 20; i++ {
+		left = append(left, []interface{}{fmt.Sprintf("k%d", i%10), fmt.Sprintf("l%d", i)})
+		right = append(right, []interface{}{fmt.Sprintf("k%d", 5+i%10), fmt.Sprintf("r%d", i)})
+	}
+
+	var semi, anti, full []string
+	rightValues := make(map[string][]string)
+	for _, r := range right {
+		rightValues[r[0].(string)] = append(rightValues[r[0].(string)], r[1].(string))
+	}
+	leftKeys := make(map[string]bool)
+	for _, l := range left {
+		key, value := l[0].(string), l[1].(string)
+		leftKeys[key] = true
+		if len(rightValues[key]) == 0 {
+			anti = append(anti, fmt.Sprint([]interface{}{key}, []interface{}{value}))
+			full = append(full, fmt.Sprint([]interface{}{key}, []interface{}{value}))
+			continue
+		}
+		semi = append(semi, fmt.Sprint([]interface{}{key}, []interface{}{value}))
+		for _, r := range rightValues[key] {
+			full = append(full, fmt.Sprint([]interface{}{key}, []interface{}{value, r}))
+		}
+	}
+	for _, r := range right {
+		if !leftKeys[r[0].(string)] {
+			full = append(full, fmt.Sprint([]interface{}{r[0]}, []interface{}{r[1]}))
+		}
+	}
+
+	for _, c := range []struct {
+		name     string
+		join     func(l, r *flow.Dataset) *flow.Dataset
+		expected []string
+	}{
+		{"left semi", func(l, r *flow.Dataset) *flow.Dataset { return l.LeftSemiJoinByKey("semi", r) }, semi},
+		{"left anti", func(l, r *flow.Dataset) *flow.Dataset { return l.LeftAntiJoinByKey("anti", r) }, anti},
+		{"full outer", func(l, r *flow.Dataset) *flow.Dataset { return l.FullOuterJoinByKey("full", r) }, full},
+	} {
+		f := flow.New(c.name)
+		l := rowsSource(f, "left", left).RoundRobin("left", 3)
+		r := rowsSource(f, "right", right).RoundRobin("right", 2)
+		rows := collectRows(c.join(l, r))
+		f.Run()
+
+		sort.Strings(*rows)
+		sort.Strings(c.expected)
+		if !reflect.DeepEqual(*rows, c.expected) {
+			t.Errorf("%s join:\n got %v\nwant %v", c.name, *rows, c.expected)
+		}
+	}
+}
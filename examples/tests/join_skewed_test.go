@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleam/flow"
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func TestSkewedJoin(t *testing.T) {
+	for _, c := range []struct {
+		name                string
+		leftOuter, rightOut bool
+	}{
+		{"inner", false, false},
+		{"left outer", true, false},
+		{"right outer", false, true},
+	} {
+		expected := joinRows(false, c.leftOuter, c.rightOut)
+		actual := joinRows(true, c.leftOuter, c.rightOut)
+		if len(expected) == 0 {
+			t.Fatalf("%s join: no rows", c.name)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s join: skewed join has %d rows, expected %d rows", c.name, len(actual), len(expected))
+		}
+	}
+}
+
+// joinRows joins a dataset with one hot key to a small dataset.
+func joinRows(isSkewed, isLeftOuter, isRightOuter bool) []string {
+	var big, small [][]interface{}
+	for i := 0; i < 6000; i++ {
+		key := "hot"
+		if i%3 == 0 {
+			key = fmt.Sprintf("k%d", i%50)
+		}
+		big = append(big, []interface{}{key, i})
+	}
+	small = append(small,
+		[]interface{}{"hot", "h1"},
+		[]interface{}{"hot", "h2"},
+		[]interface{}{"k3", "x"},
+		[]interface{}{"missing", "y"},
+	)
+
+	f := flow.New("skewedJoin")
+	left := rowsSource(f, "big", big).RoundRobin("big", 4)
+	right := rowsSource(f, "small", small).RoundRobin("small", 3)
+	if isSkewed {
+		left.Hint(flow.Skewed())
+	}
+
+	var joined *flow.Dataset
+	switch {
+	case isLeftOuter:
+		joined = left.LeftOuterJoin("join", right, flow.Field(1))
+	case isRightOuter:
+		joined = left.RightOuterJoin("join", right, flow.Field(1))
+	default:
+		joined = left.Join("join", right, flow.Field(1))
+	}
+	rows := collectRows(joined)
+	f.Run()
+
+	sort.Strings(*rows)
+	return *rows
+}
+
+func rowsSource(f *flow.Flow, name string, rows [][]interface{}) *flow.Dataset {
+	return f.Source(name, func(writer io.Writer, stats *pb.InstructionStat) error {
+		for _, row := range rows {
+			if err := util.NewRow(util.Now(), row...).WriteTo(writer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// collectRows formats the keys and the non nil values of each row.
+// The outer joins leave out the nils if the other side of a partition is empty.
+func collectRows(d *flow.Dataset) *[]string {
+	var lock sync.Mutex
+	rows := &[]string{}
+	d.Output(func(reader io.Reader) error {
+		return util.ProcessRow(reader, nil, func(row *util.Row) error {
+			var values []interface{}
+			for _, v := range row.V {
+				if v != nil {
+					values = append(values, v)
+				}
+			}
+			lock.Lock()
+			*rows = append(*rows, fmt.Sprint(row.K, values))
+			lock.Unlock()
+			return nil
+		})
+	})
+	return rows
+}
@@ -0,0 +1,13 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/chrislusf/gleam/gio"
+)
+
+func TestMain(m *testing.M) {
+	gio.Init()
+	os.Exit(m.Run())
+}
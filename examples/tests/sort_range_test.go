@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleam/flow"
+	"github.com/chrislusf/gleam/util"
+)
+
+func TestRangeSort(t *testing.T) {
+	var rows [][]interface{}
+	for i := 0; i < 5000; i++ {
+		rows = append(rows, []interface{}{rand.Intn(100000), i})
+	}
+
+	f := flow.New("rangeSort")
+	var lock sync.Mutex
+	var partitions [][]int64
+	rowsSource(f, "numbers", rows).RoundRobin("numbers", 3).
+		RangeSort("sort", 4, flow.OrderBy(1, false)).
+		Output(func(reader io.Reader) error {
+			var partition []int64
+			err := util.ProcessRow(reader, nil, func(row *util.Row) error {
+				partition = append(partition, util.ToInt64(row.K[0]))
+				return nil
+			})
+			lock.Lock()
+			partitions = append(partitions, partition)
+			lock.Unlock()
+			return err
+		})
+	f.Run()
+
+	// the partitions are read in parallel, so order them by their first keys
+	sort.Slice(partitions, func(a, b int) bool {
+		return len(partitions[b]) == 0 || len(partitions[a]) > 0 && partitions[a][0] > partitions[b][0]
+	})
+
+	count := 0
+	for i, partition := range partitions {
+		count += len(partition)
+		if len(partition) < len(rows)/4/2 {
+			t.Errorf("partition %d has only %d rows", i, len(partition))
+		}
+		if !sort.SliceIsSorted(partition, func(a, b int) bool { return partition[a] > partition[b] }) {
+			t.Errorf("partition %d is not sorted", i)
+		}
+		if i > 0 && len(partition) > 0 && len(partitions[i-1]) > 0 {
+			if previous := partitions[i-1]; previous[len(previous)-1] < partition[0] {
+				t.Errorf("partition %d starts with %d, after %d of partition %d", i, partition[0], previous[len(previous)-1], i-1)
+			}
+		}
+	}
+	if count != len(rows) {
+		t.Errorf("sorted %d rows, expected %d", count, len(rows))
+	}
+}
@@ -5,6 +5,7 @@ package filesystem
 import (
 	"fmt"
 	"io"
+	"log"
 )
 
 type OptionName string
@@ -31,6 +32,16 @@ type VirtualFileSystem interface {
 	IsDir(*FileLocation) bool
 }
 
+// HostLocator is implemented by the file systems knowing which hosts store the files.
+type HostLocator interface {
+	Hosts(*FileLocation) ([]string, error)
+}
+
+// SizeGetter is implemented by the file systems knowing the file sizes without reading the files.
+type SizeGetter interface {
+	Size(*FileLocation) (int64, error)
+}
+
 var (
 	fileSystems = []VirtualFileSystem{
 		&LocalFileSystem{},
@@ -72,3 +83,44 @@ func IsDir(filepath string) bool {
 	}
 	return false
 }
+
+// Hosts returns the hosts storing the file, to read the file locally.
+// It returns nil if the hosts are unknown.
+func Hosts(filepath string) []string {
+	fileLocation := &FileLocation{filepath}
+	for _, fs := range fileSystems {
+		if fs.Accept(fileLocation) {
+			locator, ok := fs.(HostLocator)
+			if !ok {
+				return nil
+			}
+			hosts, err := locator.Hosts(fileLocation)
+			if err != nil {
+				log.Printf("Failed to locate file %s: %v", filepath, err)
+			}
+			return hosts
+		}
+	}
+	return nil
+}
+
+// Size returns the size of the file in bytes.
+// It returns -1 if the size is unknown.
+func Size(filepath string) int64 {
+	fileLocation := &FileLocation{filepath}
+	for _, fs := range fileSystems {
+		if fs.Accept(fileLocation) {
+			getter, ok := fs.(SizeGetter)
+			if !ok {
+				return -1
+			}
+			size, err := getter.Size(fileLocation)
+			if err != nil {
+				log.Printf("Failed to get the size of file %s: %v", filepath, err)
+				return -1
+			}
+			return size
+		}
+	}
+	return -1
+}
@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/user"
 	"strings"
+	"sync"
 
 	"github.com/colinmarc/hdfs"
+	"github.com/colinmarc/hdfs/protocol/hadoop_hdfs"
+	"github.com/colinmarc/hdfs/rpc"
+	"github.com/golang/protobuf/proto"
 )
 
 /*
@@ -28,13 +33,10 @@ func (fs *HdfsFileSystem) Open(fl *FileLocation) (VirtualFile, error) {
 	if err != nil {
 		return nil, err
 	}
-	if namenode == "" {
-		namenode = os.Getenv("HADOOP_NAMENODE")
-	}
 
-	client, err := hdfs.New(namenode)
+	client, err := getHdfsClient(namenode)
 	if err != nil {
-		log.Fatalf("failed to create client to %s:%v\n", namenode, err)
+		return nil, err
 	}
 
 	file, err := client.Open(path)
@@ -50,9 +52,9 @@ func (fs *HdfsFileSystem) List(fl *FileLocation) (fileLocations []*FileLocation,
 		return
 	}
 
-	client, err := hdfs.New(namenode)
+	client, err := getHdfsClient(namenode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client to %s:%v\n", namenode, err)
+		return nil, err
 	}
 
 	fileInfos, err := client.ReadDir("/" + path)
@@ -70,25 +72,137 @@ func (fs *HdfsFileSystem) List(fl *FileLocation) (fileLocations []*FileLocation,
 func (fs *HdfsFileSystem) IsDir(fl *FileLocation) bool {
 	namenode, path, err := splitLocationToParts(fl.Location)
 	if err != nil {
-		log.Fatalf("failed to create client to %s:%v\n", namenode, err)
+		log.Println(err)
 		return false
 	}
 
-	client, err := hdfs.New(namenode)
+	client, err := getHdfsClient(namenode)
 	if err != nil {
-		log.Fatalf("failed to create client to %s:%v\n", namenode, err)
+		log.Println(err)
+		return false
 	}
 
-	file, err := client.Open(path)
+	fileInfo, err := client.Stat(path)
+	if err != nil {
+		log.Printf("failed to stat file %s:%v\n", fl.Location, err)
+		return false
+	}
+
+	return fileInfo.IsDir()
+}
+
+// Size returns the size of the file from the namenode.
+func (fs *HdfsFileSystem) Size(fl *FileLocation) (int64, error) {
+	namenode, path, err := splitLocationToParts(fl.Location)
 	if err != nil {
-		log.Fatalf("failed to open file %s:%v\n", fl.Location, err)
+		return 0, err
 	}
 
-	defer file.Close()
+	client, err := getHdfsClient(namenode)
+	if err != nil {
+		return 0, err
+	}
+	fileInfo, err := client.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return fileInfo.Size(), nil
+}
 
-	fi := file.Stat()
+// Hosts lists the data nodes storing the blocks of the file.
+func (fs *HdfsFileSystem) Hosts(fl *FileLocation) ([]string, error) {
+	namenode, path, err := splitLocationToParts(fl.Location)
+	if err != nil {
+		return nil, err
+	}
 
-	return fi.IsDir()
+	client, err := getHdfsClient(namenode)
+	if err != nil {
+		return nil, err
+	}
+	fileInfo, err := client.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := getNamenodeConnection(namenode)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &hadoop_hdfs.GetBlockLocationsRequestProto{
+		Src:    proto.String(path),
+		Offset: proto.Uint64(0),
+		Length: proto.Uint64(uint64(fileInfo.Size())),
+	}
+	response := &hadoop_hdfs.GetBlockLocationsResponseProto{}
+	if err = connection.Execute("getBlockLocations", request, response); err != nil {
+		return nil, fmt.Errorf("failed to get block locations of %s:%v", path, err)
+	}
+
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, block := range response.GetLocations().GetBlocks() {
+		for _, datanode := range block.GetLocs() {
+			host := datanode.GetId().GetHostName()
+			if host != "" && !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts, nil
+}
+
+var (
+	hdfsClients         = make(map[string]*hdfs.Client)
+	namenodeConnections = make(map[string]*rpc.NamenodeConnection)
+	hdfsClientsLock     sync.Mutex
+)
+
+// getHdfsClient returns the client to the namenode, shared by all the files on it.
+func getHdfsClient(namenode string) (*hdfs.Client, error) {
+	if namenode == "" {
+		namenode = os.Getenv("HADOOP_NAMENODE")
+	}
+
+	hdfsClientsLock.Lock()
+	defer hdfsClientsLock.Unlock()
+
+	if client, found := hdfsClients[namenode]; found {
+		return client, nil
+	}
+	client, err := hdfs.New(namenode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client to %s:%v", namenode, err)
+	}
+	hdfsClients[namenode] = client
+	return client, nil
+}
+
+// getNamenodeConnection returns the rpc connection to the namenode, for the
+// requests the client does not expose, shared by all the files on it.
+func getNamenodeConnection(namenode string) (*rpc.NamenodeConnection, error) {
+	if namenode == "" {
+		namenode = os.Getenv("HADOOP_NAMENODE")
+	}
+
+	hdfsClientsLock.Lock()
+	defer hdfsClientsLock.Unlock()
+
+	if connection, found := namenodeConnections[namenode]; found {
+		return connection, nil
+	}
+	currentUser, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	connection, err := rpc.NewNamenodeConnection(namenode, currentUser.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s:%v", namenode, err)
+	}
+	namenodeConnections[namenode] = connection
+	return connection, nil
 }
 
 func splitLocationToParts(location string) (namenode, path string, err error) {
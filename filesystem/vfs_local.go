@@ -7,20 +7,36 @@ import (
 	"strings"
 )
 
+// LocalFileSystem reads the local files. A file on one agent can be given as
+// "file://<agent host>/<path>", so the file is read on that agent.
 type LocalFileSystem struct {
 }
 
+// splitLocalLocation returns the agent host given in a "file://" location, and the local path.
+func splitLocalLocation(fl *FileLocation) (host, path string) {
+	if !strings.HasPrefix(fl.Location, "file://") {
+		return "", fl.Location
+	}
+	location := strings.TrimPrefix(fl.Location, "file://")
+	if slash := strings.Index(location, "/"); slash >= 0 {
+		return location[:slash], location[slash:]
+	}
+	return location, "/"
+}
+
 func (fs *LocalFileSystem) Accept(fl *FileLocation) bool {
 	return !strings.HasPrefix(fl.Location, "hdfs://") && !strings.HasPrefix(fl.Location, "s3://")
 }
 
 func (fs *LocalFileSystem) Open(fl *FileLocation) (VirtualFile, error) {
-	osFile, err := os.Open(fl.Location)
+	_, path := splitLocalLocation(fl)
+	osFile, err := os.Open(path)
 	return &VirtualFileLocal{osFile}, err
 }
 
 func (fs *LocalFileSystem) List(fl *FileLocation) (fileLocations []*FileLocation, err error) {
-	files, err := ioutil.ReadDir(fl.Location)
+	_, path := splitLocalLocation(fl)
+	files, err := ioutil.ReadDir(path)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +48,8 @@ func (fs *LocalFileSystem) List(fl *FileLocation) (fileLocations []*FileLocation
 }
 
 func (fs *LocalFileSystem) IsDir(fl *FileLocation) bool {
-	f, err := os.Open(fl.Location)
+	_, path := splitLocalLocation(fl)
+	f, err := os.Open(path)
 	if err != nil {
 		log.Println(err)
 		return false
@@ -52,6 +69,27 @@ func (fs *LocalFileSystem) IsDir(fl *FileLocation) bool {
 	return false
 }
 
+// Hosts returns the agent host given as "file://<agent host>/<path>".
+// Otherwise it returns no hosts. The local files are on the host building
+// the flow, which is not necessarily an agent host, and the local paths may
+// exist on every agent, as on a shared mount.
+func (fs *LocalFileSystem) Hosts(fl *FileLocation) ([]string, error) {
+	if host, _ := splitLocalLocation(fl); host != "" {
+		return []string{host}, nil
+	}
+	return nil, nil
+}
+
+// Size returns the size of the file.
+func (fs *LocalFileSystem) Size(fl *FileLocation) (int64, error) {
+	_, path := splitLocalLocation(fl)
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return fileInfo.Size(), nil
+}
+
 type VirtualFileLocal struct {
 	*os.File
 }
@@ -0,0 +1,56 @@
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLocalFileHosts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "a.txt")
+	if err := ioutil.WriteFile(fileName, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		location string
+		hosts    []string
+	}{
+		{fileName, nil},
+		{"file://" + fileName, nil},
+		{"file://agent1" + fileName, []string{"agent1"}},
+	}
+	for _, test := range tests {
+		if hosts := Hosts(test.location); !reflect.DeepEqual(hosts, test.hosts) {
+			t.Errorf("%s: hosts %v, expecting %v", test.location, hosts, test.hosts)
+		}
+		if size := Size(test.location); size != 5 {
+			t.Errorf("%s: size %d, expecting 5", test.location, size)
+		}
+		f, err := Open(test.location)
+		if err != nil {
+			t.Errorf("%s: %v", test.location, err)
+			continue
+		}
+		data, err := ioutil.ReadAll(f)
+		f.Close()
+		if string(data) != "hello" {
+			t.Errorf("%s: read %q %v", test.location, data, err)
+		}
+	}
+
+	files, err := List("file://agent1" + dir)
+	if err != nil || len(files) != 1 || files[0].Location != "file://agent1"+fileName {
+		t.Errorf("listed %v %v, expecting file://agent1%s", files, err, fileName)
+	}
+	if !IsDir("file://agent1" + dir) {
+		t.Errorf("file://agent1%s is not a directory", dir)
+	}
+}
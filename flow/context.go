@@ -33,6 +33,8 @@ func (fc *Flow) RunContext(ctx context.Context, options ...FlowOption) {
 		os.Exit(1)
 	}
 
+	fc.optimize()
+
 	if len(options) == 0 {
 		Local.RunFlowContext(ctx, fc)
 	} else {
@@ -163,6 +165,30 @@ func (f *Flow) AddLinkedNToOneStep(input *Dataset, m int, output *Dataset) (step
 	return
 }
 
+// Same as AddOneToEveryNStep, but each task also reads the same shard of the other inputs.
+// All input datasets should have the same number of shards.
+func (f *Flow) AddMergedOneToEveryNStep(inputs []*Dataset, n int, output *Dataset) (step *Step) {
+	step = f.NewStep()
+	step.NetworkType = OneShardToEveryNShard
+	fromStepToDataset(step, output)
+	for _, input := range inputs {
+		fromDatasetToStep(input, step)
+	}
+
+	// setup the network
+	m := len(inputs[0].GetShards())
+	for i := 0; i < m; i++ {
+		task := step.NewTask()
+		for k := 0; k < n; k++ {
+			fromTaskToDatasetShard(task, output.GetShards()[k*m+i])
+		}
+		for _, input := range inputs {
+			fromDatasetShardToTask(input.GetShards()[i], task)
+		}
+	}
+	return
+}
+
 // All dataset should have the same number of shards.
 func (f *Flow) MergeDatasets1ShardTo1Step(inputs []*Dataset, output *Dataset) (step *Step) {
 	step = f.NewStep()
@@ -0,0 +1,42 @@
+package flow
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/chrislusf/gleam/gio"
+	"github.com/chrislusf/gleam/util"
+)
+
+// BroadcastValue serializes the value once, and ships it to all
+// pure Go mappers and reducers, which can read it via gio.GetBroadcast(name).
+// The value is encoded with msgpack the same way as the fields of a row,
+// so only nil, bool, numbers, strings, []byte, and slices and maps of them
+// come back intact. Structs come back as maps or slices, and should be
+// encoded by the caller, e.g., to JSON bytes.
+func (fc *Flow) BroadcastValue(name string, value interface{}) *Flow {
+	var buf bytes.Buffer
+	if err := util.NewRow(util.Now(), value).WriteTo(&buf); err != nil {
+		log.Fatalf("Failed to encode broadcast value %s: %v", name, err)
+	}
+	if fc.BroadcastValues == nil {
+		fc.BroadcastValues = make(map[string][]byte)
+	}
+	fc.BroadcastValues[name] = buf.Bytes()
+	return fc
+}
+
+// SaveBroadcastValues writes the broadcast values into the folder,
+// and returns the written file paths.
+func (fc *Flow) SaveBroadcastValues(dir string) (files []string, err error) {
+	for name, data := range fc.BroadcastValues {
+		file := filepath.Join(dir, gio.BroadcastFileName(name))
+		if err = ioutil.WriteFile(file, data, 0644); err != nil {
+			return
+		}
+		files = append(files, file)
+	}
+	return
+}
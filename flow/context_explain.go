@@ -0,0 +1,65 @@
+package flow
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/gleam/instruction"
+)
+
+// Explain optimizes the flow, unless hinted with NoOptimize, and prints out
+// the rewrites and the steps to run, with the datasets each step reads and writes.
+func (fc *Flow) Explain() string {
+	fc.optimize()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "flow %s\n", fc.Name)
+	if len(fc.rewrites) > 0 {
+		fmt.Fprintf(&buf, "rewrites:\n")
+		for _, rewrite := range fc.rewrites {
+			fmt.Fprintf(&buf, "  %s\n", rewrite)
+		}
+	}
+	fmt.Fprintf(&buf, "steps:\n")
+	for _, step := range fc.Steps {
+		fmt.Fprintf(&buf, "  %d %s %s", step.Id, step.Name, step.NetworkType)
+		if step.IsOnDriverSide {
+			fmt.Fprintf(&buf, " on driver")
+		}
+		fmt.Fprintf(&buf, "\n")
+		for _, input := range step.InputDatasets {
+			fmt.Fprintf(&buf, "    input : %s\n", datasetString(input))
+		}
+		if step.OutputDataset != nil {
+			fmt.Fprintf(&buf, "    output: %s\n", datasetString(step.OutputDataset))
+		}
+	}
+	return buf.String()
+}
+
+func datasetString(d *Dataset) string {
+	s := fmt.Sprintf("d%d shards:%d", d.Id, len(d.Shards))
+	if len(d.IsPartitionedBy) > 0 {
+		s += fmt.Sprintf(" partitioned by %v", d.IsPartitionedBy)
+	}
+	if len(d.IsLocalSorted) > 0 {
+		s += " sorted by " + orderBysString(d.IsLocalSorted)
+	}
+	if d.GetIsOnDiskIO() {
+		s += " on disk"
+	}
+	return s
+}
+
+func orderBysString(orderBys []instruction.OrderBy) string {
+	var fields []string
+	for _, orderBy := range orderBys {
+		if orderBy.Order == instruction.Descending {
+			fields = append(fields, fmt.Sprintf("%d desc", orderBy.Index))
+		} else {
+			fields = append(fields, fmt.Sprintf("%d", orderBy.Index))
+		}
+	}
+	return "[" + strings.Join(fields, " ") + "]"
+}
@@ -1,17 +1,87 @@
 package flow
 
+import (
+	"runtime"
+)
+
+// Auto lets the shard count be decided by the flow,
+// from the size of the input data. See Flow.AutoShardCount.
+// Since Auto is 0, a shard count of 0 no longer means no shards.
+const Auto = 0
+
+// DefaultShardSize is the shard size in MB for Auto shard counts, if not hinted.
+const DefaultShardSize = 128
+
 type FlowHintOption func(c *FlowConfig)
 
 type FlowConfig struct {
 	OnDisk bool
+	// the size in MB of each shard for Auto shard counts
+	ShardSize int64
+	// the number of shards that can be processed at the same time
+	Capacity int
+	// run the steps as added, without the optimizer rewriting them
+	NoOptimize bool
 }
 
 // Hint adds hints to the flow.
 func (d *Flow) Hint(options ...FlowHintOption) {
-	var config FlowConfig
 	for _, option := range options {
-		option(&config)
+		option(&d.config)
+	}
+}
+
+// ShardSize hints the size in MB of each shard for Auto shard counts.
+func ShardSize(n int64) FlowHintOption {
+	return func(c *FlowConfig) {
+		c.ShardSize = n
+	}
+}
+
+// Capacity hints the number of shards that can be processed at the same time,
+// usually the number of executors in the cluster.
+func Capacity(n int) FlowHintOption {
+	return func(c *FlowConfig) {
+		c.Capacity = n
+	}
+}
+
+// NoOptimize runs the steps as added, without the optimizer rewriting them.
+func NoOptimize() FlowHintOption {
+	return func(c *FlowConfig) {
+		c.NoOptimize = true
+	}
+}
+
+// AutoShardCount returns the shard count for the total size in MB,
+// so each shard has about the hinted ShardSize, but there are no more
+// shards than the hinted Capacity, which defaults to the number of CPUs.
+// The shards of the steps piped together need to be processed at the
+// same time. If the total size is unknown, the shard count is the capacity.
+func (d *Flow) AutoShardCount(totalSize int64) int {
+	shardSize, capacity := d.config.ShardSize, d.config.Capacity
+	if shardSize <= 0 {
+		shardSize = DefaultShardSize
+	}
+	if capacity <= 0 {
+		capacity = runtime.NumCPU()
+	}
+	if totalSize <= 0 {
+		return capacity
+	}
+	n := (totalSize + shardSize - 1) / shardSize
+	if n > int64(capacity) {
+		return capacity
+	}
+	return int(n)
+}
+
+// shardCount resolves the Auto shard count from the hinted total size of the dataset.
+func (d *Dataset) shardCount(n int) int {
+	if n != Auto {
+		return n
 	}
+	return d.Flow.AutoShardCount(d.GetTotalSize())
 }
 
 // GetTotalSize returns the total size in MB for the dataset.
@@ -0,0 +1,44 @@
+package flow
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestAutoShardCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		hints     []FlowHintOption
+		totalSize int64
+		expected  int
+	}{
+		{"default shard size", []FlowHintOption{Capacity(100)}, 3 * DefaultShardSize, 3},
+		{"partial shard is rounded up", []FlowHintOption{ShardSize(10), Capacity(100)}, 21, 3},
+		{"small data in one shard", []FlowHintOption{ShardSize(10), Capacity(100)}, 1, 1},
+		{"no more shards than the capacity", []FlowHintOption{ShardSize(10), Capacity(4)}, 1000, 4},
+		{"unknown size takes the capacity", []FlowHintOption{ShardSize(10), Capacity(4)}, -1, 4},
+		{"empty data takes the capacity", []FlowHintOption{ShardSize(10), Capacity(4)}, 0, 4},
+		{"capacity defaults to the CPUs", []FlowHintOption{ShardSize(1)}, 1 << 20, runtime.NumCPU()},
+	}
+	for _, test := range tests {
+		fc := New("test")
+		fc.Hint(test.hints...)
+		if n := fc.AutoShardCount(test.totalSize); n != test.expected {
+			t.Errorf("%s: %d shards, expecting %d", test.name, n, test.expected)
+		}
+	}
+}
+
+func TestAutoShardCountOfDataset(t *testing.T) {
+	fc := New("test")
+	fc.Hint(ShardSize(10), Capacity(8))
+	d := fc.Ints([]int{1, 2, 3})
+	d.Meta.TotalSize = 25
+
+	if p := d.Partition("partition", Auto, Field(1)); len(p.Shards) != 3 {
+		t.Errorf("partitioned into %d shards, expecting 3 for 25 MB", len(p.Shards))
+	}
+	if n := d.shardCount(5); n != 5 {
+		t.Errorf("%d shards, expecting the given shard count", n)
+	}
+}
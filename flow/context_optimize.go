@@ -0,0 +1,398 @@
+package flow
+
+import (
+	"fmt"
+
+	"github.com/chrislusf/gleam/instruction"
+	"github.com/chrislusf/gleam/util"
+)
+
+// Optimize rewrites the steps before the flow is planned. It pushes a Select
+// right after a source down into the source, merges adjacent Select steps and
+// adjacent LocalLimit steps, and removes the sorting and partitioning already
+// done by the previous steps. The partitioning and sorting of the datasets are
+// followed through Select and LocalLimit to find more of them.
+// Filters are Go mappers the optimizer can not look into, so they stay in place.
+// The steps and datasets kept have the same ids as before. It only runs again
+// if more steps are added.
+func (fc *Flow) Optimize() {
+	if fc.isOptimized {
+		return
+	}
+	for {
+		fc.deriveProperties()
+		if !fc.applyRule() {
+			break
+		}
+	}
+	fc.isOptimized = true
+}
+
+// optimize runs the optimizer unless the flow is hinted with NoOptimize.
+func (fc *Flow) optimize() {
+	if !fc.config.NoOptimize {
+		fc.Optimize()
+	}
+}
+
+// an optimizerRule rewrites the flow around the step, and describes the rewrite
+type optimizerRule func(fc *Flow, step *Step) (rewrite string, ok bool)
+
+var optimizerRules = []optimizerRule{
+	pushDownSelect,
+	mergeSelects,
+	mergeLocalLimits,
+	removeRedundantLocalSort,
+	removeRedundantPartition,
+}
+
+// applyRule rewrites around the first step a rule applies to,
+// since the steps change after a rewrite.
+func (fc *Flow) applyRule() bool {
+	for _, step := range fc.Steps {
+		for _, rule := range optimizerRules {
+			if rewrite, ok := rule(fc, step); ok {
+				fc.rewrites = append(fc.rewrites, rewrite)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pushDownSelect lets the source read only the selected fields.
+func pushDownSelect(fc *Flow, step *Step) (string, bool) {
+	sel, ok := step.Instruction.(*instruction.Select)
+	if !ok {
+		return "", false
+	}
+	input := step.InputDatasets[0]
+	if input.Meta.Projector == nil || !isOnlyReadBy(input, step) {
+		return "", false
+	}
+	keyIndexes, _ := sel.Indexes()
+	if len(keyIndexes) != 1 || !input.Meta.Projector.Project(selectedFields(sel)) {
+		return "", false
+	}
+	fc.bypass(input, step.OutputDataset)
+	return fmt.Sprintf("pushed %s down into %s", step.Name, input.Step.Name), true
+}
+
+// mergeSelects selects the fields of the second Select from the input of the first.
+func mergeSelects(fc *Flow, step *Step) (string, bool) {
+	second, ok := step.Instruction.(*instruction.Select)
+	if !ok {
+		return "", false
+	}
+	input := step.InputDatasets[0]
+	first, ok := input.Step.Instruction.(*instruction.Select)
+	if !ok || !isOnlyReadBy(input, step) || input.Step.IsOnDriverSide != step.IsOnDriverSide {
+		return "", false
+	}
+	fields := selectedFields(first)
+	keyIndexes, valueIndexes := second.Indexes()
+	keys, keysFound := pickFields(fields, keyIndexes)
+	values, valuesFound := pickFields(fields, valueIndexes)
+	if !keysFound || !valuesFound {
+		return "", false
+	}
+	rewrite := fmt.Sprintf("merged %s and %s", input.Step.Name, step.Name)
+	step.SetInstruction("", instruction.NewSelect(keys, values))
+	fc.bypass(input.Step.InputDatasets[0], input)
+	return rewrite + " into " + step.Name, true
+}
+
+// mergeLocalLimits takes the rows the second LocalLimit takes from the first one.
+func mergeLocalLimits(fc *Flow, step *Step) (string, bool) {
+	second, ok := step.Instruction.(*instruction.LocalLimit)
+	if !ok {
+		return "", false
+	}
+	input := step.InputDatasets[0]
+	first, ok := input.Step.Instruction.(*instruction.LocalLimit)
+	if !ok || !isOnlyReadBy(input, step) || input.Step.IsOnDriverSide != step.IsOnDriverSide {
+		return "", false
+	}
+	n1, offset1 := first.Limit()
+	n2, offset2 := second.Limit()
+	n := n1 - offset2
+	if n2 < n {
+		n = n2
+	}
+	if n < 0 {
+		n = 0
+	}
+	name := step.Name
+	step.SetInstruction("", instruction.NewLocalLimit(n, offset1+offset2))
+	step.Name = name
+	fc.bypass(input.Step.InputDatasets[0], input)
+	return fmt.Sprintf("merged %s and %s, taking %d rows after %d rows", input.Step.Name, step.Name, n, offset1+offset2), true
+}
+
+// removeRedundantLocalSort removes a LocalSort when the input is sorted already,
+// or when the output is sorted again, since the sorting is not stable.
+func removeRedundantLocalSort(fc *Flow, step *Step) (string, bool) {
+	if _, ok := step.Instruction.(*instruction.LocalSort); !ok {
+		return "", false
+	}
+	input, output := step.InputDatasets[0], step.OutputDataset
+	if len(output.IsLocalSorted) > 0 && isOrderByPrefix(output.IsLocalSorted, input.IsLocalSorted) {
+		fc.bypass(input, output)
+		return fmt.Sprintf("removed %s, already sorted by %s", step.Name, orderBysString(input.IsLocalSorted)), true
+	}
+	if len(output.ReadingSteps) == 1 && isOnlyReadBy(output, output.ReadingSteps[0]) {
+		next := output.ReadingSteps[0]
+		if _, ok := next.Instruction.(*instruction.LocalSort); ok && next.IsOnDriverSide == step.IsOnDriverSide {
+			fc.bypass(input, output)
+			return fmt.Sprintf("removed %s, sorted again by %s", step.Name, next.Name), true
+		}
+	}
+	return "", false
+}
+
+// removeRedundantPartition removes the scattering and collecting of a Partition
+// when the input is partitioned the same way already. The partition keys are
+// moved to the front of the rows, so only the partitions by the leading fields
+// keep the rows unchanged.
+func removeRedundantPartition(fc *Flow, step *Step) (string, bool) {
+	if _, ok := step.Instruction.(*instruction.ScatterPartitions); !ok {
+		return "", false
+	}
+	input, scattered := step.InputDatasets[0], step.OutputDataset
+	if len(scattered.ReadingSteps) != 1 {
+		return "", false
+	}
+	collect := scattered.ReadingSteps[0]
+	if _, ok := collect.Instruction.(*instruction.CollectPartitions); !ok {
+		return "", false
+	}
+	output := collect.OutputDataset
+	indexes := output.IsPartitionedBy
+	if len(input.Shards) != len(output.Shards) || !isLeadingIndexes(indexes) ||
+		!intArrayEquals(input.IsPartitionedBy, indexes) {
+		return "", false
+	}
+	fc.bypass(input, output)
+	return fmt.Sprintf("removed %s and %s, already partitioned by %v into %d shards",
+		step.Name, collect.Name, indexes, len(input.Shards)), true
+}
+
+// deriveProperties follows the partitioning and sorting of the datasets through
+// the steps keeping them but not setting them when added.
+func (fc *Flow) deriveProperties() {
+	for _, step := range fc.Steps {
+		output := step.OutputDataset
+		if output == nil || len(step.InputDatasets) != 1 || step.NetworkType != OneShardToOneShard {
+			continue
+		}
+		input := step.InputDatasets[0]
+		switch ins := step.Instruction.(type) {
+		case *instruction.Select:
+			fields := selectedFields(ins)
+			if output.IsPartitionedBy == nil && isLeadingIndexes(input.IsPartitionedBy) {
+				output.IsPartitionedBy = findFields(fields, input.IsPartitionedBy)
+			}
+			if output.IsLocalSorted == nil {
+				var indexes []int
+				for _, orderBy := range input.IsLocalSorted {
+					indexes = append(indexes, orderBy.Index)
+				}
+				positions := findFields(fields, indexes)
+				for i, position := range positions {
+					output.IsLocalSorted = append(output.IsLocalSorted, instruction.OrderBy{
+						Index: position,
+						Order: input.IsLocalSorted[i].Order,
+					})
+				}
+			}
+		case *instruction.LocalLimit:
+			if output.IsPartitionedBy == nil {
+				output.IsPartitionedBy = input.IsPartitionedBy
+			}
+			if output.IsLocalSorted == nil {
+				output.IsLocalSorted = input.IsLocalSorted
+			}
+		}
+	}
+}
+
+// bypass lets the steps reading the shards of the dataset "to" read the same
+// shards of the dataset "from", and removes the steps in between.
+func (fc *Flow) bypass(from, to *Dataset) {
+	for _, step := range to.ReadingSteps {
+		for i, input := range step.InputDatasets {
+			if input == to {
+				step.InputDatasets[i] = from
+			}
+		}
+		from.ReadingSteps = append(from.ReadingSteps, step)
+	}
+	to.ReadingSteps = nil
+
+	for i, shard := range to.Shards {
+		target := from.Shards[i]
+		for k, task := range shard.ReadingTasks {
+			for j, input := range task.InputShards {
+				if input == shard {
+					task.InputShards[j] = target
+				}
+			}
+			target.ReadingTasks = append(target.ReadingTasks, task)
+			target.OutgoingChans = append(target.OutgoingChans, shard.OutgoingChans[k])
+		}
+		shard.ReadingTasks, shard.OutgoingChans = nil, nil
+	}
+
+	if from.IsPartitionedBy == nil {
+		from.IsPartitionedBy = to.IsPartitionedBy
+	}
+	if from.IsLocalSorted == nil {
+		from.IsLocalSorted = to.IsLocalSorted
+	}
+	mergeHints(from.Meta, to.Meta)
+
+	// remove the steps not read any more
+	for d := to; d != from && len(d.ReadingSteps) == 0; {
+		step := d.Step
+		fc.removeDataset(d)
+		fc.removeStep(step)
+		for _, task := range step.Tasks {
+			for j, shard := range task.InputShards {
+				shard.removeReadingTask(task, task.InputChans[j])
+			}
+		}
+		for _, input := range step.InputDatasets {
+			input.removeReadingStep(step)
+		}
+		d = step.InputDatasets[0]
+	}
+}
+
+// mergeHints keeps the hints given to the removed dataset.
+func mergeHints(meta, removed *DasetsetMetadata) {
+	if meta.TotalSize < 0 {
+		meta.TotalSize = removed.TotalSize
+	}
+	if removed.OnDisk == ModeOnDisk {
+		meta.OnDisk = ModeOnDisk
+	}
+	meta.IsSkewed = meta.IsSkewed || removed.IsSkewed
+	if meta.DataCenter == "" {
+		meta.DataCenter = removed.DataCenter
+	}
+	if meta.Rack == "" {
+		meta.Rack = removed.Rack
+	}
+}
+
+func (fc *Flow) removeStep(step *Step) {
+	var steps []*Step
+	for _, s := range fc.Steps {
+		if s != step {
+			steps = append(steps, s)
+		}
+	}
+	fc.Steps = steps
+}
+
+func (fc *Flow) removeDataset(d *Dataset) {
+	var datasets []*Dataset
+	for _, t := range fc.Datasets {
+		if t != d {
+			datasets = append(datasets, t)
+		}
+	}
+	fc.Datasets = datasets
+}
+
+func (d *Dataset) removeReadingStep(step *Step) {
+	for i, s := range d.ReadingSteps {
+		if s == step {
+			d.ReadingSteps = append(d.ReadingSteps[:i], d.ReadingSteps[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *DatasetShard) removeReadingTask(task *Task, piper *util.Piper) {
+	for i, t := range s.ReadingTasks {
+		if t == task && s.OutgoingChans[i] == piper {
+			s.ReadingTasks = append(s.ReadingTasks[:i], s.ReadingTasks[i+1:]...)
+			s.OutgoingChans = append(s.OutgoingChans[:i], s.OutgoingChans[i+1:]...)
+			return
+		}
+	}
+}
+
+func isOnlyReadBy(d *Dataset, step *Step) bool {
+	if len(d.ReadingSteps) != 1 || d.ReadingSteps[0] != step {
+		return false
+	}
+	if step.NetworkType != OneShardToOneShard || len(step.InputDatasets) != 1 {
+		return false
+	}
+	for _, shard := range d.Shards {
+		if len(shard.ReadingTasks) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// selectedFields lists the fields of the input in the output of a Select
+func selectedFields(sel *instruction.Select) []int {
+	keyIndexes, valueIndexes := sel.Indexes()
+	var fields []int
+	fields = append(fields, keyIndexes...)
+	fields = append(fields, valueIndexes...)
+	return fields
+}
+
+// pickFields lists the fields of the input at the indexes of the output
+func pickFields(fields []int, indexes []int) ([]int, bool) {
+	var ret []int
+	for _, x := range indexes {
+		if x < 1 || x > len(fields) {
+			return nil, false
+		}
+		ret = append(ret, fields[x-1])
+	}
+	return ret, true
+}
+
+// findFields lists the indexes of the output having the fields of the input,
+// or nil if some field is not in the output
+func findFields(fields []int, indexes []int) []int {
+	var ret []int
+	for _, x := range indexes {
+		position := 0
+		for i, field := range fields {
+			if field == x {
+				position = i + 1
+				break
+			}
+		}
+		if position == 0 {
+			return nil
+		}
+		ret = append(ret, position)
+	}
+	return ret
+}
+
+// isLeadingIndexes checks the indexes are 1, 2, ..., n
+func isLeadingIndexes(indexes []int) bool {
+	for i, x := range indexes {
+		if x != i+1 {
+			return false
+		}
+	}
+	return len(indexes) > 0
+}
+
+func isOrderByPrefix(prefix []instruction.OrderBy, orderBys []instruction.OrderBy) bool {
+	if len(prefix) > len(orderBys) {
+		return false
+	}
+	return isOrderByEquals(prefix, orderBys[:len(prefix)])
+}
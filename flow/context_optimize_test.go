@@ -0,0 +1,185 @@
+package flow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/gleam/instruction"
+)
+
+type testProjector struct {
+	indexes []int
+}
+
+func (p *testProjector) Project(indexes []int) bool {
+	p.indexes = indexes
+	return true
+}
+
+// checkReads checks the step reads the dataset, shard by shard.
+func checkReads(t *testing.T, d *Dataset, step *Step) {
+	if step.InputDatasets[0] != d {
+		t.Errorf("step %d reads d%d, expecting d%d", step.Id, step.InputDatasets[0].Id, d.Id)
+	}
+	for i, task := range step.Tasks {
+		shard := d.Shards[i]
+		if task.InputShards[0] != shard {
+			t.Errorf("task %d of step %d reads another shard than %s", task.Id, step.Id, shard.Name())
+		}
+		if len(shard.ReadingTasks) != 1 || shard.ReadingTasks[0] != task || len(shard.OutgoingChans) != 1 {
+			t.Errorf("%s is read by %d tasks, expecting task %d of step %d", shard.Name(), len(shard.ReadingTasks), task.Id, step.Id)
+		}
+	}
+}
+
+func stepIds(fc *Flow) (ids []int) {
+	for _, step := range fc.Steps {
+		ids = append(ids, step.Id)
+	}
+	return ids
+}
+
+func TestPushDownSelect(t *testing.T) {
+	fc := New("test")
+	source := fc.Ints([]int{1, 2, 3})
+	projector := &testProjector{}
+	source.Meta.Projector = projector
+	limited := source.Select("select", Field(2, 1)).LocalLimit("limit", 1, 0)
+
+	fc.Optimize()
+
+	if !intArrayEquals(projector.indexes, []int{2, 1}) {
+		t.Errorf("projected %v, expecting [2 1]", projector.indexes)
+	}
+	if len(fc.Steps) != 2 {
+		t.Fatalf("%d steps left, expecting the source and the limit", len(fc.Steps))
+	}
+	checkReads(t, source, limited.Step)
+}
+
+func TestMergeLocalLimits(t *testing.T) {
+	for _, c := range []struct {
+		n1, offset1, n2, offset2 int
+		n, offset                int
+	}{
+		{10, 2, 5, 1, 5, 3},
+		{3, 0, 5, 1, 2, 1},
+		{1, 0, 5, 2, 0, 2},
+	} {
+		fc := New("test")
+		source := fc.Ints([]int{1, 2, 3})
+		limited := source.LocalLimit("first", c.n1, c.offset1).LocalLimit("second", c.n2, c.offset2)
+
+		fc.Optimize()
+
+		if len(fc.Steps) != 2 {
+			t.Fatalf("%d steps left, expecting the source and one limit", len(fc.Steps))
+		}
+		checkReads(t, source, limited.Step)
+		n, offset := limited.Step.Instruction.(*instruction.LocalLimit).Limit()
+		if n != c.n || offset != c.offset {
+			t.Errorf("LocalLimit(%d, %d) and LocalLimit(%d, %d) merged into LocalLimit(%d, %d), expecting LocalLimit(%d, %d)",
+				c.n1, c.offset1, c.n2, c.offset2, n, offset, c.n, c.offset)
+		}
+	}
+}
+
+func TestRemoveRedundantPartition(t *testing.T) {
+	countPartitions := func(fc *Flow) (count int) {
+		for _, step := range fc.Steps {
+			if _, ok := step.Instruction.(*instruction.ScatterPartitions); ok {
+				count++
+			}
+		}
+		return count
+	}
+
+	fc := New("test")
+	selected := fc.Ints([]int{1, 2, 3}).Partition("first", 2, Field(1)).Select("select", Field(1, 2))
+	limited := selected.Partition("second", 2, Field(1)).LocalLimit("limit", 1, 0)
+	fc.Optimize()
+	if count := countPartitions(fc); count != 1 {
+		t.Errorf("%d partitions left, expecting the second one removed", count)
+	}
+	checkReads(t, selected, limited.Step)
+
+	// the partition key is not in front any more
+	fc = New("test")
+	fc.Ints([]int{1, 2, 3}).Partition("first", 2, Field(1)).Select("select", Field(2, 1)).
+		Partition("second", 2, Field(1)).LocalLimit("limit", 1, 0)
+	fc.Optimize()
+	if count := countPartitions(fc); count != 2 {
+		t.Errorf("%d partitions left, expecting both kept", count)
+	}
+}
+
+func TestBypass(t *testing.T) {
+	fc := New("test")
+	source := fc.Ints([]int{1, 2, 3}).Partition("partition", 2, Field(1))
+	selected := source.Select("first", Field(1, 2)).Select("second", Field(1))
+	limited := selected.LocalLimit("limit", 1, 0)
+	sorted := selected.LocalSort("sort", Field(1))
+
+	fc.bypass(source, selected)
+
+	for _, step := range fc.Steps {
+		if _, ok := step.Instruction.(*instruction.Select); ok {
+			t.Errorf("step %d %s is not removed", step.Id, step.Name)
+		}
+	}
+	for _, d := range fc.Datasets {
+		if d == selected {
+			t.Errorf("d%d is not removed", d.Id)
+		}
+	}
+	if len(source.ReadingSteps) != 2 {
+		t.Errorf("d%d is read by %d steps, expecting 2", source.Id, len(source.ReadingSteps))
+	}
+	for _, step := range []*Step{limited.Step, sorted.Step} {
+		if step.InputDatasets[0] != source {
+			t.Errorf("step %d reads d%d, expecting d%d", step.Id, step.InputDatasets[0].Id, source.Id)
+		}
+		for i, task := range step.Tasks {
+			if task.InputShards[0] != source.Shards[i] {
+				t.Errorf("task %d of step %d reads another shard than %s", task.Id, step.Id, source.Shards[i].Name())
+			}
+		}
+	}
+	for _, shard := range source.Shards {
+		if len(shard.ReadingTasks) != 2 || len(shard.OutgoingChans) != 2 {
+			t.Errorf("%s is read by %d tasks, expecting 2", shard.Name(), len(shard.ReadingTasks))
+		}
+	}
+}
+
+func TestOptimizeKeepsIds(t *testing.T) {
+	fc := New("test")
+	limited := fc.Ints([]int{1, 2, 3}).LocalLimit("first", 2, 0).LocalLimit("second", 1, 0)
+
+	explained := fc.Explain()
+	if !intArrayEquals(stepIds(fc), []int{0, 2}) || limited.Id != 2 {
+		t.Errorf("step ids %v and d%d after the optimizer, expecting [0 2] and d2", stepIds(fc), limited.Id)
+	}
+	if again := fc.Explain(); again != explained {
+		t.Errorf("explained differently the second time:\n%s", again)
+	}
+
+	next := limited.LocalLimit("third", 1, 0)
+	if next.Step.Id != 3 || next.Id != 3 {
+		t.Errorf("step %d and d%d added, expecting ids after the last ones", next.Step.Id, next.Id)
+	}
+	fc.Optimize()
+	if !intArrayEquals(stepIds(fc), []int{0, 3}) {
+		t.Errorf("step ids %v after optimizing the added step, expecting [0 3]", stepIds(fc))
+	}
+}
+
+func TestNoOptimize(t *testing.T) {
+	fc := New("test")
+	fc.Hint(NoOptimize())
+	fc.Ints([]int{1, 2, 3}).LocalLimit("first", 2, 0).LocalLimit("second", 1, 0)
+
+	if explained := fc.Explain(); strings.Contains(explained, "rewrites") || len(fc.Steps) != 3 {
+		t.Errorf("rewritten with NoOptimize:\n%s", explained)
+	}
+}
@@ -0,0 +1,47 @@
+package flow
+
+import (
+	"github.com/chrislusf/gleam/pb"
+)
+
+// Counters sums up the gio.Counter() values reported by all tasks.
+// The values are collected via the executors' stats heartbeat in distributed mode,
+// or from the finished mappers and reducers in local mode,
+// and are complete after the flow is run.
+func (fc *Flow) Counters() map[string]int64 {
+	ret := make(map[string]int64)
+	for _, step := range fc.Steps {
+		for _, c := range step.Stat().GetCounters() {
+			ret[c.Name] += c.Value
+		}
+	}
+	return ret
+}
+
+// Histograms merges the gio.Histogram() values reported by all tasks.
+func (fc *Flow) Histograms() map[string]*pb.InstructionStat_Histogram {
+	ret := make(map[string]*pb.InstructionStat_Histogram)
+	for _, step := range fc.Steps {
+		for _, h := range step.Stat().GetHistograms() {
+			if _, found := ret[h.Name]; !found {
+				ret[h.Name] = &pb.InstructionStat_Histogram{Name: h.Name}
+			}
+			ret[h.Name].Merge(h)
+		}
+	}
+	return ret
+}
+
+// Stat aggregates the stats of all tasks of this step.
+func (step *Step) Stat() *pb.InstructionStat {
+	ret := &pb.InstructionStat{StepId: int32(step.Id), TaskId: -1}
+	for _, task := range step.Tasks {
+		if task.Stat == nil {
+			continue
+		}
+		ret.InputCounter += task.Stat.InputCounter
+		ret.OutputCounter += task.Stat.OutputCounter
+		ret.Merge(task.Stat)
+	}
+	return ret
+}
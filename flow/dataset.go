@@ -7,8 +7,13 @@ import (
 )
 
 func newDataset(context *Flow) *Dataset {
+	// the ids stay unique after the optimizer removes some datasets
+	id := 0
+	if n := len(context.Datasets); n > 0 {
+		id = context.Datasets[n-1].Id + 1
+	}
 	d := &Dataset{
-		Id:   len(context.Datasets),
+		Id:   id,
 		Flow: context,
 		Meta: &DasetsetMetadata{TotalSize: -1},
 	}
@@ -44,3 +49,11 @@ func (s *DatasetShard) TimeTaken() time.Duration {
 func (s *DatasetShard) Name() string {
 	return fmt.Sprintf("f%d-d%d-s%d", s.Dataset.Flow.HashCode, s.Dataset.Id, s.Id)
 }
+
+// PreferredHosts returns the hosts having the source data of the shard.
+func (s *DatasetShard) PreferredHosts() []string {
+	if s.Meta == nil {
+		return nil
+	}
+	return s.Meta.PreferredHosts
+}
@@ -26,6 +26,15 @@ func PartitionSize(n int64) DasetsetHint {
 	}
 }
 
+// Skewed hints a few keys have many more rows than the other keys.
+// Joining a skewed dataset spreads the hot keys to all partitions,
+// and copies the matching rows of the other dataset to all partitions.
+func Skewed() DasetsetHint {
+	return func(d *Dataset) {
+		d.Meta.IsSkewed = true
+	}
+}
+
 // OnDisk ensure the intermediate dataset are persisted to disk.
 // This allows executors to run not in parallel if executors are limited.
 func (d *Dataset) OnDisk(fn func(*Dataset) *Dataset) *Dataset {
@@ -62,20 +71,18 @@ func (d *Dataset) OnDisk(fn func(*Dataset) *Dataset) *Dataset {
 	return ret
 }
 
-/*
-
-// Datacenter hints the previous dataset output location
-func Datacenter(dc string) DasetsetOption {
-	return func(c *DasetsetConfig) {
-		c.Datacenter = dc
+// Datacenter hints the previous dataset should be computed in the data center.
+// Other data centers are used if it has no agents.
+func Datacenter(dc string) DasetsetHint {
+	return func(d *Dataset) {
+		d.Meta.DataCenter = dc
 	}
 }
 
-// Rack hints the previous dataset output location
-func Rack(rack string) DasetsetOption {
-	return func(c *DasetsetConfig) {
-		c.Rack = rack
+// Rack hints the previous dataset should be computed on the rack.
+// Other racks are used if it is busy.
+func Rack(rack string) DasetsetHint {
+	return func(d *Dataset) {
+		d.Meta.Rack = rack
 	}
 }
-
-*/
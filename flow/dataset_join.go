@@ -31,7 +31,43 @@ func (d *Dataset) RightOuterJoinByKey(name string, other *Dataset) *Dataset {
 	return d.DoJoin(name, other, false, true, Field(1))
 }
 
+func (d *Dataset) FullOuterJoin(name string, other *Dataset, sortOption *SortOption) *Dataset {
+	return d.DoJoin(name, other, true, true, sortOption)
+}
+
+func (d *Dataset) FullOuterJoinByKey(name string, other *Dataset) *Dataset {
+	return d.DoJoin(name, other, true, true, Field(1))
+}
+
+// LeftSemiJoin keeps the rows of this dataset that have matching keys in the other dataset.
+// Only the rows of this dataset are output, and each row at most once.
+func (d *Dataset) LeftSemiJoin(name string, other *Dataset, sortOption *SortOption) *Dataset {
+	return d.DoSemiJoin(name, other, false, sortOption)
+}
+
+func (d *Dataset) LeftSemiJoinByKey(name string, other *Dataset) *Dataset {
+	return d.DoSemiJoin(name, other, false, Field(1))
+}
+
+// LeftAntiJoin keeps the rows of this dataset that have no matching keys in the other dataset.
+func (d *Dataset) LeftAntiJoin(name string, other *Dataset, sortOption *SortOption) *Dataset {
+	return d.DoSemiJoin(name, other, true, sortOption)
+}
+
+func (d *Dataset) LeftAntiJoinByKey(name string, other *Dataset) *Dataset {
+	return d.DoSemiJoin(name, other, true, Field(1))
+}
+
 func (d *Dataset) DoJoin(name string, other *Dataset, leftOuter, rightOuter bool, sortOption *SortOption) *Dataset {
+	// the copied rows of the non-skewed side should not be outer joined
+	if d != other && len(d.Shards) > 1 {
+		if d.Meta.IsSkewed && !rightOuter {
+			return d.doSkewedJoin(name, other, true, leftOuter, rightOuter, sortOption)
+		}
+		if other.Meta.IsSkewed && !leftOuter {
+			return d.doSkewedJoin(name, other, false, leftOuter, rightOuter, sortOption)
+		}
+	}
 	sorted_d := d.Partition(name+".left", len(d.Shards), sortOption).LocalSort(name+".left", sortOption)
 	var sorted_other *Dataset
 	if d == other {
@@ -42,6 +78,17 @@ func (d *Dataset) DoJoin(name string, other *Dataset, leftOuter, rightOuter bool
 	return sorted_d.JoinPartitionedSorted(name, sorted_other, sortOption, leftOuter, rightOuter)
 }
 
+func (d *Dataset) DoSemiJoin(name string, other *Dataset, isAntiJoin bool, sortOption *SortOption) *Dataset {
+	sorted_d := d.Partition(name+".left", len(d.Shards), sortOption).LocalSort(name+".left", sortOption)
+	var sorted_other *Dataset
+	if d == other {
+		sorted_other = sorted_d
+	} else {
+		sorted_other = other.Partition(name+".right", len(d.Shards), sortOption).LocalSort(name+".right", sortOption)
+	}
+	return sorted_d.SemiJoinPartitionedSorted(name, sorted_other, sortOption, isAntiJoin)
+}
+
 // JoinPartitionedSorted Join multiple datasets that are sharded by the same key, and locally sorted within the shard
 func (this *Dataset) JoinPartitionedSorted(name string, that *Dataset, sortOption *SortOption,
 	isLeftOuterJoin, isRightOuterJoin bool) *Dataset {
@@ -54,3 +101,17 @@ func (this *Dataset) JoinPartitionedSorted(name string, that *Dataset, sortOptio
 	step.SetInstruction(name, instruction.NewJoinPartitionedSorted(isLeftOuterJoin, isRightOuterJoin, sortOption.Indexes()))
 	return ret
 }
+
+// SemiJoinPartitionedSorted semi joins or anti joins two datasets that are sharded by the same key,
+// and locally sorted within the shard. The result keeps the rows of this dataset.
+func (this *Dataset) SemiJoinPartitionedSorted(name string, that *Dataset, sortOption *SortOption,
+	isAntiJoin bool) *Dataset {
+	ret := this.Flow.NewNextDataset(len(this.Shards))
+	ret.IsPartitionedBy = this.IsPartitionedBy
+	ret.IsLocalSorted = this.IsLocalSorted
+
+	inputs := []*Dataset{this, that}
+	step := this.Flow.MergeDatasets1ShardTo1Step(inputs, ret)
+	step.SetInstruction(name, instruction.NewSemiJoinPartitionedSorted(isAntiJoin, sortOption.Indexes()))
+	return ret
+}
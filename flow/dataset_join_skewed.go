@@ -0,0 +1,69 @@
+package flow
+
+import (
+	"github.com/chrislusf/gleam/instruction"
+)
+
+// the number of rows sampled per shard to detect hot keys
+const skewSampleSize = 10000
+
+// doSkewedJoin detects hot keys of the skewed dataset from a random sample of each shard.
+// The rows of hot keys are spread to all partitions on the skewed side,
+// and the matching rows on the other side are copied to all partitions.
+func (d *Dataset) doSkewedJoin(name string, that *Dataset, isLeftSkewed bool,
+	leftOuter, rightOuter bool, sortOption *SortOption) *Dataset {
+
+	skewed, other := d, that
+	if !isLeftSkewed {
+		skewed, other = that, d
+	}
+
+	shardCount := len(d.Shards)
+	indexes := sortOption.Indexes()
+	// a hot key alone fills half of an average partition
+	hotKeyRatio := 1 / float64(2*shardCount)
+
+	// the skewed dataset is read again after all its shards are sampled
+	skewed.Meta.OnDisk = ModeOnDisk
+	hotKeys := skewed.sampleHotKeys(name+".hotKeys", indexes, hotKeyRatio).
+		MergeTo(name+".hotKeys", 1)
+
+	sortedSkewed := skewed.scatterSkewedPartitions(name+".skewed", shardCount, indexes,
+		hotKeys.Broadcast(name+".hotKeys", len(skewed.Shards))).
+		partition_collect(name+".skewed", shardCount, indexes).
+		LocalSort(name+".skewed", sortOption)
+	sortedOther := other.scatterReplicatedPartitions(name+".replicated", shardCount, indexes,
+		hotKeys.Broadcast(name+".hotKeys", len(other.Shards))).
+		partition_collect(name+".replicated", shardCount, indexes).
+		LocalSort(name+".replicated", sortOption)
+
+	var ret *Dataset
+	if isLeftSkewed {
+		ret = sortedSkewed.JoinPartitionedSorted(name, sortedOther, sortOption, leftOuter, rightOuter)
+	} else {
+		ret = sortedOther.JoinPartitionedSorted(name, sortedSkewed, sortOption, leftOuter, rightOuter)
+	}
+	// the hot keys are not in one partition any more
+	ret.IsPartitionedBy = nil
+	return ret
+}
+
+func (d *Dataset) sampleHotKeys(name string, indexes []int, hotKeyRatio float64) *Dataset {
+	ret, step := add1ShardTo1Step(d)
+	step.SetInstruction(name, instruction.NewSampleHotKeys(indexes, skewSampleSize, hotKeyRatio))
+	return ret
+}
+
+func (d *Dataset) scatterSkewedPartitions(name string, shardCount int, indexes []int, hotKeys *Dataset) (ret *Dataset) {
+	ret = d.Flow.NewNextDataset(len(d.Shards) * shardCount)
+	step := d.Flow.AddMergedOneToEveryNStep([]*Dataset{d, hotKeys}, shardCount, ret)
+	step.SetInstruction(name, instruction.NewScatterSkewedPartitions(indexes))
+	return
+}
+
+func (d *Dataset) scatterReplicatedPartitions(name string, shardCount int, indexes []int, hotKeys *Dataset) (ret *Dataset) {
+	ret = d.Flow.NewNextDataset(len(d.Shards) * shardCount)
+	step := d.Flow.AddMergedOneToEveryNStep([]*Dataset{d, hotKeys}, shardCount, ret)
+	step.SetInstruction(name, instruction.NewScatterReplicatedPartitions(indexes))
+	return
+}
@@ -5,6 +5,7 @@ import (
 )
 
 func (d *Dataset) MergeSortedTo(name string, partitionCount int) (ret *Dataset) {
+	partitionCount = d.shardCount(partitionCount)
 	if len(d.Shards) == partitionCount {
 		return d
 	}
@@ -22,6 +23,7 @@ func (d *Dataset) MergeSortedTo(name string, partitionCount int) (ret *Dataset)
 }
 
 func (d *Dataset) TreeMergeSortedTo(name string, partitionCount int, factor int) (ret *Dataset) {
+	partitionCount = d.shardCount(partitionCount)
 	if len(d.Shards) > factor && len(d.Shards) > partitionCount {
 		t := d.MergeSortedTo(name, len(d.Shards)/factor)
 		return t.TreeMergeSortedTo(name, partitionCount, factor)
@@ -32,7 +34,10 @@ func (d *Dataset) TreeMergeSortedTo(name string, partitionCount int, factor int)
 	return d
 }
 
+// MergeTo merges the shards into partitionCount shards. With Auto, or 0,
+// the partition count is derived from the total size of the dataset.
 func (d *Dataset) MergeTo(name string, partitionCount int) (ret *Dataset) {
+	partitionCount = d.shardCount(partitionCount)
 	if len(d.Shards) == partitionCount {
 		return d
 	}
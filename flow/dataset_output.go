@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"reflect"
+	"sync"
 
 	"github.com/chrislusf/gleam/gio"
 	"github.com/chrislusf/gleam/pb"
@@ -15,6 +17,12 @@ import (
 
 // Output concurrently collects outputs from previous step to the driver.
 func (d *Dataset) Output(f func(io.Reader) error) *Dataset {
+	return d.output(f, nil)
+}
+
+// output runs f on each output, and then done, if set, after all outputs
+// are processed.
+func (d *Dataset) output(f func(io.Reader) error, done func() error) *Dataset {
 	step := d.Flow.AddAllToOneStep(d, nil)
 	step.IsOnDriverSide = true
 	step.Name = "Output"
@@ -32,6 +40,9 @@ func (d *Dataset) Output(f func(io.Reader) error) *Dataset {
 				return err
 			}
 		}
+		if done != nil {
+			return done()
+		}
 		return nil
 	}
 	return d
@@ -92,6 +103,66 @@ func (d *Dataset) SaveFirstRowTo(decodedObjects ...interface{}) *Dataset {
 	return d.Output(fn)
 }
 
+// Peek prints the first n rows of the dataset to os.Stdout, to look into
+// the data. The rest of the rows are read but not printed. See PeekSample
+// to look at rows from the whole dataset.
+func (d *Dataset) Peek(n int) *Dataset {
+	var lock sync.Mutex
+	var printed int
+	fn := func(reader io.Reader) error {
+		return util.TakeMessage(reader, n, func(encodedBytes []byte) error {
+			row, err := util.DecodeRow(encodedBytes)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to decode byte: %v\n", err)
+				return err
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if printed >= n {
+				return nil
+			}
+			printed++
+			return util.FprintRow(os.Stdout, row)
+		})
+	}
+	return d.Output(fn)
+}
+
+// PeekSample prints a random sample of n rows of the dataset to os.Stdout,
+// once all rows are read. Each row is printed with the same probability.
+func (d *Dataset) PeekSample(n int) *Dataset {
+	var lock sync.Mutex
+	var count int
+	var sample []*util.Row
+	fn := func(reader io.Reader) error {
+		return util.ProcessMessage(reader, func(encodedBytes []byte) error {
+			row, err := util.DecodeRow(encodedBytes)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to decode byte: %v\n", err)
+				return err
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			// reservoir sampling across all the outputs
+			count++
+			if len(sample) < n {
+				sample = append(sample, row)
+			} else if i := rand.Intn(count); i < n {
+				sample[i] = row
+			}
+			return nil
+		})
+	}
+	return d.output(fn, func() error {
+		for _, row := range sample {
+			if err := util.FprintRow(os.Stdout, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (d *Dataset) OutputRow(f func(*util.Row) error) *Dataset {
 	fn := func(reader io.Reader) error {
 		return util.TakeMessage(reader, -1, func(encodedBytes []byte) error {
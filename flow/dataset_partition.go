@@ -4,7 +4,13 @@ import (
 	"github.com/chrislusf/gleam/instruction"
 )
 
+// RoundRobin spreads the rows evenly into n times the number of shards.
+// With Auto, or 0, n is derived from the total size of the dataset,
+// instead of keeping the shards as they are.
 func (d *Dataset) RoundRobin(name string, n int) *Dataset {
+	if n == Auto {
+		n = d.shardCount(Auto) / len(d.Shards)
+	}
 	if n <= 1 {
 		return d
 	}
@@ -19,7 +25,9 @@ func (d *Dataset) RoundRobin(name string, n int) *Dataset {
 // This is divided into 2 steps:
 // 1. Each record is sharded to a local shard
 // 2. The destination shard will collect its child shards and merge into one
+// With Auto, or 0, the shard count is derived from the total size of the dataset.
 func (d *Dataset) Partition(name string, shard int, sortOption *SortOption) *Dataset {
+	shard = d.shardCount(shard)
 	indexes := sortOption.Indexes()
 	if intArrayEquals(d.IsPartitionedBy, indexes) && shard == len(d.Shards) {
 		return d
@@ -0,0 +1,61 @@
+package flow
+
+import (
+	"github.com/chrislusf/gleam/instruction"
+)
+
+// the number of sampled rows per shard to compute the split points
+const rangeSampleSize = 1000
+
+// RangeSort sorts on specific fields into shardCount partitions.
+// The sorting keys are sampled to compute the split points, and
+// each row goes to the partition of its key range and then sorted locally.
+// The partitions are globally ordered, so the i-th partition only has
+// rows ordered before the (i+1)-th partition.
+// Required Memory: about same size as each partition.
+// example usage: RangeSort("sort", 8, Field(1,2)) means
+// sorting on field 1 and 2 into 8 partitions.
+func (d *Dataset) RangeSort(name string, shardCount int, sortOption *SortOption) *Dataset {
+	ret := d.PartitionByRange(name, shardCount, sortOption).LocalSort(name, sortOption)
+	ret.IsPartitionedBy = nil
+	return ret
+}
+
+// PartitionByRange partitions the rows by the ranges of the sorting fields.
+// The partitions are ordered but the rows within each partition are not sorted.
+// With Auto, or 0, the shard count is derived from the total size of the dataset.
+func (d *Dataset) PartitionByRange(name string, shardCount int, sortOption *SortOption) *Dataset {
+	shardCount = d.shardCount(shardCount)
+	orderBys := sortOption.orderByList
+
+	// the dataset is read again after all its shards are sampled
+	d.Meta.OnDisk = ModeOnDisk
+	splitPoints := d.sampleSortKeys(name+".sample", orderBys).
+		MergeTo(name+".sample", 1).
+		computeSplitPoints(name+".splitPoints", orderBys, shardCount).
+		Broadcast(name+".splitPoints", len(d.Shards))
+
+	ret := d.scatterRanges(name+".scatter", shardCount, orderBys, splitPoints).
+		partition_collect(name+".collect", shardCount, nil)
+	ret.IsPartitionedBy = nil
+	return ret
+}
+
+func (d *Dataset) sampleSortKeys(name string, orderBys []instruction.OrderBy) *Dataset {
+	ret, step := add1ShardTo1Step(d)
+	step.SetInstruction(name, instruction.NewSampleSortKeys(orderBys, rangeSampleSize))
+	return ret
+}
+
+func (d *Dataset) computeSplitPoints(name string, orderBys []instruction.OrderBy, partitionCount int) *Dataset {
+	ret, step := add1ShardTo1Step(d)
+	step.SetInstruction(name, instruction.NewComputeSplitPoints(orderBys, partitionCount))
+	return ret
+}
+
+func (d *Dataset) scatterRanges(name string, shardCount int, orderBys []instruction.OrderBy, splitPoints *Dataset) (ret *Dataset) {
+	ret = d.Flow.NewNextDataset(len(d.Shards) * shardCount)
+	step := d.Flow.AddMergedOneToEveryNStep([]*Dataset{d, splitPoints}, shardCount, ret)
+	step.SetInstruction(name, instruction.NewScatterRanges(orderBys))
+	return
+}
@@ -13,6 +13,14 @@ type Sourcer interface {
 	Generate(*Flow) *Dataset
 }
 
+// Projector is a source able to read only some of the fields.
+// The optimizer pushes a Select right after the source into it.
+type Projector interface {
+	// Project reads only the fields at the indexes, starting from 1, in that
+	// order, the first one as the key. It returns false if it can not.
+	Project(indexes []int) bool
+}
+
 // Read accepts a function to read data into the flow, creating a new dataset.
 // This allows custom complicated pre-built logic for new data sources.
 func (fc *Flow) Read(s Sourcer) (ret *Dataset) {
@@ -2,11 +2,16 @@ package flow
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"log"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/chrislusf/gleam/gio"
 	"github.com/chrislusf/gleam/pb"
 	"github.com/chrislusf/gleam/util"
 	"github.com/chrislusf/gleam/util/on_interrupt"
@@ -21,7 +26,15 @@ type FlowOption interface {
 }
 
 type localDriver struct {
-	ctx context.Context
+}
+
+// localRun is the state of one run of a flow, so the flows can run
+// concurrently on the shared local driver.
+type localRun struct {
+	ctx          context.Context
+	broadcastDir string
+	// the folder for the mappers and reducers to save their stats into
+	statDir string
 }
 
 var (
@@ -32,19 +45,37 @@ func init() {
 	Local = &localDriver{}
 }
 
-func (r *localDriver) GetFlowRunner() FlowRunner {
-	return r
+func (d *localDriver) GetFlowRunner() FlowRunner {
+	return d
 }
 
-func (r *localDriver) RunFlowContext(ctx context.Context, fc *Flow) {
-	r.ctx = ctx
+func (d *localDriver) RunFlowContext(ctx context.Context, fc *Flow) {
+	r := &localRun{ctx: ctx}
+	if len(fc.BroadcastValues) > 0 {
+		dir, err := ioutil.TempDir("", "gleam")
+		if err != nil {
+			log.Fatalf("Failed to create folder for broadcast values: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		if _, err = fc.SaveBroadcastValues(dir); err != nil {
+			log.Fatalf("Failed to save broadcast values: %v", err)
+		}
+		r.broadcastDir = dir
+	}
+	statDir, err := ioutil.TempDir("", "gleam")
+	if err != nil {
+		log.Fatalf("Failed to create folder for stats: %v", err)
+	}
+	defer os.RemoveAll(statDir)
+	r.statDir = statDir
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	r.RunFlowAsync(&wg, fc)
 	wg.Wait()
 }
 
-func (r *localDriver) RunFlowAsync(wg *sync.WaitGroup, fc *Flow) {
+func (r *localRun) RunFlowAsync(wg *sync.WaitGroup, fc *Flow) {
 	defer wg.Done()
 
 	on_interrupt.OnInterrupt(fc.OnInterrupt, nil)
@@ -59,7 +90,7 @@ func (r *localDriver) RunFlowAsync(wg *sync.WaitGroup, fc *Flow) {
 	}
 }
 
-func (r *localDriver) runDataset(wg *sync.WaitGroup, d *Dataset) {
+func (r *localRun) runDataset(wg *sync.WaitGroup, d *Dataset) {
 	defer wg.Done()
 
 	d.Lock()
@@ -80,10 +111,15 @@ func (r *localDriver) runDataset(wg *sync.WaitGroup, d *Dataset) {
 	r.runStep(wg, d.Step)
 }
 
-func (r *localDriver) runDatasetShard(wg *sync.WaitGroup, shard *DatasetShard) {
+func (r *localRun) runDatasetShard(wg *sync.WaitGroup, shard *DatasetShard) {
 	defer wg.Done()
 	shard.ReadyTime = time.Now()
 
+	if shard.Dataset.GetIsOnDiskIO() && len(shard.OutgoingChans) > 1 {
+		r.runDatasetShardOnDisk(shard)
+		return
+	}
+
 	var writers []io.Writer
 	for _, outgoingChan := range shard.OutgoingChans {
 		writers = append(writers, outgoingChan.Writer)
@@ -102,7 +138,37 @@ func (r *localDriver) runDatasetShard(wg *sync.WaitGroup, shard *DatasetShard) {
 	}
 }
 
-func (r *localDriver) runStep(wg *sync.WaitGroup, step *Step) {
+// runDatasetShardOnDisk writes the whole dataset shard to a local file first,
+// and lets each reader read the file at its own pace, so a reader waiting
+// for other inputs does not block the other readers.
+func (r *localRun) runDatasetShardOnDisk(shard *DatasetShard) {
+	f, err := ioutil.TempFile("", "gleam-"+shard.Name())
+	if err != nil {
+		log.Fatalf("Failed to create file for dataset shard %s: %v", shard.Name(), err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	n, err := io.Copy(f, shard.IncomingChan.Reader)
+	if err != nil {
+		log.Fatalf("Failed to write dataset shard %s: %v", shard.Name(), err)
+	}
+	shard.Counter = n
+
+	var readersWg sync.WaitGroup
+	for _, outgoingChan := range shard.OutgoingChans {
+		readersWg.Add(1)
+		go func(writer io.WriteCloser) {
+			defer readersWg.Done()
+			io.Copy(writer, io.NewSectionReader(f, 0, n))
+			writer.Close()
+		}(outgoingChan.Writer)
+	}
+	readersWg.Wait()
+	shard.CloseTime = time.Now()
+}
+
+func (r *localRun) runStep(wg *sync.WaitGroup, step *Step) {
 	defer wg.Done()
 
 	step.Lock()
@@ -127,7 +193,7 @@ func (r *localDriver) runStep(wg *sync.WaitGroup, step *Step) {
 	}
 }
 
-func (r *localDriver) runTask(wg *sync.WaitGroup, task *Task) {
+func (r *localRun) runTask(wg *sync.WaitGroup, task *Task) {
 	defer wg.Done()
 
 	// try to run Function first
@@ -142,6 +208,14 @@ func (r *localDriver) runTask(wg *sync.WaitGroup, task *Task) {
 	// get an exec.Command
 	scriptCommand := task.Step.GetScriptCommand()
 	execCommand := scriptCommand.ToOsExecCommand()
+	if execCommand.Env == nil {
+		execCommand.Env = os.Environ()
+	}
+	if r.broadcastDir != "" {
+		execCommand.Env = append(execCommand.Env, gio.BroadcastDirEnv+"="+r.broadcastDir)
+	}
+	statFile := filepath.Join(r.statDir, fmt.Sprintf("s%d-t%d.stat", task.Step.Id, task.Id))
+	execCommand.Env = append(execCommand.Env, gio.StatFileEnv+"="+statFile)
 
 	if task.Step.NetworkType == OneShardToOneShard {
 		// fmt.Printf("execCommand: %+v\n", execCommand)
@@ -151,6 +225,12 @@ func (r *localDriver) runTask(wg *sync.WaitGroup, task *Task) {
 		prevIsPipe := task.InputShards[0].Dataset.Step.IsPipe
 		task.Stat = &pb.InstructionStat{}
 		util.Execute(r.ctx, wg, task.Stat, task.Step.Name, execCommand, reader, writer, prevIsPipe, task.Step.IsPipe, true, os.Stderr)
+		// only the Go mappers and reducers save their counters and histograms
+		if stat, err := gio.ReadStatFile(statFile); err == nil {
+			for _, s := range stat.GetStats() {
+				task.Stat.Merge(s)
+			}
+		}
 	} else {
 		println("network type:", task.Step.NetworkType)
 	}
@@ -12,12 +12,18 @@ import (
 )
 
 func (fc *Flow) NewStep() (step *Step) {
+	// the ids stay unique after the optimizer removes some steps
+	id := 0
+	if n := len(fc.Steps); n > 0 {
+		id = fc.Steps[n-1].Id + 1
+	}
 	step = &Step{
-		Id:     len(fc.Steps),
+		Id:     id,
 		Params: make(map[string]interface{}),
 		Meta:   &StepMetadata{IsIdempotent: true},
 	}
 	fc.Steps = append(fc.Steps, step)
+	fc.isOptimized = false
 	return
 }
 
@@ -1,6 +1,7 @@
 package flow
 
 import (
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -23,6 +24,23 @@ const (
 	AllShardTOAllShard
 )
 
+var networkTypeNames = []string{
+	"OneShardToOneShard",
+	"OneShardToAllShard",
+	"AllShardToOneShard",
+	"OneShardToEveryNShard",
+	"LinkedNShardToOneShard",
+	"MergeTwoShardToOneShard",
+	"AllShardTOAllShard",
+}
+
+func (t NetworkType) String() string {
+	if t < 0 || int(t) >= len(networkTypeNames) {
+		return fmt.Sprintf("NetworkType(%d)", int(t))
+	}
+	return networkTypeNames[t]
+}
+
 type DatasetShardStatus int
 
 const (
@@ -44,6 +62,12 @@ const (
 type DasetsetMetadata struct {
 	TotalSize int64
 	OnDisk    ModeIO
+	IsSkewed  bool
+	// where the dataset should be computed, empty for anywhere
+	DataCenter string
+	Rack       string
+	// the source reading the dataset, if it can read only some fields
+	Projector Projector
 }
 
 type DasetsetShardMetadata struct {
@@ -52,6 +76,8 @@ type DasetsetShardMetadata struct {
 	URI       string
 	Status    DatasetShardStatus
 	Error     error
+	// hosts having the source data of the shard, e.g. the HDFS data nodes
+	PreferredHosts []string
 }
 
 type StepMetadata struct {
@@ -60,10 +86,16 @@ type StepMetadata struct {
 }
 
 type Flow struct {
-	Name     string
-	Steps    []*Step
-	Datasets []*Dataset
-	HashCode uint32
+	Name            string
+	Steps           []*Step
+	Datasets        []*Dataset
+	HashCode        uint32
+	BroadcastValues map[string][]byte
+	// what the optimizer has rewritten
+	rewrites []string
+	// no steps are added since the optimizer ran
+	isOptimized bool
+	config      FlowConfig
 }
 
 type Dataset struct {
@@ -0,0 +1,55 @@
+package gio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/chrislusf/gleam/util"
+)
+
+// BroadcastDirEnv points to the folder with the broadcast value files.
+// If not set, the files are read from the current working directory,
+// where the gleam agent places the files sent by the driver.
+const BroadcastDirEnv = "GLEAM_BROADCAST_DIR"
+
+var (
+	broadcasts     = make(map[string]interface{})
+	broadcastsLock sync.Mutex
+)
+
+// BroadcastFileName is the file name used to ship a named broadcast value.
+func BroadcastFileName(name string) string {
+	return fmt.Sprintf("broadcast_%x.dat", name)
+}
+
+// GetBroadcast returns the value registered via flow.BroadcastValue(name, value).
+// The value is read once and cached for the life of the mapper or reducer
+// process. It is decoded the same way as the fields of a row, so a struct
+// value comes back as a map or slice, not the original struct type.
+func GetBroadcast(name string) (interface{}, error) {
+	broadcastsLock.Lock()
+	defer broadcastsLock.Unlock()
+
+	if v, found := broadcasts[name]; found {
+		return v, nil
+	}
+
+	f, err := os.Open(filepath.Join(os.Getenv(BroadcastDirEnv), BroadcastFileName(name)))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find broadcast value %s: %v", name, err)
+	}
+	defer f.Close()
+
+	row, err := util.ReadRow(f)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read broadcast value %s: %v", name, err)
+	}
+	if len(row.K) == 0 {
+		return nil, fmt.Errorf("Empty broadcast value %s", name)
+	}
+
+	broadcasts[name] = row.K[0]
+	return row.K[0], nil
+}
@@ -0,0 +1,91 @@
+package gio
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+// statLock guards the user defined counters and histograms,
+// which are read by the status heartbeat while the mapper or reducer updates them.
+var statLock sync.Mutex
+
+// NamedCounter is a user defined counter, aggregated across all tasks of a flow.
+type NamedCounter struct {
+	name string
+}
+
+// NamedHistogram is a user defined histogram, aggregated across all tasks of a flow.
+type NamedHistogram struct {
+	name string
+}
+
+// Counter returns the named counter for the current mapper or reducer.
+// The values are reported to the driver together with the execution stats.
+func Counter(name string) *NamedCounter {
+	return &NamedCounter{name: name}
+}
+
+// Histogram returns the named histogram for the current mapper or reducer.
+func Histogram(name string) *NamedHistogram {
+	return &NamedHistogram{name: name}
+}
+
+// Inc increases the counter by 1.
+func (c *NamedCounter) Inc() {
+	c.Add(1)
+}
+
+// Add increases the counter by delta.
+func (c *NamedCounter) Add(delta int64) {
+	statLock.Lock()
+	defer statLock.Unlock()
+	if len(stat.Stats) == 0 {
+		return
+	}
+	stat.Stats[0].AddCounter(c.name, delta)
+}
+
+// Observe records one value into the histogram.
+func (h *NamedHistogram) Observe(value float64) {
+	statLock.Lock()
+	defer statLock.Unlock()
+	if len(stat.Stats) == 0 {
+		return
+	}
+	stat.Stats[0].GetHistogram(h.name).Observe(value)
+}
+
+// StatFileEnv points to the file to save the final stats into when the mapper
+// or reducer finishes, for the local runner which has no executor to report to.
+const StatFileEnv = "GLEAM_STAT_FILE"
+
+func saveStatFile() error {
+	statFile := os.Getenv(StatFileEnv)
+	if statFile == "" {
+		return nil
+	}
+	statLock.Lock()
+	data, err := proto.Marshal(stat)
+	statLock.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(statFile, data, 0644)
+}
+
+// ReadStatFile reads the stats saved by a mapper or reducer via StatFileEnv.
+func ReadStatFile(statFile string) (*pb.ExecutionStat, error) {
+	data, err := ioutil.ReadFile(statFile)
+	if err != nil {
+		return nil, err
+	}
+	ret := &pb.ExecutionStat{}
+	if err = proto.Unmarshal(data, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -2,6 +2,7 @@ package gio
 
 import (
 	"context"
+	"log"
 	"sync"
 )
 
@@ -32,6 +33,9 @@ func (runner *gleamRunner) report(ctx context.Context, f func() error) error {
 	select {
 	case <-finishedChan:
 		runner.reportStatus()
+		if err := saveStatFile(); err != nil {
+			log.Printf("Failed to save stats: %v", err)
+		}
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -26,7 +26,10 @@ func (runner *gleamRunner) statusHeartbeat(wg *sync.WaitGroup, finishedChan chan
 		for {
 			select {
 			case <-tickChan:
-				if err := stream.Send(stat); err != nil {
+				statLock.Lock()
+				err := stream.Send(stat)
+				statLock.Unlock()
+				if err != nil {
 					return fmt.Errorf("runner Send(%v): %v", stat, err)
 				}
 			case <-finishedChan:
@@ -52,7 +55,10 @@ func (runner *gleamRunner) reportStatus() {
 		}
 		// defer stream.CloseSend()
 
-		if err := stream.Send(stat); err != nil {
+		statLock.Lock()
+		err = stream.Send(stat)
+		statLock.Unlock()
+		if err != nil {
 			log.Printf("%v.Send(%v) = %v", stream, stat, err)
 			return nil
 		}
@@ -0,0 +1,108 @@
+package instruction
+
+import (
+	"io"
+	"sort"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetComputeSplitPoints() != nil {
+			return NewComputeSplitPoints(
+				toOrderBys(m.GetComputeSplitPoints().GetOrderBys()),
+				int(m.GetComputeSplitPoints().GetPartitionCount()),
+			)
+		}
+		return nil
+	})
+}
+
+// ComputeSplitPoints sorts the sampled keys from SampleSortKeys,
+// and outputs partitionCount-1 keys evenly dividing the weights of the samples.
+type ComputeSplitPoints struct {
+	orderBys       []OrderBy
+	partitionCount int
+}
+
+func NewComputeSplitPoints(orderBys []OrderBy, partitionCount int) *ComputeSplitPoints {
+	return &ComputeSplitPoints{orderBys, partitionCount}
+}
+
+func (b *ComputeSplitPoints) Name(prefix string) string {
+	return prefix + ".ComputeSplitPoints"
+}
+
+func (b *ComputeSplitPoints) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoComputeSplitPoints(readers[0], writers[0], b.orderBys, b.partitionCount, stats)
+	}
+}
+
+func (b *ComputeSplitPoints) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		ComputeSplitPoints: &pb.Instruction_ComputeSplitPoints{
+			OrderBys:       getOrderBys(b.orderBys),
+			PartitionCount: int32(b.partitionCount),
+		},
+	}
+}
+
+func (b *ComputeSplitPoints) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoComputeSplitPoints(reader io.Reader, writer io.Writer, orderBys []OrderBy, partitionCount int, stats *pb.InstructionStat) error {
+	var samples []*util.Row
+	var totalWeight float64
+	err := util.ProcessRow(reader, nil, func(row *util.Row) error {
+		stats.InputCounter++
+		samples = append(samples, row)
+		totalWeight += sampleWeight(row)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+
+	keyOrderBys := toSortKeysOrderBys(orderBys)
+	sort.Slice(samples, func(a, b int) bool {
+		return lessThan(keyOrderBys, samples[a], samples[b])
+	})
+
+	var last *util.Row
+	var weight float64
+	x := 0
+	for i := 1; i < partitionCount; i++ {
+		// the first sample where the weights reach the i-th partition
+		target := totalWeight * float64(i) / float64(partitionCount)
+		for x < len(samples)-1 && weight+sampleWeight(samples[x]) <= target {
+			weight += sampleWeight(samples[x])
+			x++
+		}
+		splitPoint := &util.Row{K: samples[x].K, T: samples[x].T}
+		// skip duplicated split points, which would create empty partitions
+		if last != nil && !lessThan(keyOrderBys, last, splitPoint) {
+			continue
+		}
+		if err := splitPoint.WriteTo(writer); err != nil {
+			return err
+		}
+		stats.OutputCounter++
+		last = splitPoint
+	}
+	return nil
+}
+
+// sampleWeight returns the number of input rows the sample stands for.
+func sampleWeight(row *util.Row) float64 {
+	if len(row.V) == 0 {
+		return 1
+	}
+	return util.ToFloat64(row.V[0])
+}
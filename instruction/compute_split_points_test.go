@@ -0,0 +1,65 @@
+package instruction
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func TestComputeSplitPoints(t *testing.T) {
+	var samples []*util.Row
+	for i := 0; i < 100; i++ {
+		samples = append(samples, util.NewRow(0, i))
+	}
+	if splitPoints := computeSplitPoints(t, samples, 4); !reflect.DeepEqual(splitPoints, []int64{25, 50, 75}) {
+		t.Errorf("split points %v, expected [25 50 75]", splitPoints)
+	}
+}
+
+func TestComputeSplitPointsByWeight(t *testing.T) {
+	// 100 rows of a small shard, and 10 samples of 1000 rows of a large shard
+	var samples []*util.Row
+	for i := 0; i < 100; i++ {
+		samples = append(samples, util.NewRow(0, i).AppendValue(1.0))
+	}
+	for i := 100; i < 110; i++ {
+		samples = append(samples, util.NewRow(0, i).AppendValue(100.0))
+	}
+	if splitPoints := computeSplitPoints(t, samples, 2); !reflect.DeepEqual(splitPoints, []int64{104}) {
+		t.Errorf("split points %v, expected [104]", splitPoints)
+	}
+}
+
+func TestComputeSplitPointsWithDuplicatedKeys(t *testing.T) {
+	var samples []*util.Row
+	for i := 0; i < 100; i++ {
+		samples = append(samples, util.NewRow(0, 7))
+	}
+	if splitPoints := computeSplitPoints(t, samples, 4); !reflect.DeepEqual(splitPoints, []int64{7}) {
+		t.Errorf("split points %v, expected [7]", splitPoints)
+	}
+}
+
+func computeSplitPoints(t *testing.T, samples []*util.Row, partitionCount int) (splitPoints []int64) {
+	var input, output bytes.Buffer
+	for _, row := range samples {
+		if err := row.WriteTo(&input); err != nil {
+			t.Fatalf("write sample: %v", err)
+		}
+	}
+	orderBys := []OrderBy{{Index: 1, Order: Ascending}}
+	if err := DoComputeSplitPoints(&input, &output, orderBys, partitionCount, &pb.InstructionStat{}); err != nil {
+		t.Fatalf("compute split points: %v", err)
+	}
+	err := util.ProcessRow(&output, nil, func(row *util.Row) error {
+		splitPoints = append(splitPoints, util.ToInt64(row.K[0]))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read split points: %v", err)
+	}
+	return
+}
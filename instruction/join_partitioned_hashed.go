@@ -0,0 +1,170 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetJoinPartitionedHashed() != nil {
+			return NewJoinPartitionedHashed(
+				m.GetJoinPartitionedHashed().GetIsLeftOuterJoin(),
+				m.GetJoinPartitionedHashed().GetIsRightOuterJoin(),
+				m.GetJoinPartitionedHashed().GetIsLeftHashed(),
+				toInts(m.GetJoinPartitionedHashed().GetIndexes()),
+			)
+		}
+		return nil
+	})
+}
+
+// JoinPartitionedHashed joins the same partitions of two datasets without sorting them.
+// The rows of the hashed side are kept in memory, and the other side is streamed through.
+// The output rows are the same as JoinPartitionedSorted, except not sorted, and
+// with the timestamp of the later one of the two joined rows, instead of the
+// latest one of all rows with the same key, as the streamed side is not grouped.
+type JoinPartitionedHashed struct {
+	isLeftOuterJoin  bool
+	isRightOuterJoin bool
+	isLeftHashed     bool
+	indexes          []int
+}
+
+func NewJoinPartitionedHashed(isLeftOuterJoin bool, isRightOuterJoin bool, isLeftHashed bool, indexes []int) *JoinPartitionedHashed {
+	return &JoinPartitionedHashed{isLeftOuterJoin, isRightOuterJoin, isLeftHashed, indexes}
+}
+
+func (b *JoinPartitionedHashed) Name(prefix string) string {
+	return prefix + ".JoinPartitionedHashed"
+}
+
+func (b *JoinPartitionedHashed) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoJoinPartitionedHashed(readers[0], readers[1], writers[0], b.indexes, b.isLeftOuterJoin, b.isRightOuterJoin, b.isLeftHashed, stats)
+	}
+}
+
+func (b *JoinPartitionedHashed) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		JoinPartitionedHashed: &pb.Instruction_JoinPartitionedHashed{
+			IsLeftOuterJoin:  (b.isLeftOuterJoin),
+			IsRightOuterJoin: (b.isRightOuterJoin),
+			IsLeftHashed:     (b.isLeftHashed),
+			Indexes:          getIndexes(b.indexes),
+		},
+	}
+}
+
+func (b *JoinPartitionedHashed) GetMemoryCostInMB(partitionSize int64) int64 {
+	return int64(float32(partitionSize) * 1.1)
+}
+
+type hashedRows struct {
+	rows    []*util.Row
+	matched bool
+}
+
+func DoJoinPartitionedHashed(leftReader, rightReader io.Reader, writer io.Writer, indexes []int,
+	isLeftOuterJoin, isRightOuterJoin, isLeftHashed bool, stats *pb.InstructionStat) error {
+
+	hashedReader, streamedReader := rightReader, leftReader
+	isHashedOuterJoin, isStreamedOuterJoin := isRightOuterJoin, isLeftOuterJoin
+	if isLeftHashed {
+		hashedReader, streamedReader = leftReader, rightReader
+		isHashedOuterJoin, isStreamedOuterJoin = isLeftOuterJoin, isRightOuterJoin
+	}
+
+	// write the values in the left and right order, whichever side is hashed
+	writeRow := func(ts int64, keys, streamedValues, hashedValues []interface{}) error {
+		row := util.NewRow(ts).AppendKey(keys...)
+		if isLeftHashed {
+			row.AppendValue(hashedValues...).AppendValue(streamedValues...)
+		} else {
+			row.AppendValue(streamedValues...).AppendValue(hashedValues...)
+		}
+		stats.OutputCounter++
+		return row.WriteTo(writer)
+	}
+
+	hashmap := make(map[string]*hashedRows)
+	var hashedKeys []string
+	hashedValueLength := 0
+	err := util.ProcessRow(hashedReader, indexes, func(row *util.Row) error {
+		stats.InputCounter++
+		keyBytes, err := util.EncodeKeys(row.K...)
+		if err != nil {
+			return fmt.Errorf("Failed to encoded keys %+v: %v", row.K, err)
+		}
+		if len(hashedKeys) == 0 {
+			hashedValueLength = len(row.V)
+		}
+		key := string(keyBytes)
+		rows, found := hashmap[key]
+		if !found {
+			rows = &hashedRows{}
+			hashmap[key] = rows
+			hashedKeys = append(hashedKeys, key)
+		}
+		rows.rows = append(rows.rows, row)
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("JoinPartitionedHashed>Failed to read the hashed input data:%v\n", err)
+		return err
+	}
+	if len(hashmap) == 0 && !isStreamedOuterJoin {
+		io.Copy(ioutil.Discard, streamedReader)
+		return nil
+	}
+
+	streamedValueLength, hasStreamedRow := 0, false
+	err = util.ProcessRow(streamedReader, indexes, func(row *util.Row) error {
+		stats.InputCounter++
+		if !hasStreamedRow {
+			streamedValueLength, hasStreamedRow = len(row.V), true
+		}
+		keyBytes, err := util.EncodeKeys(row.K...)
+		if err != nil {
+			return fmt.Errorf("Failed to encoded keys %+v: %v", row.K, err)
+		}
+		rows, found := hashmap[string(keyBytes)]
+		if !found {
+			if isStreamedOuterJoin {
+				return writeRow(row.T, row.K, row.V, addNils(nil, hashedValueLength))
+			}
+			return nil
+		}
+		rows.matched = true
+		for _, hashedRow := range rows.rows {
+			if err := writeRow(max(row.T, hashedRow.T), row.K, row.V, hashedRow.V); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("JoinPartitionedHashed>Failed to process the streamed input data:%v\n", err)
+		return err
+	}
+
+	if isHashedOuterJoin {
+		for _, key := range hashedKeys {
+			rows := hashmap[key]
+			if rows.matched {
+				continue
+			}
+			for _, hashedRow := range rows.rows {
+				if err := writeRow(hashedRow.T, hashedRow.K, addNils(nil, streamedValueLength), hashedRow.V); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
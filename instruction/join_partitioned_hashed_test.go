@@ -0,0 +1,71 @@
+package instruction
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+// encodeRows writes the rows of a key and a value, sorted by the key
+func encodeRows(t *testing.T, rows [][]interface{}) io.Reader {
+	var buf bytes.Buffer
+	for i, row := range rows {
+		if err := util.NewRow(int64(i), row...).WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &buf
+}
+
+// decodeRows lists the keys and values of the rows, sorted, as the hashed
+// join does not sort them, nor take the timestamps of the key groups
+func decodeRows(t *testing.T, reader io.Reader) (rows []string) {
+	err := util.ProcessRow(reader, []int{1}, func(row *util.Row) error {
+		rows = append(rows, fmt.Sprintf("%v %v", row.K, row.V))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(rows)
+	return rows
+}
+
+func TestJoinPartitionedHashedSameAsSorted(t *testing.T) {
+	left := [][]interface{}{{1, "a"}, {2, "b"}, {2, "c"}, {4, "d"}, {5, "e"}}
+	right := [][]interface{}{{2, "x"}, {3, "y"}, {4, "z"}, {4, "w"}}
+
+	for _, c := range []struct {
+		name                              string
+		isLeftOuterJoin, isRightOuterJoin bool
+	}{
+		{"inner join", false, false},
+		{"left outer join", true, false},
+		{"right outer join", false, true},
+		{"full outer join", true, true},
+	} {
+		var sorted bytes.Buffer
+		if err := DoJoinPartitionedSorted(encodeRows(t, left), encodeRows(t, right), &sorted, []int{1},
+			c.isLeftOuterJoin, c.isRightOuterJoin, &pb.InstructionStat{}); err != nil {
+			t.Fatal(err)
+		}
+		expected := decodeRows(t, &sorted)
+
+		for _, isLeftHashed := range []bool{true, false} {
+			var hashed bytes.Buffer
+			if err := DoJoinPartitionedHashed(encodeRows(t, left), encodeRows(t, right), &hashed, []int{1},
+				c.isLeftOuterJoin, c.isRightOuterJoin, isLeftHashed, &pb.InstructionStat{}); err != nil {
+				t.Fatal(err)
+			}
+			if rows := decodeRows(t, &hashed); !reflect.DeepEqual(rows, expected) {
+				t.Errorf("%s hashing the left side %v:\n got %v\nwant %v", c.name, isLeftHashed, rows, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package instruction
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+func TestFullOuterJoinPartitionedSorted(t *testing.T) {
+	left := [][]interface{}{{1, "a"}, {2, "b"}, {2, "c"}}
+	right := [][]interface{}{{2, "x"}, {2, "y"}, {3, "z"}}
+
+	tests := []struct {
+		name        string
+		left, right [][]interface{}
+		expected    []string
+	}{
+		{"duplicated keys on both sides", left, right, []string{
+			"[1] [a <nil>]", "[2] [b x]", "[2] [b y]", "[2] [c x]", "[2] [c y]", "[3] [<nil> z]"}},
+		// without a row on one side, its number of values is unknown
+		{"empty left side", nil, right, []string{"[2] [x]", "[2] [y]", "[3] [z]"}},
+		{"empty right side", left, nil, []string{"[1] [a]", "[2] [b]", "[2] [c]"}},
+		{"empty both sides", nil, nil, nil},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := DoJoinPartitionedSorted(encodeRows(t, test.left), encodeRows(t, test.right), &out, []int{1},
+			true, true, &pb.InstructionStat{}); err != nil {
+			t.Fatal(err)
+		}
+		if rows := decodeRows(t, &out); !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("%s:\n got %v\nwant %v", test.name, rows, test.expected)
+		}
+	}
+}
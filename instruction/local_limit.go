@@ -28,6 +28,11 @@ func NewLocalLimit(n int, offset int) *LocalLimit {
 	return &LocalLimit{n, offset}
 }
 
+// Limit returns the number of rows to take and the number of rows to skip first.
+func (b *LocalLimit) Limit() (n int, offset int) {
+	return b.n, b.offset
+}
+
 func (b *LocalLimit) Name(prefix string) string {
 	return prefix + ".LocalLimit"
 }
@@ -0,0 +1,117 @@
+package instruction
+
+import (
+	"io"
+	"math/rand"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetSampleHotKeys() != nil {
+			return NewSampleHotKeys(
+				toInts(m.GetSampleHotKeys().GetIndexes()),
+				int(m.GetSampleHotKeys().GetSampleSize()),
+				m.GetSampleHotKeys().GetHotKeyRatio(),
+			)
+		}
+		return nil
+	})
+}
+
+// SampleHotKeys uniformly samples up to sampleSize rows of the whole input,
+// and outputs the keys taking at least hotKeyRatio of the sampled rows,
+// one row per hot key.
+type SampleHotKeys struct {
+	indexes     []int
+	sampleSize  int
+	hotKeyRatio float64
+}
+
+func NewSampleHotKeys(indexes []int, sampleSize int, hotKeyRatio float64) *SampleHotKeys {
+	return &SampleHotKeys{indexes, sampleSize, hotKeyRatio}
+}
+
+func (b *SampleHotKeys) Name(prefix string) string {
+	return prefix + ".SampleHotKeys"
+}
+
+func (b *SampleHotKeys) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoSampleHotKeys(readers[0], writers[0], b.indexes, b.sampleSize, b.hotKeyRatio, stats)
+	}
+}
+
+func (b *SampleHotKeys) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		SampleHotKeys: &pb.Instruction_SampleHotKeys{
+			Indexes:     getIndexes(b.indexes),
+			SampleSize:  int32(b.sampleSize),
+			HotKeyRatio: b.hotKeyRatio,
+		},
+	}
+}
+
+func (b *SampleHotKeys) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoSampleHotKeys(reader io.Reader, writer io.Writer, indexes []int,
+	sampleSize int, hotKeyRatio float64, stats *pb.InstructionStat) error {
+
+	// reservoir sampling, so sorted or clustered input is sampled evenly
+	var samples []*util.Row
+	var count int
+	err := util.ProcessRow(reader, indexes, func(row *util.Row) error {
+		stats.InputCounter++
+		count++
+		if len(samples) < sampleSize {
+			samples = append(samples, &util.Row{K: row.K, T: row.T})
+		} else if x := rand.Intn(count); x < sampleSize {
+			samples[x] = &util.Row{K: row.K, T: row.T}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	hotKeys := findHotKeys(samples, hotKeyRatio)
+	for _, row := range samples {
+		key, err := util.EncodeKeys(row.K...)
+		if err != nil {
+			continue
+		}
+		if !hotKeys[string(key)] {
+			continue
+		}
+		delete(hotKeys, string(key))
+		if err := row.WriteTo(writer); err != nil {
+			return err
+		}
+		stats.OutputCounter++
+	}
+	return nil
+}
+
+// findHotKeys returns the encoded keys taking at least hotKeyRatio of the sampled rows.
+func findHotKeys(samples []*util.Row, hotKeyRatio float64) map[string]bool {
+	counts := make(map[string]int)
+	for _, row := range samples {
+		key, err := util.EncodeKeys(row.K...)
+		if err != nil {
+			continue
+		}
+		counts[string(key)]++
+	}
+	hotKeys := make(map[string]bool)
+	threshold := hotKeyRatio * float64(len(samples))
+	for key, count := range counts {
+		if count > 1 && float64(count) >= threshold {
+			hotKeys[key] = true
+		}
+	}
+	return hotKeys
+}
@@ -0,0 +1,109 @@
+package instruction
+
+import (
+	"io"
+	"math/rand"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetSampleSortKeys() != nil {
+			return NewSampleSortKeys(
+				toOrderBys(m.GetSampleSortKeys().GetOrderBys()),
+				int(m.GetSampleSortKeys().GetSampleSize()),
+			)
+		}
+		return nil
+	})
+}
+
+// SampleSortKeys uniformly samples up to sampleSize rows,
+// and outputs only the sorting fields of the sampled rows.
+// Each sampled row has the number of input rows it stands for as its value,
+// so the samples of larger shards weigh more when computing the split points.
+type SampleSortKeys struct {
+	orderBys   []OrderBy
+	sampleSize int
+}
+
+func NewSampleSortKeys(orderBys []OrderBy, sampleSize int) *SampleSortKeys {
+	return &SampleSortKeys{orderBys, sampleSize}
+}
+
+func (b *SampleSortKeys) Name(prefix string) string {
+	return prefix + ".SampleSortKeys"
+}
+
+func (b *SampleSortKeys) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoSampleSortKeys(readers[0], writers[0], b.orderBys, b.sampleSize, stats)
+	}
+}
+
+func (b *SampleSortKeys) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		SampleSortKeys: &pb.Instruction_SampleSortKeys{
+			OrderBys:   getOrderBys(b.orderBys),
+			SampleSize: int32(b.sampleSize),
+		},
+	}
+}
+
+func (b *SampleSortKeys) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoSampleSortKeys(reader io.Reader, writer io.Writer, orderBys []OrderBy, sampleSize int, stats *pb.InstructionStat) error {
+
+	// reservoir sampling
+	var samples []*util.Row
+	var count int
+	err := util.ProcessRow(reader, nil, func(row *util.Row) error {
+		stats.InputCounter++
+		count++
+		if len(samples) < sampleSize {
+			samples = append(samples, toSortKeysRow(orderBys, row))
+		} else if x := rand.Intn(count); x < sampleSize {
+			samples[x] = toSortKeysRow(orderBys, row)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	weight := float64(count) / float64(len(samples))
+	for _, row := range samples {
+		row.V = []interface{}{weight}
+		if err := row.WriteTo(writer); err != nil {
+			return err
+		}
+		stats.OutputCounter++
+	}
+	return nil
+}
+
+// toSortKeysRow keeps only the sorting fields, in the order of orderBys.
+func toSortKeysRow(orderBys []OrderBy, row *util.Row) *util.Row {
+	klen := len(row.K)
+	keys := make([]interface{}, 0, len(orderBys))
+	for _, order := range orderBys {
+		if order.Index <= klen {
+			keys = append(keys, row.K[order.Index-1])
+		} else {
+			keys = append(keys, row.V[order.Index-1-klen])
+		}
+	}
+	return &util.Row{K: keys, T: row.T}
+}
+
+// toSortKeysOrderBys returns the orderBys to compare the rows from toSortKeysRow().
+func toSortKeysOrderBys(orderBys []OrderBy) (ret []OrderBy) {
+	for i, order := range orderBys {
+		ret = append(ret, OrderBy{Index: i + 1, Order: order.Order})
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetScatterRanges() != nil {
+			return NewScatterRanges(
+				toOrderBys(m.GetScatterRanges().GetOrderBys()),
+			)
+		}
+		return nil
+	})
+}
+
+// ScatterRanges partitions rows by the key ranges between the split points.
+// The first input is the data, the second input is the split points from ComputeSplitPoints.
+type ScatterRanges struct {
+	orderBys []OrderBy
+}
+
+func NewScatterRanges(orderBys []OrderBy) *ScatterRanges {
+	return &ScatterRanges{orderBys}
+}
+
+func (b *ScatterRanges) Name(prefix string) string {
+	return prefix + ".ScatterRanges"
+}
+
+func (b *ScatterRanges) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoScatterRanges(readers[0], readers[1], writers, b.orderBys, stats)
+	}
+}
+
+func (b *ScatterRanges) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		ScatterRanges: &pb.Instruction_ScatterRanges{
+			OrderBys: getOrderBys(b.orderBys),
+		},
+	}
+}
+
+func (b *ScatterRanges) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoScatterRanges(reader, splitPointsReader io.Reader, writers []io.Writer, orderBys []OrderBy, stats *pb.InstructionStat) error {
+
+	var splitPoints []*util.Row
+	err := util.ProcessRow(splitPointsReader, nil, func(row *util.Row) error {
+		splitPoints = append(splitPoints, row)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(splitPoints) >= len(writers) {
+		return fmt.Errorf("%d split points for %d partitions", len(splitPoints), len(writers))
+	}
+
+	keyOrderBys := toSortKeysOrderBys(orderBys)
+	return util.ProcessRow(reader, nil, func(row *util.Row) error {
+		stats.InputCounter++
+		keys := toSortKeysRow(orderBys, row)
+		x := sort.Search(len(splitPoints), func(i int) bool {
+			return lessThan(keyOrderBys, keys, splitPoints[i])
+		})
+		if err := row.WriteTo(writers[x]); err == nil {
+			stats.OutputCounter++
+		}
+		return nil
+	})
+}
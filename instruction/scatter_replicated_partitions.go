@@ -0,0 +1,93 @@
+package instruction
+
+import (
+	"io"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetScatterReplicatedPartitions() != nil {
+			return NewScatterReplicatedPartitions(
+				toInts(m.GetScatterReplicatedPartitions().GetIndexes()),
+			)
+		}
+		return nil
+	})
+}
+
+// ScatterReplicatedPartitions partitions rows by key hash,
+// but copies the rows of hot keys to all partitions.
+// The first input is the data, the second input is the hot keys from SampleHotKeys.
+type ScatterReplicatedPartitions struct {
+	indexes []int
+}
+
+func NewScatterReplicatedPartitions(indexes []int) *ScatterReplicatedPartitions {
+	return &ScatterReplicatedPartitions{indexes}
+}
+
+func (b *ScatterReplicatedPartitions) Name(prefix string) string {
+	return prefix + ".ScatterReplicatedPartitions"
+}
+
+func (b *ScatterReplicatedPartitions) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoScatterReplicatedPartitions(readers[0], readers[1], writers, b.indexes, stats)
+	}
+}
+
+func (b *ScatterReplicatedPartitions) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		ScatterReplicatedPartitions: &pb.Instruction_ScatterReplicatedPartitions{
+			Indexes: getIndexes(b.indexes),
+		},
+	}
+}
+
+func (b *ScatterReplicatedPartitions) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoScatterReplicatedPartitions(reader, hotKeysReader io.Reader, writers []io.Writer, indexes []int, stats *pb.InstructionStat) error {
+	shardCount := len(writers)
+
+	hotKeys := make(map[string]bool)
+	err := util.ProcessRow(hotKeysReader, nil, func(row *util.Row) error {
+		key, err := util.EncodeKeys(row.K...)
+		if err != nil {
+			return err
+		}
+		hotKeys[string(key)] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return util.ProcessRow(reader, indexes, func(row *util.Row) error {
+		stats.InputCounter++
+		if len(hotKeys) > 0 {
+			key, err := util.EncodeKeys(row.K...)
+			if err != nil {
+				return err
+			}
+			if hotKeys[string(key)] {
+				for _, writer := range writers {
+					if err := row.WriteTo(writer); err == nil {
+						stats.OutputCounter++
+					}
+				}
+				return nil
+			}
+		}
+		x := util.PartitionByKeys(shardCount, row.K)
+		if err := row.WriteTo(writers[x]); err == nil {
+			stats.OutputCounter++
+		}
+		return nil
+	})
+
+}
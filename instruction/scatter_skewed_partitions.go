@@ -0,0 +1,91 @@
+package instruction
+
+import (
+	"io"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetScatterSkewedPartitions() != nil {
+			return NewScatterSkewedPartitions(
+				toInts(m.GetScatterSkewedPartitions().GetIndexes()),
+			)
+		}
+		return nil
+	})
+}
+
+// ScatterSkewedPartitions partitions rows by key hash,
+// but spreads the rows of hot keys evenly to all partitions.
+// The first input is the data, the second input is the hot keys from SampleHotKeys.
+type ScatterSkewedPartitions struct {
+	indexes []int
+}
+
+func NewScatterSkewedPartitions(indexes []int) *ScatterSkewedPartitions {
+	return &ScatterSkewedPartitions{indexes}
+}
+
+func (b *ScatterSkewedPartitions) Name(prefix string) string {
+	return prefix + ".ScatterSkewedPartitions"
+}
+
+func (b *ScatterSkewedPartitions) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoScatterSkewedPartitions(readers[0], readers[1], writers, b.indexes, stats)
+	}
+}
+
+func (b *ScatterSkewedPartitions) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		ScatterSkewedPartitions: &pb.Instruction_ScatterSkewedPartitions{
+			Indexes: getIndexes(b.indexes),
+		},
+	}
+}
+
+func (b *ScatterSkewedPartitions) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoScatterSkewedPartitions(reader, hotKeysReader io.Reader, writers []io.Writer, indexes []int, stats *pb.InstructionStat) error {
+	shardCount := len(writers)
+
+	// the next partition offset of each hot key
+	hotKeys := make(map[string]int)
+	err := util.ProcessRow(hotKeysReader, nil, func(row *util.Row) error {
+		key, err := util.EncodeKeys(row.K...)
+		if err != nil {
+			return err
+		}
+		hotKeys[string(key)] = 0
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return util.ProcessRow(reader, indexes, func(row *util.Row) error {
+		stats.InputCounter++
+		x := util.PartitionByKeys(shardCount, row.K)
+		if len(hotKeys) > 0 {
+			key, err := util.EncodeKeys(row.K...)
+			if err != nil {
+				return err
+			}
+			if n, isHot := hotKeys[string(key)]; isHot {
+				// round robin the hot key to all partitions
+				x = (x + n) % shardCount
+				hotKeys[string(key)] = (n + 1) % shardCount
+			}
+		}
+		if err := row.WriteTo(writers[x]); err == nil {
+			stats.OutputCounter++
+		}
+		return nil
+	})
+
+}
@@ -30,6 +30,11 @@ func NewSelect(keyIndexes, valueIndexes []int) *Select {
 	return &Select{keyIndexes, valueIndexes}
 }
 
+// Indexes returns the fields, starting from 1, selected as the keys and the values.
+func (b *Select) Indexes() (keyIndexes, valueIndexes []int) {
+	return b.keyIndexes, b.valueIndexes
+}
+
 func (b *Select) Name(prefix string) string {
 	return "Select k:" + joinInts(b.keyIndexes, ",") + " v:" + joinInts(b.valueIndexes, ",")
 }
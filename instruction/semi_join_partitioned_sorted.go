@@ -0,0 +1,114 @@
+package instruction
+
+import (
+	"io"
+
+	"github.com/chrislusf/gleam/pb"
+	"github.com/chrislusf/gleam/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetSemiJoinPartitionedSorted() != nil {
+			return NewSemiJoinPartitionedSorted(
+				m.GetSemiJoinPartitionedSorted().GetIsAntiJoin(),
+				toInts(m.GetSemiJoinPartitionedSorted().GetIndexes()),
+			)
+		}
+		return nil
+	})
+}
+
+// SemiJoinPartitionedSorted outputs the left rows having matching keys on the right side.
+// If isAntiJoin, it outputs the left rows having no matching keys on the right side instead.
+// Only the left rows are output, each one at most once.
+type SemiJoinPartitionedSorted struct {
+	isAntiJoin bool
+	indexes    []int
+}
+
+func NewSemiJoinPartitionedSorted(isAntiJoin bool, indexes []int) *SemiJoinPartitionedSorted {
+	return &SemiJoinPartitionedSorted{isAntiJoin, indexes}
+}
+
+func (b *SemiJoinPartitionedSorted) Name(prefix string) string {
+	if b.isAntiJoin {
+		return prefix + ".AntiJoinPartitionedSorted"
+	}
+	return prefix + ".SemiJoinPartitionedSorted"
+}
+
+func (b *SemiJoinPartitionedSorted) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoSemiJoinPartitionedSorted(readers[0], readers[1], writers[0], b.indexes, b.isAntiJoin, stats)
+	}
+}
+
+func (b *SemiJoinPartitionedSorted) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		SemiJoinPartitionedSorted: &pb.Instruction_SemiJoinPartitionedSorted{
+			IsAntiJoin: (b.isAntiJoin),
+			Indexes:    getIndexes(b.indexes),
+		},
+	}
+}
+
+func (b *SemiJoinPartitionedSorted) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+func DoSemiJoinPartitionedSorted(leftRawChan, rightRawChan io.Reader, writer io.Writer, indexes []int,
+	isAntiJoin bool, stats *pb.InstructionStat) error {
+	leftChan := newChannelOfValuesWithSameKey("left", leftRawChan, indexes)
+	rightChan := newChannelOfValuesWithSameKey("right", rightRawChan, indexes)
+
+	writeLeftValues := func(leftValuesWithSameKey util.Row) {
+		for _, leftValue := range leftValuesWithSameKey.V {
+			util.NewRow(leftValuesWithSameKey.T).AppendKey(
+				leftValuesWithSameKey.K...).AppendValue(
+				leftValue.([]interface{})...).WriteTo(writer)
+			stats.OutputCounter++
+		}
+	}
+
+	// get first value from both channels
+	leftValuesWithSameKey, leftHasValue := <-leftChan
+	rightValuesWithSameKey, rightHasValue := <-rightChan
+
+	for leftHasValue && rightHasValue {
+		x := util.Compare(leftValuesWithSameKey.K, rightValuesWithSameKey.K)
+		switch {
+		case x == 0:
+			if !isAntiJoin {
+				writeLeftValues(leftValuesWithSameKey)
+			}
+			leftValuesWithSameKey, leftHasValue = <-leftChan
+			rightValuesWithSameKey, rightHasValue = <-rightChan
+			stats.InputCounter += 2
+		case x < 0:
+			if isAntiJoin {
+				writeLeftValues(leftValuesWithSameKey)
+			}
+			leftValuesWithSameKey, leftHasValue = <-leftChan
+			stats.InputCounter++
+		case x > 0:
+			rightValuesWithSameKey, rightHasValue = <-rightChan
+			stats.InputCounter++
+		}
+	}
+	if leftHasValue && isAntiJoin {
+		writeLeftValues(leftValuesWithSameKey)
+	}
+	for leftValuesWithSameKey = range leftChan {
+		stats.InputCounter++
+		if isAntiJoin {
+			writeLeftValues(leftValuesWithSameKey)
+		}
+	}
+	for range rightChan {
+		stats.InputCounter++
+	}
+
+	return nil
+
+}
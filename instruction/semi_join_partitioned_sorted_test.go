@@ -0,0 +1,41 @@
+package instruction
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/chrislusf/gleam/pb"
+)
+
+func TestSemiJoinPartitionedSorted(t *testing.T) {
+	left := [][]interface{}{{1, "a"}, {2, "b"}, {2, "c"}, {4, "d"}, {5, "e"}}
+	right := [][]interface{}{{2, "x"}, {2, "y"}, {3, "z"}, {4, "w"}, {4, "v"}}
+
+	tests := []struct {
+		name        string
+		left, right [][]interface{}
+		isAntiJoin  bool
+		expected    []string
+	}{
+		{"semi join with duplicated keys on both sides", left, right, false,
+			[]string{"[2] [b]", "[2] [c]", "[4] [d]"}},
+		{"anti join with duplicated keys on both sides", left, right, true,
+			[]string{"[1] [a]", "[5] [e]"}},
+		{"semi join with empty left side", nil, right, false, nil},
+		{"anti join with empty left side", nil, right, true, nil},
+		{"semi join with empty right side", left, nil, false, nil},
+		{"anti join with empty right side", left, nil, true,
+			[]string{"[1] [a]", "[2] [b]", "[2] [c]", "[4] [d]", "[5] [e]"}},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := DoSemiJoinPartitionedSorted(encodeRows(t, test.left), encodeRows(t, test.right), &out, []int{1},
+			test.isAntiJoin, &pb.InstructionStat{}); err != nil {
+			t.Fatal(err)
+		}
+		if rows := decodeRows(t, &out); !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("%s:\n got %v\nwant %v", test.name, rows, test.expected)
+		}
+	}
+}
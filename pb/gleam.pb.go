@@ -36,6 +36,25 @@ It has these top-level messages:
 	OrderBy
 	DatasetShard
 	DatasetShardLocation
+	PreemptRequest
+	PreemptResponse
+	MasterStateRequest
+	MasterState
+	CancelFlowRequest
+	DrainAgentRequest
+	DrainAgentResponse
+	DrainRequest
+	DrainResponse
+	JobLogsRequest
+	JobLogsResponse
+	TaskLogs
+	GetLogsRequest
+	GetLogsResponse
+	ShufflePartition
+	DatasetLocationsRequest
+	DatasetLocationsResponse
+	CapacityRequest
+	CapacityResponse
 */
 package pb
 
@@ -66,6 +85,9 @@ type ComputeRequest struct {
 	Username         string             `protobuf:"bytes,3,opt,name=username" json:"username,omitempty"`
 	Hostname         string             `protobuf:"bytes,4,opt,name=hostname" json:"hostname,omitempty"`
 	FlowHashCode     uint32             `protobuf:"varint,5,opt,name=flowHashCode" json:"flowHashCode,omitempty"`
+	Queue            string             `protobuf:"bytes,6,opt,name=queue" json:"queue,omitempty"`
+	// locality preference of each compute resource, with only the data center, rack or server set
+	PreferredLocations []*Location `protobuf:"bytes,7,rep,name=preferredLocations" json:"preferredLocations,omitempty"`
 }
 
 func (m *ComputeRequest) Reset()                    { *m = ComputeRequest{} }
@@ -108,6 +130,20 @@ func (m *ComputeRequest) GetFlowHashCode() uint32 {
 	return 0
 }
 
+func (m *ComputeRequest) GetQueue() string {
+	if m != nil {
+		return m.Queue
+	}
+	return ""
+}
+
+func (m *ComputeRequest) GetPreferredLocations() []*Location {
+	if m != nil {
+		return m.PreferredLocations
+	}
+	return nil
+}
+
 type ComputeResource struct {
 	CpuCount int32 `protobuf:"varint,1,opt,name=cpu_count,json=cpuCount" json:"cpu_count,omitempty"`
 	CpuLevel int32 `protobuf:"varint,2,opt,name=cpu_level,json=cpuLevel" json:"cpu_level,omitempty"`
@@ -254,6 +290,8 @@ func (m *Allocation) GetAllocated() *ComputeResource {
 
 type AllocationResult struct {
 	Allocations []*Allocation `protobuf:"bytes,1,rep,name=allocations" json:"allocations,omitempty"`
+	// dataset shards of the flow moved away from drained agents
+	MigratedShards []*DataLocation `protobuf:"bytes,2,rep,name=migratedShards" json:"migratedShards,omitempty"`
 }
 
 func (m *AllocationResult) Reset()                    { *m = AllocationResult{} }
@@ -269,10 +307,22 @@ func (m *AllocationResult) GetAllocations() []*Allocation {
 }
 
 // ////////////////////////////////////////////////
+
+func (m *AllocationResult) GetMigratedShards() []*DataLocation {
+	if m != nil {
+		return m.MigratedShards
+	}
+	return nil
+}
+
 type Heartbeat struct {
 	Location  *Location        `protobuf:"bytes,1,opt,name=location" json:"location,omitempty"`
 	Resource  *ComputeResource `protobuf:"bytes,2,opt,name=resource" json:"resource,omitempty"`
 	Allocated *ComputeResource `protobuf:"bytes,3,opt,name=allocated" json:"allocated,omitempty"`
+	// allocated resources by each flow
+	FlowAllocated []*Heartbeat_FlowAllocated `protobuf:"bytes,4,rep,name=flowAllocated" json:"flowAllocated,omitempty"`
+	// the agent is draining, do not allocate to it
+	Draining bool `protobuf:"varint,5,opt,name=draining" json:"draining,omitempty"`
 }
 
 func (m *Heartbeat) Reset()                    { *m = Heartbeat{} }
@@ -301,6 +351,44 @@ func (m *Heartbeat) GetAllocated() *ComputeResource {
 	return nil
 }
 
+func (m *Heartbeat) GetFlowAllocated() []*Heartbeat_FlowAllocated {
+	if m != nil {
+		return m.FlowAllocated
+	}
+	return nil
+}
+
+func (m *Heartbeat) GetDraining() bool {
+	if m != nil {
+		return m.Draining
+	}
+	return false
+}
+
+type Heartbeat_FlowAllocated struct {
+	FlowHashCode uint32           `protobuf:"varint,1,opt,name=flowHashCode" json:"flowHashCode,omitempty"`
+	Allocated    *ComputeResource `protobuf:"bytes,2,opt,name=allocated" json:"allocated,omitempty"`
+}
+
+func (m *Heartbeat_FlowAllocated) Reset()                    { *m = Heartbeat_FlowAllocated{} }
+func (m *Heartbeat_FlowAllocated) String() string            { return proto.CompactTextString(m) }
+func (*Heartbeat_FlowAllocated) ProtoMessage()               {}
+func (*Heartbeat_FlowAllocated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 0} }
+
+func (m *Heartbeat_FlowAllocated) GetFlowHashCode() uint32 {
+	if m != nil {
+		return m.FlowHashCode
+	}
+	return 0
+}
+
+func (m *Heartbeat_FlowAllocated) GetAllocated() *ComputeResource {
+	if m != nil {
+		return m.Allocated
+	}
+	return nil
+}
+
 type Empty struct {
 }
 
@@ -311,9 +399,10 @@ func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 // ////////////////////////////////////////////////
 type DataLocation struct {
-	Name     string    `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Location *Location `protobuf:"bytes,2,opt,name=location" json:"location,omitempty"`
-	OnDisk   bool      `protobuf:"varint,3,opt,name=onDisk" json:"onDisk,omitempty"`
+	Name     string            `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Location *Location         `protobuf:"bytes,2,opt,name=location" json:"location,omitempty"`
+	OnDisk   bool              `protobuf:"varint,3,opt,name=onDisk" json:"onDisk,omitempty"`
+	Shuffle  *ShufflePartition `protobuf:"bytes,4,opt,name=shuffle" json:"shuffle,omitempty"`
 }
 
 func (m *DataLocation) Reset()                    { *m = DataLocation{} }
@@ -343,6 +432,14 @@ func (m *DataLocation) GetOnDisk() bool {
 }
 
 // ////////////////////////////////////////////////
+
+func (m *DataLocation) GetShuffle() *ShufflePartition {
+	if m != nil {
+		return m.Shuffle
+	}
+	return nil
+}
+
 type FlowExecutionStatus struct {
 	StepGroups    []*FlowExecutionStatus_StepGroup    `protobuf:"bytes,1,rep,name=stepGroups" json:"stepGroups,omitempty"`
 	TaskGroups    []*FlowExecutionStatus_TaskGroup    `protobuf:"bytes,2,rep,name=taskGroups" json:"taskGroups,omitempty"`
@@ -839,6 +936,7 @@ type ExecutionRequest struct {
 	InstructionSet *InstructionSet  `protobuf:"bytes,1,opt,name=instructionSet" json:"instructionSet,omitempty"`
 	Dir            string           `protobuf:"bytes,2,opt,name=dir" json:"dir,omitempty"`
 	Resource       *ComputeResource `protobuf:"bytes,3,opt,name=resource" json:"resource,omitempty"`
+	IsPreemptible  bool             `protobuf:"varint,4,opt,name=isPreemptible" json:"isPreemptible,omitempty"`
 }
 
 func (m *ExecutionRequest) Reset()                    { *m = ExecutionRequest{} }
@@ -867,6 +965,13 @@ func (m *ExecutionRequest) GetResource() *ComputeResource {
 	return nil
 }
 
+func (m *ExecutionRequest) GetIsPreemptible() bool {
+	if m != nil {
+		return m.IsPreemptible
+	}
+	return false
+}
+
 type ExecutionResponse struct {
 	Output        []byte         `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
 	Error         []byte         `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
@@ -940,10 +1045,13 @@ func (m *ExecutionStat) GetStats() []*InstructionStat {
 }
 
 type InstructionStat struct {
-	StepId        int32 `protobuf:"varint,1,opt,name=stepId" json:"stepId,omitempty"`
-	TaskId        int32 `protobuf:"varint,2,opt,name=taskId" json:"taskId,omitempty"`
-	InputCounter  int64 `protobuf:"varint,3,opt,name=inputCounter" json:"inputCounter,omitempty"`
-	OutputCounter int64 `protobuf:"varint,4,opt,name=outputCounter" json:"outputCounter,omitempty"`
+	StepId           int32                        `protobuf:"varint,1,opt,name=stepId" json:"stepId,omitempty"`
+	TaskId           int32                        `protobuf:"varint,2,opt,name=taskId" json:"taskId,omitempty"`
+	InputCounter     int64                        `protobuf:"varint,3,opt,name=inputCounter" json:"inputCounter,omitempty"`
+	OutputCounter    int64                        `protobuf:"varint,4,opt,name=outputCounter" json:"outputCounter,omitempty"`
+	Counters         []*InstructionStat_Counter   `protobuf:"bytes,5,rep,name=counters" json:"counters,omitempty"`
+	Histograms       []*InstructionStat_Histogram `protobuf:"bytes,6,rep,name=histograms" json:"histograms,omitempty"`
+	OutputShardBytes []int64                      `protobuf:"varint,7,rep,packed,name=outputShardBytes" json:"outputShardBytes,omitempty"`
 }
 
 func (m *InstructionStat) Reset()                    { *m = InstructionStat{} }
@@ -979,10 +1087,115 @@ func (m *InstructionStat) GetOutputCounter() int64 {
 	return 0
 }
 
+func (m *InstructionStat) GetCounters() []*InstructionStat_Counter {
+	if m != nil {
+		return m.Counters
+	}
+	return nil
+}
+
+func (m *InstructionStat) GetHistograms() []*InstructionStat_Histogram {
+	if m != nil {
+		return m.Histograms
+	}
+	return nil
+}
+
+func (m *InstructionStat) GetOutputShardBytes() []int64 {
+	if m != nil {
+		return m.OutputShardBytes
+	}
+	return nil
+}
+
+type InstructionStat_Counter struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value int64  `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *InstructionStat_Counter) Reset()                    { *m = InstructionStat_Counter{} }
+func (m *InstructionStat_Counter) String() string            { return proto.CompactTextString(m) }
+func (*InstructionStat_Counter) ProtoMessage()               {}
+func (*InstructionStat_Counter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15, 0} }
+
+func (m *InstructionStat_Counter) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InstructionStat_Counter) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type InstructionStat_Histogram struct {
+	Name  string  `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Count int64   `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+	Sum   float64 `protobuf:"fixed64,3,opt,name=sum" json:"sum,omitempty"`
+	Min   float64 `protobuf:"fixed64,4,opt,name=min" json:"min,omitempty"`
+	Max   float64 `protobuf:"fixed64,5,opt,name=max" json:"max,omitempty"`
+	// buckets[0] counts values below 1, buckets[i] counts values in [2^(i-1), 2^i)
+	Buckets []int64 `protobuf:"varint,6,rep,packed,name=buckets" json:"buckets,omitempty"`
+}
+
+func (m *InstructionStat_Histogram) Reset()                    { *m = InstructionStat_Histogram{} }
+func (m *InstructionStat_Histogram) String() string            { return proto.CompactTextString(m) }
+func (*InstructionStat_Histogram) ProtoMessage()               {}
+func (*InstructionStat_Histogram) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15, 1} }
+
+func (m *InstructionStat_Histogram) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InstructionStat_Histogram) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *InstructionStat_Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *InstructionStat_Histogram) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *InstructionStat_Histogram) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *InstructionStat_Histogram) GetBuckets() []int64 {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
+}
+
 type ControlMessage struct {
-	IsOnDiskIO   bool          `protobuf:"varint,1,opt,name=isOnDiskIO" json:"isOnDiskIO,omitempty"`
-	ReadRequest  *ReadRequest  `protobuf:"bytes,2,opt,name=readRequest" json:"readRequest,omitempty"`
-	WriteRequest *WriteRequest `protobuf:"bytes,3,opt,name=writeRequest" json:"writeRequest,omitempty"`
+	IsOnDiskIO    bool              `protobuf:"varint,1,opt,name=isOnDiskIO" json:"isOnDiskIO,omitempty"`
+	ReadRequest   *ReadRequest      `protobuf:"bytes,2,opt,name=readRequest" json:"readRequest,omitempty"`
+	WriteRequest  *WriteRequest     `protobuf:"bytes,3,opt,name=writeRequest" json:"writeRequest,omitempty"`
+	Compression   string            `protobuf:"bytes,4,opt,name=compression" json:"compression,omitempty"`
+	Shuffle       *ShufflePartition `protobuf:"bytes,5,opt,name=shuffle" json:"shuffle,omitempty"`
+	Authorization string            `protobuf:"bytes,6,opt,name=authorization" json:"authorization,omitempty"`
 }
 
 func (m *ControlMessage) Reset()                    { *m = ControlMessage{} }
@@ -1011,6 +1224,27 @@ func (m *ControlMessage) GetWriteRequest() *WriteRequest {
 	return nil
 }
 
+func (m *ControlMessage) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *ControlMessage) GetShuffle() *ShufflePartition {
+	if m != nil {
+		return m.Shuffle
+	}
+	return nil
+}
+
+func (m *ControlMessage) GetAuthorization() string {
+	if m != nil {
+		return m.Authorization
+	}
+	return ""
+}
+
 type DeleteDatasetShardRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 }
@@ -1108,8 +1342,9 @@ func (m *WriteRequest) GetReaderCount() int32 {
 }
 
 type ReadRequest struct {
-	ChannelName string `protobuf:"bytes,1,opt,name=channelName" json:"channelName,omitempty"`
-	ReaderName  string `protobuf:"bytes,2,opt,name=readerName" json:"readerName,omitempty"`
+	ChannelName  string `protobuf:"bytes,1,opt,name=channelName" json:"channelName,omitempty"`
+	ReaderName   string `protobuf:"bytes,2,opt,name=readerName" json:"readerName,omitempty"`
+	IsInspecting bool   `protobuf:"varint,3,opt,name=isInspecting" json:"isInspecting,omitempty"`
 }
 
 func (m *ReadRequest) Reset()                    { *m = ReadRequest{} }
@@ -1131,6 +1366,13 @@ func (m *ReadRequest) GetReaderName() string {
 	return ""
 }
 
+func (m *ReadRequest) GetIsInspecting() bool {
+	if m != nil {
+		return m.IsInspecting
+	}
+	return false
+}
+
 type InstructionSet struct {
 	Instructions []*Instruction `protobuf:"bytes,1,rep,name=instructions" json:"instructions,omitempty"`
 	ReaderCount  int32          `protobuf:"varint,2,opt,name=readerCount" json:"readerCount,omitempty"`
@@ -1138,6 +1380,7 @@ type InstructionSet struct {
 	IsProfiling  bool           `protobuf:"varint,4,opt,name=isProfiling" json:"isProfiling,omitempty"`
 	AgentAddress string         `protobuf:"bytes,5,opt,name=agentAddress" json:"agentAddress,omitempty"`
 	Name         string         `protobuf:"bytes,6,opt,name=name" json:"name,omitempty"`
+	Compression  string         `protobuf:"bytes,7,opt,name=compression" json:"compression,omitempty"`
 }
 
 func (m *InstructionSet) Reset()                    { *m = InstructionSet{} }
@@ -1187,31 +1430,46 @@ func (m *InstructionSet) GetName() string {
 	return ""
 }
 
+func (m *InstructionSet) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
 type Instruction struct {
-	StepId                   int32                                 `protobuf:"varint,1,opt,name=stepId" json:"stepId,omitempty"`
-	TaskId                   int32                                 `protobuf:"varint,2,opt,name=taskId" json:"taskId,omitempty"`
-	MemoryInMB               int32                                 `protobuf:"varint,3,opt,name=memoryInMB" json:"memoryInMB,omitempty"`
-	InputShardLocations      []*DatasetShardLocation               `protobuf:"bytes,4,rep,name=inputShardLocations" json:"inputShardLocations,omitempty"`
-	OutputShardLocations     []*DatasetShardLocation               `protobuf:"bytes,5,rep,name=OutputShardLocations" json:"OutputShardLocations,omitempty"`
-	Select                   *Instruction_Select                   `protobuf:"bytes,6,opt,name=select" json:"select,omitempty"`
-	JoinPartitionedSorted    *Instruction_JoinPartitionedSorted    `protobuf:"bytes,7,opt,name=joinPartitionedSorted" json:"joinPartitionedSorted,omitempty"`
-	CoGroupPartitionedSorted *Instruction_CoGroupPartitionedSorted `protobuf:"bytes,8,opt,name=coGroupPartitionedSorted" json:"coGroupPartitionedSorted,omitempty"`
-	PipeAsArgs               *Instruction_PipeAsArgs               `protobuf:"bytes,9,opt,name=pipeAsArgs" json:"pipeAsArgs,omitempty"`
-	ScatterPartitions        *Instruction_ScatterPartitions        `protobuf:"bytes,10,opt,name=scatterPartitions" json:"scatterPartitions,omitempty"`
-	CollectPartitions        *Instruction_CollectPartitions        `protobuf:"bytes,11,opt,name=collectPartitions" json:"collectPartitions,omitempty"`
-	InputSplitReader         *Instruction_InputSplitReader         `protobuf:"bytes,12,opt,name=inputSplitReader" json:"inputSplitReader,omitempty"`
-	RoundRobin               *Instruction_RoundRobin               `protobuf:"bytes,13,opt,name=roundRobin" json:"roundRobin,omitempty"`
-	LocalTop                 *Instruction_LocalTop                 `protobuf:"bytes,14,opt,name=localTop" json:"localTop,omitempty"`
-	Broadcast                *Instruction_Broadcast                `protobuf:"bytes,15,opt,name=broadcast" json:"broadcast,omitempty"`
-	LocalHashAndJoinWith     *Instruction_LocalHashAndJoinWith     `protobuf:"bytes,16,opt,name=localHashAndJoinWith" json:"localHashAndJoinWith,omitempty"`
-	Script                   *Instruction_Script                   `protobuf:"bytes,17,opt,name=script" json:"script,omitempty"`
-	LocalSort                *Instruction_LocalSort                `protobuf:"bytes,18,opt,name=localSort" json:"localSort,omitempty"`
-	MergeSortedTo            *Instruction_MergeSortedTo            `protobuf:"bytes,19,opt,name=mergeSortedTo" json:"mergeSortedTo,omitempty"`
-	MergeTo                  *Instruction_MergeTo                  `protobuf:"bytes,20,opt,name=mergeTo" json:"mergeTo,omitempty"`
-	LocalDistinct            *Instruction_LocalDistinct            `protobuf:"bytes,21,opt,name=localDistinct" json:"localDistinct,omitempty"`
-	LocalLimit               *Instruction_LocalLimit               `protobuf:"bytes,22,opt,name=localLimit" json:"localLimit,omitempty"`
-	LocalGroupBySorted       *Instruction_LocalGroupBySorted       `protobuf:"bytes,23,opt,name=localGroupBySorted" json:"localGroupBySorted,omitempty"`
-	Union                    *Instruction_Union                    `protobuf:"bytes,24,opt,name=union" json:"union,omitempty"`
+	StepId                      int32                                    `protobuf:"varint,1,opt,name=stepId" json:"stepId,omitempty"`
+	TaskId                      int32                                    `protobuf:"varint,2,opt,name=taskId" json:"taskId,omitempty"`
+	MemoryInMB                  int32                                    `protobuf:"varint,3,opt,name=memoryInMB" json:"memoryInMB,omitempty"`
+	InputShardLocations         []*DatasetShardLocation                  `protobuf:"bytes,4,rep,name=inputShardLocations" json:"inputShardLocations,omitempty"`
+	OutputShardLocations        []*DatasetShardLocation                  `protobuf:"bytes,5,rep,name=OutputShardLocations" json:"OutputShardLocations,omitempty"`
+	Select                      *Instruction_Select                      `protobuf:"bytes,6,opt,name=select" json:"select,omitempty"`
+	JoinPartitionedSorted       *Instruction_JoinPartitionedSorted       `protobuf:"bytes,7,opt,name=joinPartitionedSorted" json:"joinPartitionedSorted,omitempty"`
+	CoGroupPartitionedSorted    *Instruction_CoGroupPartitionedSorted    `protobuf:"bytes,8,opt,name=coGroupPartitionedSorted" json:"coGroupPartitionedSorted,omitempty"`
+	PipeAsArgs                  *Instruction_PipeAsArgs                  `protobuf:"bytes,9,opt,name=pipeAsArgs" json:"pipeAsArgs,omitempty"`
+	ScatterPartitions           *Instruction_ScatterPartitions           `protobuf:"bytes,10,opt,name=scatterPartitions" json:"scatterPartitions,omitempty"`
+	CollectPartitions           *Instruction_CollectPartitions           `protobuf:"bytes,11,opt,name=collectPartitions" json:"collectPartitions,omitempty"`
+	InputSplitReader            *Instruction_InputSplitReader            `protobuf:"bytes,12,opt,name=inputSplitReader" json:"inputSplitReader,omitempty"`
+	RoundRobin                  *Instruction_RoundRobin                  `protobuf:"bytes,13,opt,name=roundRobin" json:"roundRobin,omitempty"`
+	LocalTop                    *Instruction_LocalTop                    `protobuf:"bytes,14,opt,name=localTop" json:"localTop,omitempty"`
+	Broadcast                   *Instruction_Broadcast                   `protobuf:"bytes,15,opt,name=broadcast" json:"broadcast,omitempty"`
+	LocalHashAndJoinWith        *Instruction_LocalHashAndJoinWith        `protobuf:"bytes,16,opt,name=localHashAndJoinWith" json:"localHashAndJoinWith,omitempty"`
+	Script                      *Instruction_Script                      `protobuf:"bytes,17,opt,name=script" json:"script,omitempty"`
+	LocalSort                   *Instruction_LocalSort                   `protobuf:"bytes,18,opt,name=localSort" json:"localSort,omitempty"`
+	MergeSortedTo               *Instruction_MergeSortedTo               `protobuf:"bytes,19,opt,name=mergeSortedTo" json:"mergeSortedTo,omitempty"`
+	MergeTo                     *Instruction_MergeTo                     `protobuf:"bytes,20,opt,name=mergeTo" json:"mergeTo,omitempty"`
+	LocalDistinct               *Instruction_LocalDistinct               `protobuf:"bytes,21,opt,name=localDistinct" json:"localDistinct,omitempty"`
+	LocalLimit                  *Instruction_LocalLimit                  `protobuf:"bytes,22,opt,name=localLimit" json:"localLimit,omitempty"`
+	LocalGroupBySorted          *Instruction_LocalGroupBySorted          `protobuf:"bytes,23,opt,name=localGroupBySorted" json:"localGroupBySorted,omitempty"`
+	Union                       *Instruction_Union                       `protobuf:"bytes,24,opt,name=union" json:"union,omitempty"`
+	ScatterSkewedPartitions     *Instruction_ScatterSkewedPartitions     `protobuf:"bytes,25,opt,name=scatterSkewedPartitions" json:"scatterSkewedPartitions,omitempty"`
+	SampleHotKeys               *Instruction_SampleHotKeys               `protobuf:"bytes,26,opt,name=sampleHotKeys" json:"sampleHotKeys,omitempty"`
+	ScatterReplicatedPartitions *Instruction_ScatterReplicatedPartitions `protobuf:"bytes,27,opt,name=scatterReplicatedPartitions" json:"scatterReplicatedPartitions,omitempty"`
+	SampleSortKeys              *Instruction_SampleSortKeys              `protobuf:"bytes,28,opt,name=sampleSortKeys" json:"sampleSortKeys,omitempty"`
+	ComputeSplitPoints          *Instruction_ComputeSplitPoints          `protobuf:"bytes,29,opt,name=computeSplitPoints" json:"computeSplitPoints,omitempty"`
+	ScatterRanges               *Instruction_ScatterRanges               `protobuf:"bytes,30,opt,name=scatterRanges" json:"scatterRanges,omitempty"`
+	SemiJoinPartitionedSorted   *Instruction_SemiJoinPartitionedSorted   `protobuf:"bytes,31,opt,name=semiJoinPartitionedSorted" json:"semiJoinPartitionedSorted,omitempty"`
+	JoinPartitionedHashed       *Instruction_JoinPartitionedHashed       `protobuf:"bytes,32,opt,name=joinPartitionedHashed" json:"joinPartitionedHashed,omitempty"`
 }
 
 func (m *Instruction) Reset()                    { *m = Instruction{} }
@@ -1387,6 +1645,62 @@ func (m *Instruction) GetUnion() *Instruction_Union {
 	return nil
 }
 
+func (m *Instruction) GetScatterSkewedPartitions() *Instruction_ScatterSkewedPartitions {
+	if m != nil {
+		return m.ScatterSkewedPartitions
+	}
+	return nil
+}
+
+func (m *Instruction) GetSampleHotKeys() *Instruction_SampleHotKeys {
+	if m != nil {
+		return m.SampleHotKeys
+	}
+	return nil
+}
+
+func (m *Instruction) GetScatterReplicatedPartitions() *Instruction_ScatterReplicatedPartitions {
+	if m != nil {
+		return m.ScatterReplicatedPartitions
+	}
+	return nil
+}
+
+func (m *Instruction) GetSampleSortKeys() *Instruction_SampleSortKeys {
+	if m != nil {
+		return m.SampleSortKeys
+	}
+	return nil
+}
+
+func (m *Instruction) GetComputeSplitPoints() *Instruction_ComputeSplitPoints {
+	if m != nil {
+		return m.ComputeSplitPoints
+	}
+	return nil
+}
+
+func (m *Instruction) GetScatterRanges() *Instruction_ScatterRanges {
+	if m != nil {
+		return m.ScatterRanges
+	}
+	return nil
+}
+
+func (m *Instruction) GetSemiJoinPartitionedSorted() *Instruction_SemiJoinPartitionedSorted {
+	if m != nil {
+		return m.SemiJoinPartitionedSorted
+	}
+	return nil
+}
+
+func (m *Instruction) GetJoinPartitionedHashed() *Instruction_JoinPartitionedHashed {
+	if m != nil {
+		return m.JoinPartitionedHashed
+	}
+	return nil
+}
+
 type Instruction_Select struct {
 	KeyIndexes   []int32 `protobuf:"varint,1,rep,packed,name=keyIndexes" json:"keyIndexes,omitempty"`
 	ValueIndexes []int32 `protobuf:"varint,2,rep,packed,name=valueIndexes" json:"valueIndexes,omitempty"`
@@ -1737,108 +2051,847 @@ func (m *Instruction_Union) GetIsParallel() bool {
 	return false
 }
 
-type OrderBy struct {
-	Index int32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
-	Order int32 `protobuf:"varint,2,opt,name=order" json:"order,omitempty"`
+type Instruction_ScatterSkewedPartitions struct {
+	Indexes []int32 `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
 }
 
-func (m *OrderBy) Reset()                    { *m = OrderBy{} }
-func (m *OrderBy) String() string            { return proto.CompactTextString(m) }
-func (*OrderBy) ProtoMessage()               {}
-func (*OrderBy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
-
-func (m *OrderBy) GetIndex() int32 {
-	if m != nil {
-		return m.Index
-	}
-	return 0
+func (m *Instruction_ScatterSkewedPartitions) Reset()         { *m = Instruction_ScatterSkewedPartitions{} }
+func (m *Instruction_ScatterSkewedPartitions) String() string { return proto.CompactTextString(m) }
+func (*Instruction_ScatterSkewedPartitions) ProtoMessage()    {}
+func (*Instruction_ScatterSkewedPartitions) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{24, 19}
 }
 
-func (m *OrderBy) GetOrder() int32 {
+func (m *Instruction_ScatterSkewedPartitions) GetIndexes() []int32 {
 	if m != nil {
-		return m.Order
+		return m.Indexes
 	}
-	return 0
+	return nil
 }
 
-type DatasetShard struct {
-	FlowName       string `protobuf:"bytes,1,opt,name=FlowName" json:"FlowName,omitempty"`
-	DatasetId      int32  `protobuf:"varint,2,opt,name=DatasetId" json:"DatasetId,omitempty"`
-	DatasetShardId int32  `protobuf:"varint,3,opt,name=DatasetShardId" json:"DatasetShardId,omitempty"`
-	FlowHashCode   uint32 `protobuf:"varint,4,opt,name=FlowHashCode" json:"FlowHashCode,omitempty"`
+type Instruction_SampleHotKeys struct {
+	Indexes     []int32 `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
+	SampleSize  int32   `protobuf:"varint,2,opt,name=sampleSize" json:"sampleSize,omitempty"`
+	HotKeyRatio float64 `protobuf:"fixed64,3,opt,name=hotKeyRatio" json:"hotKeyRatio,omitempty"`
 }
 
-func (m *DatasetShard) Reset()                    { *m = DatasetShard{} }
-func (m *DatasetShard) String() string            { return proto.CompactTextString(m) }
-func (*DatasetShard) ProtoMessage()               {}
-func (*DatasetShard) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (m *Instruction_SampleHotKeys) Reset()                    { *m = Instruction_SampleHotKeys{} }
+func (m *Instruction_SampleHotKeys) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_SampleHotKeys) ProtoMessage()               {}
+func (*Instruction_SampleHotKeys) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24, 20} }
 
-func (m *DatasetShard) GetFlowName() string {
+func (m *Instruction_SampleHotKeys) GetIndexes() []int32 {
 	if m != nil {
-		return m.FlowName
+		return m.Indexes
 	}
-	return ""
+	return nil
 }
 
-func (m *DatasetShard) GetDatasetId() int32 {
+func (m *Instruction_SampleHotKeys) GetSampleSize() int32 {
 	if m != nil {
-		return m.DatasetId
+		return m.SampleSize
 	}
 	return 0
 }
 
-func (m *DatasetShard) GetDatasetShardId() int32 {
+func (m *Instruction_SampleHotKeys) GetHotKeyRatio() float64 {
 	if m != nil {
-		return m.DatasetShardId
+		return m.HotKeyRatio
 	}
 	return 0
 }
 
-func (m *DatasetShard) GetFlowHashCode() uint32 {
-	if m != nil {
-		return m.FlowHashCode
-	}
-	return 0
+type Instruction_ScatterReplicatedPartitions struct {
+	Indexes []int32 `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
 }
 
-type DatasetShardLocation struct {
-	Name   string `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
-	Host   string `protobuf:"bytes,2,opt,name=Host" json:"Host,omitempty"`
-	Port   int32  `protobuf:"varint,3,opt,name=Port" json:"Port,omitempty"`
-	OnDisk bool   `protobuf:"varint,4,opt,name=onDisk" json:"onDisk,omitempty"`
+func (m *Instruction_ScatterReplicatedPartitions) Reset() {
+	*m = Instruction_ScatterReplicatedPartitions{}
+}
+func (m *Instruction_ScatterReplicatedPartitions) String() string { return proto.CompactTextString(m) }
+func (*Instruction_ScatterReplicatedPartitions) ProtoMessage()    {}
+func (*Instruction_ScatterReplicatedPartitions) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{24, 21}
 }
 
-func (m *DatasetShardLocation) Reset()                    { *m = DatasetShardLocation{} }
-func (m *DatasetShardLocation) String() string            { return proto.CompactTextString(m) }
-func (*DatasetShardLocation) ProtoMessage()               {}
-func (*DatasetShardLocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
-
-func (m *DatasetShardLocation) GetName() string {
+func (m *Instruction_ScatterReplicatedPartitions) GetIndexes() []int32 {
 	if m != nil {
-		return m.Name
+		return m.Indexes
 	}
-	return ""
+	return nil
 }
 
-func (m *DatasetShardLocation) GetHost() string {
+type Instruction_SampleSortKeys struct {
+	OrderBys   []*OrderBy `protobuf:"bytes,1,rep,name=orderBys" json:"orderBys,omitempty"`
+	SampleSize int32      `protobuf:"varint,2,opt,name=sampleSize" json:"sampleSize,omitempty"`
+}
+
+func (m *Instruction_SampleSortKeys) Reset()         { *m = Instruction_SampleSortKeys{} }
+func (m *Instruction_SampleSortKeys) String() string { return proto.CompactTextString(m) }
+func (*Instruction_SampleSortKeys) ProtoMessage()    {}
+func (*Instruction_SampleSortKeys) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{24, 22}
+}
+
+func (m *Instruction_SampleSortKeys) GetOrderBys() []*OrderBy {
 	if m != nil {
-		return m.Host
+		return m.OrderBys
 	}
-	return ""
+	return nil
 }
 
-func (m *DatasetShardLocation) GetPort() int32 {
+func (m *Instruction_SampleSortKeys) GetSampleSize() int32 {
 	if m != nil {
-		return m.Port
+		return m.SampleSize
 	}
 	return 0
 }
 
-func (m *DatasetShardLocation) GetOnDisk() bool {
+type Instruction_ComputeSplitPoints struct {
+	OrderBys       []*OrderBy `protobuf:"bytes,1,rep,name=orderBys" json:"orderBys,omitempty"`
+	PartitionCount int32      `protobuf:"varint,2,opt,name=partitionCount" json:"partitionCount,omitempty"`
+}
+
+func (m *Instruction_ComputeSplitPoints) Reset()         { *m = Instruction_ComputeSplitPoints{} }
+func (m *Instruction_ComputeSplitPoints) String() string { return proto.CompactTextString(m) }
+func (*Instruction_ComputeSplitPoints) ProtoMessage()    {}
+func (*Instruction_ComputeSplitPoints) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{24, 23}
+}
+
+func (m *Instruction_ComputeSplitPoints) GetOrderBys() []*OrderBy {
 	if m != nil {
-		return m.OnDisk
+		return m.OrderBys
 	}
-	return false
+	return nil
+}
+
+func (m *Instruction_ComputeSplitPoints) GetPartitionCount() int32 {
+	if m != nil {
+		return m.PartitionCount
+	}
+	return 0
+}
+
+type Instruction_ScatterRanges struct {
+	OrderBys []*OrderBy `protobuf:"bytes,1,rep,name=orderBys" json:"orderBys,omitempty"`
+}
+
+func (m *Instruction_ScatterRanges) Reset()                    { *m = Instruction_ScatterRanges{} }
+func (m *Instruction_ScatterRanges) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_ScatterRanges) ProtoMessage()               {}
+func (*Instruction_ScatterRanges) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24, 24} }
+
+func (m *Instruction_ScatterRanges) GetOrderBys() []*OrderBy {
+	if m != nil {
+		return m.OrderBys
+	}
+	return nil
+}
+
+type Instruction_SemiJoinPartitionedSorted struct {
+	Indexes    []int32 `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
+	IsAntiJoin bool    `protobuf:"varint,2,opt,name=isAntiJoin" json:"isAntiJoin,omitempty"`
+}
+
+func (m *Instruction_SemiJoinPartitionedSorted) Reset()         { *m = Instruction_SemiJoinPartitionedSorted{} }
+func (m *Instruction_SemiJoinPartitionedSorted) String() string { return proto.CompactTextString(m) }
+func (*Instruction_SemiJoinPartitionedSorted) ProtoMessage()    {}
+func (*Instruction_SemiJoinPartitionedSorted) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{24, 25}
+}
+
+func (m *Instruction_SemiJoinPartitionedSorted) GetIndexes() []int32 {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+func (m *Instruction_SemiJoinPartitionedSorted) GetIsAntiJoin() bool {
+	if m != nil {
+		return m.IsAntiJoin
+	}
+	return false
+}
+
+type Instruction_JoinPartitionedHashed struct {
+	Indexes          []int32 `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
+	IsLeftOuterJoin  bool    `protobuf:"varint,2,opt,name=isLeftOuterJoin" json:"isLeftOuterJoin,omitempty"`
+	IsRightOuterJoin bool    `protobuf:"varint,3,opt,name=isRightOuterJoin" json:"isRightOuterJoin,omitempty"`
+	IsLeftHashed     bool    `protobuf:"varint,4,opt,name=isLeftHashed" json:"isLeftHashed,omitempty"`
+}
+
+func (m *Instruction_JoinPartitionedHashed) Reset()         { *m = Instruction_JoinPartitionedHashed{} }
+func (m *Instruction_JoinPartitionedHashed) String() string { return proto.CompactTextString(m) }
+func (*Instruction_JoinPartitionedHashed) ProtoMessage()    {}
+func (*Instruction_JoinPartitionedHashed) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{24, 26}
+}
+
+func (m *Instruction_JoinPartitionedHashed) GetIndexes() []int32 {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+func (m *Instruction_JoinPartitionedHashed) GetIsLeftOuterJoin() bool {
+	if m != nil {
+		return m.IsLeftOuterJoin
+	}
+	return false
+}
+
+func (m *Instruction_JoinPartitionedHashed) GetIsRightOuterJoin() bool {
+	if m != nil {
+		return m.IsRightOuterJoin
+	}
+	return false
+}
+
+func (m *Instruction_JoinPartitionedHashed) GetIsLeftHashed() bool {
+	if m != nil {
+		return m.IsLeftHashed
+	}
+	return false
+}
+
+type OrderBy struct {
+	Index int32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Order int32 `protobuf:"varint,2,opt,name=order" json:"order,omitempty"`
+}
+
+func (m *OrderBy) Reset()                    { *m = OrderBy{} }
+func (m *OrderBy) String() string            { return proto.CompactTextString(m) }
+func (*OrderBy) ProtoMessage()               {}
+func (*OrderBy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *OrderBy) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *OrderBy) GetOrder() int32 {
+	if m != nil {
+		return m.Order
+	}
+	return 0
+}
+
+type DatasetShard struct {
+	FlowName       string `protobuf:"bytes,1,opt,name=FlowName" json:"FlowName,omitempty"`
+	DatasetId      int32  `protobuf:"varint,2,opt,name=DatasetId" json:"DatasetId,omitempty"`
+	DatasetShardId int32  `protobuf:"varint,3,opt,name=DatasetShardId" json:"DatasetShardId,omitempty"`
+	FlowHashCode   uint32 `protobuf:"varint,4,opt,name=FlowHashCode" json:"FlowHashCode,omitempty"`
+}
+
+func (m *DatasetShard) Reset()                    { *m = DatasetShard{} }
+func (m *DatasetShard) String() string            { return proto.CompactTextString(m) }
+func (*DatasetShard) ProtoMessage()               {}
+func (*DatasetShard) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *DatasetShard) GetFlowName() string {
+	if m != nil {
+		return m.FlowName
+	}
+	return ""
+}
+
+func (m *DatasetShard) GetDatasetId() int32 {
+	if m != nil {
+		return m.DatasetId
+	}
+	return 0
+}
+
+func (m *DatasetShard) GetDatasetShardId() int32 {
+	if m != nil {
+		return m.DatasetShardId
+	}
+	return 0
+}
+
+func (m *DatasetShard) GetFlowHashCode() uint32 {
+	if m != nil {
+		return m.FlowHashCode
+	}
+	return 0
+}
+
+type DatasetShardLocation struct {
+	Name    string            `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
+	Host    string            `protobuf:"bytes,2,opt,name=Host" json:"Host,omitempty"`
+	Port    int32             `protobuf:"varint,3,opt,name=Port" json:"Port,omitempty"`
+	OnDisk  bool              `protobuf:"varint,4,opt,name=onDisk" json:"onDisk,omitempty"`
+	Shuffle *ShufflePartition `protobuf:"bytes,5,opt,name=shuffle" json:"shuffle,omitempty"`
+}
+
+func (m *DatasetShardLocation) Reset()                    { *m = DatasetShardLocation{} }
+func (m *DatasetShardLocation) String() string            { return proto.CompactTextString(m) }
+func (*DatasetShardLocation) ProtoMessage()               {}
+func (*DatasetShardLocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *DatasetShardLocation) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DatasetShardLocation) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *DatasetShardLocation) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *DatasetShardLocation) GetOnDisk() bool {
+	if m != nil {
+		return m.OnDisk
+	}
+	return false
+}
+
+func (m *DatasetShardLocation) GetShuffle() *ShufflePartition {
+	if m != nil {
+		return m.Shuffle
+	}
+	return nil
+}
+
+type PreemptRequest struct {
+	FlowHashCode uint32           `protobuf:"varint,1,opt,name=flowHashCode" json:"flowHashCode,omitempty"`
+	Resource     *ComputeResource `protobuf:"bytes,2,opt,name=resource" json:"resource,omitempty"`
+}
+
+func (m *PreemptRequest) Reset()                    { *m = PreemptRequest{} }
+func (m *PreemptRequest) String() string            { return proto.CompactTextString(m) }
+func (*PreemptRequest) ProtoMessage()               {}
+func (*PreemptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *PreemptRequest) GetFlowHashCode() uint32 {
+	if m != nil {
+		return m.FlowHashCode
+	}
+	return 0
+}
+
+func (m *PreemptRequest) GetResource() *ComputeResource {
+	if m != nil {
+		return m.Resource
+	}
+	return nil
+}
+
+type PreemptResponse struct {
+	Preempted *ComputeResource `protobuf:"bytes,1,opt,name=preempted" json:"preempted,omitempty"`
+}
+
+func (m *PreemptResponse) Reset()                    { *m = PreemptResponse{} }
+func (m *PreemptResponse) String() string            { return proto.CompactTextString(m) }
+func (*PreemptResponse) ProtoMessage()               {}
+func (*PreemptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *PreemptResponse) GetPreempted() *ComputeResource {
+	if m != nil {
+		return m.Preempted
+	}
+	return nil
+}
+
+type MasterStateRequest struct {
+	SinceVersion uint64 `protobuf:"varint,1,opt,name=sinceVersion" json:"sinceVersion,omitempty"`
+}
+
+func (m *MasterStateRequest) Reset()                    { *m = MasterStateRequest{} }
+func (m *MasterStateRequest) String() string            { return proto.CompactTextString(m) }
+func (*MasterStateRequest) ProtoMessage()               {}
+func (*MasterStateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *MasterStateRequest) GetSinceVersion() uint64 {
+	if m != nil {
+		return m.SinceVersion
+	}
+	return 0
+}
+
+type MasterState struct {
+	IsActive bool                  `protobuf:"varint,1,opt,name=isActive" json:"isActive,omitempty"`
+	Version  uint64                `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Records  []*MasterState_Record `protobuf:"bytes,3,rep,name=records" json:"records,omitempty"`
+	// to decide the active master when both masters start as standby
+	StartTime  int64  `protobuf:"varint,4,opt,name=startTime" json:"startTime,omitempty"`
+	Term       uint64 `protobuf:"varint,5,opt,name=term" json:"term,omitempty"`
+	IsSnapshot bool   `protobuf:"varint,6,opt,name=isSnapshot" json:"isSnapshot,omitempty"`
+}
+
+func (m *MasterState) Reset()                    { *m = MasterState{} }
+func (m *MasterState) String() string            { return proto.CompactTextString(m) }
+func (*MasterState) ProtoMessage()               {}
+func (*MasterState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *MasterState) GetIsActive() bool {
+	if m != nil {
+		return m.IsActive
+	}
+	return false
+}
+
+func (m *MasterState) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *MasterState) GetRecords() []*MasterState_Record {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+func (m *MasterState) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *MasterState) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *MasterState) GetIsSnapshot() bool {
+	if m != nil {
+		return m.IsSnapshot
+	}
+	return false
+}
+
+type MasterState_Record struct {
+	Bucket  string `protobuf:"bytes,1,opt,name=bucket" json:"bucket,omitempty"`
+	Key     []byte `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	Value   []byte `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
+	Deleted bool   `protobuf:"varint,4,opt,name=deleted" json:"deleted,omitempty"`
+}
+
+func (m *MasterState_Record) Reset()                    { *m = MasterState_Record{} }
+func (m *MasterState_Record) String() string            { return proto.CompactTextString(m) }
+func (*MasterState_Record) ProtoMessage()               {}
+func (*MasterState_Record) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31, 0} }
+
+func (m *MasterState_Record) GetBucket() string {
+	if m != nil {
+		return m.Bucket
+	}
+	return ""
+}
+
+func (m *MasterState_Record) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *MasterState_Record) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *MasterState_Record) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+type CancelFlowRequest struct {
+	Id uint32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *CancelFlowRequest) Reset()                    { *m = CancelFlowRequest{} }
+func (m *CancelFlowRequest) String() string            { return proto.CompactTextString(m) }
+func (*CancelFlowRequest) ProtoMessage()               {}
+func (*CancelFlowRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *CancelFlowRequest) GetId() uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type DrainAgentRequest struct {
+	Agent   string `protobuf:"bytes,1,opt,name=agent" json:"agent,omitempty"`
+	Migrate bool   `protobuf:"varint,2,opt,name=migrate" json:"migrate,omitempty"`
+}
+
+func (m *DrainAgentRequest) Reset()                    { *m = DrainAgentRequest{} }
+func (m *DrainAgentRequest) String() string            { return proto.CompactTextString(m) }
+func (*DrainAgentRequest) ProtoMessage()               {}
+func (*DrainAgentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *DrainAgentRequest) GetAgent() string {
+	if m != nil {
+		return m.Agent
+	}
+	return ""
+}
+
+func (m *DrainAgentRequest) GetMigrate() bool {
+	if m != nil {
+		return m.Migrate
+	}
+	return false
+}
+
+type DrainAgentResponse struct {
+	MigratedShards []*DataLocation `protobuf:"bytes,1,rep,name=migratedShards" json:"migratedShards,omitempty"`
+}
+
+func (m *DrainAgentResponse) Reset()                    { *m = DrainAgentResponse{} }
+func (m *DrainAgentResponse) String() string            { return proto.CompactTextString(m) }
+func (*DrainAgentResponse) ProtoMessage()               {}
+func (*DrainAgentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *DrainAgentResponse) GetMigratedShards() []*DataLocation {
+	if m != nil {
+		return m.MigratedShards
+	}
+	return nil
+}
+
+type DrainRequest struct {
+	Migrate       bool        `protobuf:"varint,1,opt,name=migrate" json:"migrate,omitempty"`
+	FlowHashCodes []uint32    `protobuf:"varint,2,rep,packed,name=flowHashCodes" json:"flowHashCodes,omitempty"`
+	Targets       []*Location `protobuf:"bytes,3,rep,name=targets" json:"targets,omitempty"`
+}
+
+func (m *DrainRequest) Reset()                    { *m = DrainRequest{} }
+func (m *DrainRequest) String() string            { return proto.CompactTextString(m) }
+func (*DrainRequest) ProtoMessage()               {}
+func (*DrainRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *DrainRequest) GetMigrate() bool {
+	if m != nil {
+		return m.Migrate
+	}
+	return false
+}
+
+func (m *DrainRequest) GetFlowHashCodes() []uint32 {
+	if m != nil {
+		return m.FlowHashCodes
+	}
+	return nil
+}
+
+func (m *DrainRequest) GetTargets() []*Location {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+type DrainResponse struct {
+	MigratedShards []*DataLocation `protobuf:"bytes,1,rep,name=migratedShards" json:"migratedShards,omitempty"`
+}
+
+func (m *DrainResponse) Reset()                    { *m = DrainResponse{} }
+func (m *DrainResponse) String() string            { return proto.CompactTextString(m) }
+func (*DrainResponse) ProtoMessage()               {}
+func (*DrainResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *DrainResponse) GetMigratedShards() []*DataLocation {
+	if m != nil {
+		return m.MigratedShards
+	}
+	return nil
+}
+
+type JobLogsRequest struct {
+	Id        uint32  `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	StepIds   []int32 `protobuf:"varint,2,rep,packed,name=stepIds" json:"stepIds,omitempty"`
+	TailBytes int64   `protobuf:"varint,3,opt,name=tailBytes" json:"tailBytes,omitempty"`
+	Name      string  `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *JobLogsRequest) Reset()                    { *m = JobLogsRequest{} }
+func (m *JobLogsRequest) String() string            { return proto.CompactTextString(m) }
+func (*JobLogsRequest) ProtoMessage()               {}
+func (*JobLogsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *JobLogsRequest) GetId() uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *JobLogsRequest) GetStepIds() []int32 {
+	if m != nil {
+		return m.StepIds
+	}
+	return nil
+}
+
+func (m *JobLogsRequest) GetTailBytes() int64 {
+	if m != nil {
+		return m.TailBytes
+	}
+	return 0
+}
+
+func (m *JobLogsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type JobLogsResponse struct {
+	TaskLogs []*TaskLogs `protobuf:"bytes,1,rep,name=taskLogs" json:"taskLogs,omitempty"`
+}
+
+func (m *JobLogsResponse) Reset()                    { *m = JobLogsResponse{} }
+func (m *JobLogsResponse) String() string            { return proto.CompactTextString(m) }
+func (*JobLogsResponse) ProtoMessage()               {}
+func (*JobLogsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *JobLogsResponse) GetTaskLogs() []*TaskLogs {
+	if m != nil {
+		return m.TaskLogs
+	}
+	return nil
+}
+
+type TaskLogs struct {
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Agent   string `protobuf:"bytes,2,opt,name=agent" json:"agent,omitempty"`
+	Content []byte `protobuf:"bytes,3,opt,name=content" json:"content,omitempty"`
+	Error   string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *TaskLogs) Reset()                    { *m = TaskLogs{} }
+func (m *TaskLogs) String() string            { return proto.CompactTextString(m) }
+func (*TaskLogs) ProtoMessage()               {}
+func (*TaskLogs) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *TaskLogs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TaskLogs) GetAgent() string {
+	if m != nil {
+		return m.Agent
+	}
+	return ""
+}
+
+func (m *TaskLogs) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *TaskLogs) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type GetLogsRequest struct {
+	FlowHashCode uint32 `protobuf:"varint,1,opt,name=flowHashCode" json:"flowHashCode,omitempty"`
+	Name         string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	TailBytes    int64  `protobuf:"varint,3,opt,name=tailBytes" json:"tailBytes,omitempty"`
+}
+
+func (m *GetLogsRequest) Reset()                    { *m = GetLogsRequest{} }
+func (m *GetLogsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetLogsRequest) ProtoMessage()               {}
+func (*GetLogsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *GetLogsRequest) GetFlowHashCode() uint32 {
+	if m != nil {
+		return m.FlowHashCode
+	}
+	return 0
+}
+
+func (m *GetLogsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetLogsRequest) GetTailBytes() int64 {
+	if m != nil {
+		return m.TailBytes
+	}
+	return 0
+}
+
+type GetLogsResponse struct {
+	Content []byte `protobuf:"bytes,1,opt,name=content" json:"content,omitempty"`
+}
+
+func (m *GetLogsResponse) Reset()                    { *m = GetLogsResponse{} }
+func (m *GetLogsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetLogsResponse) ProtoMessage()               {}
+func (*GetLogsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *GetLogsResponse) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+// The part of a reduce partition pushed by one map task. The agent merges
+// the parts of all map tasks into one file per reduce partition.
+type ShufflePartition struct {
+	Name    string  `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Mapper  int32   `protobuf:"varint,2,opt,name=mapper" json:"mapper,omitempty"`
+	Mappers []int32 `protobuf:"varint,3,rep,packed,name=mappers" json:"mappers,omitempty"`
+}
+
+func (m *ShufflePartition) Reset()                    { *m = ShufflePartition{} }
+func (m *ShufflePartition) String() string            { return proto.CompactTextString(m) }
+func (*ShufflePartition) ProtoMessage()               {}
+func (*ShufflePartition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *ShufflePartition) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ShufflePartition) GetMapper() int32 {
+	if m != nil {
+		return m.Mapper
+	}
+	return 0
+}
+
+func (m *ShufflePartition) GetMappers() []int32 {
+	if m != nil {
+		return m.Mappers
+	}
+	return nil
+}
+
+type DatasetLocationsRequest struct {
+	Id        uint32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	DatasetId int32  `protobuf:"varint,2,opt,name=datasetId" json:"datasetId,omitempty"`
+}
+
+func (m *DatasetLocationsRequest) Reset()                    { *m = DatasetLocationsRequest{} }
+func (m *DatasetLocationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*DatasetLocationsRequest) ProtoMessage()               {}
+func (*DatasetLocationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *DatasetLocationsRequest) GetId() uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *DatasetLocationsRequest) GetDatasetId() int32 {
+	if m != nil {
+		return m.DatasetId
+	}
+	return 0
+}
+
+// the locations of the dataset shards, ordered by the shard id
+type DatasetLocationsResponse struct {
+	Locations []*DatasetShardLocation `protobuf:"bytes,1,rep,name=locations" json:"locations,omitempty"`
+}
+
+func (m *DatasetLocationsResponse) Reset()                    { *m = DatasetLocationsResponse{} }
+func (m *DatasetLocationsResponse) String() string            { return proto.CompactTextString(m) }
+func (*DatasetLocationsResponse) ProtoMessage()               {}
+func (*DatasetLocationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *DatasetLocationsResponse) GetLocations() []*DatasetShardLocation {
+	if m != nil {
+		return m.Locations
+	}
+	return nil
+}
+
+type CapacityRequest struct {
+	// empty for all data centers
+	DataCenter string `protobuf:"bytes,1,opt,name=dataCenter" json:"dataCenter,omitempty"`
+}
+
+func (m *CapacityRequest) Reset()                    { *m = CapacityRequest{} }
+func (m *CapacityRequest) String() string            { return proto.CompactTextString(m) }
+func (*CapacityRequest) ProtoMessage()               {}
+func (*CapacityRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *CapacityRequest) GetDataCenter() string {
+	if m != nil {
+		return m.DataCenter
+	}
+	return ""
+}
+
+type CapacityResponse struct {
+	Resource   *ComputeResource `protobuf:"bytes,1,opt,name=resource" json:"resource,omitempty"`
+	Allocated  *ComputeResource `protobuf:"bytes,2,opt,name=allocated" json:"allocated,omitempty"`
+	AgentCount int32            `protobuf:"varint,3,opt,name=agentCount" json:"agentCount,omitempty"`
+}
+
+func (m *CapacityResponse) Reset()                    { *m = CapacityResponse{} }
+func (m *CapacityResponse) String() string            { return proto.CompactTextString(m) }
+func (*CapacityResponse) ProtoMessage()               {}
+func (*CapacityResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *CapacityResponse) GetResource() *ComputeResource {
+	if m != nil {
+		return m.Resource
+	}
+	return nil
+}
+
+func (m *CapacityResponse) GetAllocated() *ComputeResource {
+	if m != nil {
+		return m.Allocated
+	}
+	return nil
+}
+
+func (m *CapacityResponse) GetAgentCount() int32 {
+	if m != nil {
+		return m.AgentCount
+	}
+	return 0
 }
 
 func init() {
@@ -1849,6 +2902,7 @@ func init() {
 	proto.RegisterType((*Allocation)(nil), "pb.Allocation")
 	proto.RegisterType((*AllocationResult)(nil), "pb.AllocationResult")
 	proto.RegisterType((*Heartbeat)(nil), "pb.Heartbeat")
+	proto.RegisterType((*Heartbeat_FlowAllocated)(nil), "pb.Heartbeat.FlowAllocated")
 	proto.RegisterType((*Empty)(nil), "pb.Empty")
 	proto.RegisterType((*DataLocation)(nil), "pb.DataLocation")
 	proto.RegisterType((*FlowExecutionStatus)(nil), "pb.FlowExecutionStatus")
@@ -1866,6 +2920,8 @@ func init() {
 	proto.RegisterType((*ExecutionResponse)(nil), "pb.ExecutionResponse")
 	proto.RegisterType((*ExecutionStat)(nil), "pb.ExecutionStat")
 	proto.RegisterType((*InstructionStat)(nil), "pb.InstructionStat")
+	proto.RegisterType((*InstructionStat_Counter)(nil), "pb.InstructionStat.Counter")
+	proto.RegisterType((*InstructionStat_Histogram)(nil), "pb.InstructionStat.Histogram")
 	proto.RegisterType((*ControlMessage)(nil), "pb.ControlMessage")
 	proto.RegisterType((*DeleteDatasetShardRequest)(nil), "pb.DeleteDatasetShardRequest")
 	proto.RegisterType((*DeleteDatasetShardResponse)(nil), "pb.DeleteDatasetShardResponse")
@@ -1894,9 +2950,37 @@ func init() {
 	proto.RegisterType((*Instruction_LocalLimit)(nil), "pb.Instruction.LocalLimit")
 	proto.RegisterType((*Instruction_LocalGroupBySorted)(nil), "pb.Instruction.LocalGroupBySorted")
 	proto.RegisterType((*Instruction_Union)(nil), "pb.Instruction.Union")
+	proto.RegisterType((*Instruction_ScatterSkewedPartitions)(nil), "pb.Instruction.ScatterSkewedPartitions")
+	proto.RegisterType((*Instruction_SampleHotKeys)(nil), "pb.Instruction.SampleHotKeys")
+	proto.RegisterType((*Instruction_ScatterReplicatedPartitions)(nil), "pb.Instruction.ScatterReplicatedPartitions")
+	proto.RegisterType((*Instruction_SampleSortKeys)(nil), "pb.Instruction.SampleSortKeys")
+	proto.RegisterType((*Instruction_ComputeSplitPoints)(nil), "pb.Instruction.ComputeSplitPoints")
+	proto.RegisterType((*Instruction_ScatterRanges)(nil), "pb.Instruction.ScatterRanges")
+	proto.RegisterType((*Instruction_SemiJoinPartitionedSorted)(nil), "pb.Instruction.SemiJoinPartitionedSorted")
+	proto.RegisterType((*Instruction_JoinPartitionedHashed)(nil), "pb.Instruction.JoinPartitionedHashed")
 	proto.RegisterType((*OrderBy)(nil), "pb.OrderBy")
 	proto.RegisterType((*DatasetShard)(nil), "pb.DatasetShard")
 	proto.RegisterType((*DatasetShardLocation)(nil), "pb.DatasetShardLocation")
+	proto.RegisterType((*PreemptRequest)(nil), "pb.PreemptRequest")
+	proto.RegisterType((*PreemptResponse)(nil), "pb.PreemptResponse")
+	proto.RegisterType((*MasterStateRequest)(nil), "pb.MasterStateRequest")
+	proto.RegisterType((*MasterState)(nil), "pb.MasterState")
+	proto.RegisterType((*MasterState_Record)(nil), "pb.MasterState.Record")
+	proto.RegisterType((*CancelFlowRequest)(nil), "pb.CancelFlowRequest")
+	proto.RegisterType((*DrainAgentRequest)(nil), "pb.DrainAgentRequest")
+	proto.RegisterType((*DrainAgentResponse)(nil), "pb.DrainAgentResponse")
+	proto.RegisterType((*DrainRequest)(nil), "pb.DrainRequest")
+	proto.RegisterType((*DrainResponse)(nil), "pb.DrainResponse")
+	proto.RegisterType((*JobLogsRequest)(nil), "pb.JobLogsRequest")
+	proto.RegisterType((*JobLogsResponse)(nil), "pb.JobLogsResponse")
+	proto.RegisterType((*TaskLogs)(nil), "pb.TaskLogs")
+	proto.RegisterType((*GetLogsRequest)(nil), "pb.GetLogsRequest")
+	proto.RegisterType((*GetLogsResponse)(nil), "pb.GetLogsResponse")
+	proto.RegisterType((*ShufflePartition)(nil), "pb.ShufflePartition")
+	proto.RegisterType((*DatasetLocationsRequest)(nil), "pb.DatasetLocationsRequest")
+	proto.RegisterType((*DatasetLocationsResponse)(nil), "pb.DatasetLocationsResponse")
+	proto.RegisterType((*CapacityRequest)(nil), "pb.CapacityRequest")
+	proto.RegisterType((*CapacityResponse)(nil), "pb.CapacityResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1913,6 +2997,18 @@ type GleamMasterClient interface {
 	GetResources(ctx context.Context, in *ComputeRequest, opts ...grpc.CallOption) (*AllocationResult, error)
 	SendHeartbeat(ctx context.Context, opts ...grpc.CallOption) (GleamMaster_SendHeartbeatClient, error)
 	SendFlowExecutionStatus(ctx context.Context, opts ...grpc.CallOption) (GleamMaster_SendFlowExecutionStatusClient, error)
+	// replicate the state changes to a standby master
+	GetMasterState(ctx context.Context, in *MasterStateRequest, opts ...grpc.CallOption) (*MasterState, error)
+	// stop a running flow, the id is the flow hash code
+	CancelFlow(ctx context.Context, in *CancelFlowRequest, opts ...grpc.CallOption) (*Empty, error)
+	// stop allocating to an agent, wait for its executors, and deregister it
+	DrainAgent(ctx context.Context, in *DrainAgentRequest, opts ...grpc.CallOption) (*DrainAgentResponse, error)
+	// collect the executor logs of a job from the agents
+	GetJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (*JobLogsResponse, error)
+	// locate the shards of a dataset of a job, to read them back
+	GetDatasetLocations(ctx context.Context, in *DatasetLocationsRequest, opts ...grpc.CallOption) (*DatasetLocationsResponse, error)
+	// the resources of the agents not drained
+	GetCapacity(ctx context.Context, in *CapacityRequest, opts ...grpc.CallOption) (*CapacityResponse, error)
 }
 
 type gleamMasterClient struct {
@@ -2000,12 +3096,78 @@ func (x *gleamMasterSendFlowExecutionStatusClient) CloseAndRecv() (*Empty, error
 	return m, nil
 }
 
+func (c *gleamMasterClient) GetMasterState(ctx context.Context, in *MasterStateRequest, opts ...grpc.CallOption) (*MasterState, error) {
+	out := new(MasterState)
+	err := grpc.Invoke(ctx, "/pb.GleamMaster/GetMasterState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamMasterClient) CancelFlow(ctx context.Context, in *CancelFlowRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.GleamMaster/CancelFlow", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamMasterClient) DrainAgent(ctx context.Context, in *DrainAgentRequest, opts ...grpc.CallOption) (*DrainAgentResponse, error) {
+	out := new(DrainAgentResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamMaster/DrainAgent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamMasterClient) GetJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (*JobLogsResponse, error) {
+	out := new(JobLogsResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamMaster/GetJobLogs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamMasterClient) GetDatasetLocations(ctx context.Context, in *DatasetLocationsRequest, opts ...grpc.CallOption) (*DatasetLocationsResponse, error) {
+	out := new(DatasetLocationsResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamMaster/GetDatasetLocations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamMasterClient) GetCapacity(ctx context.Context, in *CapacityRequest, opts ...grpc.CallOption) (*CapacityResponse, error) {
+	out := new(CapacityResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamMaster/GetCapacity", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for GleamMaster service
 
 type GleamMasterServer interface {
 	GetResources(context.Context, *ComputeRequest) (*AllocationResult, error)
 	SendHeartbeat(GleamMaster_SendHeartbeatServer) error
 	SendFlowExecutionStatus(GleamMaster_SendFlowExecutionStatusServer) error
+	// replicate the state changes to a standby master
+	GetMasterState(context.Context, *MasterStateRequest) (*MasterState, error)
+	// stop a running flow, the id is the flow hash code
+	CancelFlow(context.Context, *CancelFlowRequest) (*Empty, error)
+	// stop allocating to an agent, wait for its executors, and deregister it
+	DrainAgent(context.Context, *DrainAgentRequest) (*DrainAgentResponse, error)
+	// collect the executor logs of a job from the agents
+	GetJobLogs(context.Context, *JobLogsRequest) (*JobLogsResponse, error)
+	// locate the shards of a dataset of a job, to read them back
+	GetDatasetLocations(context.Context, *DatasetLocationsRequest) (*DatasetLocationsResponse, error)
+	// the resources of the agents not drained
+	GetCapacity(context.Context, *CapacityRequest) (*CapacityResponse, error)
 }
 
 func RegisterGleamMasterServer(s *grpc.Server, srv GleamMasterServer) {
@@ -2082,6 +3244,114 @@ func (x *gleamMasterSendFlowExecutionStatusServer) Recv() (*FlowExecutionStatus,
 	return m, nil
 }
 
+func _GleamMaster_GetMasterState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MasterStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamMasterServer).GetMasterState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamMaster/GetMasterState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamMasterServer).GetMasterState(ctx, req.(*MasterStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamMaster_CancelFlow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelFlowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamMasterServer).CancelFlow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamMaster/CancelFlow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamMasterServer).CancelFlow(ctx, req.(*CancelFlowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamMaster_DrainAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamMasterServer).DrainAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamMaster/DrainAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamMasterServer).DrainAgent(ctx, req.(*DrainAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamMaster_GetJobLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamMasterServer).GetJobLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamMaster/GetJobLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamMasterServer).GetJobLogs(ctx, req.(*JobLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamMaster_GetDatasetLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DatasetLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamMasterServer).GetDatasetLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamMaster/GetDatasetLocations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamMasterServer).GetDatasetLocations(ctx, req.(*DatasetLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamMaster_GetCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamMasterServer).GetCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamMaster/GetCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamMasterServer).GetCapacity(ctx, req.(*CapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _GleamMaster_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.GleamMaster",
 	HandlerType: (*GleamMasterServer)(nil),
@@ -2090,6 +3360,30 @@ var _GleamMaster_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetResources",
 			Handler:    _GleamMaster_GetResources_Handler,
 		},
+		{
+			MethodName: "GetMasterState",
+			Handler:    _GleamMaster_GetMasterState_Handler,
+		},
+		{
+			MethodName: "CancelFlow",
+			Handler:    _GleamMaster_CancelFlow_Handler,
+		},
+		{
+			MethodName: "DrainAgent",
+			Handler:    _GleamMaster_DrainAgent_Handler,
+		},
+		{
+			MethodName: "GetJobLogs",
+			Handler:    _GleamMaster_GetJobLogs_Handler,
+		},
+		{
+			MethodName: "GetDatasetLocations",
+			Handler:    _GleamMaster_GetDatasetLocations_Handler,
+		},
+		{
+			MethodName: "GetCapacity",
+			Handler:    _GleamMaster_GetCapacity_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2213,6 +3507,12 @@ type GleamAgentClient interface {
 	CollectExecutionStatistics(ctx context.Context, opts ...grpc.CallOption) (GleamAgent_CollectExecutionStatisticsClient, error)
 	Delete(ctx context.Context, in *DeleteDatasetShardRequest, opts ...grpc.CallOption) (*DeleteDatasetShardResponse, error)
 	Cleanup(ctx context.Context, in *CleanupRequest, opts ...grpc.CallOption) (*CleanupResponse, error)
+	// stop executions of a flow to release the resource
+	Preempt(ctx context.Context, in *PreemptRequest, opts ...grpc.CallOption) (*PreemptResponse, error)
+	// wait for the executors to finish, move the dataset shards, and stop heartbeats
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
+	// read the log of a task group of a flow
+	GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (*GetLogsResponse, error)
 }
 
 type gleamAgentClient struct {
@@ -2338,6 +3638,33 @@ func (c *gleamAgentClient) Cleanup(ctx context.Context, in *CleanupRequest, opts
 	return out, nil
 }
 
+func (c *gleamAgentClient) Preempt(ctx context.Context, in *PreemptRequest, opts ...grpc.CallOption) (*PreemptResponse, error) {
+	out := new(PreemptResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamAgent/Preempt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamAgentClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	out := new(DrainResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamAgent/Drain", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gleamAgentClient) GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (*GetLogsResponse, error) {
+	out := new(GetLogsResponse)
+	err := grpc.Invoke(ctx, "/pb.GleamAgent/GetLogs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for GleamAgent service
 
 type GleamAgentServer interface {
@@ -2347,6 +3674,12 @@ type GleamAgentServer interface {
 	CollectExecutionStatistics(GleamAgent_CollectExecutionStatisticsServer) error
 	Delete(context.Context, *DeleteDatasetShardRequest) (*DeleteDatasetShardResponse, error)
 	Cleanup(context.Context, *CleanupRequest) (*CleanupResponse, error)
+	// stop executions of a flow to release the resource
+	Preempt(context.Context, *PreemptRequest) (*PreemptResponse, error)
+	// wait for the executors to finish, move the dataset shards, and stop heartbeats
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
+	// read the log of a task group of a flow
+	GetLogs(context.Context, *GetLogsRequest) (*GetLogsResponse, error)
 }
 
 func RegisterGleamAgentServer(s *grpc.Server, srv GleamAgentServer) {
@@ -2462,6 +3795,60 @@ func _GleamAgent_Cleanup_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GleamAgent_Preempt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreemptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamAgentServer).Preempt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamAgent/Preempt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamAgentServer).Preempt(ctx, req.(*PreemptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamAgent_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamAgentServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamAgent/Drain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamAgentServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GleamAgent_GetLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GleamAgentServer).GetLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GleamAgent/GetLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GleamAgentServer).GetLogs(ctx, req.(*GetLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _GleamAgent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.GleamAgent",
 	HandlerType: (*GleamAgentServer)(nil),
@@ -2474,6 +3861,18 @@ var _GleamAgent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Cleanup",
 			Handler:    _GleamAgent_Cleanup_Handler,
 		},
+		{
+			MethodName: "Preempt",
+			Handler:    _GleamAgent_Preempt_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _GleamAgent_Drain_Handler,
+		},
+		{
+			MethodName: "GetLogs",
+			Handler:    _GleamAgent_GetLogs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2499,163 +3898,241 @@ var _GleamAgent_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("gleam.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2515 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0x5f, 0x6f, 0xdc, 0xc6,
-	0x11, 0x0f, 0xef, 0x74, 0xa7, 0xbb, 0xb9, 0xd3, 0xbf, 0x95, 0x6c, 0x33, 0x6c, 0xe2, 0x28, 0x44,
-	0x1a, 0xab, 0x0d, 0xa2, 0x38, 0x8a, 0x8b, 0x14, 0x6e, 0x51, 0x54, 0x96, 0xe3, 0x44, 0xc9, 0x39,
-	0x32, 0x56, 0x2a, 0xd2, 0x3f, 0x40, 0x0d, 0xea, 0xb8, 0x96, 0x18, 0x51, 0xe4, 0x95, 0xbb, 0x67,
-	0x5b, 0xfd, 0x02, 0x7d, 0x28, 0xfa, 0x50, 0xa0, 0x2f, 0xfd, 0x1e, 0x45, 0x5f, 0xf2, 0x21, 0x0a,
-	0xf4, 0xa1, 0x6f, 0x7d, 0x4b, 0x3f, 0x42, 0xdf, 0x8b, 0x99, 0x5d, 0x92, 0x4b, 0x1e, 0xef, 0xac,
-	0xa0, 0x6f, 0xbb, 0x33, 0xbf, 0x19, 0xce, 0xce, 0xce, 0xcc, 0xce, 0x2e, 0x61, 0x70, 0x16, 0x8b,
-	0xe0, 0x72, 0x77, 0x92, 0xa5, 0x2a, 0x65, 0xad, 0xc9, 0xa9, 0xff, 0x0f, 0x07, 0x56, 0x0f, 0xd2,
-	0xcb, 0xc9, 0x54, 0x09, 0x2e, 0x7e, 0x37, 0x15, 0x52, 0xb1, 0xb7, 0x60, 0x10, 0x06, 0x2a, 0x78,
-	0x3a, 0x16, 0x89, 0x12, 0x99, 0xeb, 0x6c, 0x3b, 0x3b, 0x7d, 0x0e, 0x48, 0x3a, 0x20, 0x0a, 0xfb,
-	0x39, 0x6c, 0x8c, 0xb5, 0xc8, 0xd3, 0x4c, 0xc8, 0x74, 0x9a, 0x8d, 0x85, 0x74, 0x5b, 0xdb, 0xed,
-	0x9d, 0xc1, 0xde, 0xe6, 0xee, 0xe4, 0x74, 0xb7, 0xd0, 0xa7, 0x79, 0x7c, 0x7d, 0x5c, 0x25, 0x48,
-	0xe6, 0x41, 0x6f, 0x2a, 0x45, 0x96, 0x04, 0x97, 0xc2, 0x6d, 0x93, 0xfe, 0x62, 0x8e, 0xbc, 0xf3,
-	0x54, 0x2a, 0xe2, 0x2d, 0x69, 0x5e, 0x3e, 0x67, 0x3e, 0x0c, 0x9f, 0xc5, 0xe9, 0x8b, 0xcf, 0x02,
-	0x79, 0x7e, 0x90, 0x86, 0xc2, 0xed, 0x6c, 0x3b, 0x3b, 0x2b, 0xbc, 0x42, 0xf3, 0xbf, 0x71, 0x60,
-	0xad, 0x66, 0x01, 0xfb, 0x1e, 0xf4, 0xc7, 0x93, 0xe9, 0xd3, 0x71, 0x3a, 0x4d, 0x14, 0x2d, 0xa8,
-	0xc3, 0x7b, 0xe3, 0xc9, 0xf4, 0x00, 0xe7, 0x39, 0x33, 0x16, 0xcf, 0x45, 0xec, 0xb6, 0x0a, 0xe6,
-	0x08, 0xe7, 0xc8, 0x3c, 0x2b, 0x24, 0xdb, 0x9a, 0x79, 0x66, 0x49, 0x9e, 0x15, 0x92, 0x4b, 0x05,
-	0xb3, 0x90, 0xbc, 0x14, 0x97, 0x69, 0x76, 0xf5, 0xf4, 0xf2, 0x94, 0x0c, 0x6d, 0xf3, 0x9e, 0x26,
-	0x3c, 0x3e, 0x65, 0xb7, 0x60, 0x39, 0x8c, 0xe4, 0x05, 0xb2, 0xba, 0xc4, 0xea, 0xe2, 0xf4, 0xf1,
-	0xa9, 0x3f, 0x82, 0xe1, 0xc3, 0x40, 0x05, 0x85, 0xe5, 0x3b, 0xd0, 0x8b, 0xd3, 0x71, 0xa0, 0xa2,
-	0x34, 0x21, 0xc3, 0x07, 0x7b, 0x43, 0x74, 0xf1, 0xc8, 0xd0, 0x78, 0xc1, 0x65, 0x0c, 0x96, 0x64,
-	0xf4, 0x7b, 0x41, 0x2b, 0x68, 0x73, 0x1a, 0xfb, 0x17, 0xd0, 0xcb, 0x91, 0xaf, 0xde, 0x56, 0x06,
-	0x4b, 0x59, 0x30, 0xbe, 0x20, 0x05, 0x7d, 0x4e, 0x63, 0x76, 0x13, 0xba, 0x52, 0x64, 0xcf, 0x45,
-	0x66, 0xb6, 0xc9, 0xcc, 0x10, 0x3b, 0x49, 0x33, 0x65, 0x16, 0x4d, 0x63, 0x3f, 0x02, 0xd8, 0x8f,
-	0x0b, 0x73, 0xae, 0x6f, 0xf8, 0x87, 0xd0, 0x0f, 0xb4, 0x9c, 0x08, 0xe9, 0xe3, 0x73, 0xc2, 0xa8,
-	0x44, 0xf9, 0x0f, 0x61, 0xbd, 0xfc, 0x14, 0x17, 0x72, 0x1a, 0x2b, 0x76, 0x17, 0x06, 0x41, 0x41,
-	0x93, 0xae, 0x43, 0xf1, 0xb8, 0x8a, 0x8a, 0x2c, 0xa8, 0x0d, 0xf1, 0xff, 0xea, 0x40, 0xff, 0x33,
-	0x11, 0x64, 0xea, 0x54, 0x04, 0xea, 0x3b, 0x18, 0xfc, 0x01, 0xf4, 0xf2, 0xb8, 0x5f, 0x64, 0x6f,
-	0x01, 0xaa, 0xae, 0xb0, 0x7d, 0xad, 0x15, 0x2e, 0x43, 0xe7, 0x93, 0xcb, 0x89, 0xba, 0xf2, 0x43,
-	0x1d, 0x10, 0x23, 0x6b, 0x9b, 0x29, 0x35, 0xf4, 0xfe, 0xd1, 0xb8, 0x62, 0x7a, 0x6b, 0xa1, 0xe9,
-	0x37, 0xa1, 0x9b, 0x26, 0x0f, 0x23, 0x79, 0x41, 0x66, 0xf4, 0xb8, 0x99, 0xf9, 0xdf, 0x0e, 0x61,
-	0xf3, 0x51, 0x9c, 0xbe, 0xf8, 0xe4, 0xa5, 0x18, 0x4f, 0x11, 0x79, 0xac, 0x02, 0x35, 0x95, 0x6c,
-	0x1f, 0x40, 0x2a, 0x31, 0xf9, 0x34, 0x4b, 0xa7, 0x93, 0xdc, 0xa7, 0x6f, 0xa3, 0xee, 0x06, 0xf0,
-	0xee, 0x71, 0x8e, 0xe4, 0x96, 0x10, 0xaa, 0x50, 0x81, 0xbc, 0x30, 0x2a, 0x5a, 0x8b, 0x55, 0x9c,
-	0xe4, 0x48, 0x6e, 0x09, 0xb1, 0x9f, 0x40, 0x0f, 0xe3, 0x54, 0x0a, 0x25, 0xdd, 0x36, 0x29, 0x78,
-	0x6b, 0x9e, 0x82, 0x87, 0x1a, 0xc7, 0x0b, 0x01, 0xf6, 0x39, 0xac, 0x98, 0xf1, 0xf1, 0x79, 0x90,
-	0x85, 0xd2, 0x5d, 0x22, 0x0d, 0xef, 0xbc, 0x42, 0x03, 0x81, 0x79, 0x55, 0x94, 0xed, 0x41, 0x07,
-	0xcd, 0x92, 0x6e, 0x87, 0x74, 0xbc, 0xb1, 0x68, 0x19, 0x5c, 0x43, 0x51, 0x06, 0xbd, 0x21, 0xdd,
-	0xee, 0x62, 0x19, 0xf4, 0x1e, 0xd7, 0x50, 0xb6, 0x0a, 0xad, 0x28, 0x74, 0x97, 0xa9, 0xba, 0xb5,
-	0xa2, 0x90, 0xdd, 0x87, 0x6e, 0x98, 0x45, 0x98, 0x86, 0x3d, 0xda, 0x5e, 0x7f, 0xae, 0xf1, 0x84,
-	0x3a, 0x4c, 0x9e, 0xa5, 0xdc, 0x48, 0x78, 0xbb, 0xb0, 0x84, 0xe6, 0x50, 0x2a, 0x2b, 0x31, 0x39,
-	0x0c, 0x4d, 0x01, 0x34, 0x33, 0xf3, 0x2d, 0x5d, 0xf7, 0x5a, 0x51, 0xe8, 0xfd, 0xcd, 0x81, 0x25,
-	0xb4, 0xc5, 0x30, 0x9c, 0x9c, 0x51, 0x44, 0x5e, 0xcb, 0x8a, 0xbc, 0x37, 0xa0, 0x3f, 0x09, 0x32,
-	0x91, 0xa8, 0xc3, 0x50, 0x6f, 0x4d, 0x87, 0x97, 0x04, 0xe6, 0xc2, 0x32, 0xfa, 0xe0, 0xd0, 0x38,
-	0xbd, 0xc3, 0xf3, 0x29, 0x7b, 0x17, 0x56, 0xa3, 0x64, 0x32, 0x55, 0xc6, 0xd9, 0x87, 0x21, 0x79,
-	0xb4, 0xc3, 0x6b, 0x54, 0xb6, 0x03, 0x6b, 0xe9, 0x54, 0x55, 0x80, 0x5d, 0x32, 0xa8, 0x4e, 0xf6,
-	0x7e, 0x05, 0xcb, 0x66, 0x32, 0x63, 0x78, 0xb9, 0xf2, 0x56, 0x65, 0xe5, 0xef, 0xc2, 0x6a, 0x26,
-	0x82, 0x30, 0x4a, 0xce, 0x8e, 0x89, 0x90, 0xaf, 0xa0, 0x46, 0xf5, 0x7e, 0xaa, 0x53, 0x30, 0x0f,
-	0x03, 0x5c, 0x74, 0x58, 0x98, 0xa3, 0x3f, 0x53, 0x12, 0x66, 0xfc, 0x79, 0x00, 0xfd, 0x22, 0x31,
-	0xd0, 0x23, 0xd2, 0x7c, 0xcb, 0xd1, 0x1e, 0x31, 0xd3, 0xaa, 0x27, 0x5b, 0x35, 0x4f, 0x7a, 0xdf,
-	0xb6, 0xa1, 0x5f, 0xe4, 0xc6, 0x02, 0x2d, 0x96, 0xc7, 0x5b, 0x55, 0x8f, 0xef, 0xc2, 0x72, 0xa6,
-	0x0f, 0x78, 0x53, 0x81, 0xb6, 0x30, 0x86, 0x8a, 0xf8, 0x31, 0x87, 0x3f, 0xcf, 0x41, 0x6c, 0x17,
-	0xa0, 0xac, 0x95, 0x54, 0xe7, 0x67, 0xab, 0xa9, 0x85, 0x60, 0x5f, 0x00, 0x88, 0x5c, 0x59, 0x9e,
-	0x1f, 0xef, 0xbd, 0x32, 0xcd, 0x2d, 0x03, 0x2c, 0x71, 0xef, 0xbf, 0x0e, 0xf4, 0x0b, 0x0e, 0x7b,
-	0x13, 0x8b, 0x50, 0x90, 0xa9, 0xa7, 0x2a, 0x32, 0x85, 0xaf, 0xcd, 0xfb, 0x44, 0x39, 0x89, 0x2e,
-	0xe9, 0x70, 0x97, 0x2a, 0x9d, 0x68, 0xae, 0x3e, 0xfd, 0x7a, 0x48, 0x20, 0xe6, 0x5b, 0x30, 0x90,
-	0x57, 0x52, 0x89, 0x4b, 0xcd, 0xc6, 0xa5, 0x3b, 0x1c, 0x34, 0x29, 0x97, 0xc6, 0xd6, 0x43, 0xb3,
-	0x97, 0x88, 0x4d, 0xbd, 0x08, 0x31, 0xb7, 0xa0, 0x23, 0xb2, 0x2c, 0xcd, 0xe8, 0xfc, 0x1e, 0x72,
-	0x3d, 0x41, 0x9d, 0x3a, 0xfa, 0x9e, 0x9e, 0x07, 0xf2, 0x9c, 0x02, 0x72, 0xc8, 0x41, 0x93, 0xb0,
-	0x0d, 0x61, 0x1f, 0xc3, 0x8a, 0xb0, 0x57, 0x4c, 0x99, 0x3c, 0xd8, 0xdb, 0xa8, 0x78, 0x1c, 0x19,
-	0xbc, 0x8a, 0xf3, 0xfe, 0xed, 0x00, 0x94, 0x29, 0x5c, 0x69, 0x93, 0x9c, 0x05, 0x6d, 0x52, 0xab,
-	0xd6, 0x26, 0xdd, 0xce, 0xf7, 0x22, 0x38, 0x8d, 0xf3, 0x06, 0xcb, 0xa2, 0xb0, 0x3b, 0xb0, 0x56,
-	0xce, 0xf4, 0x22, 0x74, 0xa7, 0xb5, 0x5a, 0x92, 0x69, 0x21, 0x55, 0xcf, 0x77, 0x16, 0x7a, 0xbe,
-	0x5b, 0xf3, 0x7c, 0x5e, 0x2e, 0x96, 0xcb, 0x72, 0xe1, 0xff, 0xc9, 0x81, 0xcd, 0x47, 0x51, 0x5c,
-	0x9e, 0x78, 0x26, 0xd8, 0x9a, 0x0e, 0xb5, 0x75, 0x68, 0x87, 0x51, 0x66, 0xd6, 0x86, 0x43, 0x44,
-	0x91, 0xad, 0x6d, 0xaa, 0x8b, 0x34, 0x9e, 0xe9, 0x08, 0x97, 0x66, 0x3b, 0x42, 0x4c, 0x8a, 0x71,
-	0x9a, 0x28, 0x91, 0x28, 0xb3, 0x8f, 0xf9, 0xd4, 0x1f, 0xc1, 0x56, 0xd5, 0x1c, 0x39, 0x49, 0x13,
-	0x29, 0xd8, 0x3b, 0xb0, 0x12, 0xc4, 0x58, 0x05, 0xae, 0x3e, 0x79, 0x19, 0x49, 0x25, 0xc9, 0xb0,
-	0x1e, 0xaf, 0x12, 0x31, 0xd3, 0x53, 0xdd, 0x2e, 0xf5, 0x78, 0x2b, 0xbd, 0xf0, 0xff, 0xec, 0xc0,
-	0x7a, 0x3d, 0xa1, 0xd8, 0x7d, 0xac, 0x74, 0x52, 0x65, 0xd3, 0x31, 0xed, 0xb2, 0x50, 0xa6, 0xb9,
-	0x60, 0x18, 0x0c, 0x87, 0x15, 0x0e, 0xaf, 0x21, 0x1b, 0x5c, 0x60, 0xb7, 0x1e, 0xed, 0x6b, 0xb4,
-	0x1e, 0xfe, 0xdf, 0x1d, 0xd8, 0xb0, 0x6c, 0x32, 0xeb, 0xc3, 0x36, 0x80, 0xc2, 0x95, 0x8c, 0x19,
-	0x72, 0x33, 0x2b, 0xe3, 0xbd, 0x65, 0xc7, 0xfb, 0x6d, 0xb0, 0x12, 0xa6, 0x21, 0x85, 0x4c, 0x98,
-	0x9e, 0x34, 0x65, 0xd0, 0x4c, 0x2a, 0x74, 0xae, 0x97, 0x0a, 0xfe, 0x6f, 0x61, 0xa5, 0xc2, 0x9f,
-	0xd9, 0x69, 0xa7, 0x61, 0xa7, 0x7f, 0x80, 0x67, 0x6d, 0xa0, 0x2a, 0xb7, 0x11, 0xdb, 0xc7, 0xf8,
-	0x1d, 0x8d, 0xf0, 0xff, 0xe8, 0xc0, 0x5a, 0x8d, 0x35, 0xf7, 0x88, 0xbc, 0x09, 0x5d, 0x5d, 0x46,
-	0xf3, 0x03, 0x44, 0xcf, 0xd0, 0x24, 0x3a, 0xaf, 0xe8, 0x36, 0x60, 0x7a, 0xe4, 0x36, 0xaf, 0xd0,
-	0x30, 0x94, 0xb4, 0x73, 0x73, 0xd0, 0x12, 0x81, 0xaa, 0x44, 0x6c, 0x45, 0x57, 0x0f, 0xd2, 0x44,
-	0x65, 0x69, 0xfc, 0x58, 0x48, 0x19, 0x9c, 0x51, 0x12, 0x47, 0xf2, 0x88, 0xda, 0xb3, 0xc3, 0x23,
-	0x13, 0x80, 0x16, 0x85, 0x7d, 0x08, 0x03, 0x0c, 0x46, 0x13, 0x67, 0xa6, 0xef, 0x5b, 0xc3, 0x15,
-	0xf3, 0x92, 0xcc, 0x6d, 0x0c, 0xbb, 0x07, 0xc3, 0x17, 0x59, 0x54, 0xdc, 0xf4, 0x4c, 0x04, 0xad,
-	0xa3, 0xcc, 0x57, 0x16, 0x9d, 0x57, 0x50, 0xfe, 0x07, 0xf0, 0xfa, 0x43, 0x11, 0x0b, 0x25, 0x2a,
-	0x9d, 0xd1, 0xfc, 0xcc, 0xf5, 0xf7, 0xc0, 0x6b, 0x12, 0x30, 0xb1, 0x57, 0xc4, 0x98, 0x16, 0xd1,
-	0x13, 0xff, 0x1e, 0xac, 0x1e, 0xc4, 0x22, 0x48, 0xa6, 0x93, 0x5c, 0xf3, 0x35, 0xf6, 0xdb, 0xbf,
-	0x03, 0x6b, 0x85, 0xd4, 0x42, 0xf5, 0x19, 0x0c, 0xed, 0x15, 0xb2, 0x6d, 0x18, 0x8c, 0xcf, 0x83,
-	0x24, 0x11, 0xf1, 0x97, 0xa5, 0xf5, 0x36, 0x09, 0xdd, 0x4f, 0x5e, 0xc8, 0xbe, 0x2c, 0x2b, 0xac,
-	0x45, 0x41, 0x0d, 0xe8, 0x5a, 0x91, 0x1d, 0x58, 0x57, 0x43, 0x9b, 0xe4, 0x1f, 0xc1, 0xc0, 0xda,
-	0x89, 0xeb, 0x7d, 0x52, 0xcb, 0xdb, 0x9f, 0x2c, 0x29, 0xfe, 0x7f, 0x1c, 0x58, 0xad, 0x56, 0x0c,
-	0xf6, 0x11, 0x46, 0x60, 0x41, 0xc9, 0x3b, 0xf4, 0xb5, 0x5a, 0xdc, 0xf3, 0x0a, 0xa8, 0x6e, 0x7a,
-	0x6b, 0xc6, 0xf4, 0x19, 0xdf, 0xb7, 0x1b, 0x72, 0x6d, 0x1b, 0x06, 0x91, 0x7c, 0x92, 0xa5, 0xcf,
-	0xa2, 0x38, 0x4a, 0xce, 0x28, 0xac, 0x7b, 0xdc, 0x26, 0xa1, 0x96, 0xe0, 0x4c, 0x24, 0x6a, 0x3f,
-	0x0c, 0x33, 0x21, 0x25, 0xa5, 0x7e, 0x9f, 0x57, 0x68, 0x45, 0xfc, 0x74, 0xad, 0xf8, 0xf9, 0xe7,
-	0x26, 0x0c, 0x2c, 0xeb, 0xbf, 0x73, 0x5a, 0xde, 0x06, 0xd0, 0x17, 0xed, 0xc3, 0xe4, 0xf1, 0x03,
-	0xb3, 0x33, 0x16, 0x85, 0x7d, 0x0e, 0x9b, 0x94, 0xa2, 0x14, 0x97, 0xa3, 0xe2, 0xc6, 0xa8, 0xef,
-	0x05, 0x2e, 0xfa, 0xce, 0x0e, 0xdc, 0xe2, 0x16, 0xd5, 0x24, 0xc4, 0x46, 0xb0, 0x75, 0x44, 0x99,
-	0x5c, 0x53, 0xd6, 0x79, 0x85, 0xb2, 0x46, 0x29, 0xb6, 0x8b, 0xd7, 0xed, 0x58, 0x8c, 0x15, 0xf9,
-	0x63, 0xb0, 0x77, 0xb3, 0xb6, 0x91, 0xbb, 0xc7, 0xc4, 0xe5, 0x06, 0xc5, 0x7e, 0x03, 0x37, 0xbe,
-	0x4e, 0xa3, 0xe4, 0x49, 0x90, 0xa9, 0x08, 0xf9, 0x22, 0x3c, 0x4e, 0x33, 0xbc, 0x64, 0xea, 0x86,
-	0xe3, 0xfb, 0x75, 0xf1, 0xcf, 0x9b, 0xc0, 0xbc, 0x59, 0x07, 0x0b, 0xc1, 0x1d, 0xa7, 0xd4, 0xa5,
-	0xcd, 0xea, 0xd7, 0xd7, 0x90, 0x9d, 0xba, 0xfe, 0x83, 0x39, 0x78, 0x3e, 0x57, 0x13, 0xbb, 0x0f,
-	0x30, 0x89, 0x26, 0x62, 0x5f, 0xee, 0x67, 0x67, 0xd2, 0xed, 0x93, 0x5e, 0xaf, 0xae, 0xf7, 0x49,
-	0x81, 0xe0, 0x16, 0x9a, 0x1d, 0xc1, 0x86, 0x1c, 0x07, 0x4a, 0x89, 0xac, 0xd0, 0x2b, 0x5d, 0x20,
-	0x15, 0x6f, 0xcf, 0x78, 0xae, 0x0e, 0xe4, 0xb3, 0xb2, 0xa8, 0x70, 0x9c, 0xc6, 0xe8, 0x5a, 0x4b,
-	0xe1, 0xa0, 0x59, 0xe1, 0x41, 0x1d, 0xc8, 0x67, 0x65, 0xd9, 0x08, 0xd6, 0x75, 0xd4, 0x4c, 0xe2,
-	0x48, 0x71, 0xca, 0x30, 0x77, 0x48, 0xfa, 0xb6, 0xeb, 0xfa, 0x0e, 0x6b, 0x38, 0x3e, 0x23, 0x89,
-	0xbe, 0xca, 0xd2, 0x69, 0x12, 0xf2, 0xf4, 0x34, 0x4a, 0xdc, 0x95, 0x66, 0x5f, 0xf1, 0x02, 0xc1,
-	0x2d, 0x34, 0xbb, 0xa7, 0xdf, 0x08, 0xe2, 0x93, 0x74, 0xe2, 0xae, 0x92, 0xa4, 0x5b, 0x97, 0x1c,
-	0x19, 0x3e, 0x2f, 0x90, 0xec, 0x63, 0xe8, 0x9f, 0x66, 0x69, 0x10, 0x8e, 0x03, 0xa9, 0xdc, 0x35,
-	0x12, 0x7b, 0xbd, 0x2e, 0xf6, 0x20, 0x07, 0xf0, 0x12, 0xcb, 0x7e, 0x09, 0x5b, 0xa4, 0x04, 0xcb,
-	0xc5, 0x7e, 0x12, 0x62, 0xe0, 0x7d, 0x15, 0xa9, 0x73, 0x77, 0x9d, 0x74, 0xbc, 0xd3, 0xf8, 0xe9,
-	0x1a, 0x96, 0x37, 0x6a, 0xa0, 0x1c, 0x19, 0x67, 0xd1, 0x44, 0xb9, 0x1b, 0x73, 0x72, 0x84, 0xb8,
-	0xdc, 0xa0, 0x70, 0x09, 0xa4, 0x07, 0xe3, 0xcd, 0x65, 0xcd, 0x4b, 0x18, 0xe5, 0x00, 0x5e, 0x62,
-	0xd9, 0x01, 0xac, 0x5c, 0x8a, 0xec, 0x4c, 0xe8, 0x40, 0x3d, 0x49, 0xdd, 0x4d, 0x12, 0x7e, 0xb3,
-	0x2e, 0xfc, 0xd8, 0x06, 0xf1, 0xaa, 0x0c, 0xfb, 0x10, 0x96, 0x89, 0x70, 0x92, 0xba, 0x5b, 0x24,
-	0x7e, 0xab, 0x51, 0xfc, 0x24, 0xe5, 0x39, 0x0e, 0xbf, 0x4b, 0x46, 0x3c, 0x8c, 0xa4, 0x8a, 0x92,
-	0xb1, 0x72, 0x6f, 0x34, 0x7f, 0x77, 0x64, 0x83, 0x78, 0x55, 0x06, 0x43, 0x85, 0x08, 0xa3, 0xe8,
-	0x32, 0x52, 0xee, 0xcd, 0xe6, 0x50, 0x19, 0x15, 0x08, 0x6e, 0xa1, 0x19, 0x07, 0x46, 0x33, 0xca,
-	0xd8, 0x07, 0x57, 0x26, 0xe5, 0x6f, 0x95, 0x2f, 0x0f, 0x33, 0x3a, 0x2a, 0x48, 0xde, 0x20, 0xcd,
-	0xde, 0x83, 0xce, 0x34, 0xc1, 0x9b, 0xa4, 0x4b, 0x6a, 0x6e, 0xd4, 0xd5, 0xfc, 0x02, 0x99, 0x5c,
-	0x63, 0xbc, 0x11, 0x74, 0x75, 0xa1, 0xc3, 0x52, 0x7e, 0x21, 0xae, 0x0e, 0x93, 0x50, 0xbc, 0x14,
-	0xf9, 0x65, 0xd7, 0xa2, 0xe0, 0x11, 0xf3, 0x3c, 0x88, 0xa7, 0x22, 0x47, 0xe8, 0x4b, 0x6f, 0x85,
-	0xe6, 0xfd, 0xc1, 0x81, 0x1b, 0x8d, 0x85, 0x0f, 0x2f, 0x06, 0x51, 0x45, 0x75, 0x3e, 0x65, 0x3b,
-	0xb0, 0x16, 0xc9, 0x91, 0x78, 0xa6, 0x8e, 0xa6, 0x4a, 0x64, 0x28, 0x6d, 0xfa, 0xfc, 0x3a, 0x99,
-	0xfd, 0x10, 0xd6, 0x23, 0xc9, 0xa3, 0xb3, 0x73, 0x0b, 0xaa, 0xdf, 0xd6, 0x66, 0xe8, 0xde, 0x3d,
-	0x70, 0xe7, 0x55, 0xc8, 0xf9, 0xb6, 0x78, 0xdb, 0x00, 0x65, 0xfd, 0xc3, 0x03, 0x73, 0x9c, 0xb7,
-	0x43, 0x7d, 0x4e, 0x63, 0xef, 0x7d, 0xd8, 0x98, 0x29, 0x6f, 0x0b, 0x14, 0x6e, 0xc2, 0xc6, 0x4c,
-	0xf1, 0xf2, 0xee, 0xc2, 0x7a, 0xbd, 0x02, 0xb1, 0x37, 0xa0, 0x4f, 0x35, 0xe8, 0xe4, 0x6a, 0x92,
-	0x7f, 0xb0, 0x24, 0x78, 0x43, 0x80, 0xb2, 0xd6, 0x78, 0xfb, 0xfa, 0xa9, 0x99, 0xaa, 0xc6, 0x10,
-	0x9c, 0xc4, 0x9c, 0xd5, 0x4e, 0xc2, 0xee, 0x40, 0x2f, 0xcd, 0x42, 0x91, 0x3d, 0xb8, 0xca, 0xfb,
-	0xf2, 0x01, 0xee, 0xfe, 0x91, 0xa6, 0xf1, 0x82, 0xe9, 0x0d, 0xa0, 0x5f, 0xd4, 0x12, 0xef, 0x2e,
-	0x6c, 0x35, 0x15, 0x85, 0x05, 0xcb, 0xfa, 0x35, 0x74, 0x75, 0xea, 0x63, 0x63, 0x10, 0x49, 0xf4,
-	0x99, 0x69, 0x9b, 0xcd, 0x8c, 0x5e, 0xad, 0x03, 0x75, 0x9e, 0xbf, 0x60, 0xe1, 0x18, 0x69, 0x01,
-	0x9e, 0x3c, 0xed, 0xed, 0x36, 0xd2, 0x70, 0x8c, 0xf7, 0x2e, 0x91, 0x3c, 0xa7, 0x86, 0xa0, 0xcf,
-	0x71, 0xe8, 0xdd, 0x83, 0x7e, 0x51, 0x23, 0x2a, 0x0b, 0x72, 0x16, 0x2d, 0xe8, 0xc7, 0xb0, 0x52,
-	0x29, 0x0e, 0xd7, 0x97, 0xec, 0xc3, 0xb2, 0xa9, 0x0b, 0xa8, 0xa4, 0x92, 0xe9, 0xd7, 0x57, 0xb2,
-	0x07, 0x50, 0x66, 0x78, 0x6d, 0x53, 0xf0, 0x06, 0xf8, 0xec, 0x99, 0x14, 0x79, 0xfb, 0x67, 0x66,
-	0xde, 0x2e, 0xb0, 0xd9, 0x8c, 0x5e, 0xe0, 0xf4, 0x3b, 0xd0, 0xa1, 0xd4, 0xd5, 0xd7, 0x95, 0x27,
-	0x41, 0x16, 0xc4, 0xb1, 0x88, 0xcb, 0xeb, 0x4a, 0x4e, 0xf1, 0x7f, 0x04, 0xcb, 0xc6, 0x42, 0x6c,
-	0xd1, 0x49, 0xdc, 0x58, 0xa3, 0x27, 0x48, 0x25, 0xcb, 0x8d, 0x41, 0x7a, 0xe2, 0xff, 0xc5, 0xa9,
-	0x3d, 0xbe, 0x79, 0xd0, 0x7b, 0x14, 0xa7, 0x2f, 0xac, 0x1e, 0xba, 0x98, 0x63, 0xbc, 0x96, 0xef,
-	0x84, 0x5a, 0x4d, 0x49, 0x60, 0xef, 0xc2, 0xaa, 0xad, 0xe9, 0x30, 0x34, 0xad, 0x61, 0x8d, 0x8a,
-	0x35, 0xe5, 0x51, 0xc3, 0x93, 0x82, 0x4d, 0xf3, 0xbf, 0x86, 0xad, 0xa6, 0xb6, 0x0e, 0xa3, 0xc9,
-	0xb2, 0x8c, 0xc6, 0x48, 0xfb, 0x2c, 0x35, 0x37, 0xb4, 0x3e, 0xa7, 0x31, 0xd2, 0x9e, 0xe0, 0x79,
-	0xa4, 0x2d, 0xa0, 0xb1, 0xf5, 0x36, 0xbf, 0x64, 0xbf, 0xcd, 0xef, 0x7d, 0xe3, 0xc0, 0xe0, 0xd3,
-	0x58, 0x04, 0x97, 0x8f, 0x03, 0xa9, 0xa8, 0x0b, 0x18, 0x7e, 0x2a, 0x54, 0xf9, 0x33, 0x8d, 0x55,
-	0x5e, 0x00, 0xe8, 0xb2, 0xe1, 0x6d, 0xd5, 0x5e, 0xea, 0xe8, 0x17, 0x89, 0xff, 0x1a, 0x7b, 0x1f,
-	0x56, 0x8e, 0x45, 0x12, 0x96, 0x7f, 0x3d, 0x56, 0x10, 0x58, 0x4c, 0xbd, 0x3e, 0xdd, 0xcb, 0xe9,
-	0xc7, 0xc3, 0x6b, 0x3b, 0x0e, 0xdb, 0x87, 0x5b, 0x08, 0x6f, 0xfa, 0x33, 0x70, 0x6b, 0xce, 0xdb,
-	0x5e, 0x4d, 0xc5, 0xde, 0x11, 0xac, 0x90, 0xf1, 0x1a, 0x96, 0x66, 0xec, 0x67, 0xe0, 0x99, 0xea,
-	0x53, 0x91, 0xc4, 0xe8, 0x1e, 0x4b, 0x36, 0xfb, 0x30, 0x50, 0x57, 0xf8, 0xaf, 0x16, 0x00, 0x69,
-	0xdc, 0xc7, 0x7b, 0x04, 0xfb, 0x02, 0xd6, 0xc9, 0x44, 0xeb, 0x19, 0xc7, 0xd8, 0x36, 0xfb, 0xce,
-	0xe4, 0xb9, 0xb3, 0x0c, 0x7d, 0x6d, 0x44, 0xcd, 0x77, 0x1d, 0x76, 0x1f, 0x96, 0xf5, 0xb7, 0x05,
-	0x6b, 0x7c, 0x1e, 0xf5, 0x6e, 0xd4, 0xa8, 0xb9, 0xf4, 0x5d, 0xe7, 0xff, 0x5d, 0x17, 0x3b, 0x84,
-	0xae, 0xbe, 0x35, 0x33, 0x3a, 0xe9, 0xe7, 0x5e, 0xb9, 0xbd, 0xdb, 0xf3, 0xd8, 0xb9, 0x31, 0xec,
-	0x1e, 0x2c, 0x9b, 0x6b, 0xb1, 0x09, 0x8e, 0xca, 0xcd, 0xda, 0xdb, 0xac, 0xd0, 0x72, 0xa9, 0xd3,
-	0x2e, 0xfd, 0x15, 0xfe, 0xe8, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x41, 0xa1, 0x2a, 0x72, 0x24,
-	0x1e, 0x00, 0x00,
+	// 3764 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x4d, 0x6f, 0x1c, 0x47,
+	0x76, 0xee, 0xf9, 0x9e, 0x37, 0x1c, 0x7e, 0x14, 0x29, 0xa9, 0xd5, 0xf2, 0xca, 0x4c, 0xc7, 0xb1,
+	0xb8, 0x31, 0x96, 0x2b, 0xd1, 0x4a, 0x64, 0xc8, 0x9b, 0x20, 0x14, 0x65, 0x4b, 0xb4, 0x29, 0x4b,
+	0x28, 0x72, 0xe3, 0xdd, 0x04, 0x88, 0xd0, 0x9c, 0x2e, 0x0e, 0x3b, 0x9c, 0xe9, 0x9e, 0xed, 0xaa,
+	0x91, 0xc5, 0x3d, 0xe4, 0x16, 0xec, 0x29, 0x87, 0x00, 0x41, 0x0e, 0xb9, 0xe6, 0xb8, 0xb7, 0x20,
+	0x48, 0x0e, 0xf9, 0x2d, 0x01, 0x72, 0xd8, 0xe3, 0x9e, 0x02, 0xe4, 0x1e, 0xbc, 0xfa, 0xe8, 0xae,
+	0xfe, 0x98, 0xd1, 0x28, 0x0b, 0xe4, 0xd6, 0xf5, 0xea, 0xbd, 0x57, 0xaf, 0x5e, 0xbd, 0x8f, 0xaa,
+	0x57, 0xd5, 0x30, 0x18, 0x4f, 0x58, 0x30, 0xdd, 0x9f, 0xa5, 0x89, 0x48, 0x48, 0x63, 0x76, 0xee,
+	0xff, 0x73, 0x03, 0xd6, 0x8f, 0x92, 0xe9, 0x6c, 0x2e, 0x18, 0x65, 0xbf, 0x98, 0x33, 0x2e, 0xc8,
+	0x47, 0x30, 0x08, 0x03, 0x11, 0xbc, 0x1e, 0xb1, 0x58, 0xb0, 0xd4, 0x75, 0x76, 0x9d, 0xbd, 0x3e,
+	0x05, 0x04, 0x1d, 0x49, 0x08, 0xf9, 0x33, 0xd8, 0x1a, 0x29, 0x92, 0xd7, 0x29, 0xe3, 0xc9, 0x3c,
+	0x1d, 0x31, 0xee, 0x36, 0x76, 0x9b, 0x7b, 0x83, 0x83, 0xed, 0xfd, 0xd9, 0xf9, 0x7e, 0xc6, 0x4f,
+	0xf5, 0xd1, 0xcd, 0x51, 0x11, 0xc0, 0x89, 0x07, 0xbd, 0x39, 0x67, 0x69, 0x1c, 0x4c, 0x99, 0xdb,
+	0x94, 0xfc, 0xb3, 0x36, 0xf6, 0x5d, 0x26, 0x5c, 0xc8, 0xbe, 0x96, 0xea, 0x33, 0x6d, 0xe2, 0xc3,
+	0xda, 0xc5, 0x24, 0xf9, 0xfe, 0x79, 0xc0, 0x2f, 0x8f, 0x92, 0x90, 0xb9, 0xed, 0x5d, 0x67, 0x6f,
+	0x48, 0x0b, 0x30, 0xb2, 0x03, 0xed, 0x5f, 0xcc, 0xd9, 0x9c, 0xb9, 0x1d, 0x49, 0xac, 0x1a, 0xe4,
+	0x27, 0x40, 0x66, 0x29, 0xbb, 0x60, 0x69, 0xca, 0xc2, 0x93, 0x64, 0x14, 0x88, 0x28, 0x89, 0xb9,
+	0xdb, 0x95, 0x42, 0xaf, 0xa1, 0xd0, 0x06, 0x48, 0x6b, 0xf0, 0xfc, 0xff, 0x70, 0x60, 0xa3, 0x34,
+	0x2b, 0x72, 0x07, 0xfa, 0xa3, 0xd9, 0xfc, 0xf5, 0x28, 0x99, 0xc7, 0x42, 0x2a, 0xa9, 0x4d, 0x7b,
+	0xa3, 0xd9, 0xfc, 0x08, 0xdb, 0xa6, 0x73, 0xc2, 0xde, 0xb0, 0x89, 0xdb, 0xc8, 0x3a, 0x4f, 0xb0,
+	0x8d, 0x9d, 0xe3, 0x8c, 0xb2, 0xa9, 0x3a, 0xc7, 0x16, 0xe5, 0x38, 0xa3, 0x6c, 0x65, 0x9d, 0x19,
+	0xe5, 0x94, 0x4d, 0x93, 0xf4, 0xfa, 0xf5, 0xf4, 0x5c, 0x4e, 0xbe, 0x49, 0x7b, 0x0a, 0xf0, 0xe2,
+	0x9c, 0xdc, 0x82, 0x6e, 0x18, 0xf1, 0x2b, 0xec, 0xea, 0xc8, 0xae, 0x0e, 0x36, 0x5f, 0x9c, 0xfb,
+	0x27, 0xb0, 0xf6, 0x34, 0x10, 0x41, 0x26, 0xf9, 0x1e, 0xf4, 0x26, 0x7a, 0x6a, 0x52, 0xf0, 0xb2,
+	0x06, 0xb2, 0x5e, 0x42, 0xa0, 0xc5, 0xa3, 0x5f, 0x32, 0x39, 0x83, 0x26, 0x95, 0xdf, 0xfe, 0x15,
+	0xf4, 0x0c, 0xe6, 0xbb, 0x4d, 0x85, 0x40, 0x2b, 0x0d, 0x46, 0x57, 0x92, 0x41, 0x9f, 0xca, 0x6f,
+	0x72, 0x13, 0x3a, 0x9c, 0xa5, 0x6f, 0x58, 0xaa, 0x97, 0x5e, 0xb7, 0x10, 0x77, 0x96, 0xa4, 0x42,
+	0x4f, 0x5a, 0x7e, 0xfb, 0x11, 0xc0, 0xe1, 0x24, 0x13, 0x67, 0x75, 0xc1, 0x1f, 0x40, 0x3f, 0x50,
+	0x74, 0x2c, 0x94, 0x83, 0x2f, 0x30, 0xcd, 0x1c, 0xcb, 0xff, 0x1b, 0xd8, 0xcc, 0x87, 0xa2, 0x8c,
+	0xcf, 0x27, 0x82, 0xdc, 0x87, 0x41, 0x90, 0xc1, 0xb8, 0xeb, 0x48, 0x73, 0x59, 0x47, 0x46, 0x16,
+	0xaa, 0x8d, 0x42, 0x3e, 0x87, 0xf5, 0x69, 0x34, 0x4e, 0x91, 0xe3, 0xe9, 0x65, 0x90, 0x86, 0xc6,
+	0x31, 0x36, 0x91, 0x08, 0x57, 0x21, 0x13, 0xb6, 0x84, 0xe7, 0xff, 0x57, 0x03, 0xfa, 0xcf, 0x59,
+	0x90, 0x8a, 0x73, 0x16, 0x88, 0xf7, 0x98, 0xea, 0x8f, 0xa1, 0x67, 0xbc, 0x70, 0xd9, 0x4c, 0x33,
+	0xa4, 0xa2, 0x6e, 0x9a, 0xab, 0xe8, 0x86, 0x1c, 0xc2, 0x10, 0x7d, 0xec, 0x30, 0x23, 0x6b, 0xc9,
+	0x49, 0xdd, 0x41, 0xb2, 0x4c, 0xe6, 0xfd, 0xaf, 0x6c, 0x14, 0x5a, 0xa4, 0x40, 0xb7, 0x0e, 0xd3,
+	0x20, 0x8a, 0xa3, 0x78, 0x2c, 0x2d, 0xb7, 0x47, 0xb3, 0xb6, 0x77, 0x01, 0xc3, 0x02, 0x6d, 0xc5,
+	0xcf, 0x9d, 0x1a, 0x3f, 0xff, 0x3f, 0x2c, 0x71, 0x17, 0xda, 0x5f, 0x4e, 0x67, 0xe2, 0xda, 0xff,
+	0x07, 0x47, 0xb9, 0xc4, 0x89, 0x65, 0xe8, 0x32, 0xe0, 0x28, 0x0b, 0x96, 0xdf, 0x85, 0x25, 0x68,
+	0x2c, 0x5d, 0x82, 0x9b, 0xd0, 0x49, 0xe2, 0xa7, 0x11, 0xbf, 0x92, 0xea, 0xec, 0x51, 0xdd, 0x22,
+	0xfb, 0xd0, 0xe5, 0x97, 0xf3, 0x8b, 0x8b, 0x89, 0x8a, 0x64, 0x83, 0x83, 0x1d, 0x64, 0x70, 0xaa,
+	0x40, 0xaf, 0x82, 0x54, 0x44, 0x92, 0x91, 0x41, 0xf2, 0x7f, 0xb3, 0x06, 0xdb, 0xa8, 0x88, 0x2f,
+	0xdf, 0xb2, 0xd1, 0x1c, 0xbb, 0x4e, 0x45, 0x20, 0xe6, 0x9c, 0x1c, 0x02, 0x70, 0xc1, 0x66, 0xcf,
+	0xd2, 0x64, 0x3e, 0x33, 0x56, 0xf8, 0x7b, 0xc8, 0xaa, 0x06, 0x79, 0xff, 0xd4, 0x60, 0x52, 0x8b,
+	0x08, 0x59, 0x88, 0x80, 0x5f, 0x69, 0x16, 0x8d, 0xe5, 0x2c, 0xce, 0x0c, 0x26, 0xb5, 0x88, 0xc8,
+	0x17, 0xd0, 0x43, 0xcf, 0xe6, 0x4c, 0x70, 0xb7, 0x29, 0x19, 0x7c, 0xb4, 0x88, 0xc1, 0x53, 0x85,
+	0x47, 0x33, 0x02, 0xf2, 0x35, 0x0c, 0xf5, 0xb7, 0x76, 0x0b, 0x65, 0x41, 0x1f, 0xbf, 0x83, 0x83,
+	0x44, 0xa6, 0x45, 0x52, 0x72, 0x00, 0x6d, 0x14, 0x8b, 0xbb, 0x6d, 0xc9, 0xe3, 0xc3, 0x65, 0xd3,
+	0xa0, 0x0a, 0x15, 0x69, 0x50, 0x1b, 0xdc, 0xed, 0x2c, 0xa7, 0x41, 0xed, 0x51, 0x85, 0x4a, 0xd6,
+	0xa1, 0x11, 0x85, 0x6e, 0x57, 0xda, 0x5e, 0x23, 0x0a, 0xc9, 0x63, 0xe8, 0x84, 0x69, 0x84, 0x81,
+	0xab, 0x27, 0x57, 0xd3, 0x5f, 0x28, 0xbc, 0xc4, 0x3a, 0x8e, 0x2f, 0x12, 0xaa, 0x29, 0xbc, 0x7d,
+	0x68, 0xa1, 0x38, 0x32, 0xf8, 0x09, 0x36, 0x3b, 0x0e, 0x75, 0xca, 0xd0, 0x2d, 0x3d, 0x96, 0xca,
+	0x14, 0x8d, 0x28, 0xf4, 0xfe, 0xd5, 0x81, 0x16, 0xca, 0xa2, 0x3b, 0x1c, 0xd3, 0x91, 0x59, 0x6a,
+	0xc3, 0xb2, 0xd4, 0x0f, 0xa1, 0x3f, 0x0b, 0x52, 0x16, 0x8b, 0xe3, 0x50, 0x2d, 0x4d, 0x9b, 0xe6,
+	0x00, 0xe2, 0x42, 0x17, 0x75, 0x70, 0xac, 0x95, 0xde, 0xa6, 0xa6, 0x49, 0x3e, 0x81, 0xf5, 0x28,
+	0x9e, 0xcd, 0x85, 0x56, 0xf6, 0x71, 0x28, 0x35, 0xda, 0xa6, 0x25, 0x28, 0xd9, 0x83, 0x8d, 0x64,
+	0x2e, 0x0a, 0x88, 0x1d, 0x29, 0x50, 0x19, 0xec, 0xfd, 0x1c, 0xba, 0xba, 0x51, 0x11, 0x3c, 0x9f,
+	0x79, 0xa3, 0x30, 0xf3, 0x4f, 0x60, 0x3d, 0x65, 0x41, 0x18, 0xc5, 0xe3, 0x53, 0x09, 0x30, 0x33,
+	0x28, 0x41, 0xbd, 0x9f, 0x28, 0x97, 0x35, 0x66, 0x80, 0x93, 0x0e, 0x33, 0x71, 0xd4, 0x30, 0x39,
+	0xa0, 0xa2, 0xcf, 0x23, 0xe8, 0x67, 0x8e, 0x81, 0x1a, 0xe1, 0x7a, 0x2c, 0x47, 0x69, 0x44, 0x37,
+	0x8b, 0x9a, 0x6c, 0x94, 0x34, 0xe9, 0xfd, 0xa6, 0x09, 0xfd, 0xcc, 0x37, 0x96, 0x70, 0xb1, 0x34,
+	0xde, 0x28, 0x6a, 0x7c, 0x1f, 0xba, 0xa9, 0xda, 0x66, 0xb9, 0xcd, 0x3c, 0x22, 0x64, 0xf6, 0xa3,
+	0xb7, 0x60, 0xd4, 0x20, 0x91, 0x7d, 0x80, 0x3c, 0xbb, 0xe8, 0x20, 0x52, 0xce, 0x3f, 0x16, 0x06,
+	0xf9, 0x06, 0x80, 0x19, 0x66, 0xc6, 0x3f, 0x3e, 0x7d, 0xa7, 0x9b, 0x5b, 0x02, 0x58, 0xe4, 0xde,
+	0xff, 0x38, 0xd0, 0xcf, 0x7a, 0xc8, 0x0f, 0x30, 0x08, 0x05, 0xa9, 0x78, 0x2d, 0x22, 0x1d, 0x28,
+	0x9b, 0xb4, 0x2f, 0x21, 0x67, 0xd1, 0x54, 0x6e, 0x87, 0xb8, 0x48, 0x66, 0xaa, 0x57, 0xed, 0x17,
+	0x7a, 0x08, 0x90, 0x9d, 0x1f, 0xc1, 0x80, 0x5f, 0x73, 0xc1, 0xa6, 0xaa, 0x1b, 0xa7, 0xee, 0x50,
+	0x50, 0x20, 0x43, 0x8d, 0x1b, 0x40, 0xd5, 0xdd, 0x92, 0xdd, 0x72, 0x47, 0x28, 0x3b, 0x77, 0xa0,
+	0xcd, 0xd2, 0x34, 0x49, 0x65, 0xde, 0x58, 0xa3, 0xaa, 0x81, 0x3c, 0x95, 0xf5, 0xbd, 0xbe, 0x0c,
+	0xf8, 0xa5, 0x34, 0xc8, 0x35, 0x0a, 0x0a, 0x84, 0x49, 0x82, 0x3c, 0x82, 0x21, 0xb3, 0x67, 0x2c,
+	0x3d, 0x79, 0x70, 0xb0, 0x55, 0xd0, 0x38, 0x76, 0xd0, 0x22, 0x9e, 0xf7, 0x9f, 0x0e, 0x40, 0xee,
+	0xc2, 0x85, 0xcd, 0xaa, 0xb3, 0x64, 0xb3, 0xda, 0x28, 0x6d, 0x56, 0xef, 0x9a, 0xb5, 0x08, 0xce,
+	0x27, 0x66, 0x9b, 0x6b, 0x41, 0xc8, 0x3d, 0xd8, 0xc8, 0x5b, 0x6a, 0x12, 0x6a, 0xbf, 0xbb, 0x9e,
+	0x83, 0xe5, 0x44, 0x8a, 0x9a, 0x6f, 0x2f, 0xd5, 0x7c, 0xa7, 0xa4, 0x79, 0x13, 0x2e, 0xba, 0x79,
+	0xb8, 0xf0, 0xff, 0xce, 0x81, 0xed, 0xaf, 0xa2, 0x49, 0x9e, 0x22, 0xb5, 0xb1, 0xd5, 0x25, 0xc1,
+	0x4d, 0x68, 0x86, 0x51, 0xaa, 0xe7, 0x86, 0x9f, 0x88, 0x25, 0x65, 0x6d, 0xca, 0xb8, 0x28, 0xbf,
+	0x2b, 0xf9, 0xba, 0x55, 0x93, 0xaf, 0x5d, 0xe8, 0x8e, 0x92, 0x58, 0xb0, 0x58, 0xe8, 0x75, 0x34,
+	0x4d, 0xff, 0x04, 0x76, 0x8a, 0xe2, 0xf0, 0x59, 0x12, 0x73, 0x46, 0x3e, 0x86, 0x61, 0x30, 0xc1,
+	0x28, 0x70, 0xfd, 0xe5, 0xdb, 0x88, 0x0b, 0x2e, 0x05, 0xeb, 0xd1, 0x22, 0x10, 0x3d, 0x3d, 0x51,
+	0x1b, 0xcc, 0x1e, 0x6d, 0x24, 0x57, 0xfe, 0xbf, 0x3b, 0xb0, 0x59, 0x76, 0x28, 0xf2, 0x18, 0x23,
+	0x1d, 0x17, 0xe9, 0x7c, 0x24, 0x57, 0x99, 0x09, 0xbd, 0xa9, 0x22, 0x68, 0x0c, 0xc7, 0x85, 0x1e,
+	0x5a, 0xc2, 0xac, 0x51, 0x81, 0xbd, 0xe5, 0x6a, 0xae, 0xb2, 0xe5, 0xfa, 0x18, 0x86, 0x11, 0x7f,
+	0x95, 0x32, 0x36, 0x9d, 0x89, 0xe8, 0x5c, 0x6f, 0x07, 0x7a, 0xb4, 0x08, 0xf4, 0xff, 0xcd, 0x81,
+	0x2d, 0x4b, 0x72, 0xad, 0x05, 0xdc, 0x5c, 0x48, 0xa3, 0x96, 0x22, 0xaf, 0x51, 0xdd, 0xca, 0xbd,
+	0xa2, 0x61, 0x7b, 0xc5, 0x5d, 0xb0, 0xdc, 0xaa, 0xc6, 0xd1, 0xb4, 0x31, 0x9f, 0xd5, 0xf9, 0x59,
+	0xc5, 0x61, 0xda, 0xab, 0x39, 0x8c, 0xff, 0x57, 0x30, 0x2c, 0xf4, 0xaf, 0xb4, 0x7f, 0xfb, 0x21,
+	0x66, 0xe4, 0x40, 0x14, 0x4e, 0x8e, 0xf6, 0x4a, 0xe0, 0x38, 0x0a, 0xc3, 0xff, 0x6d, 0x13, 0x36,
+	0x4a, 0x5d, 0x0b, 0x13, 0xe9, 0x4d, 0xe8, 0xa8, 0x60, 0x6b, 0xd2, 0x8c, 0x6a, 0xa1, 0x48, 0x32,
+	0xab, 0xc9, 0x53, 0x96, 0x3e, 0x7b, 0x34, 0x69, 0x01, 0x86, 0xcb, 0xa4, 0x94, 0x6b, 0x90, 0x5a,
+	0x12, 0xa9, 0x08, 0x24, 0x8f, 0xa0, 0x37, 0x52, 0x9f, 0x26, 0xc2, 0xde, 0xa9, 0x91, 0x7d, 0x5f,
+	0xa3, 0xd3, 0x0c, 0x99, 0xfc, 0x09, 0xc0, 0x65, 0xc4, 0x45, 0x32, 0x4e, 0x83, 0xa9, 0xd9, 0x88,
+	0xfc, 0xa0, 0x8e, 0xf4, 0xb9, 0xc1, 0xa2, 0x16, 0x01, 0xf9, 0x43, 0xd8, 0x54, 0x82, 0xc8, 0xfc,
+	0xf7, 0xe4, 0x5a, 0x30, 0x75, 0x80, 0x6d, 0xd2, 0x0a, 0xdc, 0xfb, 0x0c, 0xba, 0x46, 0xdc, 0x3a,
+	0xaf, 0xde, 0x81, 0xf6, 0x9b, 0x60, 0x32, 0x37, 0x81, 0x5a, 0x35, 0xbc, 0xbf, 0x75, 0xa0, 0x9f,
+	0x0d, 0xbd, 0x88, 0x4e, 0x9d, 0x5a, 0x35, 0x9d, 0x6c, 0xa0, 0x83, 0xf0, 0xf9, 0x54, 0x1b, 0x1b,
+	0x7e, 0x22, 0x64, 0x1a, 0xc5, 0xda, 0xc0, 0xf0, 0x53, 0x42, 0x82, 0xb7, 0x6e, 0x5b, 0x43, 0x82,
+	0xb7, 0x18, 0x0f, 0xce, 0xe7, 0xa3, 0x2b, 0x26, 0x94, 0x2a, 0x9a, 0xd4, 0x34, 0xfd, 0xbf, 0x97,
+	0x35, 0x89, 0x58, 0xa4, 0xc9, 0xe4, 0x05, 0xe3, 0x3c, 0x18, 0xcb, 0x58, 0x1a, 0xf1, 0x97, 0x72,
+	0x57, 0x7d, 0xfc, 0x52, 0xc7, 0x01, 0x0b, 0x42, 0x1e, 0xc0, 0x00, 0x63, 0x82, 0x76, 0x77, 0xbd,
+	0x5d, 0xdf, 0x40, 0xdd, 0xd2, 0x1c, 0x4c, 0x6d, 0x1c, 0xf2, 0x10, 0xd6, 0xbe, 0x4f, 0xa3, 0xac,
+	0xec, 0xa1, 0x1d, 0x59, 0x9e, 0xd3, 0xbe, 0xb3, 0xe0, 0xb4, 0x80, 0x45, 0x76, 0x61, 0x80, 0xd5,
+	0x8c, 0x94, 0x71, 0x6e, 0x32, 0x72, 0x9f, 0xda, 0x20, 0x7b, 0xd3, 0xdf, 0x5e, 0x61, 0xd3, 0x2f,
+	0xa3, 0xdc, 0x5c, 0x5c, 0x26, 0x69, 0xf4, 0x4b, 0x95, 0xe5, 0x55, 0xdd, 0xa2, 0x08, 0xf4, 0x7f,
+	0x0c, 0xb7, 0x9f, 0xb2, 0x09, 0x13, 0xac, 0xb0, 0x31, 0x5e, 0x1c, 0xb8, 0xfd, 0x03, 0xf0, 0xea,
+	0x08, 0x74, 0x50, 0xc9, 0x82, 0x87, 0x22, 0x51, 0x0d, 0xff, 0x21, 0xac, 0x1f, 0x4d, 0x58, 0x10,
+	0xcf, 0x67, 0x86, 0xf3, 0x0a, 0x8e, 0xec, 0xdf, 0x83, 0x8d, 0x8c, 0x6a, 0x29, 0xfb, 0x14, 0xd6,
+	0xbe, 0x2b, 0xeb, 0xf2, 0x32, 0x88, 0x63, 0x36, 0xf9, 0x36, 0x97, 0xde, 0x06, 0xe1, 0xb2, 0x4b,
+	0xed, 0xa7, 0xdf, 0xe6, 0x09, 0xd6, 0x82, 0x20, 0x07, 0x5c, 0x52, 0x96, 0x1e, 0x59, 0xb5, 0x14,
+	0x1b, 0xe4, 0x73, 0x18, 0x58, 0x16, 0xb0, 0xda, 0x90, 0x8a, 0xde, 0x1e, 0x32, 0x87, 0xc8, 0x38,
+	0xc2, 0x8f, 0x63, 0x3e, 0x63, 0x23, 0x81, 0x67, 0x59, 0x75, 0xe2, 0x2b, 0xc0, 0xfc, 0x5f, 0x35,
+	0x60, 0xbd, 0x98, 0x54, 0xc8, 0x67, 0x18, 0x7e, 0x32, 0x88, 0x39, 0xc4, 0x6d, 0x94, 0xbc, 0x9f,
+	0x16, 0x90, 0xca, 0xd3, 0x6b, 0x54, 0xa6, 0x57, 0x59, 0x9f, 0x66, 0x4d, 0xa0, 0xdd, 0x85, 0x01,
+	0xe6, 0x99, 0xe4, 0x22, 0x9a, 0xa0, 0xc0, 0x2a, 0xf5, 0xd8, 0x20, 0xe4, 0x12, 0x8c, 0x59, 0x2c,
+	0x0e, 0xc3, 0x10, 0xad, 0x58, 0xda, 0x6d, 0x9f, 0x16, 0x60, 0x99, 0x8d, 0x75, 0xac, 0x70, 0x50,
+	0x72, 0x86, 0x6e, 0xc5, 0x19, 0xfc, 0xdf, 0xde, 0x85, 0x81, 0x35, 0xbf, 0xf7, 0x8e, 0xda, 0x77,
+	0x01, 0x54, 0x7d, 0xeb, 0x38, 0x7e, 0xf1, 0x44, 0xaf, 0xaf, 0x05, 0x21, 0x5f, 0xc3, 0xb6, 0x8c,
+	0xe0, 0xd2, 0xba, 0xf3, 0xba, 0x9e, 0x3a, 0x5c, 0xba, 0xa6, 0xe6, 0xc2, 0x59, 0x11, 0x81, 0xd6,
+	0x11, 0x91, 0x13, 0xd8, 0x79, 0x39, 0x17, 0x15, 0xb8, 0xdb, 0x7e, 0x07, 0xb3, 0x5a, 0x2a, 0xb2,
+	0x8f, 0x55, 0xae, 0x09, 0x1b, 0x09, 0xa9, 0xb1, 0xc1, 0xc1, 0xcd, 0xd2, 0x52, 0xef, 0x9f, 0xca,
+	0x5e, 0xaa, 0xb1, 0xc8, 0x5f, 0xc2, 0x8d, 0xbf, 0x4e, 0xa2, 0x38, 0x0b, 0x10, 0x2c, 0x3c, 0x4d,
+	0x52, 0xc1, 0x42, 0xbd, 0x6b, 0xfd, 0x83, 0x32, 0xf9, 0xd7, 0x75, 0xc8, 0xb4, 0x9e, 0x07, 0x09,
+	0xc1, 0x1d, 0x25, 0x72, 0xab, 0x5f, 0xe5, 0xaf, 0xce, 0xb2, 0x7b, 0x65, 0xfe, 0x47, 0x0b, 0xf0,
+	0xe9, 0x42, 0x4e, 0xe4, 0x31, 0xc0, 0x2c, 0x9a, 0xb1, 0x43, 0x7e, 0x98, 0x8e, 0xb9, 0xdb, 0x97,
+	0x7c, 0xbd, 0x32, 0xdf, 0x57, 0x19, 0x06, 0xb5, 0xb0, 0xc9, 0x4b, 0xd8, 0xe2, 0xa3, 0x40, 0x08,
+	0x96, 0x66, 0x7c, 0xb9, 0x0b, 0xbb, 0x8e, 0x29, 0x53, 0x14, 0x34, 0x57, 0x46, 0xa4, 0x55, 0x5a,
+	0x64, 0x38, 0x4a, 0x26, 0xa8, 0x5a, 0x8b, 0xe1, 0xa0, 0x9e, 0xe1, 0x51, 0x19, 0x91, 0x56, 0x69,
+	0xc9, 0x09, 0x6c, 0x2a, 0xab, 0x99, 0x4d, 0x22, 0x41, 0xa5, 0x0f, 0xba, 0x6b, 0x92, 0xdf, 0x6e,
+	0x99, 0xdf, 0x71, 0x09, 0x8f, 0x56, 0x28, 0x51, 0x57, 0x69, 0x32, 0x8f, 0x43, 0x9a, 0x9c, 0x47,
+	0xb1, 0x3b, 0xac, 0xd7, 0x15, 0xcd, 0x30, 0xa8, 0x85, 0x4d, 0x1e, 0xaa, 0xc2, 0xd4, 0xe4, 0x2c,
+	0x99, 0xb9, 0xeb, 0xbb, 0x8e, 0x31, 0x4e, 0x9b, 0xf2, 0x44, 0xf7, 0xd3, 0x0c, 0x93, 0x3c, 0x82,
+	0xfe, 0x79, 0x9a, 0x04, 0xe1, 0x28, 0xe0, 0xc2, 0xdd, 0x90, 0x64, 0xb7, 0xcb, 0x64, 0x4f, 0x0c,
+	0x02, 0xcd, 0x71, 0xc9, 0xcf, 0x60, 0x47, 0x32, 0xc1, 0x80, 0x72, 0x18, 0x87, 0x68, 0x78, 0xdf,
+	0x45, 0xe2, 0xd2, 0xdd, 0xdc, 0x75, 0x4c, 0x05, 0xa7, 0x32, 0x74, 0x09, 0x97, 0xd6, 0x72, 0x90,
+	0x3e, 0x32, 0x4a, 0xa3, 0x99, 0x70, 0xb7, 0x16, 0xf8, 0x88, 0xec, 0xa5, 0x1a, 0x0b, 0xa7, 0x20,
+	0xf9, 0xa0, 0xbd, 0xb9, 0xa4, 0x7e, 0x0a, 0x27, 0x06, 0x81, 0xe6, 0xb8, 0xe4, 0x08, 0x86, 0x53,
+	0x96, 0x8e, 0x99, 0x32, 0xd4, 0xb3, 0xc4, 0xdd, 0xde, 0x75, 0x6a, 0x36, 0x5f, 0xfb, 0x2f, 0x6c,
+	0x24, 0x5a, 0xa4, 0x21, 0x0f, 0xa0, 0x2b, 0x01, 0x67, 0x89, 0xbb, 0x23, 0xc9, 0x6f, 0xd5, 0x92,
+	0x9f, 0x25, 0xd4, 0xe0, 0xe1, 0xb8, 0x52, 0x88, 0xa7, 0x11, 0x17, 0x51, 0x3c, 0x12, 0xee, 0x8d,
+	0xfa, 0x71, 0x4f, 0x6c, 0x24, 0x5a, 0xa4, 0x41, 0x53, 0x91, 0x80, 0x93, 0x68, 0x1a, 0x09, 0xf7,
+	0x66, 0xbd, 0xa9, 0x9c, 0x64, 0x18, 0xd4, 0xc2, 0x26, 0x14, 0x88, 0x6c, 0x49, 0x8f, 0x7d, 0x72,
+	0xad, 0x5d, 0xfe, 0x56, 0x5e, 0xbe, 0xaa, 0xf0, 0x28, 0x60, 0xd2, 0x1a, 0x6a, 0xf2, 0x29, 0xb4,
+	0xe7, 0x31, 0xc6, 0x7b, 0x57, 0xb2, 0xb9, 0x51, 0x66, 0xf3, 0x53, 0xec, 0xa4, 0x0a, 0x87, 0x04,
+	0x70, 0x4b, 0xfb, 0xe6, 0xe9, 0x15, 0xfb, 0x9e, 0x85, 0x96, 0x33, 0xde, 0x96, 0xe4, 0xf7, 0x16,
+	0x78, 0x77, 0x19, 0x9d, 0x2e, 0xe2, 0x83, 0x4a, 0xe6, 0xc1, 0x74, 0x36, 0x61, 0xcf, 0x13, 0xf1,
+	0x0d, 0xbb, 0xe6, 0xae, 0x57, 0xaf, 0xe4, 0x53, 0x1b, 0x89, 0x16, 0x69, 0xc8, 0x14, 0xee, 0x68,
+	0xfe, 0x94, 0xcd, 0x26, 0x91, 0xac, 0x17, 0x5b, 0xb2, 0xde, 0xd9, 0x75, 0x4c, 0x25, 0xa5, 0x46,
+	0xd6, 0x3a, 0x12, 0xba, 0x8c, 0x1f, 0xf9, 0x0a, 0xd6, 0xd5, 0xf8, 0xa8, 0x53, 0x29, 0xf4, 0x87,
+	0x72, 0x84, 0xbb, 0xf5, 0x42, 0x1b, 0x2c, 0x5a, 0xa2, 0xc2, 0xf5, 0xd5, 0x97, 0x6b, 0x32, 0xb8,
+	0xbc, 0x4a, 0xa2, 0x58, 0x70, 0xf7, 0x07, 0xf5, 0xeb, 0x7b, 0x54, 0xc1, 0xa4, 0x35, 0xd4, 0x52,
+	0x9f, 0x5a, 0xf4, 0x20, 0x1e, 0x33, 0xee, 0xde, 0x5d, 0xa0, 0x4f, 0x1b, 0x89, 0x16, 0x69, 0xc8,
+	0x18, 0x6e, 0x73, 0x36, 0x8d, 0x6a, 0xb3, 0x94, 0xfb, 0x91, 0x64, 0xf8, 0xc3, 0x0a, 0xc3, 0x45,
+	0x04, 0x74, 0x31, 0xaf, 0x9a, 0xbc, 0x89, 0x51, 0x86, 0x85, 0xee, 0xee, 0x4a, 0x79, 0x53, 0x21,
+	0xd3, 0x7a, 0x1e, 0xde, 0x09, 0x74, 0x54, 0x9a, 0xc6, 0x8d, 0xc8, 0x15, 0xbb, 0x3e, 0x8e, 0x43,
+	0xf6, 0x96, 0x99, 0x7a, 0x9f, 0x05, 0xc1, 0x2d, 0x94, 0x3c, 0x44, 0x19, 0x0c, 0x55, 0xf7, 0x2b,
+	0xc0, 0xbc, 0x5f, 0x39, 0x70, 0xa3, 0x7e, 0x12, 0x2e, 0x74, 0xa3, 0x02, 0x6b, 0xd3, 0xc4, 0xd2,
+	0x6b, 0xc4, 0x4f, 0xd8, 0x85, 0x78, 0x39, 0x17, 0x2c, 0x45, 0x6a, 0x5d, 0xea, 0x28, 0x83, 0xf1,
+	0x78, 0x18, 0x71, 0x1a, 0x8d, 0x2f, 0x2d, 0x54, 0xb5, 0x39, 0xad, 0xc0, 0xbd, 0x87, 0xe0, 0x2e,
+	0xca, 0xef, 0x8b, 0x65, 0xf1, 0x76, 0x01, 0xf2, 0xec, 0x8d, 0x1b, 0xc2, 0x91, 0x39, 0x12, 0xf4,
+	0xa9, 0xfc, 0xf6, 0x7e, 0x04, 0x5b, 0x95, 0xe4, 0xbc, 0x84, 0xe1, 0x36, 0x6c, 0x55, 0x52, 0xaf,
+	0x77, 0x1f, 0x36, 0xcb, 0xf9, 0x13, 0xcb, 0xb2, 0x32, 0x83, 0x9e, 0x5d, 0xcf, 0xcc, 0x80, 0x39,
+	0xc0, 0x5b, 0x03, 0xc8, 0x33, 0xa5, 0x77, 0xa8, 0xee, 0x27, 0x65, 0xce, 0x5b, 0x03, 0x27, 0xd6,
+	0x3b, 0x4d, 0x27, 0x26, 0xf7, 0xa0, 0x97, 0xa4, 0x21, 0x4b, 0x9f, 0x5c, 0x9b, 0xa2, 0xc3, 0x00,
+	0xad, 0xe3, 0xa5, 0x82, 0xd1, 0xac, 0xd3, 0x1b, 0x40, 0x3f, 0xcb, 0x84, 0xde, 0x7d, 0xd8, 0xa9,
+	0x4b, 0x69, 0x4b, 0xa6, 0xf5, 0x17, 0xd0, 0x51, 0x89, 0x0b, 0xb7, 0xb5, 0x11, 0x47, 0x9d, 0xe9,
+	0x23, 0xab, 0x6e, 0xc9, 0xab, 0xce, 0x40, 0x5c, 0x9a, 0x22, 0x3e, 0x7e, 0x23, 0x2c, 0x48, 0xc7,
+	0xaa, 0xfa, 0xdd, 0xa7, 0xf2, 0x1b, 0x4f, 0xcd, 0x2c, 0x7e, 0x23, 0xb7, 0xb3, 0x7d, 0x8a, 0x9f,
+	0xde, 0x43, 0xe8, 0x67, 0x19, 0xae, 0x30, 0x21, 0x67, 0xd9, 0x84, 0x3e, 0x87, 0x61, 0x21, 0xb5,
+	0xad, 0x4e, 0xd9, 0x87, 0xae, 0xce, 0x6a, 0xc8, 0xa4, 0x90, 0xa7, 0x56, 0x67, 0x72, 0x00, 0x90,
+	0xe7, 0xa7, 0xd2, 0xa2, 0x60, 0x79, 0xeb, 0xe2, 0x82, 0x33, 0x73, 0xbc, 0xd1, 0x2d, 0x6f, 0x1f,
+	0x48, 0x35, 0x1f, 0x2d, 0x51, 0xfa, 0x3d, 0x68, 0xcb, 0xc4, 0xa3, 0x4a, 0x05, 0xaf, 0x82, 0x34,
+	0x98, 0x4c, 0xd8, 0x24, 0x2f, 0x15, 0x18, 0x88, 0xf7, 0x19, 0xdc, 0x5a, 0x90, 0x62, 0x96, 0x70,
+	0xbf, 0x82, 0x61, 0x21, 0x7d, 0x2c, 0xf1, 0x58, 0xac, 0xc0, 0xa9, 0x20, 0x6d, 0x6e, 0xce, 0xdb,
+	0xd4, 0x82, 0xe0, 0xa1, 0xe9, 0x52, 0x32, 0xa1, 0x78, 0x52, 0xd0, 0x55, 0x13, 0x1b, 0xe4, 0x3d,
+	0x82, 0x3b, 0x4b, 0x12, 0xcb, 0x12, 0x29, 0x7f, 0x0e, 0xeb, 0xc5, 0x7c, 0xb1, 0xf2, 0x12, 0xbd,
+	0x4b, 0x6a, 0x8f, 0x01, 0xa9, 0xa6, 0x8f, 0xd5, 0xd9, 0x7f, 0x02, 0xeb, 0x33, 0x33, 0x03, 0xfb,
+	0x30, 0x5b, 0x82, 0xa2, 0x8d, 0x15, 0xd2, 0xca, 0xea, 0x36, 0xf6, 0x53, 0xb8, 0xbd, 0x30, 0x7f,
+	0x2c, 0x5f, 0xad, 0x88, 0x1f, 0xc6, 0x22, 0xb2, 0x42, 0xab, 0x05, 0xf1, 0x7e, 0x5d, 0x8d, 0xd9,
+	0x2a, 0x37, 0xfc, 0x7f, 0xc7, 0x6c, 0x55, 0x78, 0x40, 0x72, 0x9d, 0xdf, 0x5a, 0xa6, 0xf0, 0x90,
+	0xc3, 0xfc, 0x3f, 0x82, 0xae, 0xd6, 0x0c, 0x96, 0x60, 0xa4, 0x3c, 0xda, 0xd3, 0x54, 0x03, 0xa1,
+	0x52, 0x63, 0x5a, 0xfd, 0xaa, 0x91, 0x5d, 0x87, 0x67, 0x77, 0x6b, 0x1e, 0xf4, 0xf0, 0xc2, 0xc8,
+	0xaa, 0x91, 0x64, 0x6d, 0x8c, 0xc5, 0xf9, 0x35, 0xa0, 0x62, 0x93, 0x03, 0x70, 0xa1, 0x6d, 0x4e,
+	0xc7, 0xa1, 0x3e, 0xb4, 0x97, 0xa0, 0x38, 0x9b, 0xaf, 0x6a, 0x6e, 0x0c, 0x6c, 0x98, 0xff, 0x4f,
+	0x0e, 0xec, 0xd4, 0x9d, 0xb8, 0x31, 0x54, 0x5a, 0xa2, 0xc9, 0x6f, 0x84, 0x3d, 0x4f, 0x74, 0xe9,
+	0xaf, 0x4f, 0xe5, 0x37, 0xc2, 0x5e, 0xe1, 0x51, 0x41, 0x89, 0x20, 0xbf, 0xad, 0xbb, 0xfa, 0xd6,
+	0xa2, 0xbb, 0xfa, 0x55, 0xca, 0x76, 0x3e, 0x83, 0x75, 0x5d, 0xbb, 0x7f, 0x8f, 0x5a, 0xd9, 0x7b,
+	0x3f, 0xd6, 0xf0, 0x9f, 0xc2, 0x46, 0x36, 0x8c, 0x2e, 0xae, 0x3d, 0x80, 0xfe, 0x4c, 0x81, 0x58,
+	0xe8, 0x3a, 0x8b, 0x99, 0xe4, 0x58, 0xfe, 0xe7, 0x40, 0x5e, 0x04, 0x1c, 0x43, 0x9e, 0x08, 0xf2,
+	0xfa, 0x9b, 0x0f, 0x6b, 0x3c, 0x8a, 0x47, 0xec, 0xcf, 0x59, 0xca, 0xcd, 0x3b, 0x93, 0x16, 0x2d,
+	0xc0, 0xfc, 0x5f, 0x37, 0x60, 0x60, 0x91, 0xa2, 0x65, 0x44, 0xfc, 0x70, 0x24, 0xa2, 0x37, 0x26,
+	0xa7, 0x65, 0x6d, 0xf4, 0x88, 0x37, 0x9a, 0x55, 0x43, 0xb2, 0x32, 0x4d, 0x72, 0x1f, 0xaf, 0x3d,
+	0x47, 0x49, 0x1a, 0x9a, 0x97, 0x03, 0xf2, 0xa4, 0x67, 0xf1, 0xdd, 0xa7, 0xb2, 0x9b, 0x1a, 0x34,
+	0xb4, 0xb2, 0xec, 0x86, 0x4b, 0x97, 0xe1, 0x73, 0x00, 0x2e, 0xac, 0x60, 0xe9, 0x54, 0xae, 0x54,
+	0x8b, 0xca, 0x6f, 0xe5, 0xc9, 0xa7, 0x71, 0x30, 0xe3, 0x97, 0x89, 0x2a, 0xba, 0xf4, 0xa8, 0x05,
+	0xf1, 0xce, 0xa1, 0xa3, 0x06, 0x41, 0x13, 0x50, 0xa5, 0x66, 0x6d, 0x40, 0xba, 0x85, 0xd9, 0xf6,
+	0x8a, 0x5d, 0xeb, 0xfb, 0x14, 0xfc, 0xcc, 0xeb, 0xe4, 0x4d, 0x09, 0x53, 0x0d, 0x9c, 0x67, 0x28,
+	0x4b, 0xab, 0xc6, 0x09, 0x4d, 0xd3, 0xff, 0x7d, 0xd8, 0x3a, 0x0a, 0xe2, 0x11, 0x9b, 0xa0, 0x1d,
+	0x1b, 0x35, 0xe7, 0x17, 0xe1, 0xf2, 0x19, 0x81, 0x7f, 0x04, 0x5b, 0x4f, 0xf1, 0xe5, 0xcb, 0x21,
+	0x96, 0xd7, 0x0c, 0xd2, 0x0e, 0xb4, 0x65, 0xb9, 0xcd, 0x54, 0x4c, 0x65, 0x03, 0x47, 0xd2, 0xaf,
+	0x84, 0x74, 0x04, 0x31, 0x4d, 0xff, 0x5b, 0x20, 0x36, 0x13, 0x6d, 0x1a, 0xd5, 0xd7, 0x47, 0xce,
+	0x8a, 0xaf, 0x8f, 0xde, 0xc0, 0x9a, 0xe4, 0x67, 0xe4, 0xb1, 0x46, 0x76, 0x0a, 0x23, 0x63, 0xbd,
+	0xda, 0x36, 0x69, 0xb5, 0x95, 0x1a, 0xd2, 0x22, 0x90, 0x7c, 0x82, 0x57, 0xe0, 0xe9, 0x38, 0x7f,
+	0x2b, 0x52, 0x7c, 0x3b, 0x63, 0x3a, 0xfd, 0x63, 0x18, 0xea, 0x71, 0x7f, 0xe7, 0x29, 0x4c, 0x60,
+	0xfd, 0xeb, 0xe4, 0xfc, 0x24, 0x19, 0xf3, 0x05, 0x9a, 0xb7, 0x6f, 0xec, 0x1b, 0x95, 0x7b, 0x7f,
+	0x11, 0x44, 0x13, 0x75, 0xa9, 0xa2, 0xae, 0x86, 0x72, 0x40, 0x56, 0xfb, 0x6c, 0x59, 0xf5, 0xf5,
+	0x2f, 0x60, 0x23, 0x1b, 0x4d, 0x8b, 0xbe, 0x07, 0x3d, 0x2c, 0x5b, 0x22, 0xcc, 0x75, 0xf2, 0x49,
+	0x9f, 0x69, 0x18, 0xcd, 0x7a, 0xfd, 0x10, 0x7a, 0x06, 0xba, 0xe8, 0x9e, 0x45, 0x59, 0x43, 0xa3,
+	0x64, 0x0d, 0xe6, 0x06, 0xb5, 0x59, 0xb8, 0x41, 0xcd, 0xeb, 0xed, 0x2d, 0xbb, 0xde, 0x7e, 0x01,
+	0xeb, 0xcf, 0x98, 0xb0, 0x15, 0xb2, 0x4a, 0x88, 0x5a, 0xf0, 0xc0, 0x64, 0xb1, 0x7a, 0xfc, 0x4f,
+	0x61, 0x23, 0x1b, 0x47, 0xab, 0xc2, 0x12, 0xd5, 0x29, 0x5e, 0xf6, 0xfe, 0x0c, 0x36, 0xcb, 0x41,
+	0xb5, 0x56, 0x05, 0x37, 0xa1, 0x33, 0x0d, 0x66, 0xb3, 0x2c, 0x55, 0xe9, 0x96, 0x34, 0x4c, 0xf9,
+	0x65, 0xde, 0x89, 0x98, 0xa6, 0xff, 0x0c, 0x6e, 0xe9, 0x6c, 0x91, 0x55, 0x61, 0x17, 0x19, 0x42,
+	0xe1, 0xed, 0x48, 0xa3, 0xf4, 0x76, 0xc4, 0xa7, 0xe0, 0x56, 0x19, 0xe9, 0x89, 0xfd, 0xb1, 0x2a,
+	0x41, 0xd9, 0x45, 0xfc, 0xc5, 0x95, 0xe1, 0x1c, 0xd5, 0x7f, 0x00, 0x1b, 0x47, 0xc1, 0x2c, 0x18,
+	0x45, 0xe2, 0xda, 0x08, 0x75, 0x17, 0xac, 0x97, 0x92, 0xd5, 0xb7, 0x93, 0xfe, 0x3f, 0x3a, 0xb0,
+	0x99, 0xd3, 0xe8, 0xf1, 0xed, 0x04, 0xe2, 0xbc, 0xf7, 0x6b, 0xbf, 0x95, 0x9e, 0xc9, 0xa1, 0x60,
+	0xd2, 0xe0, 0xec, 0x4b, 0x15, 0x0b, 0x72, 0xf0, 0x2f, 0x2d, 0x18, 0x3c, 0xc3, 0x77, 0xc4, 0x2a,
+	0x80, 0x93, 0xc7, 0xb0, 0xf6, 0x8c, 0x89, 0xfc, 0x75, 0x2f, 0x29, 0xf0, 0x97, 0x93, 0xf5, 0x76,
+	0x4a, 0x8f, 0x56, 0xe4, 0xfb, 0x4a, 0xff, 0x03, 0xf2, 0x23, 0x18, 0x9e, 0xb2, 0x38, 0xcc, 0x1f,
+	0x3e, 0x0e, 0x0b, 0x6f, 0x0a, 0xbd, 0x3e, 0x36, 0xd5, 0xa3, 0xbd, 0x0f, 0xf6, 0x1c, 0x72, 0x08,
+	0xb7, 0x10, 0xbd, 0xee, 0x91, 0xdc, 0xad, 0x05, 0xcf, 0x5c, 0xca, 0x2c, 0xbe, 0x90, 0x5e, 0x61,
+	0xe7, 0xb4, 0x72, 0x32, 0x32, 0x32, 0x6f, 0x94, 0xe0, 0xfe, 0x07, 0xe4, 0x3e, 0x40, 0x1e, 0xe0,
+	0x89, 0x2c, 0x7d, 0x55, 0x02, 0x7e, 0x61, 0x40, 0xbc, 0xf4, 0xcd, 0x03, 0xb5, 0xa2, 0xa8, 0x44,
+	0x7f, 0xef, 0x66, 0x19, 0xac, 0x56, 0xdb, 0xff, 0x80, 0x3c, 0x02, 0x78, 0xc6, 0x84, 0x8e, 0x34,
+	0x4a, 0xb3, 0xc5, 0x20, 0xe7, 0x6d, 0x17, 0x60, 0x19, 0x21, 0x85, 0xed, 0x67, 0x4c, 0x94, 0xed,
+	0x98, 0xdc, 0xb1, 0x8c, 0xb5, 0xec, 0x26, 0xde, 0x87, 0xf5, 0x9d, 0x19, 0xcf, 0xc7, 0x30, 0x78,
+	0xc6, 0x84, 0xb1, 0x49, 0xa2, 0xec, 0xa8, 0x68, 0xd5, 0xde, 0x4e, 0x11, 0x68, 0x68, 0x0f, 0x5e,
+	0xc2, 0x50, 0xda, 0x8c, 0x5a, 0x9d, 0x24, 0x25, 0x7f, 0x0a, 0x9e, 0x3e, 0xfc, 0x17, 0x16, 0x0c,
+	0x0f, 0x97, 0x23, 0x4e, 0xaa, 0x8f, 0x0e, 0x4a, 0xeb, 0x78, 0xf0, 0xdf, 0x4d, 0x00, 0xc9, 0x51,
+	0x69, 0xf6, 0x1b, 0xd8, 0x94, 0x96, 0x61, 0x3d, 0x24, 0xd1, 0x26, 0x51, 0x7d, 0xe9, 0xe2, 0xb9,
+	0xd5, 0x0e, 0x23, 0xe8, 0x9e, 0x73, 0xdf, 0x21, 0x8f, 0xa1, 0xab, 0xc6, 0x66, 0xa4, 0xf6, 0x81,
+	0x96, 0x77, 0xa3, 0x04, 0x35, 0xd4, 0xf7, 0x9d, 0xdf, 0x75, 0x5e, 0xe4, 0x18, 0x3a, 0xea, 0xe2,
+	0x96, 0xc8, 0x8a, 0xdb, 0xc2, 0x5b, 0x5f, 0xef, 0xee, 0xa2, 0xee, 0x6c, 0xbd, 0x1e, 0x42, 0x57,
+	0xdf, 0xcc, 0x6a, 0x9f, 0x2c, 0x5c, 0xee, 0x7a, 0xdb, 0x05, 0x98, 0x4d, 0xa5, 0xb7, 0x9c, 0x8a,
+	0xaa, 0xb8, 0xcd, 0xf5, 0xb6, 0x0b, 0xb0, 0x8c, 0x6a, 0x1f, 0xda, 0xd2, 0x80, 0xc9, 0x66, 0x66,
+	0xcb, 0x86, 0x62, 0xcb, 0x82, 0xd8, 0xa3, 0xe8, 0xa4, 0xa1, 0x46, 0x29, 0x66, 0x2a, 0x6f, 0xbb,
+	0x00, 0x33, 0x54, 0xe7, 0x1d, 0xf9, 0xe7, 0xc2, 0x67, 0xff, 0x3b, 0x00, 0xa7, 0x6a, 0x34, 0xbf,
+	0xc8, 0x30, 0x00, 0x00,
 }
@@ -2,12 +2,70 @@ package pb
 
 import (
 	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
 )
 
 func (l *Location) URL() string {
 	return fmt.Sprintf("%s:%d", l.Server, l.Port)
 }
 
+// IsOnHost checks whether the location is on the host, by the host name
+// or the IP addresses of the host.
+func (l *Location) IsOnHost(host string) bool {
+	if strings.EqualFold(l.Server, host) {
+		return true
+	}
+	addresses := lookupHost(host)
+	for _, a := range lookupHost(l.Server) {
+		for _, b := range addresses {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const (
+	hostLookupTTL       = 10 * time.Minute
+	failedHostLookupTTL = 30 * time.Second
+)
+
+type hostLookup struct {
+	addresses []string
+	expireAt  time.Time
+}
+
+var (
+	hostLookups     = make(map[string]*hostLookup)
+	hostLookupsLock sync.Mutex
+)
+
+// lookupHost caches the addresses of the host, since it is checked for every allocation.
+// The lookup is done outside of the lock, and a failed lookup is retried soon.
+func lookupHost(host string) []string {
+	hostLookupsLock.Lock()
+	lookup, found := hostLookups[host]
+	hostLookupsLock.Unlock()
+	if found && time.Now().Before(lookup.expireAt) {
+		return lookup.addresses
+	}
+
+	addresses, err := net.LookupHost(host)
+	ttl := hostLookupTTL
+	if err != nil {
+		ttl = failedHostLookupTTL
+	}
+
+	hostLookupsLock.Lock()
+	hostLookups[host] = &hostLookup{addresses: addresses, expireAt: time.Now().Add(ttl)}
+	hostLookupsLock.Unlock()
+	return addresses
+}
+
 // the distance is a relative value, similar to network lantency
 func (a *Location) Distance(b *Location) float64 {
 	if a.DataCenter != b.DataCenter {
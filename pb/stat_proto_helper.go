@@ -0,0 +1,109 @@
+package pb
+
+import (
+	"math"
+)
+
+// AddCounter adds delta to the named counter, creating it if necessary.
+func (m *InstructionStat) AddCounter(name string, delta int64) {
+	for _, c := range m.Counters {
+		if c.Name == name {
+			c.Value += delta
+			return
+		}
+	}
+	m.Counters = append(m.Counters, &InstructionStat_Counter{Name: name, Value: delta})
+}
+
+// GetHistogram returns the named histogram, creating it if necessary.
+func (m *InstructionStat) GetHistogram(name string) *InstructionStat_Histogram {
+	for _, h := range m.Histograms {
+		if h.Name == name {
+			return h
+		}
+	}
+	h := &InstructionStat_Histogram{Name: name}
+	m.Histograms = append(m.Histograms, h)
+	return h
+}
+
+// Merge adds the counters and histograms of b into m.
+// The step id, task id, and input/output counters are left unchanged.
+func (m *InstructionStat) Merge(b *InstructionStat) {
+	for _, c := range b.GetCounters() {
+		m.AddCounter(c.Name, c.Value)
+	}
+	for _, h := range b.GetHistograms() {
+		m.GetHistogram(h.Name).Merge(h)
+	}
+}
+
+// Observe records one value into the histogram.
+func (h *InstructionStat_Histogram) Observe(value float64) {
+	if h.Count == 0 || value < h.Min {
+		h.Min = value
+	}
+	if h.Count == 0 || value > h.Max {
+		h.Max = value
+	}
+	h.Count++
+	h.Sum += value
+
+	bucket := 0
+	if value >= 1 {
+		bucket = int(math.Floor(math.Log2(value))) + 1
+	}
+	for len(h.Buckets) <= bucket {
+		h.Buckets = append(h.Buckets, 0)
+	}
+	h.Buckets[bucket]++
+}
+
+// Merge adds all observations of b into h.
+func (h *InstructionStat_Histogram) Merge(b *InstructionStat_Histogram) {
+	if b.Count == 0 {
+		return
+	}
+	if h.Count == 0 || b.Min < h.Min {
+		h.Min = b.Min
+	}
+	if h.Count == 0 || b.Max > h.Max {
+		h.Max = b.Max
+	}
+	h.Count += b.Count
+	h.Sum += b.Sum
+	for len(h.Buckets) < len(b.Buckets) {
+		h.Buckets = append(h.Buckets, 0)
+	}
+	for i, n := range b.Buckets {
+		h.Buckets[i] += n
+	}
+}
+
+// Mean returns the average of all observed values.
+func (h *InstructionStat_Histogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / float64(h.Count)
+}
+
+// GetStepStat aggregates the latest execution stats of all tasks of one step.
+func (m *FlowExecutionStatus) GetStepStat(stepId int32) *InstructionStat {
+	ret := &InstructionStat{StepId: stepId, TaskId: -1}
+	for _, tg := range m.GetTaskGroups() {
+		executions := tg.GetExecutions()
+		if len(executions) == 0 {
+			continue
+		}
+		for _, stat := range executions[len(executions)-1].GetExecutionStat().GetStats() {
+			if stat.StepId != stepId {
+				continue
+			}
+			ret.InputCounter += stat.InputCounter
+			ret.OutputCounter += stat.OutputCounter
+			ret.Merge(stat)
+		}
+	}
+	return ret
+}
@@ -66,3 +66,20 @@ func (m *FlowExecutionStatus) GetStep(stepId int32) *FlowExecutionStatus_Step {
 	}
 	return nil
 }
+
+// State is "pending" before the first execution, "running" until the last
+// execution stops, then "failed" if it reported an error, or "finished".
+func (m *FlowExecutionStatus_TaskGroup) State() string {
+	executions := m.GetExecutions()
+	if len(executions) == 0 {
+		return "pending"
+	}
+	execution := executions[len(executions)-1]
+	if execution.GetStopTime() == 0 {
+		return "running"
+	}
+	if len(execution.GetError()) > 0 {
+		return "failed"
+	}
+	return "finished"
+}
@@ -0,0 +1,77 @@
+package file
+
+type filePartition struct {
+	fileNames []string
+	// the hosts storing the files
+	hosts []string
+}
+
+// partitionByHosts spreads the files to the partitions, putting the files
+// stored on the same host together, while keeping the partitions balanced.
+func partitionByHosts(fileNames []string, fileHosts [][]string, partitionCount int) []*filePartition {
+	if partitionCount <= 0 {
+		partitionCount = 1
+	}
+	partitions := make([]*filePartition, partitionCount)
+	for i := range partitions {
+		partitions[i] = &filePartition{}
+	}
+	// a partition takes at most its fair share of files, so one host does not get all files
+	limit := (len(fileNames) + partitionCount - 1) / partitionCount
+
+	// place the files with known hosts first
+	var order []int
+	for i := range fileNames {
+		if len(fileHosts[i]) > 0 {
+			order = append(order, i)
+		}
+	}
+	for i := range fileNames {
+		if len(fileHosts[i]) == 0 {
+			order = append(order, i)
+		}
+	}
+
+	for _, i := range order {
+		fileName, hosts := fileNames[i], fileHosts[i]
+		var picked *filePartition
+		// a partition already on one of the hosts
+		for _, p := range partitions {
+			if len(p.fileNames) < limit && hasCommonHost(p.hosts, hosts) && (picked == nil || len(p.fileNames) < len(picked.fileNames)) {
+				picked = p
+			}
+		}
+		// or the partition with the fewest files
+		if picked == nil {
+			for _, p := range partitions {
+				if picked == nil || len(p.fileNames) < len(picked.fileNames) {
+					picked = p
+				}
+			}
+		}
+		// the files with unknown hosts can be read anywhere
+		if len(picked.fileNames) == 0 {
+			picked.hosts = hosts
+		} else if len(hosts) > 0 {
+			picked.hosts = commonHosts(picked.hosts, hosts)
+		}
+		picked.fileNames = append(picked.fileNames, fileName)
+	}
+	return partitions
+}
+
+func hasCommonHost(a, b []string) bool {
+	return len(commonHosts(a, b)) > 0
+}
+
+func commonHosts(a, b []string) (ret []string) {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				ret = append(ret, x)
+				break
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package file
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPartitionByHosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileNames      []string
+		fileHosts      [][]string
+		partitionCount int
+		expected       []filePartition
+	}{
+		{
+			name:           "files on the same host are put together",
+			fileNames:      []string{"a1", "b1", "a2", "b2"},
+			fileHosts:      [][]string{{"a"}, {"b"}, {"a"}, {"b"}},
+			partitionCount: 2,
+			expected: []filePartition{
+				{fileNames: []string{"a1", "a2"}, hosts: []string{"a"}},
+				{fileNames: []string{"b1", "b2"}, hosts: []string{"b"}},
+			},
+		},
+		{
+			name:           "files on one host are still balanced",
+			fileNames:      []string{"a1", "a2", "a3", "a4"},
+			fileHosts:      [][]string{{"a"}, {"a"}, {"a"}, {"a"}},
+			partitionCount: 2,
+			expected: []filePartition{
+				{fileNames: []string{"a1", "a2"}, hosts: []string{"a"}},
+				{fileNames: []string{"a3", "a4"}, hosts: []string{"a"}},
+			},
+		},
+		{
+			name:           "replicas keep only the common hosts",
+			fileNames:      []string{"ab", "bc"},
+			fileHosts:      [][]string{{"a", "b"}, {"b", "c"}},
+			partitionCount: 1,
+			expected: []filePartition{
+				{fileNames: []string{"ab", "bc"}, hosts: []string{"b"}},
+			},
+		},
+		{
+			name:           "files with unknown hosts fill the remaining partitions",
+			fileNames:      []string{"x1", "a1", "x2"},
+			fileHosts:      [][]string{nil, {"a"}, nil},
+			partitionCount: 3,
+			expected: []filePartition{
+				{fileNames: []string{"a1"}, hosts: []string{"a"}},
+				{fileNames: []string{"x1"}},
+				{fileNames: []string{"x2"}},
+			},
+		},
+		{
+			name:           "no partition count",
+			fileNames:      []string{"x1", "a1"},
+			fileHosts:      [][]string{nil, {"a"}},
+			partitionCount: 0,
+			expected: []filePartition{
+				{fileNames: []string{"a1", "x1"}, hosts: []string{"a"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		partitions := partitionByHosts(test.fileNames, test.fileHosts, test.partitionCount)
+		var actual []filePartition
+		for _, p := range partitions {
+			actual = append(actual, *p)
+		}
+		sort.Slice(actual, func(i, j int) bool {
+			return actual[i].fileNames[0] < actual[j].fileNames[0]
+		})
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, actual)
+		}
+	}
+}
@@ -27,10 +27,12 @@ type FileSource struct {
 }
 
 // Generate generates data shard info,
-// partitions them via round robin,
-// and reads each shard on each executor
+// partitions them by the hosts storing the files,
+// and reads each shard on each executor, preferably on those hosts
 func (s *FileSource) Generate(f *flow.Flow) *flow.Dataset {
-	return s.genShardInfos(f).RoundRobin(s.prefix, s.PartitionCount).Map(s.prefix+".Read", registeredMapperReadShard)
+	ret := s.genShardInfos(f).Map(s.prefix+".Read", registeredMapperReadShard)
+	ret.Meta.Projector = s
+	return ret
 }
 
 // SetHasHeader sets whether the data contains header
@@ -46,8 +48,28 @@ func (q *FileSource) Select(fields ...string) *FileSource {
 	return q
 }
 
+// Project narrows the selected fields to the ones at the indexes, in that order.
+// Only orc files read the fields selected, and only if they are named.
+func (q *FileSource) Project(indexes []int) bool {
+	if q.FileType != "orc" || len(q.Fields) == 0 {
+		return false
+	}
+	var fields []string
+	used := make(map[int]bool)
+	for _, x := range indexes {
+		if x < 1 || x > len(q.Fields) || used[x] {
+			return false
+		}
+		used[x] = true
+		fields = append(fields, q.Fields[x-1])
+	}
+	q.Fields = fields
+	return true
+}
+
 // New creates a FileSource based on a file name.
 // The base file name can have "*", "?" pattern denoting a list of file names.
+// With flow.Auto partitions, the partition count is derived from the file sizes.
 func newFileSource(fileType, fileOrPattern string, partitionCount int) *FileSource {
 
 	s := &FileSource{
@@ -76,35 +98,96 @@ func newFileSource(fileType, fileOrPattern string, partitionCount int) *FileSour
 }
 
 func (s *FileSource) genShardInfos(f *flow.Flow) *flow.Dataset {
-	return f.Source(s.prefix+"."+s.fileBaseName, func(writer io.Writer, stats *pb.InstructionStat) error {
-		stats.InputCounter++
-		if !s.hasWildcard && !filesystem.IsDir(s.Path) {
-			stats.OutputCounter++
-			util.NewRow(util.Now(), encodeShardInfo(&FileShardInfo{
-				FileName:  s.Path,
-				FileType:  s.FileType,
-				HasHeader: s.HasHeader,
-				Fields:    s.Fields,
-			})).WriteTo(writer)
-		} else {
-			virtualFiles, err := filesystem.List(s.folder)
-			if err != nil {
-				return fmt.Errorf("Failed to list folder %s: %v", s.folder, err)
-			}
-			for _, vf := range virtualFiles {
-				if !s.hasWildcard || s.match(vf.Location) {
-					stats.OutputCounter++
-					util.NewRow(util.Now(), encodeShardInfo(&FileShardInfo{
-						FileName:  vf.Location,
-						FileType:  s.FileType,
-						HasHeader: s.HasHeader,
-						Fields:    s.Fields,
-					})).WriteTo(writer)
+	fileNames, err := s.listFiles()
+	if err != nil {
+		// report the error when the flow runs, instead of exiting while building it
+		return s.listingFailure(f, err)
+	}
+	var fileHosts [][]string
+	for _, fileName := range fileNames {
+		fileHosts = append(fileHosts, filesystem.Hosts(fileName))
+	}
+	partitionCount, totalSize := s.PartitionCount, int64(-1)
+	if partitionCount == flow.Auto {
+		totalSize = sizeInMB(fileNames)
+		partitionCount = f.AutoShardCount(totalSize)
+		if partitionCount > len(fileNames) {
+			partitionCount = len(fileNames)
+		}
+	}
+	partitions := partitionByHosts(fileNames, fileHosts, partitionCount)
+
+	ret := f.NewNextDataset(len(partitions))
+	if totalSize >= 0 {
+		ret.Meta.TotalSize = totalSize
+	}
+	step := f.AddOneToAllStep(nil, ret)
+	step.IsOnDriverSide = true
+	step.Name = s.prefix + "." + s.fileBaseName
+	step.Function = func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		for i, p := range partitions {
+			for _, fileName := range p.fileNames {
+				stats.InputCounter++
+				err := util.NewRow(util.Now(), encodeShardInfo(&FileShardInfo{
+					FileName:  fileName,
+					FileType:  s.FileType,
+					HasHeader: s.HasHeader,
+					Fields:    s.Fields,
+				})).WriteTo(writers[i])
+				if err != nil {
+					return err
 				}
+				stats.OutputCounter++
 			}
 		}
 		return nil
-	})
+	}
+	for i, p := range partitions {
+		ret.Shards[i].Meta = &flow.DasetsetShardMetadata{PreferredHosts: p.hosts}
+	}
+	return ret
+}
+
+// listingFailure generates one shard whose step fails with the listing error.
+func (s *FileSource) listingFailure(f *flow.Flow, err error) *flow.Dataset {
+	ret := f.NewNextDataset(1)
+	step := f.AddOneToAllStep(nil, ret)
+	step.IsOnDriverSide = true
+	step.Name = s.prefix + "." + s.fileBaseName
+	step.Function = func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return fmt.Errorf("Failed to list %s: %v", s.Path, err)
+	}
+	return ret
+}
+
+// sizeInMB sums the file sizes in MB, or returns -1 if any size is unknown.
+func sizeInMB(fileNames []string) int64 {
+	var total int64
+	for _, fileName := range fileNames {
+		size := filesystem.Size(fileName)
+		if size < 0 {
+			return -1
+		}
+		total += size
+	}
+	return (total + 1024*1024 - 1) / (1024 * 1024)
+}
+
+func (s *FileSource) listFiles() ([]string, error) {
+	if !s.hasWildcard && !filesystem.IsDir(s.Path) {
+		return []string{s.Path}, nil
+	}
+	virtualFiles, err := filesystem.List(s.folder)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list folder %s: %v", s.folder, err)
+	}
+	var fileNames []string
+	for _, vf := range virtualFiles {
+		if !s.hasWildcard || s.match(vf.Location) {
+			fileNames = append(fileNames, vf.Location)
+		}
+	}
+	return fileNames, nil
 }
 
 func (s *FileSource) match(fullPath string) bool {
@@ -0,0 +1,46 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSizeInMB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sizes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name string, size int) string {
+		fileName := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(fileName, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return fileName
+	}
+	empty := writeFile("empty", 0)
+	small := writeFile("small", 1)
+	mb := writeFile("mb", 1024*1024)
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name      string
+		fileNames []string
+		expected  int64
+	}{
+		{"no files", nil, 0},
+		{"empty file", []string{empty}, 0},
+		{"partial MB is rounded up", []string{small}, 1},
+		{"exact MB", []string{mb}, 1},
+		{"sizes are added before rounding", []string{mb, small, small}, 2},
+		{"unknown size of any file", []string{mb, missing}, -1},
+	}
+	for _, test := range tests {
+		if size := sizeInMB(test.fileNames); size != test.expected {
+			t.Errorf("%s: %d MB, expecting %d MB", test.name, size, test.expected)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/chrislusf/gleam/pb"
 )
@@ -98,3 +99,21 @@ func fprintRow(writer io.Writer, base int, delimiter string, decodedObjects ...i
 	}
 	return written, nil
 }
+
+// FprintRow pretty prints the fields of a row on one line, tab separated,
+// with the strings quoted, to inspect the data.
+func FprintRow(writer io.Writer, row *Row) error {
+	var fields []string
+	for _, obj := range append(append([]interface{}{}, row.K...), row.V...) {
+		switch v := obj.(type) {
+		case string:
+			fields = append(fields, fmt.Sprintf("%q", v))
+		case []byte:
+			fields = append(fields, fmt.Sprintf("%q", v))
+		default:
+			fields = append(fields, fmt.Sprintf("%v", v))
+		}
+	}
+	_, err := fmt.Fprintln(writer, strings.Join(fields, "\t"))
+	return err
+}
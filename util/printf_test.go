@@ -0,0 +1,28 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFprintRow(t *testing.T) {
+	row := NewRow(0, "a b", []byte(""), 3, 1.5, nil)
+
+	encoded, err := encodeRow(*row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeRow(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := FprintRow(&buf, decoded); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\"a b\"\t\"\"\t3\t1.5\t<nil>\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -9,11 +9,23 @@ func Retry(fn func() error) error {
 	return TimeDelayedRetry(fn, time.Second, 3*time.Second)
 }
 
+// retryable is implemented by errors that tell whether trying again may succeed.
+type retryable interface {
+	Retryable() bool
+}
+
+func isRetryable(err error) bool {
+	r, ok := err.(retryable)
+	return !ok || r.Retryable()
+}
+
+// TimeDelayedRetry calls fn again after each of the wait times, until it succeeds,
+// or fails with an error whose Retryable() method returns false.
 func TimeDelayedRetry(fn func() error, waitTimes ...time.Duration) error {
 
 	err := fn()
-	if err == nil {
-		return nil
+	if err == nil || !isRetryable(err) {
+		return err
 	}
 
 	log.Printf("Retrying after failure: %v", err)
@@ -24,6 +36,9 @@ func TimeDelayedRetry(fn func() error, waitTimes ...time.Duration) error {
 		if err == nil {
 			return nil
 		}
+		if !isRetryable(err) {
+			return err
+		}
 		log.Printf("Failed %d time due to %v", i+1, err)
 	}
 
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+type permanentError struct{}
+
+func (e permanentError) Error() string   { return "permanent" }
+func (e permanentError) Retryable() bool { return false }
+
+func TestTimeDelayedRetry(t *testing.T) {
+	var calls int
+	err := TimeDelayedRetry(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, 0, 0, 0)
+	if err != nil || calls != 3 {
+		t.Errorf("expected success after 3 calls, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = TimeDelayedRetry(func() error {
+		calls++
+		return permanentError{}
+	}, 0, 0, 0)
+	if _, ok := err.(permanentError); !ok || calls != 1 {
+		t.Errorf("expected no retry, got %v after %d calls", err, calls)
+	}
+}